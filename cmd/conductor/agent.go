@@ -12,16 +12,16 @@ import (
 	"syscall"
 
 	"github.com/hammashamzah/conductor/internal/agent"
-	"github.com/hammashamzah/conductor/internal/clickup"
 	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/secrets"
 	"github.com/hammashamzah/conductor/internal/store"
 	"github.com/spf13/cobra"
 )
 
 var agentCmd = &cobra.Command{
 	Use:   "agent",
-	Short: "Manage the ClickUp agent daemon",
-	Long:  `The agent daemon watches ClickUp for task status changes and automatically creates worktrees with Claude Code.`,
+	Short: "Manage the issue-tracker agent daemon",
+	Long:  `The agent daemon watches ClickUp lists and GitHub Issues for tasks entering a trigger status (or label) and automatically creates worktrees with Claude Code.`,
 }
 
 var agentStartCmd = &cobra.Command{
@@ -73,8 +73,8 @@ func runAgentStart(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if cfg.Defaults.ClickUp == nil || cfg.Defaults.ClickUp.APIToken == "" {
-		return fmt.Errorf("ClickUp not configured. Run 'conductor agent setup' first")
+	if !agentConfigured(cfg) {
+		return fmt.Errorf("no issue tracker configured. Run 'conductor agent setup' for ClickUp, or add githubIssues to a project's conductor.json")
 	}
 
 	if !foregroundFlag {
@@ -84,7 +84,7 @@ func runAgentStart(cmd *cobra.Command, args []string) error {
 
 	// Foreground mode
 	s := store.New(cfg)
-	defer func() { _, _ = s.Close() }()
+	defer closeStore(s)
 
 	daemon, err := agent.NewDaemon(s, cfg.Defaults.ClickUp, nil)
 	if err != nil {
@@ -113,8 +113,15 @@ func runAgentStart(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "error during shutdown: %v\n", err)
 	}
 
-	// Save updated ClickUp config (webhook IDs cleared on stop)
-	_ = config.Save(cfg)
+	// Save updated ClickUp config (webhook IDs cleared on stop) through the store,
+	// which merges with changes other processes made while the daemon ran
+	if cfg.Defaults.ClickUp != nil {
+		clickupCfg := *cfg.Defaults.ClickUp
+		_ = s.BatchMutate(func(c *config.Config) error {
+			c.Defaults.ClickUp = &clickupCfg
+			return nil
+		})
+	}
 
 	fmt.Println("Agent daemon stopped.")
 	return nil
@@ -171,23 +178,38 @@ func runAgentStatus(cmd *cobra.Command, args []string) error {
 		if err != nil || projectConfig == nil {
 			continue
 		}
-		if projectConfig.ClickUp != nil && projectConfig.ClickUp.ListID != "" {
-			triggerStatus := projectConfig.ClickUp.TriggerStatus
-			if triggerStatus == "" {
-				triggerStatus = "in progress"
-			}
-			mode := projectConfig.ClickUp.GetMode()
-			modeStr := string(mode)
-			if projectConfig.ClickUp.AutoPick {
+		if settings := projectConfig.Tracker(project); settings != nil {
+			modeStr := string(settings.Mode)
+			if settings.AutoPick {
 				modeStr += "+autopick"
 			}
-			fmt.Printf("  %s (list: %s, trigger: %q, mode: %s)\n", projectName, projectConfig.ClickUp.ListID, triggerStatus, modeStr)
+			limitStr := ""
+			if settings.MaxConcurrentAgents > 0 {
+				limitStr = fmt.Sprintf(", max agents: %d", settings.MaxConcurrentAgents)
+			}
+			fmt.Printf("  %s (%s: %s, trigger: %q, mode: %s%s)\n", projectName, settings.Provider, settings.ListID, settings.TriggerStatus, modeStr, limitStr)
 		}
 	}
+	if cfg.Defaults.MaxConcurrentAgents > 0 {
+		fmt.Printf("\nMax concurrent agents: %d\n", cfg.Defaults.MaxConcurrentAgents)
+	}
+
+	queued := agent.LoadTaskQueue().Tasks()
+	if len(queued) == 0 {
+		fmt.Println("\nQueue: empty")
+		return nil
+	}
+	fmt.Printf("\nQueue (%d waiting):\n", len(queued))
+	for i, qt := range queued {
+		fmt.Printf("  %d. %s: %s (%s, queued %s)\n", i+1, qt.Project, qt.Task.Name, qt.Task.ID, qt.QueuedAt.Local().Format("2006-01-02 15:04"))
+	}
 
 	return nil
 }
 
+// clickupTokenSecret is the secret name agent setup stores the API token under
+const clickupTokenSecret = "clickup-token"
+
 func runAgentSetup(cmd *cobra.Command, args []string) error {
 	cfg, err := config.Load()
 	if err != nil {
@@ -204,6 +226,17 @@ func runAgentSetup(cmd *cobra.Command, args []string) error {
 	fmt.Printf("ClickUp API Token [%s]: ", maskToken(cfg.Defaults.ClickUp.APIToken))
 	token, _ := reader.ReadString('\n')
 	token = strings.TrimSpace(token)
+	if token != "" && !secrets.IsReference(token) {
+		// Keep the token itself out of conductor.json
+		backend, err := secrets.Default()
+		if err != nil {
+			return err
+		}
+		if err := backend.Set(clickupTokenSecret, token); err != nil {
+			return fmt.Errorf("failed to store API token: %w", err)
+		}
+		token = secrets.Ref(clickupTokenSecret)
+	}
 	if token != "" {
 		cfg.Defaults.ClickUp.APIToken = token
 	}
@@ -246,14 +279,23 @@ func runAgentSetup(cmd *cobra.Command, args []string) error {
 		cfg.Defaults.ClickUp.WebhookPort = 9876
 	}
 
-	// Save
-	if err := config.Save(cfg); err != nil {
+	// Save onto the latest config so changes made while prompting aren't lost
+	clickupCfg := cfg.Defaults.ClickUp
+	err = config.UpdateConfigFile(func(current []byte) ([]byte, error) {
+		latest, err := config.Parse(current)
+		if err != nil {
+			return nil, err
+		}
+		latest.Defaults.ClickUp = clickupCfg
+		return config.Marshal(latest)
+	})
+	if err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
 	fmt.Println("\nClickUp configuration saved!")
 	fmt.Println("\nNext steps:")
-	fmt.Println("  1. Add clickup.listId to each project's conductor.json")
+	fmt.Println("  1. Add clickup.listId to each project's conductor.json (or githubIssues for GitHub Issues)")
 	fmt.Println("  2. Run: conductor agent start")
 
 	return nil
@@ -265,10 +307,6 @@ func runAgentPick(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if cfg.Defaults.ClickUp == nil || cfg.Defaults.ClickUp.APIToken == "" {
-		return fmt.Errorf("ClickUp not configured. Run 'conductor agent setup' first")
-	}
-
 	// Determine project name
 	var projectName string
 	if len(args) > 0 {
@@ -296,23 +334,30 @@ func runAgentPick(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("project %q not found", projectName)
 	}
 
-	projectConfig, err := config.LoadProjectConfig(project.Path)
-	if err != nil || projectConfig == nil || projectConfig.ClickUp == nil {
-		return fmt.Errorf("project %q has no ClickUp configuration", projectName)
+	var settings *config.TrackerSettings
+	if projectConfig, err := config.LoadProjectConfig(project.Path); err == nil && projectConfig != nil {
+		settings = projectConfig.Tracker(project)
+	}
+	if settings == nil {
+		return fmt.Errorf("project %q has no issue tracker configuration", projectName)
 	}
 
-	if projectConfig.ClickUp.GetMode() != config.AgentModeSequential {
-		return fmt.Errorf("project %q is not in sequential mode (current: %s)", projectName, projectConfig.ClickUp.GetMode())
+	if settings.Mode != config.AgentModeSequential {
+		return fmt.Errorf("project %q is not in sequential mode (current: %s)", projectName, settings.Mode)
 	}
 
 	// Create picker and pick next task
-	client := clickup.NewClient(cfg.Defaults.ClickUp.APIToken)
-	picker := agent.NewTaskPicker(client)
+	provider, err := agent.NewProvider(settings.Provider, cfg.Defaults.ClickUp,
+		[]string{settings.TriggerStatus, settings.ReadyStatus, settings.ReviewStatus})
+	if err != nil {
+		return err
+	}
+	picker := agent.NewTaskPicker(provider)
 
-	readyStatus := projectConfig.ClickUp.GetReadyStatus()
-	fmt.Printf("Fetching tasks with status %q from list %s...\n", readyStatus, projectConfig.ClickUp.ListID)
+	readyStatus := settings.ReadyStatus
+	fmt.Printf("Fetching tasks with status %q from %s...\n", readyStatus, settings.ListID)
 
-	task, err := picker.PickNextTask(projectConfig.ClickUp.ListID, readyStatus)
+	task, err := picker.PickNextTask(settings.ListID, readyStatus)
 	if err != nil {
 		return fmt.Errorf("failed to pick task: %w", err)
 	}
@@ -320,13 +365,10 @@ func runAgentPick(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Selected: %s (ID: %s)\n", task.Name, task.ID)
 
 	// Move to trigger status so the running daemon picks it up
-	triggerStatus := projectConfig.ClickUp.TriggerStatus
-	if triggerStatus == "" {
-		triggerStatus = "in progress"
-	}
+	triggerStatus := settings.TriggerStatus
 	fmt.Printf("Moving task to %q...\n", triggerStatus)
 
-	if err := client.UpdateTaskStatus(task.ID, triggerStatus); err != nil {
+	if err := provider.UpdateStatus(task.ID, triggerStatus); err != nil {
 		return fmt.Errorf("failed to update task status: %w", err)
 	}
 
@@ -408,9 +450,27 @@ func startBackground() error {
 	return nil
 }
 
+// agentConfigured reports whether the agent has an issue tracker to watch:
+// ClickUp credentials, or a project using GitHub Issues
+func agentConfigured(cfg *config.Config) bool {
+	if cfg.Defaults.ClickUp != nil && cfg.Defaults.ClickUp.APIToken != "" {
+		return true
+	}
+	for _, project := range cfg.Projects {
+		projectConfig, err := config.LoadProjectConfig(project.Path)
+		if err != nil || projectConfig == nil {
+			continue
+		}
+		if settings := projectConfig.Tracker(project); settings != nil && settings.Provider == config.TrackerGitHub {
+			return true
+		}
+	}
+	return false
+}
+
 func maskToken(token string) string {
-	if token == "" {
-		return ""
+	if token == "" || secrets.IsReference(token) {
+		return token
 	}
 	if len(token) <= 8 {
 		return "****"
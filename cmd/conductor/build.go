@@ -65,7 +65,7 @@ func runBuild(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	defer func() { _, _ = s.Close() }()
+	defer closeStore(s)
 
 	// Detect project from cwd
 	cwd, err := os.Getwd()
@@ -143,7 +143,7 @@ func runBuild(cmd *cobra.Command, args []string) error {
 				return
 			}
 
-			if err := mux.Current().CreateCodingWindowWithTask(projectName, wt.Branch, wt.Path, prompt, codingagent.ClaudeCode); err != nil {
+			if _, err := mux.Current().CreateCodingWindowWithTask(projectName, wt.Branch, wt.Path, prompt, codingagent.ClaudeCode); err != nil {
 				log.Printf("build: failed to create coding window: %v", err)
 				return
 			}
@@ -162,7 +162,7 @@ func runBuild(cmd *cobra.Command, args []string) error {
 			// Still open the window
 			fallbackPrompt := buildFeaturePrompt(featureTitle, buildDescription, specContent, evalContent, scopeName, project, projectConfig, wt)
 			if !buildNoOpen {
-				_ = mux.Current().CreateCodingWindowWithTask(projectName, wt.Branch, wt.Path, fallbackPrompt, codingagent.ClaudeCode)
+				_, _ = mux.Current().CreateCodingWindowWithTask(projectName, wt.Branch, wt.Path, fallbackPrompt, codingagent.ClaudeCode)
 			}
 		}
 	})
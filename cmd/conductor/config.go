@@ -0,0 +1,544 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/database"
+	"github.com/hammashamzah/conductor/internal/secrets"
+	"github.com/hammashamzah/conductor/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View, validate and edit conductor settings",
+	Long: `View, validate and edit ~/.conductor/conductor.json and the conductor.json
+of registered projects.
+
+Settings are addressed by the dotted path of their JSON fields, e.g.
+defaults.openWith, defaults.tmux.disableCc or updates.checkInterval.`,
+}
+
+var (
+	configMigrateDryRun bool
+	configProject       bool
+)
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all settings",
+	Long: `List every setting with its dotted path and current value.
+
+Secrets (tokens and connection string passwords) are masked. Use --project
+to list the current project's conductor.json instead of the global config.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, err := loadConfigTarget()
+		if err != nil {
+			return err
+		}
+		defer target.close()
+
+		printSettings(config.ListSettings(target.cfg()), "")
+		return nil
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <path>",
+	Short: "Print a setting",
+	Long: `Print the value of a setting by its dotted path, e.g.:
+
+  conductor config get defaults.multiplexer
+  conductor config get defaults.tmux
+  conductor config get --project ports.labels
+
+Getting a section prints each setting inside it. Secrets are masked.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, err := loadConfigTarget()
+		if err != nil {
+			return err
+		}
+		defer target.close()
+
+		path := args[0]
+		value, err := config.GetSetting(target.cfg(), path)
+		if err != nil {
+			return err
+		}
+
+		// Sections print their settings one per line
+		if settings := settingsUnder(target.cfg(), path); len(settings) > 1 || (len(settings) == 1 && settings[0].Path != path) {
+			printSettings(settings, path+".")
+			return nil
+		}
+		if value == nil {
+			return fmt.Errorf("'%s' is not set", path)
+		}
+		fmt.Println(formatSetting(config.Setting{Path: path, Value: value, Secret: config.IsSecretSetting(path)}))
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <path> <value>",
+	Short: "Change a setting",
+	Long: `Change a setting by its dotted path. The value is checked against the
+setting's type (string, integer, true/false or list) and, for settings with
+a fixed set of values, against that set. Lists take a JSON array or
+comma-separated values.
+
+Examples:
+  conductor config set defaults.multiplexer herdr
+  conductor config set defaults.portRangeStart 4000
+  conductor config set defaults.tmux.disableCc true
+  conductor config set --project ports.labels web,api`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, err := loadConfigTarget()
+		if err != nil {
+			return err
+		}
+		defer target.close()
+
+		if err := target.set(args[0], args[1]); err != nil {
+			return err
+		}
+
+		value, _ := config.GetSetting(target.cfg(), args[0])
+		fmt.Printf("✓ %s = %s\n", args[0], formatSetting(config.Setting{Path: args[0], Value: value, Secret: config.IsSecretSetting(args[0])}))
+		return nil
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <path>",
+	Short: "Reset a setting to its default",
+	Long:  "Reset a setting to its zero value, or remove a map entry such as scripts.<name>",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, err := loadConfigTarget()
+		if err != nil {
+			return err
+		}
+		defer target.close()
+
+		if err := target.unset(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Unset %s\n", args[0])
+		return nil
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config for invalid values",
+	Long: `Check ~/.conductor/conductor.json and every registered project's
+conductor.json for values conductor can't use: unknown enum values, invalid
+port ranges, malformed durations and the like.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := store.Load()
+		if err != nil {
+			return err
+		}
+		defer closeStore(s)
+
+		cfg := s.GetConfigSnapshot()
+		count := 0
+		report := func(file string, problems []string) {
+			if len(problems) == 0 {
+				return
+			}
+			fmt.Println(file)
+			for _, problem := range problems {
+				fmt.Printf("  ✗ %s\n", problem)
+			}
+			count += len(problems)
+		}
+
+		path, _ := config.ConfigPath()
+		report(path, cfg.Validate())
+
+		names := make([]string, 0, len(cfg.Projects))
+		for name := range cfg.Projects {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			projectPath := cfg.Projects[name].Path
+			projCfg, err := config.LoadProjectConfig(projectPath)
+			if err != nil {
+				report(filepath.Join(projectPath, "conductor.json"), []string{err.Error()})
+				continue
+			}
+			if projCfg != nil {
+				report(filepath.Join(projectPath, "conductor.json"), projCfg.Validate())
+			}
+		}
+
+		if count > 0 {
+			return fmt.Errorf("%d problem(s) found", count)
+		}
+		fmt.Println("✓ Config is valid")
+		return nil
+	},
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade config files to the current schema version",
+	Long: `Upgrade ~/.conductor/conductor.json and each registered project's
+conductor.json to the schema version of this conductor build.
+
+The global config is also migrated automatically when conductor starts;
+project files are only rewritten by this command since they are usually
+committed. A copy of every file is kept in ~/.conductor/backups as
+<name>.v<old-version> before it is changed.
+
+Use --dry-run to print the migrations and the resulting changes without
+writing anything.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Read the raw file: config.Load would migrate it before we could show anything
+		path, err := config.ConfigPath()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("conductor not initialized. Run 'conductor init' first")
+			}
+			return fmt.Errorf("failed to read config: %w", err)
+		}
+
+		migrated, applied, err := config.MigrateConfigData(data)
+		if err != nil {
+			return err
+		}
+		pending := printMigrationPlan(path, data, migrated, applied)
+
+		cfg, err := config.Parse(migrated)
+		if err != nil {
+			return err
+		}
+		projectNames := make([]string, 0, len(cfg.Projects))
+		for name := range cfg.Projects {
+			projectNames = append(projectNames, name)
+		}
+		sort.Strings(projectNames)
+
+		for _, name := range projectNames {
+			projectPath := filepath.Join(cfg.Projects[name].Path, "conductor.json")
+			data, err := os.ReadFile(projectPath)
+			if err != nil {
+				if !os.IsNotExist(err) {
+					fmt.Printf("⚠ %s: %v\n", projectPath, err)
+				}
+				continue
+			}
+			migrated, applied, err := config.MigrateProjectConfigData(data)
+			if err != nil {
+				fmt.Printf("⚠ %s: %v\n", projectPath, err)
+				continue
+			}
+			if printMigrationPlan(projectPath, data, migrated, applied) {
+				pending = true
+			}
+		}
+
+		if !pending {
+			fmt.Println("All config files are up to date.")
+			return nil
+		}
+		if configMigrateDryRun {
+			fmt.Println("Dry run: no files were changed.")
+			return nil
+		}
+
+		if _, err := config.MigrateConfigFile(); err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", path, err)
+		}
+		for _, name := range projectNames {
+			if _, err := config.MigrateProjectConfigFile(name, cfg.Projects[name].Path); err != nil {
+				return fmt.Errorf("failed to migrate project '%s': %w", name, err)
+			}
+		}
+
+		backupDir, _ := config.BackupDir()
+		fmt.Printf("✓ Migrated. Previous versions were saved in %s\n", backupDir)
+		return nil
+	},
+}
+
+func init() {
+	configMigrateCmd.Flags().BoolVar(&configMigrateDryRun, "dry-run", false, "Show what would change without writing")
+	for _, c := range []*cobra.Command{configListCmd, configGetCmd, configSetCmd, configUnsetCmd} {
+		c.Flags().BoolVarP(&configProject, "project", "p", false, "Use the current project's conductor.json instead of the global config")
+	}
+
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configMigrateCmd)
+}
+
+// configTarget is the file config get/set/unset/list operate on: the global
+// config (through the store) or, with --project, a project's conductor.json
+type configTarget struct {
+	store       *store.Store
+	global      *config.Config
+	projectPath string
+	project     *config.ProjectConfig
+}
+
+func loadConfigTarget() (*configTarget, error) {
+	s, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	target := &configTarget{store: s}
+
+	if !configProject {
+		target.global = s.GetConfigSnapshot()
+		return target, nil
+	}
+
+	projectName, err := resolvePortsProject(s, "")
+	if err != nil {
+		_, _ = s.Close()
+		return nil, err
+	}
+	project, _ := s.GetProject(projectName)
+	target.projectPath = project.Path
+	if target.project, err = config.LoadProjectConfig(project.Path); err != nil {
+		_, _ = s.Close()
+		return nil, err
+	}
+	if target.project == nil {
+		target.project = &config.ProjectConfig{}
+	}
+	return target, nil
+}
+
+func (t *configTarget) cfg() any {
+	if t.project != nil {
+		return t.project
+	}
+	return t.global
+}
+
+func (t *configTarget) set(path, value string) error {
+	if t.project == nil {
+		if err := t.store.SetSetting(path, value); err != nil {
+			return err
+		}
+		t.global = t.store.GetConfigSnapshot()
+		return nil
+	}
+	if err := config.SetSetting(t.project, path, value); err != nil {
+		return err
+	}
+	return t.saveProject()
+}
+
+func (t *configTarget) unset(path string) error {
+	if t.project == nil {
+		if err := t.store.UnsetSetting(path); err != nil {
+			return err
+		}
+		t.global = t.store.GetConfigSnapshot()
+		return nil
+	}
+	if err := config.UnsetSetting(t.project, path); err != nil {
+		return err
+	}
+	return t.saveProject()
+}
+
+func (t *configTarget) saveProject() error {
+	if problems := t.project.Validate(); len(problems) > 0 {
+		return fmt.Errorf("invalid setting: %s", problems[0])
+	}
+	return config.SaveProjectConfig(t.projectPath, t.project)
+}
+
+func (t *configTarget) close() {
+	_, _ = t.store.Close()
+}
+
+// settingsUnder returns the settings at or below path
+func settingsUnder(cfg any, path string) []config.Setting {
+	var settings []config.Setting
+	for _, setting := range config.ListSettings(cfg) {
+		if setting.Path == path || strings.HasPrefix(setting.Path, path+".") {
+			settings = append(settings, setting)
+		}
+	}
+	return settings
+}
+
+// printSettings prints settings as aligned "path = value" lines, with prefix trimmed
+func printSettings(settings []config.Setting, prefix string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	for _, setting := range settings {
+		_, _ = fmt.Fprintf(w, "%s\t= %s\n", strings.TrimPrefix(setting.Path, prefix), formatSetting(setting))
+	}
+	_ = w.Flush()
+}
+
+// formatSetting renders a setting value for display, masking secrets
+func formatSetting(setting config.Setting) string {
+	var value string
+	switch v := setting.Value.(type) {
+	case string:
+		value = v
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		value = v.Format(time.RFC3339)
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map || rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Struct {
+			// Composite values (e.g. "config get projects") hold credentials of
+			// their own, so mask them leaf by leaf
+			var decoded any
+			data, _ := json.Marshal(v)
+			if json.Unmarshal(data, &decoded) == nil {
+				data, _ = json.Marshal(maskSecrets(setting.Path, decoded))
+			}
+			value = string(data)
+		} else {
+			value = fmt.Sprint(v)
+		}
+	}
+
+	if needsMasking(setting.Secret, value) {
+		return maskSecret(value)
+	}
+	return value
+}
+
+// maskSecrets masks every credential in a decoded JSON value rooted at path
+func maskSecrets(path string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			v[k] = maskSecrets(path+"."+k, child)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = maskSecrets(path, child)
+		}
+	case string:
+		if needsMasking(config.IsSecretSetting(path), v) {
+			return maskSecret(v)
+		}
+	}
+	return v
+}
+
+// needsMasking reports whether a displayed value is a credential: a secret
+// setting or any URL carrying a password. Secret references are only pointers
+// and are shown as they are.
+func needsMasking(secret bool, value string) bool {
+	if value == "" || secrets.IsReference(value) {
+		return false
+	}
+	return secret || hasURLPassword(value)
+}
+
+// hasURLPassword reports whether value is a URL with a password in it
+func hasURLPassword(value string) bool {
+	if !strings.Contains(value, "://") {
+		return false
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return false
+	}
+	_, ok := u.User.Password()
+	return ok
+}
+
+// maskSecret hides a credential, keeping enough to recognize it
+func maskSecret(value string) string {
+	if strings.Contains(value, "://") {
+		return database.MaskConnectionString(value)
+	}
+	if len(value) >= 12 {
+		return "****" + value[len(value)-4:]
+	}
+	return "****"
+}
+
+// printMigrationPlan prints the migrations pending for one file and the line
+// diff they produce. Returns false if the file is already up to date.
+func printMigrationPlan(path string, before, after []byte, applied []config.Migration) bool {
+	if len(applied) == 0 {
+		return false
+	}
+
+	fmt.Printf("%s\n", path)
+	for _, m := range applied {
+		fmt.Printf("  → v%d: %s\n", m.Version, m.Description)
+	}
+	fmt.Println()
+	for _, line := range diffLines(string(before), string(after)) {
+		fmt.Printf("  %s\n", line)
+	}
+	fmt.Println()
+	return true
+}
+
+// diffLines returns the lines removed ("- ") and added ("+ ") between a and b,
+// in order, based on their longest common subsequence
+func diffLines(a, b string) []string {
+	x := strings.Split(strings.TrimRight(a, "\n"), "\n")
+	y := strings.Split(strings.TrimRight(b, "\n"), "\n")
+
+	// lcs[i][j] is the LCS length of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			out = append(out, "- "+x[i])
+			i++
+		default:
+			out = append(out, "+ "+y[j])
+			j++
+		}
+	}
+	return out
+}
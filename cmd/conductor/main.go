@@ -47,6 +47,8 @@ func init() {
 	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(migrateCmd)
 	rootCmd.AddCommand(agentCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(secretsCmd)
 }
 
 var versionCmd = &cobra.Command{
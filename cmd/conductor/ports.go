@@ -6,6 +6,7 @@ import (
 	"text/tabwriter"
 
 	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/portprobe"
 	"github.com/hammashamzah/conductor/internal/store"
 	"github.com/spf13/cobra"
 )
@@ -16,17 +17,28 @@ var portsCmd = &cobra.Command{
 	Long:  "List and manage port allocations across all projects",
 }
 
-var portsListProject string
+var (
+	portsListProject    string
+	portsDoctorFix      bool
+	portsReserveProject string
+	portsReserveClear   bool
+	portsStickyProject  string
+)
 
 var portsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all allocated ports",
+	Long: `List all allocated ports.
+
+Each allocated port is probed on the host. Ports that are bound by a process
+not running from the owning worktree are flagged in the HELD BY column with
+the process ID and command.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		s, err := store.Load()
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		portInfo := s.GetAllPortInfo()
 		if len(portInfo) == 0 {
@@ -48,22 +60,56 @@ var portsListCmd = &cobra.Command{
 		fmt.Printf("Allocated ports: %d\n\n", len(portInfo))
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		_, _ = fmt.Fprintln(w, "PORT\tPROJECT\tWORKTREE\tINDEX\tLABEL")
-		_, _ = fmt.Fprintln(w, "----\t-------\t--------\t-----\t-----")
+		_, _ = fmt.Fprintln(w, "PORT\tPROJECT\tWORKTREE\tINDEX\tLABEL\tHELD BY")
+		_, _ = fmt.Fprintln(w, "----\t-------\t--------\t-----\t-----\t-------")
 
+		foreign := 0
 		for _, p := range portInfo {
 			label := p.Label
 			if label == "" {
 				label = "-"
 			}
-			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\n", p.Port, p.Project, p.Worktree, p.Index, label)
+			heldBy := "-"
+			if holder := foreignHolder(s, p); holder != "" {
+				heldBy = holder
+				foreign++
+			}
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%s\n", p.Port, p.Project, p.Worktree, p.Index, label, heldBy)
 		}
 		_ = w.Flush()
 
+		if foreign > 0 {
+			fmt.Printf("\n%d allocated port(s) are held by processes outside their worktree.\n", foreign)
+		}
+
 		return nil
 	},
 }
 
+// foreignHolder returns a description of the process listening on an allocated
+// port when that process is not running from the port's own worktree, or "" if
+// the port is free or used by its worktree
+func foreignHolder(s *store.Store, p config.PortInfo) string {
+	if !portprobe.InUse(p.Port) {
+		return ""
+	}
+	owner, err := portprobe.Owner(p.Port)
+	if err != nil || owner == nil {
+		return "! in use (owner unknown)"
+	}
+
+	dir := ""
+	if wt, ok := s.GetWorktree(p.Project, p.Worktree); ok {
+		dir = wt.Path
+	} else if proj, ok := s.GetProject(p.Project); ok {
+		dir = proj.Path
+	}
+	if owner.RunsIn(dir) {
+		return ""
+	}
+	return "! " + owner.String()
+}
+
 var portsFreeCmd = &cobra.Command{
 	Use:   "free <port>",
 	Short: "Manually free a port (use with caution)",
@@ -73,7 +119,7 @@ var portsFreeCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		portStr := args[0]
 		portAllocations := s.GetAllPortAllocations()
@@ -113,6 +159,8 @@ var portsFreeCmd = &cobra.Command{
 			if err := s.SetWorktreePorts(alloc.Project, alloc.Worktree, newPorts); err != nil {
 				// Worktree might not exist anymore, that's ok
 				_ = err
+			} else {
+				refreshEnvFile(s, alloc.Project, alloc.Worktree)
 			}
 		}
 
@@ -121,9 +169,189 @@ var portsFreeCmd = &cobra.Command{
 	},
 }
 
+var portsDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check port allocations for inconsistencies",
+	Long: `Cross-check the port allocation table against each worktree's ports and the
+worktrees on disk, and report:
+
+  orphaned          allocations for worktrees that no longer exist
+  missing-worktree  worktrees whose directory was removed outside conductor
+  duplicate-index   two ports allocated with the same index to one worktree
+  mismatch          worktree ports and allocations that disagree
+  conflict          ports listed by more than one worktree
+  out-of-range      ports outside defaults.portRangeStart-portRangeEnd
+
+With --fix the allocation table is rebuilt from the worktrees' own port lists
+in a single transaction. Worktrees whose directory is gone are marked archived,
+and worktrees that lost ports to a conflict or range change get a new block
+(rerun their setup to update .env).`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := store.Load()
+		if err != nil {
+			return err
+		}
+		defer closeStore(s)
+
+		issues := s.DiagnosePorts()
+		if len(issues) == 0 {
+			fmt.Println("No port allocation issues found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "ISSUE\tPORT\tPROJECT\tWORKTREE\tDETAIL")
+		_, _ = fmt.Fprintln(w, "-----\t----\t-------\t--------\t------")
+		for _, issue := range issues {
+			port := "-"
+			if issue.Port != 0 {
+				port = fmt.Sprintf("%d", issue.Port)
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", issue.Kind, port, issue.Project, issue.Worktree, issue.Detail)
+		}
+		_ = w.Flush()
+
+		if !portsDoctorFix {
+			fmt.Printf("\nFound %d issue(s). Run 'conductor ports doctor --fix' to repair.\n", len(issues))
+			return nil
+		}
+
+		changes, err := s.RepairPorts()
+		if err != nil {
+			return fmt.Errorf("repair failed, no changes were made: %w", err)
+		}
+		fmt.Println()
+		for _, change := range changes {
+			fmt.Printf("Fixed: %s\n", change)
+		}
+		for projectName, project := range s.GetConfigSnapshot().Projects {
+			for wtName := range project.Worktrees {
+				refreshEnvFile(s, projectName, wtName)
+			}
+		}
+		fmt.Printf("\nRepaired %d issue(s) with %d change(s).\n", len(issues), len(changes))
+		return nil
+	},
+}
+
+var portsReserveCmd = &cobra.Command{
+	Use:   "reserve [start-end]",
+	Short: "Reserve a port sub-range for a project",
+	Long: `Reserve a port sub-range for a project so its port numbers are predictable.
+
+The project's worktrees allocate only from the reserved range and other projects
+no longer allocate inside it. Ranges of different projects may not overlap.
+Existing allocations are not moved; run 'conductor ports doctor --fix' for that.
+
+Example:
+  conductor ports reserve 3200-3299
+  conductor ports reserve --clear`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !portsReserveClear {
+			return fmt.Errorf("specify a range like 3200-3299, or --clear")
+		}
+
+		s, err := store.Load()
+		if err != nil {
+			return err
+		}
+		defer closeStore(s)
+
+		projectName, err := resolvePortsProject(s, portsReserveProject)
+		if err != nil {
+			return err
+		}
+
+		if portsReserveClear {
+			if err := s.SetProjectPortRange(projectName, nil); err != nil {
+				return err
+			}
+			fmt.Printf("Cleared reserved port range for '%s'\n", projectName)
+			return nil
+		}
+
+		var r config.PortRange
+		if _, err := fmt.Sscanf(args[0], "%d-%d", &r.Start, &r.End); err != nil {
+			return fmt.Errorf("invalid range '%s': expected start-end, e.g. 3200-3299", args[0])
+		}
+		if err := s.SetProjectPortRange(projectName, &r); err != nil {
+			return err
+		}
+		fmt.Printf("Reserved ports %d-%d for '%s'\n", r.Start, r.End, projectName)
+		return nil
+	},
+}
+
+var portsStickyCmd = &cobra.Command{
+	Use:   "sticky <on|off>",
+	Short: "Reuse a branch's previous ports when its worktree is recreated",
+	Long: `Turn sticky port allocation on or off for a project.
+
+With sticky ports, conductor remembers the port block last handed to each branch
+and gives it back when a worktree for that branch is created or restored again,
+as long as the ports are still free. This keeps OAuth redirect URIs and
+bookmarks working across worktree recreations.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"on", "off"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var sticky bool
+		switch args[0] {
+		case "on":
+			sticky = true
+		case "off":
+		default:
+			return fmt.Errorf("expected 'on' or 'off', got '%s'", args[0])
+		}
+
+		s, err := store.Load()
+		if err != nil {
+			return err
+		}
+		defer closeStore(s)
+
+		projectName, err := resolvePortsProject(s, portsStickyProject)
+		if err != nil {
+			return err
+		}
+		if err := s.SetProjectStickyPorts(projectName, sticky); err != nil {
+			return err
+		}
+		fmt.Printf("Sticky ports %s for '%s'\n", args[0], projectName)
+		return nil
+	},
+}
+
+// resolvePortsProject returns the named project, or the project containing the current directory
+func resolvePortsProject(s *store.Store, name string) (string, error) {
+	if name != "" {
+		if !s.ProjectExists(name) {
+			return "", fmt.Errorf("project '%s' not found", name)
+		}
+		return name, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	projectName, _, _, err := s.GetConfigSnapshot().DetectProject(cwd)
+	if err != nil {
+		return "", fmt.Errorf("not in a registered project (use --project)")
+	}
+	return projectName, nil
+}
+
 func init() {
 	portsListCmd.Flags().StringVarP(&portsListProject, "project", "p", "", "Filter by project name")
+	portsDoctorCmd.Flags().BoolVar(&portsDoctorFix, "fix", false, "Repair the issues found")
+	portsReserveCmd.Flags().StringVarP(&portsReserveProject, "project", "p", "", "Project name (default: current project)")
+	portsReserveCmd.Flags().BoolVar(&portsReserveClear, "clear", false, "Remove the project's reserved range")
+	portsStickyCmd.Flags().StringVarP(&portsStickyProject, "project", "p", "", "Project name (default: current project)")
 
 	portsCmd.AddCommand(portsListCmd)
 	portsCmd.AddCommand(portsFreeCmd)
+	portsCmd.AddCommand(portsDoctorCmd)
+	portsCmd.AddCommand(portsReserveCmd)
+	portsCmd.AddCommand(portsStickyCmd)
 }
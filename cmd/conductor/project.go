@@ -51,7 +51,7 @@ This command will:
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		path := "."
 		if len(args) > 0 {
@@ -138,7 +138,7 @@ var projectRemoveCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		name := args[0]
 
@@ -164,7 +164,7 @@ var projectListCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		projects := s.GetAllProjects()
 		if len(projects) == 0 {
@@ -296,7 +296,7 @@ var projectShowCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		name := args[0]
 		project, ok := s.GetProject(name)
@@ -32,7 +32,7 @@ If no project name is given, detects from the current directory.`,
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		// Resolve project
 		var projectName string
@@ -66,7 +66,10 @@ var statusCmd = &cobra.Command{
 
 		// Show environment variables
 		projCfg, _ := config.LoadProjectConfig(project.Path)
-		envMap := runner.GetEnvMap(projectName, project, wtName, worktree, projCfg)
+		envMap, err := runner.GetEnvMap(projectName, project, wtName, worktree, projCfg)
+		if err != nil {
+			return err
+		}
 
 		fmt.Println("\nEnvironment Variables:")
 		for k, v := range envMap {
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hammashamzah/conductor/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Manage secrets referenced from conductor.json",
+	Long: `Store credentials outside conductor.json and reference them instead.
+
+Any credential setting (ClickUp API token, database connection strings)
+accepts a reference that is resolved each time the value is used:
+
+  secret://<name>  a secret stored with 'conductor secrets set <name>'
+  env://<VAR>      the value of an environment variable
+  file://<path>    the contents of a file
+
+Secrets are kept in ~/.conductor/secrets.enc, encrypted with a key from
+~/.conductor/secrets.key (or the CONDUCTOR_SECRETS_KEY passphrase).
+
+Example:
+  conductor secrets set prod-db
+  conductor database set-source secret://prod-db`,
+}
+
+var secretsSetCmd = &cobra.Command{
+	Use:   "set <name> [value]",
+	Short: "Store a secret",
+	Long: `Store a secret under a name. The value is read from standard input when
+it is not given as an argument, which keeps it out of your shell history.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := secrets.ValidateName(name); err != nil {
+			return err
+		}
+
+		var value string
+		if len(args) == 2 {
+			value = args[1]
+		} else {
+			fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				return fmt.Errorf("failed to read value: %w", err)
+			}
+			value = strings.TrimRight(line, "\r\n")
+		}
+		if value == "" {
+			return fmt.Errorf("secret value cannot be empty")
+		}
+
+		backend, err := secrets.Default()
+		if err != nil {
+			return err
+		}
+		if err := backend.Set(name, value); err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Stored secret '%s'. Reference it as %s\n", name, secrets.Ref(name))
+		return nil
+	},
+}
+
+var secretsGetCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print a secret",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backend, err := secrets.Default()
+		if err != nil {
+			return err
+		}
+		value, err := backend.Get(args[0])
+		if errors.Is(err, secrets.ErrNotFound) {
+			return fmt.Errorf("secret '%s' not found", args[0])
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var secretsRmCmd = &cobra.Command{
+	Use:     "rm <name>",
+	Aliases: []string{"remove"},
+	Short:   "Delete a secret",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backend, err := secrets.Default()
+		if err != nil {
+			return err
+		}
+		if err := backend.Delete(args[0]); err != nil {
+			if errors.Is(err, secrets.ErrNotFound) {
+				return fmt.Errorf("secret '%s' not found", args[0])
+			}
+			return err
+		}
+
+		fmt.Printf("✓ Deleted secret '%s'\n", args[0])
+		return nil
+	},
+}
+
+var secretsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stored secret names",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backend, err := secrets.Default()
+		if err != nil {
+			return err
+		}
+		names, err := backend.List()
+		if err != nil {
+			return err
+		}
+
+		if len(names) == 0 {
+			fmt.Println("No secrets stored.")
+			return nil
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	secretsCmd.AddCommand(secretsSetCmd)
+	secretsCmd.AddCommand(secretsGetCmd)
+	secretsCmd.AddCommand(secretsRmCmd)
+	secretsCmd.AddCommand(secretsListCmd)
+}
@@ -80,6 +80,10 @@ func runTUIDirectly() {
 	m.StartSessionTracker(p)
 	defer m.StopSessionTracker()
 
+	// Run scheduled database syncs while the TUI is open
+	m.StartSyncScheduler()
+	defer m.StopSyncScheduler()
+
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
 		os.Exit(1)
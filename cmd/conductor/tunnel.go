@@ -32,7 +32,7 @@ var tunnelStartCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		cfg := s.GetConfigSnapshot()
 
@@ -81,6 +81,7 @@ var tunnelStartCmd = &cobra.Command{
 		if err := s.SetTunnelState(projectName, wtName, state); err != nil {
 			return fmt.Errorf("failed to update tunnel state: %w", err)
 		}
+		refreshEnvFile(s, projectName, wtName)
 
 		fmt.Printf("Tunnel started for %s\n", wtName)
 		fmt.Printf("  URL: %s\n", state.URL)
@@ -102,7 +103,7 @@ var tunnelStopCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		cfg := s.GetConfigSnapshot()
 
@@ -132,6 +133,7 @@ var tunnelStopCmd = &cobra.Command{
 		if err := s.ClearTunnelState(projectName, wtName); err != nil {
 			return fmt.Errorf("failed to clear tunnel state: %w", err)
 		}
+		refreshEnvFile(s, projectName, wtName)
 
 		fmt.Printf("Tunnel stopped for %s\n", wtName)
 		return nil
@@ -147,7 +149,7 @@ var tunnelListCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		cfg := s.GetConfigSnapshot()
 
@@ -199,7 +201,7 @@ var tunnelStatusCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		cfg := s.GetConfigSnapshot()
 
@@ -254,7 +256,7 @@ var tunnelLogsCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		cfg := s.GetConfigSnapshot()
 
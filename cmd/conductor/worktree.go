@@ -34,7 +34,7 @@ var worktreeCreateCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		// Detect current project
 		cwd, err := os.Getwd()
@@ -82,7 +82,7 @@ var worktreeListCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		cfg := s.GetConfigSnapshot()
 
@@ -153,7 +153,7 @@ var worktreeOpenCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		cfg := s.GetConfigSnapshot()
 
@@ -224,17 +224,29 @@ var worktreeOpenCmd = &cobra.Command{
 	},
 }
 
+var (
+	worktreeArchiveStash bool
+	worktreeArchivePush  bool
+	worktreeArchiveForce bool
+)
+
 var worktreeArchiveCmd = &cobra.Command{
 	Use:   "archive <name>",
 	Short: "Archive a worktree",
-	Long:  "Remove a worktree and free its allocated ports",
-	Args:  cobra.ExactArgs(1),
+	Long: `Remove a worktree and free its allocated ports.
+
+Before archiving, the worktree is checked for uncommitted changes and for
+commits that are not on any remote. By default (defaults.archiveSafety =
+"block") such work blocks the archive. Use --stash to preserve it in a local
+conductor/archive/<name> branch, --push to also push that branch to origin,
+or --force to discard it.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		s, err := store.Load()
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		// Detect current project
 		cwd, err := os.Getwd()
@@ -244,7 +256,101 @@ var worktreeArchiveCmd = &cobra.Command{
 
 		name := args[0]
 
+		snap := s.GetConfigSnapshot()
+		projectName, _, _, err := snap.DetectProject(cwd)
+		if err != nil {
+			return fmt.Errorf("not in a registered project")
+		}
+
+		opts := workspace.ArchiveOptions{Safety: snap.Defaults.ArchiveSafety, Force: worktreeArchiveForce}
+		switch {
+		case worktreeArchivePush:
+			opts.Safety = config.ArchiveSafetyPush
+		case worktreeArchiveStash:
+			opts.Safety = config.ArchiveSafetyStash
+		}
+
+		// Show what would be lost before doing anything destructive
+		risk, riskErr := workspace.NewManager(snap).CheckArchiveSafety(projectName, name)
+		if riskErr == nil && risk.HasRisk() {
+			printArchiveRisk(name, risk)
+		}
+		if riskErr == nil && len(risk.ModifiedFiles) > 0 {
+			fmt.Printf("Worktree '%s' changed files copied from the project root; archiving discards them:\n", name)
+			for _, f := range risk.ModifiedFiles {
+				fmt.Printf("    %s\n", f)
+			}
+			fmt.Println()
+		}
+
 		// Use BatchMutate to wrap manager operations since manager still uses config directly
+		var backupBranch string
+		err = s.BatchMutate(func(cfg *config.Config) error {
+			mgr := workspace.NewManager(cfg)
+			if err := mgr.ArchiveWorktreeWithOptions(projectName, name, opts); err != nil {
+				return err
+			}
+			if wt, ok := cfg.Projects[projectName].Worktrees[name]; ok && wt.Snapshot != nil {
+				backupBranch = wt.Snapshot.BackupBranch
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if backupBranch != "" {
+			fmt.Printf("Preserved unsaved work in branch '%s'\n", backupBranch)
+		}
+		fmt.Printf("Archived worktree '%s'\n", name)
+		return nil
+	},
+}
+
+// printArchiveRisk lists the uncommitted files and unpushed commits of a worktree
+func printArchiveRisk(name string, risk *workspace.ArchiveRisk) {
+	fmt.Printf("Worktree '%s' has %s:\n", name, risk.Summary())
+	if len(risk.Uncommitted) > 0 {
+		fmt.Println("\n  Uncommitted files:")
+		for _, line := range risk.Uncommitted {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	if len(risk.Unpushed) > 0 {
+		fmt.Println("\n  Unpushed commits:")
+		for _, line := range risk.Unpushed {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	fmt.Println()
+}
+
+var worktreeRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Restore an archived worktree",
+	Long: `Recreate an archived worktree from the snapshot taken when it was archived.
+
+The branch is recreated at the commit it had at archive time, uncommitted work
+preserved in conductor/archive/<name> is put back, fresh ports are allocated and
+the database dump (if one was taken) is restored before the setup script runs.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := store.Load()
+		if err != nil {
+			return err
+		}
+		defer closeStore(s)
+
+		// Detect current project
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+
+		name := args[0]
+
+		// Use BatchMutate to wrap manager operations since manager still uses config directly
+		var result *workspace.RestoreResult
 		err = s.BatchMutate(func(cfg *config.Config) error {
 			projectName, _, _, detectErr := cfg.DetectProject(cwd)
 			if detectErr != nil {
@@ -252,14 +358,49 @@ var worktreeArchiveCmd = &cobra.Command{
 			}
 
 			mgr := workspace.NewManager(cfg)
-			return mgr.ArchiveWorktree(projectName, name)
+			var restoreErr error
+			result, restoreErr = mgr.RestoreWorktree(projectName, name)
+			return restoreErr
 		})
-		if err != nil {
+		if result == nil {
 			return err
 		}
 
-		fmt.Printf("Archived worktree '%s'\n", name)
-		return nil
+		wt := result.Worktree
+		fmt.Printf("Restored worktree '%s'\n", name)
+		fmt.Printf("  Path: %s\n", wt.Path)
+		fmt.Printf("  Branch: %s\n", wt.Branch)
+		fmt.Printf("  Ports: %v", wt.Ports)
+		if len(result.PreviousPorts) > 0 {
+			fmt.Printf(" (was %v)", result.PreviousPorts)
+		}
+		fmt.Println()
+		if len(result.PortLabels) > 0 {
+			labels := make([]string, 0, len(result.PortLabels))
+			for label := range result.PortLabels {
+				labels = append(labels, label)
+			}
+			sort.Strings(labels)
+			for _, label := range labels {
+				old := result.PortLabels[label]
+				for i, p := range result.PreviousPorts {
+					if p == old && i < len(wt.Ports) {
+						fmt.Printf("    %s: %d (was %d)\n", label, wt.Ports[i], old)
+					}
+				}
+			}
+		}
+		if result.ReappliedWork {
+			fmt.Println("  Reapplied uncommitted work from the archive backup branch")
+		}
+		switch {
+		case result.DatabaseRestored:
+			fmt.Printf("  Database: %s (restored from archive dump)\n", wt.DatabaseName)
+		case result.DatabaseError != nil:
+			fmt.Printf("  Database: could not restore archive dump: %v\n", result.DatabaseError)
+		}
+
+		return err
 	},
 }
 
@@ -273,7 +414,7 @@ var worktreeStatusCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		defer func() { _, _ = s.Close() }()
+		defer closeStore(s)
 
 		cfg := s.GetConfigSnapshot()
 
@@ -330,6 +471,89 @@ var worktreeStatusCmd = &cobra.Command{
 	},
 }
 
+var worktreeEnvCmd = &cobra.Command{
+	Use:   "env [name]",
+	Short: "Regenerate a worktree's env file",
+	Long: `Render the envFile block configured in the project's conductor.json into
+the worktree (default .env.conductor), for processes started outside conductor.
+
+The file is also regenerated automatically when the worktree's ports, tunnel
+URL or database URL change. Lines outside conductor's marked block are kept.
+
+Example conductor.json:
+  "envFile": {
+    "path": ".env.local",
+    "template": {
+      "API_URL": "http://localhost:${CONDUCTOR_PORT_API}",
+      "DATABASE_URL": "${DATABASE_URL}"
+    }
+  }`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := store.Load()
+		if err != nil {
+			return err
+		}
+		defer closeStore(s)
+
+		cfg := s.GetConfigSnapshot()
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		projectName, project, currentWt, err := cfg.DetectProject(cwd)
+		if err != nil {
+			return fmt.Errorf("not in a registered project")
+		}
+
+		var wtName string
+		if len(args) > 0 {
+			wtName = args[0]
+			if project.Worktrees[wtName] == nil {
+				return fmt.Errorf("worktree '%s' not found", wtName)
+			}
+		} else {
+			for name, w := range project.Worktrees {
+				if w == currentWt {
+					wtName = name
+					break
+				}
+			}
+		}
+
+		path, err := workspace.RefreshEnvFile(s, projectName, wtName)
+		if err != nil {
+			return err
+		}
+		if path == "" {
+			return fmt.Errorf("no envFile configured in %s's conductor.json", projectName)
+		}
+
+		fmt.Printf("✓ Wrote %s\n", path)
+		return nil
+	},
+}
+
+// refreshEnvFile regenerates a worktree's env file, warning instead of failing
+// since the state change that triggered it has already been saved
+func refreshEnvFile(s *store.Store, projectName, worktreeName string) {
+	if _, err := workspace.RefreshEnvFile(s, projectName, worktreeName); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update env file for %s: %v\n", worktreeName, err)
+	}
+}
+
+// closeStore flushes the store, then refreshes and reports any worktree its
+// saves moved to new ports because another conductor process took them first
+func closeStore(s *store.Store) {
+	_, _ = s.Close()
+	for _, moved := range s.TakePortReallocations() {
+		fmt.Fprintf(os.Stderr, "Note: another conductor process took ports %s first; %s/%s moved to %s\n",
+			formatPortRange(moved.OldPorts), moved.Project, moved.Worktree, formatPortRange(moved.NewPorts))
+		refreshEnvFile(s, moved.Project, moved.Worktree)
+	}
+}
+
 func formatPortRange(ports []int) string {
 	if len(ports) == 0 {
 		return "-"
@@ -352,9 +576,15 @@ func init() {
 	worktreeOpenCmd.Flags().BoolVar(&worktreeOpenDev, "dev", false, "Start the project dev server with conductor run in Herdr")
 	worktreeOpenCmd.Flags().StringVarP(&worktreeOpenPrompt, "prompt", "p", "", "Run Claude Code once with this prompt in Herdr (non-interactive)")
 
+	worktreeArchiveCmd.Flags().BoolVar(&worktreeArchiveStash, "stash", false, "Preserve unsaved work in a local conductor/archive/<name> branch")
+	worktreeArchiveCmd.Flags().BoolVar(&worktreeArchivePush, "push", false, "Preserve unsaved work and push the backup branch to origin")
+	worktreeArchiveCmd.Flags().BoolVarP(&worktreeArchiveForce, "force", "f", false, "Archive even if uncommitted or unpushed work would be lost")
+
 	worktreeCmd.AddCommand(worktreeCreateCmd)
 	worktreeCmd.AddCommand(worktreeListCmd)
 	worktreeCmd.AddCommand(worktreeOpenCmd)
 	worktreeCmd.AddCommand(worktreeArchiveCmd)
+	worktreeCmd.AddCommand(worktreeRestoreCmd)
 	worktreeCmd.AddCommand(worktreeStatusCmd)
+	worktreeCmd.AddCommand(worktreeEnvCmd)
 }
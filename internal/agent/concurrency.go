@@ -0,0 +1,283 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/mux"
+	"github.com/hammashamzah/conductor/internal/tracker"
+)
+
+// queueDrainInterval is how often finished agent sessions are looked for so
+// queued tasks can start. Like the sequential monitor, it polls often enough
+// to see a short-lived agent running before it exits.
+const queueDrainInterval = 5 * time.Second
+
+// maxStartAttempts is how many times a queued task is tried before the
+// dispatcher gives up on it
+const maxStartAttempts = 3
+
+// agentSlot is a parallel-mode agent session counted against maxConcurrentAgents.
+// It holds its slot from dispatch until the agent exits, its coding window is
+// closed or its worktree is archived.
+type agentSlot struct {
+	project  string
+	worktree string
+	branch   string
+	taskID   string
+	// opened is set once the coding window has been created; until then the
+	// worktree is still being set up
+	opened bool
+	// paneID is the agent's pane; unknown for sessions recovered after a restart
+	paneID    string
+	agentSeen bool // the agent has been seen running in paneID
+}
+
+func slotKey(projectName, worktreeName string) string {
+	return projectName + "/" + worktreeName
+}
+
+// RecoverSlots counts the task worktrees whose coding window is still open,
+// so agents started before a daemon restart keep their slots
+func (d *Dispatcher) RecoverSlots() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	m := mux.Current()
+	for projectName, project := range d.store.GetAllProjects() {
+		settings := projectTracker(project)
+		if settings == nil || settings.Mode == config.AgentModeSequential {
+			continue
+		}
+		for worktreeName, wt := range d.store.GetAllWorktrees(projectName) {
+			if wt.TaskID == "" || wt.Archived || !m.WindowExists(projectName, wt.Branch) {
+				continue
+			}
+			d.slots[slotKey(projectName, worktreeName)] = &agentSlot{
+				project:  projectName,
+				worktree: worktreeName,
+				branch:   wt.Branch,
+				taskID:   wt.TaskID,
+				opened:   true,
+			}
+		}
+	}
+	if len(d.slots) > 0 {
+		log.Printf("dispatcher: %d agent sessions still running", len(d.slots))
+	}
+}
+
+// RunQueue drains the queue every interval until ctx is cancelled
+func (d *Dispatcher) RunQueue(ctx context.Context, interval time.Duration) {
+	d.DrainQueue()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.DrainQueue()
+		}
+	}
+}
+
+// DrainQueue frees the slots of finished agent sessions and starts queued
+// tasks while there is room
+func (d *Dispatcher) DrainQueue() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.drainLocked()
+}
+
+func (d *Dispatcher) drainLocked() {
+	d.releaseFinishedLocked()
+
+	limits := make(map[string]int)
+	projectLimit := func(projectName string) int {
+		limit, ok := limits[projectName]
+		if !ok {
+			limit = d.projectLimit(projectName)
+			limits[projectName] = limit
+		}
+		return limit
+	}
+	globalLimit := d.store.GetDefaults().MaxConcurrentAgents
+
+	for {
+		queued := d.queue.Tasks()
+		i := nextStartable(queued, d.runningLocked(), projectLimit, globalLimit)
+		if i < 0 {
+			return
+		}
+		qt := queued[i]
+		d.queue.Remove(qt.Task.Provider, qt.Task.ID)
+		if d.worktreeExistsForTask(qt.Project, qt.Task.Provider, qt.Task.ID) {
+			continue
+		}
+		log.Printf("dispatcher: starting task %s for project %s (queued %s)", qt.Task.ID, qt.Project, time.Since(qt.QueuedAt).Round(time.Second))
+		if err := d.startParallelTask(qt.Project, &qt.Task); err != nil {
+			// Try again on a later drain rather than spinning on it now
+			d.requeueFailed(qt, err)
+			return
+		}
+	}
+}
+
+// requeueFailed puts a task that failed to start back at the front of the
+// queue, or gives up on it after maxStartAttempts and says so on the task
+func (d *Dispatcher) requeueFailed(qt QueuedTask, err error) {
+	qt.Attempts++
+	if qt.Attempts < maxStartAttempts {
+		log.Printf("dispatcher: failed to start task %s (attempt %d of %d), re-queued: %v", qt.Task.ID, qt.Attempts, maxStartAttempts, err)
+		d.queue.PushFront(qt)
+		return
+	}
+	log.Printf("dispatcher: giving up on task %s after %d attempts: %v", qt.Task.ID, qt.Attempts, err)
+	go d.notifyFailed(&qt.Task, err)
+}
+
+// releaseFinishedLocked frees the slots of agent sessions whose agent has
+// exited, whose coding window has closed or whose worktree was archived.
+// Archiving usually happens in the TUI, so the config on disk is checked as
+// well as the store. An agent has exited once its pane is back at a shell
+// prompt after the agent was seen running, as in sequential mode.
+func (d *Dispatcher) releaseFinishedLocked() {
+	if len(d.slots) == 0 {
+		return
+	}
+	onDisk, _ := config.Load()
+
+	m := mux.Current()
+	for key, slot := range d.slots {
+		reason := ""
+		if wt, ok := d.store.GetWorktree(slot.project, slot.worktree); !ok || wt.Archived {
+			reason = "worktree archived"
+		} else if archivedOnDisk(onDisk, slot.project, slot.worktree) {
+			reason = "worktree archived"
+		} else if slot.opened && !m.WindowExists(slot.project, slot.branch) {
+			reason = "window closed"
+		} else if slot.opened && slot.paneID != "" {
+			reason = agentExitReason(m, slot)
+		}
+		if reason != "" {
+			log.Printf("dispatcher: agent for task %s finished (%s), freeing its slot", slot.taskID, reason)
+			delete(d.slots, key)
+		}
+	}
+}
+
+// agentExitReason checks the agent pane, returning why the agent counts as
+// finished or "" while it may still be running
+func agentExitReason(m mux.Multiplexer, slot *agentSlot) string {
+	if !m.PaneExists(slot.paneID) {
+		return "agent pane closed"
+	}
+	if !isShellCommand(m.GetPaneCommand(slot.paneID)) {
+		slot.agentSeen = true
+		return ""
+	}
+	if !slot.agentSeen {
+		// The agent hasn't started (or been detected) yet
+		return ""
+	}
+	return "agent exited"
+}
+
+func archivedOnDisk(cfg *config.Config, projectName, worktreeName string) bool {
+	if cfg == nil {
+		return false
+	}
+	project, ok := cfg.Projects[projectName]
+	if !ok {
+		return false
+	}
+	wt, ok := project.Worktrees[worktreeName]
+	return ok && wt.Archived
+}
+
+// runningLocked counts agent sessions per project
+func (d *Dispatcher) runningLocked() map[string]int {
+	running := make(map[string]int)
+	for _, slot := range d.slots {
+		running[slot.project]++
+	}
+	return running
+}
+
+// projectLimit returns a project's maxConcurrentAgents (0 = no limit)
+func (d *Dispatcher) projectLimit(projectName string) int {
+	project, ok := d.store.GetProject(projectName)
+	if !ok {
+		return 0
+	}
+	if settings := projectTracker(project); settings != nil {
+		return settings.MaxConcurrentAgents
+	}
+	return 0
+}
+
+// limitReasonLocked describes the limit keeping a project's tasks queued
+func (d *Dispatcher) limitReasonLocked(projectName string) string {
+	running := d.runningLocked()
+	if limit := d.projectLimit(projectName); limit > 0 && running[projectName] >= limit {
+		return fmt.Sprintf("%s already has %d of %d agents running", projectName, running[projectName], limit)
+	}
+	total := 0
+	for _, n := range running {
+		total += n
+	}
+	if limit := d.store.GetDefaults().MaxConcurrentAgents; limit > 0 && total >= limit {
+		return fmt.Sprintf("%d of %d agents are already running", total, limit)
+	}
+	return "earlier tasks are waiting for an agent"
+}
+
+// markOpened records that a task's coding window is open, with the agent
+// running in paneID, so the agent exiting or the window closing frees the slot
+func (d *Dispatcher) markOpened(projectName, worktreeName, paneID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if slot := d.slots[slotKey(projectName, worktreeName)]; slot != nil {
+		slot.opened = true
+		slot.paneID = paneID
+	}
+}
+
+// releaseSlot frees a slot whose agent never started
+func (d *Dispatcher) releaseSlot(projectName, worktreeName string) {
+	d.mu.Lock()
+	delete(d.slots, slotKey(projectName, worktreeName))
+	d.mu.Unlock()
+
+	d.DrainQueue()
+}
+
+// notifyFailed tells the task its agent could not be started
+func (d *Dispatcher) notifyFailed(task *tracker.Task, startErr error) {
+	provider := d.providers[task.Provider]
+	if provider == nil {
+		return
+	}
+	comment := fmt.Sprintf("Conductor agent could not start this task: %v. Move it out of and back into the trigger status to try again.", startErr)
+	if err := provider.AddComment(task.ID, comment); err != nil {
+		log.Printf("dispatcher: failed to comment on task %s: %v", task.ID, err)
+	}
+}
+
+// notifyQueued tells the task it is waiting for an agent
+func (d *Dispatcher) notifyQueued(task *tracker.Task, position int, reason string) {
+	provider := d.providers[task.Provider]
+	if provider == nil {
+		return
+	}
+	comment := fmt.Sprintf("Queued by conductor agent: %s. This task is number %d in the queue and will start when an agent finishes.", reason, position)
+	if err := provider.AddComment(task.ID, comment); err != nil {
+		log.Printf("dispatcher: failed to comment on queued task %s: %v", task.ID, err)
+	}
+}
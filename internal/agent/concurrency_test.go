@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/hammashamzah/conductor/internal/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// paneMux reports a fixed pane state; other Multiplexer methods are unused
+type paneMux struct {
+	mux.Multiplexer
+	exists  bool
+	command string
+}
+
+func (p *paneMux) PaneExists(string) bool       { return p.exists }
+func (p *paneMux) GetPaneCommand(string) string { return p.command }
+
+func TestAgentExitReason(t *testing.T) {
+	m := &paneMux{exists: true, command: "bash"}
+	slot := &agentSlot{taskID: "t1", paneID: "%3", opened: true}
+
+	// A shell before the agent has been seen is the agent still starting
+	assert.Empty(t, agentExitReason(m, slot))
+
+	m.command = "claude"
+	assert.Empty(t, agentExitReason(m, slot))
+	assert.True(t, slot.agentSeen)
+
+	// Back at the shell prompt with the window still open: the agent exited
+	m.command = "zsh"
+	assert.Equal(t, "agent exited", agentExitReason(m, slot))
+
+	m.exists = false
+	assert.Equal(t, "agent pane closed", agentExitReason(m, slot))
+}
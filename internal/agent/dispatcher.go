@@ -4,44 +4,55 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/hammashamzah/conductor/internal/clickup"
 	"github.com/hammashamzah/conductor/internal/codingagent"
 	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/database"
 	"github.com/hammashamzah/conductor/internal/mux"
 	"github.com/hammashamzah/conductor/internal/store"
+	"github.com/hammashamzah/conductor/internal/tracker"
 	"github.com/hammashamzah/conductor/internal/workspace"
 )
 
-// Dispatcher receives ClickUp task events and maps them to conductor worktree lifecycle
+// Dispatcher receives issue-tracker task events and maps them to conductor worktree lifecycle
 type Dispatcher struct {
 	store      *store.Store
 	manager    *workspace.Manager
-	clickupMgr *clickup.Manager
 	seqHandler *SequentialHandler
+	providers  map[string]tracker.Provider
+	queue      *TaskQueue
+
+	// mu serializes starting parallel-mode tasks so the limits hold across trackers
+	mu    sync.Mutex
+	slots map[string]*agentSlot // running parallel agents by project/worktree
 }
 
-// NewDispatcher creates a new task dispatcher
-func NewDispatcher(s *store.Store, mgr *workspace.Manager, clickupMgr *clickup.Manager, seqHandler *SequentialHandler) *Dispatcher {
+// NewDispatcher creates a new task dispatcher. Parallel-mode tasks over the
+// maxConcurrentAgents limits wait in queue.
+func NewDispatcher(s *store.Store, mgr *workspace.Manager, seqHandler *SequentialHandler, providers map[string]tracker.Provider, queue *TaskQueue) *Dispatcher {
 	return &Dispatcher{
 		store:      s,
 		manager:    mgr,
-		clickupMgr: clickupMgr,
 		seqHandler: seqHandler,
+		providers:  providers,
+		queue:      queue,
+		slots:      make(map[string]*agentSlot),
 	}
 }
 
-// HandleEvent processes a ClickUp task event
-func (d *Dispatcher) HandleEvent(event clickup.TaskEvent) {
+// HandleEvent processes a task event
+func (d *Dispatcher) HandleEvent(event tracker.TaskEvent) {
 	if event.Task == nil {
 		log.Printf("dispatcher: received event with nil task for %s", event.TaskID)
 		return
 	}
 
-	// Find project by matching task list ID to project config
-	projectName, projectConfig, project, err := d.findProjectWithConfig(event.Task.List.ID)
+	// Find project by matching the task's list to project config
+	projectName, settings, project, err := d.findProjectWithConfig(event.Provider, event.Task.ListID)
 	if err != nil {
 		log.Printf("dispatcher: %v", err)
 		return
@@ -50,35 +61,66 @@ func (d *Dispatcher) HandleEvent(event clickup.TaskEvent) {
 	log.Printf("dispatcher: processing task '%s' (ID: %s) for project %s", event.Task.Name, event.TaskID, projectName)
 
 	// Route by mode
-	if projectConfig.ClickUp != nil && projectConfig.ClickUp.GetMode() == config.AgentModeSequential {
-		d.handleSequentialEvent(projectName, event, projectConfig.ClickUp, project.Path)
+	if settings.Mode == config.AgentModeSequential {
+		d.handleSequentialEvent(projectName, event, settings, project.Path)
 	} else {
 		d.handleParallelEvent(projectName, event)
 	}
 }
 
 // handleSequentialEvent processes a task in sequential mode
-func (d *Dispatcher) handleSequentialEvent(projectName string, event clickup.TaskEvent, clickupConfig *config.ProjectClickUpConfig, projectPath string) {
+func (d *Dispatcher) handleSequentialEvent(projectName string, event tracker.TaskEvent, settings *config.TrackerSettings, projectPath string) {
 	if d.seqHandler.HasActiveTask(projectName) {
 		log.Printf("dispatcher: project %s already has an active sequential task, skipping %s", projectName, event.TaskID)
 		return
 	}
 
-	if err := d.seqHandler.StartTask(projectName, event.Task, clickupConfig, projectPath); err != nil {
+	if err := d.seqHandler.StartTask(projectName, event.Task, settings, projectPath); err != nil {
 		log.Printf("dispatcher: failed to start sequential task %s: %v", event.TaskID, err)
 	}
 }
 
-// handleParallelEvent processes a task in parallel mode (existing worktree-based flow)
-func (d *Dispatcher) handleParallelEvent(projectName string, event clickup.TaskEvent) {
+// handleParallelEvent processes a task in parallel mode. The task joins the
+// back of the queue, which starts it right away if there is a free agent slot.
+func (d *Dispatcher) handleParallelEvent(projectName string, event tracker.TaskEvent) {
+	d.mu.Lock()
+
 	// Check if worktree already exists for this task
-	if d.worktreeExistsForTask(projectName, event.TaskID) {
+	if d.worktreeExistsForTask(projectName, event.Provider, event.TaskID) {
+		d.mu.Unlock()
 		log.Printf("dispatcher: worktree already exists for task %s", event.TaskID)
 		return
 	}
 
+	if !d.queue.Push(QueuedTask{Project: projectName, Task: *event.Task, QueuedAt: time.Now()}) {
+		d.mu.Unlock()
+		log.Printf("dispatcher: task %s is already queued", event.TaskID)
+		return
+	}
+	d.drainLocked()
+
+	position := d.queue.Position(event.Task.Provider, event.TaskID)
+	if d.queue.Attempts(event.Task.Provider, event.TaskID) > 0 {
+		position = 0 // It was due to start but failed; the drain logged why
+	}
+	var reason string
+	if position > 0 {
+		reason = d.limitReasonLocked(projectName)
+	}
+	d.mu.Unlock()
+
+	if position > 0 {
+		log.Printf("dispatcher: queued task %s at position %d (%s)", event.TaskID, position, reason)
+		d.notifyQueued(event.Task, position, reason)
+	}
+}
+
+// startParallelTask creates a worktree for the task and opens a coding agent
+// in it once setup finishes. It takes an agent slot; d.mu must be held. An
+// error means no worktree was created and the task can be retried.
+func (d *Dispatcher) startParallelTask(projectName string, task *tracker.Task) error {
 	// Generate branch name
-	branch := GenerateBranchName(event.TaskID, event.Task.Name)
+	branch := GenerateBranchName(task.Key, task.Name)
 
 	// Get project's default port count
 	portCount := d.store.GetProjectDefaultPorts(projectName)
@@ -86,70 +128,73 @@ func (d *Dispatcher) handleParallelEvent(projectName string, event clickup.TaskE
 	// Create worktree
 	worktreeName, wt, err := d.manager.PrepareWorktree(projectName, branch, portCount)
 	if err != nil {
-		log.Printf("dispatcher: failed to prepare worktree for task %s: %v", event.TaskID, err)
-		return
+		return fmt.Errorf("failed to prepare worktree: %w", err)
 	}
 
-	// Set ClickUp task linkage
-	taskURL := event.Task.URL
-	if taskURL == "" {
-		taskURL = fmt.Sprintf("https://app.clickup.com/t/%s", event.TaskID)
-	}
-	_ = d.store.SetWorktreeClickUpTask(projectName, worktreeName, event.TaskID, taskURL)
+	// Set task linkage
+	_ = d.store.SetWorktreeTask(projectName, worktreeName, task.Provider, task.ID, task.URL)
+
+	log.Printf("dispatcher: created worktree %s (branch: %s) for task %s", worktreeName, branch, task.ID)
 
-	log.Printf("dispatcher: created worktree %s (branch: %s) for task %s", worktreeName, branch, event.TaskID)
+	d.slots[slotKey(projectName, worktreeName)] = &agentSlot{
+		project:  projectName,
+		worktree: worktreeName,
+		branch:   wt.Branch,
+		taskID:   task.ID,
+	}
 
 	// Create git worktree async
 	err = d.manager.CreateWorktreeAsync(projectName, worktreeName, func(success bool, createErr error) {
 		if !success {
-			log.Printf("dispatcher: failed to create git worktree for task %s: %v", event.TaskID, createErr)
+			log.Printf("dispatcher: failed to create git worktree for task %s: %v", task.ID, createErr)
 			_ = d.store.SetWorktreeStatus(projectName, worktreeName, config.SetupStatusFailed)
+			d.releaseSlot(projectName, worktreeName)
+			d.notifyFailed(task, createErr)
 			return
 		}
 
 		// Run setup async
 		err := d.manager.RunSetupAsync(projectName, worktreeName, func(setupSuccess bool, setupErr error) {
 			if !setupSuccess {
-				log.Printf("dispatcher: setup failed for task %s: %v", event.TaskID, setupErr)
+				log.Printf("dispatcher: setup failed for task %s: %v", task.ID, setupErr)
 			}
 
 			// Open tmux window with claude task prompt regardless of setup result
-			d.openCodingWindow(projectName, worktreeName, wt, event.Task)
+			d.openCodingWindow(projectName, worktreeName, wt, task)
 		})
 		if err != nil {
-			log.Printf("dispatcher: failed to run setup for task %s: %v", event.TaskID, err)
+			log.Printf("dispatcher: failed to run setup for task %s: %v", task.ID, err)
 			// Still try to open the window
-			d.openCodingWindow(projectName, worktreeName, wt, event.Task)
+			d.openCodingWindow(projectName, worktreeName, wt, task)
 		}
 	})
 	if err != nil {
-		log.Printf("dispatcher: failed to create worktree async for task %s: %v", event.TaskID, err)
+		log.Printf("dispatcher: failed to create worktree async for task %s: %v", task.ID, err)
+		delete(d.slots, slotKey(projectName, worktreeName))
+		go d.notifyFailed(task, err)
 	}
+	return nil
 }
 
-// findProjectWithConfig finds a project by ClickUp list ID and returns all relevant data
-func (d *Dispatcher) findProjectWithConfig(listID string) (string, *config.ProjectConfig, *config.Project, error) {
+// findProjectWithConfig finds the project watching a tracker list and returns all relevant data
+func (d *Dispatcher) findProjectWithConfig(provider, listID string) (string, *config.TrackerSettings, *config.Project, error) {
 	projects := d.store.GetAllProjects()
 
 	for projectName, project := range projects {
-		projectConfig, err := config.LoadProjectConfig(project.Path)
-		if err != nil || projectConfig == nil {
-			continue
-		}
-
-		if projectConfig.ClickUp != nil && projectConfig.ClickUp.ListID == listID {
-			return projectName, projectConfig, project, nil
+		settings := projectTracker(project)
+		if settings != nil && settings.Provider == provider && strings.EqualFold(settings.ListID, listID) {
+			return projectName, settings, project, nil
 		}
 	}
 
-	return "", nil, nil, fmt.Errorf("no project found for ClickUp list ID %s", listID)
+	return "", nil, nil, fmt.Errorf("no project found for %s list %s", provider, listID)
 }
 
-// worktreeExistsForTask checks if a worktree already exists for a ClickUp task
-func (d *Dispatcher) worktreeExistsForTask(projectName, taskID string) bool {
+// worktreeExistsForTask checks if a worktree already exists for a task
+func (d *Dispatcher) worktreeExistsForTask(projectName, provider, taskID string) bool {
 	worktrees := d.store.GetAllWorktrees(projectName)
 	for _, wt := range worktrees {
-		if wt.ClickUpTaskID == taskID && !wt.Archived {
+		if wt.TaskProvider == provider && wt.TaskID == taskID && !wt.Archived {
 			return true
 		}
 	}
@@ -157,13 +202,15 @@ func (d *Dispatcher) worktreeExistsForTask(projectName, taskID string) bool {
 }
 
 // openCodingWindow opens a tmux window with claude pre-loaded with the task prompt
-func (d *Dispatcher) openCodingWindow(projectName, worktreeName string, wt *config.Worktree, task *clickup.Task) {
-	taskURL := fmt.Sprintf("https://app.clickup.com/t/%s", task.ID)
-	taskPrompt := BuildTaskPrompt(task.Name, task.Description, taskURL)
+func (d *Dispatcher) openCodingWindow(projectName, worktreeName string, wt *config.Worktree, task *tracker.Task) {
+	taskPrompt := BuildTaskPrompt(task.Name, task.Description, task.URL)
 
-	if err := mux.Current().CreateCodingWindowWithTask(projectName, wt.Branch, wt.Path, taskPrompt, codingagent.ClaudeCode); err != nil {
+	paneID, err := mux.Current().CreateCodingWindowWithTask(projectName, wt.Branch, wt.Path, taskPrompt, codingagent.ClaudeCode)
+	if err != nil {
 		log.Printf("dispatcher: failed to create coding window for task %s: %v", task.ID, err)
 	}
+	// Without a window the next drain frees the slot
+	d.markOpened(projectName, worktreeName, paneID)
 }
 
 // Daemon represents the full agent daemon lifecycle
@@ -171,19 +218,18 @@ type Daemon struct {
 	store      *store.Store
 	dispatcher *Dispatcher
 	watcher    *PRWatcher
-	clickupMgr *clickup.Manager
+	scheduler  *database.Scheduler
+	providers  map[string]tracker.Provider
+	started    []tracker.Provider
 	seqHandler *SequentialHandler
 
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
-// NewDaemon creates a new agent daemon
+// NewDaemon creates a new agent daemon with a provider for each issue tracker
+// the projects use. clickupCfg may be nil when no project uses ClickUp.
 func NewDaemon(s *store.Store, clickupCfg *config.ClickUpConfig, tunnelMgr interface{}) (*Daemon, error) {
-	if clickupCfg == nil || clickupCfg.APIToken == "" {
-		return nil, fmt.Errorf("ClickUp not configured. Run 'conductor agent setup' first")
-	}
-
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// We pass nil for tunnel manager since we handle it in the clickup manager
@@ -200,21 +246,52 @@ func NewDaemon(s *store.Store, clickupCfg *config.ClickUpConfig, tunnelMgr inter
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
+	var settings []*config.TrackerSettings
+	for _, project := range s.GetAllProjects() {
+		if ts := projectTracker(project); ts != nil {
+			settings = append(settings, ts)
+		}
+	}
+	providers := make(map[string]tracker.Provider)
+	for _, ts := range settings {
+		if providers[ts.Provider] != nil {
+			continue
+		}
+		provider, err := NewProvider(ts.Provider, clickupCfg, statusLabels(settings, ts.Provider))
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		providers[ts.Provider] = provider
+	}
+	if len(providers) == 0 && clickupCfg != nil && clickupCfg.APIToken != "" {
+		// ClickUp is set up but no project uses it yet; Start reports that
+		provider, err := NewProvider(config.TrackerClickUp, clickupCfg, nil)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		providers[config.TrackerClickUp] = provider
+	}
+	if len(providers) == 0 {
+		cancel()
+		return nil, fmt.Errorf("no issue tracker configured. Run 'conductor agent setup' for ClickUp, or add githubIssues to a project's conductor.json")
+	}
+
 	mgr := workspace.NewManagerWithStore(cfg, s)
-	clickupMgr := clickup.NewManager(clickupCfg, nil) // tunnel support added later
 
-	seqHandler := NewSequentialHandler(clickupMgr.Client())
+	seqHandler := NewSequentialHandler(providers)
 
-	dispatcher := NewDispatcher(s, mgr, clickupMgr, seqHandler)
-	clickupMgr.SetEventHandler(dispatcher.HandleEvent)
+	dispatcher := NewDispatcher(s, mgr, seqHandler, providers, LoadTaskQueue())
 
-	watcher := NewPRWatcher(s, clickupMgr.Client(), 60*time.Second)
+	watcher := NewPRWatcher(s, providers, 60*time.Second)
 
 	return &Daemon{
 		store:      s,
 		dispatcher: dispatcher,
 		watcher:    watcher,
-		clickupMgr: clickupMgr,
+		scheduler:  database.NewScheduler(s, log.Printf),
+		providers:  providers,
 		seqHandler: seqHandler,
 		ctx:        ctx,
 		cancel:     cancel,
@@ -223,24 +300,41 @@ func NewDaemon(s *store.Store, clickupCfg *config.ClickUpConfig, tunnelMgr inter
 
 // Start starts the agent daemon
 func (d *Daemon) Start() error {
-	// Collect list IDs from all projects
-	listIDs := d.collectListIDs()
-	if len(listIDs) == 0 {
-		return fmt.Errorf("no projects have ClickUp list IDs configured. Set clickup.listId in project conductor.json")
+	// Collect watched lists from all projects
+	watches := d.collectWatches()
+	if len(watches) == 0 {
+		return fmt.Errorf("no projects have an issue tracker configured. Set clickup.listId or githubIssues in project conductor.json")
 	}
 
-	// Start ClickUp event listener
-	if err := d.clickupMgr.Start(listIDs); err != nil {
-		return fmt.Errorf("failed to start ClickUp manager: %w", err)
+	// Start each tracker's event listener
+	count := 0
+	for _, name := range sortedProviderNames(d.providers) {
+		if len(watches[name]) == 0 {
+			continue
+		}
+		provider := d.providers[name]
+		if err := provider.Start(watches[name], d.dispatcher.HandleEvent); err != nil {
+			_ = d.stopProviders()
+			return fmt.Errorf("failed to start %s listener: %w", name, err)
+		}
+		d.started = append(d.started, provider)
+		count += len(watches[name])
 	}
 
 	// Start PR watcher
 	go d.watcher.Start(d.ctx)
 
+	// Start queued tasks as agent sessions finish
+	d.dispatcher.RecoverSlots()
+	go d.dispatcher.RunQueue(d.ctx, queueDrainInterval)
+
+	// Run golden copy syncs on each project's database.syncSchedule
+	go d.scheduler.Start(d.ctx)
+
 	// Trigger initial auto-pick for idle sequential+autoPick projects
 	d.initialAutoPick()
 
-	log.Printf("agent daemon started (mode: %s, watching %d lists)", d.clickupMgr.Mode(), len(listIDs))
+	log.Printf("agent daemon started (mode: %s, watching %d lists)", d.Mode(), count)
 	return nil
 }
 
@@ -248,7 +342,19 @@ func (d *Daemon) Start() error {
 func (d *Daemon) Stop() error {
 	d.cancel()
 	d.seqHandler.Stop()
-	return d.clickupMgr.Stop()
+	return d.stopProviders()
+}
+
+// stopProviders stops the started event listeners, returning the first error
+func (d *Daemon) stopProviders() error {
+	var firstErr error
+	for _, provider := range d.started {
+		if err := provider.Stop(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	d.started = nil
+	return firstErr
 }
 
 // Wait blocks until the daemon context is cancelled
@@ -256,40 +362,57 @@ func (d *Daemon) Wait() {
 	<-d.ctx.Done()
 }
 
-// Mode returns the current event mode
+// Mode returns the event mode of each started tracker, e.g. "clickup: webhook, github: polling"
 func (d *Daemon) Mode() string {
-	return d.clickupMgr.Mode()
+	if len(d.started) == 0 {
+		return "inactive"
+	}
+	modes := make([]string, len(d.started))
+	for i, provider := range d.started {
+		modes[i] = provider.Name() + ": " + provider.Mode()
+	}
+	return strings.Join(modes, ", ")
 }
 
 // initialAutoPick triggers auto-pick for idle sequential+autoPick projects on daemon start
 func (d *Daemon) initialAutoPick() {
 	projects := d.store.GetAllProjects()
 	for projectName, project := range projects {
-		projectConfig, err := config.LoadProjectConfig(project.Path)
-		if err != nil || projectConfig == nil || projectConfig.ClickUp == nil {
+		settings := projectTracker(project)
+		if settings == nil {
 			continue
 		}
-		go d.seqHandler.InitialAutoPick(projectName, projectConfig.ClickUp, project.Path)
+		go d.seqHandler.InitialAutoPick(projectName, settings, project.Path)
 	}
 }
 
-// collectListIDs gathers ClickUp list IDs from all project configs
-func (d *Daemon) collectListIDs() []string {
-	var listIDs []string
+// collectWatches gathers each provider's watched lists from all project configs
+func (d *Daemon) collectWatches() map[string][]tracker.Watch {
+	watches := make(map[string][]tracker.Watch)
 	projects := d.store.GetAllProjects()
 
 	for _, project := range projects {
-		projectConfig, err := config.LoadProjectConfig(project.Path)
-		if err != nil || projectConfig == nil {
+		settings := projectTracker(project)
+		if settings == nil || d.providers[settings.Provider] == nil {
 			continue
 		}
-
-		if projectConfig.ClickUp != nil && projectConfig.ClickUp.ListID != "" {
-			listIDs = append(listIDs, projectConfig.ClickUp.ListID)
-		}
+		watches[settings.Provider] = append(watches[settings.Provider], tracker.Watch{
+			ListID:        settings.ListID,
+			TriggerStatus: settings.TriggerStatus,
+		})
 	}
 
-	return listIDs
+	return watches
+}
+
+// sortedProviderNames returns the provider names in a stable order
+func sortedProviderNames(providers map[string]tracker.Provider) []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // collectProjectInfo gathers info about watched projects for status display
@@ -298,45 +421,37 @@ func (d *Daemon) CollectProjectInfo() []ProjectInfo {
 	projects := d.store.GetAllProjects()
 
 	for projectName, project := range projects {
-		projectConfig, err := config.LoadProjectConfig(project.Path)
-		if err != nil || projectConfig == nil {
+		settings := projectTracker(project)
+		if settings == nil {
 			continue
 		}
 
-		if projectConfig.ClickUp != nil && projectConfig.ClickUp.ListID != "" {
-			triggerStatus := projectConfig.ClickUp.TriggerStatus
-			if triggerStatus == "" {
-				triggerStatus = "in progress"
-			}
-
-			mode := projectConfig.ClickUp.GetMode()
-
-			// Count active agent worktrees
-			activeCount := 0
-			worktrees := d.store.GetAllWorktrees(projectName)
-			for _, wt := range worktrees {
-				if wt.ClickUpTaskID != "" && !wt.Archived {
-					activeCount++
-				}
+		// Count active agent worktrees
+		activeCount := 0
+		worktrees := d.store.GetAllWorktrees(projectName)
+		for _, wt := range worktrees {
+			if wt.TaskID != "" && !wt.Archived {
+				activeCount++
 			}
+		}
 
-			// Get active sequential task name
-			var activeTaskName string
-			if mode == config.AgentModeSequential {
-				if at := d.seqHandler.GetActiveTask(projectName); at != nil {
-					activeTaskName = at.TaskName
-				}
+		// Get active sequential task name
+		var activeTaskName string
+		if settings.Mode == config.AgentModeSequential {
+			if at := d.seqHandler.GetActiveTask(projectName); at != nil {
+				activeTaskName = at.TaskName
 			}
-
-			infos = append(infos, ProjectInfo{
-				Name:            projectName,
-				ListID:          projectConfig.ClickUp.ListID,
-				TriggerStatus:   triggerStatus,
-				ActiveWorktrees: activeCount,
-				Mode:            mode,
-				ActiveTask:      activeTaskName,
-			})
 		}
+
+		infos = append(infos, ProjectInfo{
+			Name:            projectName,
+			Provider:        settings.Provider,
+			ListID:          settings.ListID,
+			TriggerStatus:   settings.TriggerStatus,
+			ActiveWorktrees: activeCount,
+			Mode:            settings.Mode,
+			ActiveTask:      activeTaskName,
+		})
 	}
 
 	return infos
@@ -345,6 +460,7 @@ func (d *Daemon) CollectProjectInfo() []ProjectInfo {
 // ProjectInfo contains display info for a watched project
 type ProjectInfo struct {
 	Name            string
+	Provider        string // config.TrackerClickUp or config.TrackerGitHub
 	ListID          string
 	TriggerStatus   string
 	ActiveWorktrees int
@@ -6,23 +6,23 @@ import (
 	"os/exec"
 	"strings"
 
-	"github.com/hammashamzah/conductor/internal/clickup"
 	"github.com/hammashamzah/conductor/internal/codingagent"
+	"github.com/hammashamzah/conductor/internal/tracker"
 )
 
 // TaskPicker uses AI to select the most important task from a list
 type TaskPicker struct {
-	client *clickup.Client
+	provider tracker.Provider
 }
 
 // NewTaskPicker creates a new TaskPicker
-func NewTaskPicker(client *clickup.Client) *TaskPicker {
-	return &TaskPicker{client: client}
+func NewTaskPicker(provider tracker.Provider) *TaskPicker {
+	return &TaskPicker{provider: provider}
 }
 
 // PickNextTask fetches ready tasks and uses Claude to pick the best one
-func (p *TaskPicker) PickNextTask(listID, readyStatus string) (*clickup.Task, error) {
-	tasks, err := p.client.GetFilteredTasks(listID, []string{readyStatus})
+func (p *TaskPicker) PickNextTask(listID, readyStatus string) (*tracker.Task, error) {
+	tasks, err := p.provider.ListTasks(listID, readyStatus)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch ready tasks: %w", err)
 	}
@@ -39,20 +39,13 @@ func (p *TaskPicker) PickNextTask(listID, readyStatus string) (*clickup.Task, er
 	// Build summaries for the picker prompt
 	summaries := make([]TaskSummary, len(tasks))
 	for i, t := range tasks {
-		s := TaskSummary{
-			ID:   t.ID,
-			Name: t.Name,
+		summaries[i] = TaskSummary{
+			ID:           t.ID,
+			Name:         t.Name,
+			Priority:     t.Priority,
+			Description:  t.Description,
+			Dependencies: t.Dependencies,
 		}
-		if t.Priority != nil {
-			s.Priority = priorityLabel(t.Priority.Priority)
-		}
-		if t.Description != "" {
-			s.Description = t.Description
-		}
-		for _, dep := range t.Dependencies {
-			s.Dependencies = append(s.Dependencies, dep.DependsOn)
-		}
-		summaries[i] = s
 	}
 
 	prompt := BuildTaskPickerPrompt(summaries)
@@ -86,19 +79,3 @@ func askAgent(prompt string) (string, error) {
 	}
 	return strings.TrimSpace(string(out)), nil
 }
-
-// priorityLabel converts ClickUp priority ID to human-readable label
-func priorityLabel(id string) string {
-	switch id {
-	case "1":
-		return "urgent"
-	case "2":
-		return "high"
-	case "3":
-		return "normal"
-	case "4":
-		return "low"
-	default:
-		return ""
-	}
-}
@@ -5,7 +5,7 @@ import (
 	"strings"
 )
 
-// BuildTaskPrompt constructs the claude task prompt from ClickUp task data
+// BuildTaskPrompt constructs the claude task prompt from issue-tracker task data
 func BuildTaskPrompt(title, description, taskURL string) string {
 	var sb strings.Builder
 
@@ -53,13 +53,13 @@ func SlugifyTitle(title string) string {
 	return s
 }
 
-// GenerateBranchName creates a branch name from a ClickUp task
-func GenerateBranchName(taskID, title string) string {
+// GenerateBranchName creates a branch name from a task's key and title
+func GenerateBranchName(taskKey, title string) string {
 	slug := SlugifyTitle(title)
 	if slug == "" {
-		return fmt.Sprintf("feature/%s", taskID)
+		return fmt.Sprintf("feature/%s", taskKey)
 	}
-	return fmt.Sprintf("feature/%s-%s", taskID, slug)
+	return fmt.Sprintf("feature/%s-%s", taskKey, slug)
 }
 
 // BuildSequentialTaskPrompt constructs a prompt for sequential mode (no PRs, commit directly)
@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/github"
+	"github.com/hammashamzah/conductor/internal/secrets"
+	"github.com/hammashamzah/conductor/internal/tracker"
+)
+
+// githubPollInterval is how often GitHub Issues are checked for trigger labels
+const githubPollInterval = 60 * time.Second
+
+// NewProvider creates the issue-tracker provider with the given name.
+// statusLabels are the labels GitHub projects use as statuses.
+func NewProvider(name string, clickupCfg *config.ClickUpConfig, statusLabels []string) (tracker.Provider, error) {
+	switch name {
+	case config.TrackerClickUp:
+		if clickupCfg == nil || clickupCfg.APIToken == "" {
+			return nil, fmt.Errorf("ClickUp not configured. Run 'conductor agent setup' first")
+		}
+		if _, err := secrets.Resolve(clickupCfg.APIToken); err != nil {
+			return nil, fmt.Errorf("failed to resolve ClickUp API token: %w", err)
+		}
+		return tracker.NewClickUp(clickupCfg, nil), nil // tunnel support added later
+	case config.TrackerGitHub:
+		if !github.IsGHInstalled() {
+			return nil, fmt.Errorf("gh CLI not installed (needed for GitHub Issues)")
+		}
+		return tracker.NewGitHubIssues(githubPollInterval, statusLabels...), nil
+	}
+	return nil, fmt.Errorf("unknown issue tracker %q", name)
+}
+
+// projectTracker returns the project's issue-tracker settings, or nil if the
+// agent doesn't watch it
+func projectTracker(project *config.Project) *config.TrackerSettings {
+	projectConfig, err := config.LoadProjectConfig(project.Path)
+	if err != nil || projectConfig == nil {
+		return nil
+	}
+	return projectConfig.Tracker(project)
+}
+
+// statusLabels collects the statuses the projects of one provider use
+func statusLabels(settings []*config.TrackerSettings, provider string) []string {
+	seen := make(map[string]bool)
+	var labels []string
+	for _, ts := range settings {
+		if ts.Provider != provider {
+			continue
+		}
+		for _, s := range []string{ts.TriggerStatus, ts.ReadyStatus, ts.ReviewStatus} {
+			if s != "" && !seen[strings.ToLower(s)] {
+				seen[strings.ToLower(s)] = true
+				labels = append(labels, s)
+			}
+		}
+	}
+	return labels
+}
@@ -0,0 +1,171 @@
+package agent
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/tracker"
+)
+
+// QueuedTask is a parallel-mode task waiting for an agent slot
+type QueuedTask struct {
+	Project  string       `json:"project"`
+	Task     tracker.Task `json:"task"`
+	QueuedAt time.Time    `json:"queuedAt"`
+	Attempts int          `json:"attempts,omitempty"` // failed starts so far
+}
+
+// TaskQueue is the FIFO backlog of tasks that arrived while their project, or
+// the daemon as a whole, was at its maxConcurrentAgents limit. It is saved on
+// every change so queued tasks survive daemon restarts.
+type TaskQueue struct {
+	mu    sync.Mutex
+	path  string
+	tasks []QueuedTask
+}
+
+// queueFilePath returns ~/.conductor/agent-queue.json
+func queueFilePath() string {
+	dir, err := config.ConductorDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "agent-queue.json")
+}
+
+// LoadTaskQueue loads the persisted queue. A missing or unreadable file is an
+// empty queue.
+func LoadTaskQueue() *TaskQueue {
+	q := &TaskQueue{path: queueFilePath()}
+	if q.path == "" {
+		return q
+	}
+	data, err := os.ReadFile(q.path)
+	if err != nil {
+		return q // No queue file is fine
+	}
+	if err := json.Unmarshal(data, &q.tasks); err != nil {
+		log.Printf("queue: failed to parse queue file: %v", err)
+	}
+	return q
+}
+
+// Push appends a task to the queue. It returns false if the task is already queued.
+func (q *TaskQueue) Push(qt QueuedTask) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.indexLocked(qt.Task.Provider, qt.Task.ID) >= 0 {
+		return false
+	}
+	q.tasks = append(q.tasks, qt)
+	q.saveLocked()
+	return true
+}
+
+// PushFront puts a task back at the head of the queue, so a task that failed
+// to start keeps its place. It returns false if the task is already queued.
+func (q *TaskQueue) PushFront(qt QueuedTask) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.indexLocked(qt.Task.Provider, qt.Task.ID) >= 0 {
+		return false
+	}
+	q.tasks = append([]QueuedTask{qt}, q.tasks...)
+	q.saveLocked()
+	return true
+}
+
+// Remove drops a task from the queue, reporting whether it was queued
+func (q *TaskQueue) Remove(provider, taskID string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := q.indexLocked(provider, taskID)
+	if i < 0 {
+		return false
+	}
+	q.tasks = append(q.tasks[:i], q.tasks[i+1:]...)
+	q.saveLocked()
+	return true
+}
+
+// Position returns a task's 1-based place in the queue, or 0 if it isn't queued
+func (q *TaskQueue) Position(provider, taskID string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.indexLocked(provider, taskID) + 1
+}
+
+// Attempts returns how many times a queued task has failed to start
+func (q *TaskQueue) Attempts(provider, taskID string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if i := q.indexLocked(provider, taskID); i >= 0 {
+		return q.tasks[i].Attempts
+	}
+	return 0
+}
+
+// Tasks returns a copy of the queue, oldest first
+func (q *TaskQueue) Tasks() []QueuedTask {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]QueuedTask(nil), q.tasks...)
+}
+
+// Len returns the number of queued tasks
+func (q *TaskQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.tasks)
+}
+
+func (q *TaskQueue) indexLocked(provider, taskID string) int {
+	for i, qt := range q.tasks {
+		if qt.Task.Provider == provider && qt.Task.ID == taskID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (q *TaskQueue) saveLocked() {
+	if q.path == "" {
+		return
+	}
+	data, err := json.MarshalIndent(q.tasks, "", "  ")
+	if err != nil {
+		log.Printf("queue: failed to marshal queue: %v", err)
+		return
+	}
+	if err := config.WriteFileAtomic(q.path, data, 0644); err != nil {
+		log.Printf("queue: failed to save queue: %v", err)
+	}
+}
+
+// nextStartable returns the index of the oldest queued task that fits under
+// both limits, or -1. Tasks of a full project don't hold up other projects.
+// running counts agent sessions per project; a limit of 0 means no limit.
+func nextStartable(queued []QueuedTask, running map[string]int, projectLimit func(project string) int, globalLimit int) int {
+	total := 0
+	for _, n := range running {
+		total += n
+	}
+	if globalLimit > 0 && total >= globalLimit {
+		return -1
+	}
+	for i, qt := range queued {
+		if limit := projectLimit(qt.Project); limit > 0 && running[qt.Project] >= limit {
+			continue
+		}
+		return i
+	}
+	return -1
+}
@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hammashamzah/conductor/internal/tracker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func queued(project, taskID string) QueuedTask {
+	return QueuedTask{
+		Project:  project,
+		Task:     tracker.Task{Provider: "clickup", ID: taskID, Key: taskID, Name: "Task " + taskID},
+		QueuedAt: time.Now(),
+	}
+}
+
+func TestTaskQueue(t *testing.T) {
+	t.Setenv("CONDUCTOR_CONFIG_DIR", t.TempDir())
+
+	q := LoadTaskQueue()
+	assert.Equal(t, 0, q.Len())
+
+	require.True(t, q.Push(queued("web", "a")))
+	require.True(t, q.Push(queued("api", "b")))
+	require.True(t, q.Push(queued("web", "c")))
+	assert.False(t, q.Push(queued("web", "a")), "already queued")
+
+	assert.Equal(t, 2, q.Position("clickup", "b"))
+	assert.Equal(t, 0, q.Position("github", "b"), "IDs are per tracker")
+
+	assert.True(t, q.Remove("clickup", "b"))
+	assert.False(t, q.Remove("clickup", "b"))
+
+	// The queue survives a daemon restart, in order
+	restarted := LoadTaskQueue()
+	tasks := restarted.Tasks()
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "a", tasks[0].Task.ID)
+	assert.Equal(t, "Task a", tasks[0].Task.Name)
+	assert.Equal(t, "c", tasks[1].Task.ID)
+	assert.Equal(t, "web", tasks[1].Project)
+}
+
+func TestNextStartable(t *testing.T) {
+	queue := []QueuedTask{queued("web", "a"), queued("api", "b"), queued("web", "c")}
+	limits := map[string]int{"web": 2}
+	projectLimit := func(project string) int { return limits[project] }
+
+	assert.Equal(t, 0, nextStartable(queue, nil, projectLimit, 0))
+	assert.Equal(t, -1, nextStartable(nil, nil, projectLimit, 0))
+
+	// A full project doesn't hold up the others
+	assert.Equal(t, 1, nextStartable(queue, map[string]int{"web": 2}, projectLimit, 0))
+	assert.Equal(t, -1, nextStartable(queue[:1], map[string]int{"web": 2}, projectLimit, 0))
+
+	// The global limit counts every project's agents
+	assert.Equal(t, -1, nextStartable(queue, map[string]int{"web": 1, "api": 2}, projectLimit, 3))
+	assert.Equal(t, 0, nextStartable(queue, map[string]int{"web": 1, "api": 1}, projectLimit, 3))
+}
+
+func TestTaskQueue_PushFrontKeepsPlace(t *testing.T) {
+	t.Setenv("CONDUCTOR_CONFIG_DIR", t.TempDir())
+
+	q := LoadTaskQueue()
+	require.True(t, q.Push(queued("web", "a")))
+	require.True(t, q.Push(queued("web", "b")))
+
+	// a is taken off to start, fails, and goes back ahead of b
+	require.True(t, q.Remove("clickup", "a"))
+	retry := queued("web", "a")
+	retry.Attempts = 1
+	require.True(t, q.PushFront(retry))
+	assert.False(t, q.PushFront(retry), "already queued")
+
+	assert.Equal(t, 1, q.Position("clickup", "a"))
+	assert.Equal(t, 1, q.Attempts("clickup", "a"))
+	assert.Equal(t, 0, q.Attempts("clickup", "b"))
+
+	tasks := LoadTaskQueue().Tasks()
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "a", tasks[0].Task.ID)
+	assert.Equal(t, 1, tasks[0].Attempts)
+}
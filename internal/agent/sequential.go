@@ -10,39 +10,43 @@ import (
 	"sync"
 	"time"
 
-	"github.com/hammashamzah/conductor/internal/clickup"
 	"github.com/hammashamzah/conductor/internal/codingagent"
 	"github.com/hammashamzah/conductor/internal/config"
 	"github.com/hammashamzah/conductor/internal/mux"
+	"github.com/hammashamzah/conductor/internal/tracker"
 )
 
 // activeTask tracks a currently running sequential task
 type activeTask struct {
-	ProjectName string    `json:"projectName"`
-	TaskID      string    `json:"taskId"`
-	TaskName    string    `json:"taskName"`
-	PaneID      string    `json:"paneId"`
-	WindowName  string    `json:"windowName"`
-	StartedAt   time.Time `json:"startedAt"`
+	ProjectName string `json:"projectName"`
+	// Provider is the task's issue tracker (empty in state saved before
+	// trackers were pluggable, meaning ClickUp)
+	Provider string `json:"provider,omitempty"`
+	// DoneStatus is the status to move the task to if it finishes while the
+	// daemon is down
+	DoneStatus string    `json:"doneStatus,omitempty"`
+	TaskID     string    `json:"taskId"`
+	TaskName   string    `json:"taskName"`
+	PaneID     string    `json:"paneId"`
+	WindowName string    `json:"windowName"`
+	StartedAt  time.Time `json:"startedAt"`
 }
 
 // SequentialHandler manages one-at-a-time task execution per project
 type SequentialHandler struct {
 	mu          sync.Mutex
 	activeTasks map[string]*activeTask // projectName → active task
-	client      *clickup.Client
-	picker      *TaskPicker
+	providers   map[string]tracker.Provider
 	ctx         context.Context
 	cancel      context.CancelFunc
 }
 
 // NewSequentialHandler creates a new sequential handler
-func NewSequentialHandler(client *clickup.Client) *SequentialHandler {
+func NewSequentialHandler(providers map[string]tracker.Provider) *SequentialHandler {
 	ctx, cancel := context.WithCancel(context.Background())
 	h := &SequentialHandler{
 		activeTasks: make(map[string]*activeTask),
-		client:      client,
-		picker:      NewTaskPicker(client),
+		providers:   providers,
 		ctx:         ctx,
 		cancel:      cancel,
 	}
@@ -66,7 +70,7 @@ func (h *SequentialHandler) GetActiveTask(projectName string) *activeTask {
 }
 
 // StartTask starts a sequential task for a project
-func (h *SequentialHandler) StartTask(projectName string, task *clickup.Task, projectConfig *config.ProjectClickUpConfig, projectPath string) error {
+func (h *SequentialHandler) StartTask(projectName string, task *tracker.Task, settings *config.TrackerSettings, projectPath string) error {
 	h.mu.Lock()
 	if h.activeTasks[projectName] != nil {
 		h.mu.Unlock()
@@ -74,19 +78,16 @@ func (h *SequentialHandler) StartTask(projectName string, task *clickup.Task, pr
 	}
 	h.mu.Unlock()
 
-	taskURL := task.URL
-	if taskURL == "" {
-		taskURL = fmt.Sprintf("https://app.clickup.com/t/%s", task.ID)
-	}
-
 	// Create tmux window in the project's root worktree path
-	paneID, windowName, err := h.createSequentialWindow(projectName, task, projectPath, taskURL)
+	paneID, windowName, err := h.createSequentialWindow(projectName, task, projectPath)
 	if err != nil {
 		return fmt.Errorf("failed to create sequential window: %w", err)
 	}
 
 	at := &activeTask{
 		ProjectName: projectName,
+		Provider:    task.Provider,
+		DoneStatus:  settings.DoneStatus,
 		TaskID:      task.ID,
 		TaskName:    task.Name,
 		PaneID:      paneID,
@@ -103,7 +104,7 @@ func (h *SequentialHandler) StartTask(projectName string, task *clickup.Task, pr
 	log.Printf("sequential: started task %q (ID: %s) for project %s [pane: %s]", task.Name, task.ID, projectName, paneID)
 
 	// Start monitoring for completion
-	go h.monitorCompletion(projectName, at, projectConfig, projectPath)
+	go h.monitorCompletion(projectName, at, settings, projectPath)
 
 	return nil
 }
@@ -115,9 +116,9 @@ func (h *SequentialHandler) Stop() {
 
 // createSequentialWindow creates a multiplexer window for sequential mode.
 // Returns the agent pane ID and window name.
-func (h *SequentialHandler) createSequentialWindow(projectName string, task *clickup.Task, projectPath, taskURL string) (string, string, error) {
-	windowName := fmt.Sprintf("%s/seq-%s", projectName, task.ID)
-	taskPrompt := BuildSequentialTaskPrompt(task.Name, task.Description, taskURL)
+func (h *SequentialHandler) createSequentialWindow(projectName string, task *tracker.Task, projectPath string) (string, string, error) {
+	windowName := fmt.Sprintf("%s/seq-%s", projectName, task.Key)
+	taskPrompt := BuildSequentialTaskPrompt(task.Name, task.Description, task.URL)
 
 	agent := codingagent.ClaudeCode // Default to Claude Code for agent daemon
 	paneTitle := fmt.Sprintf("%s - %s (sequential)", task.Name, agent.PaneLabel())
@@ -144,7 +145,7 @@ func isShellCommand(cmd string) bool {
 // running, and under herdr that means once herdr has *detected* the agent —
 // which lags pane creation. So completion is only inferred after the agent has
 // been seen at least once; before that, an empty reading means "still starting".
-func (h *SequentialHandler) monitorCompletion(projectName string, at *activeTask, projectConfig *config.ProjectClickUpConfig, projectPath string) {
+func (h *SequentialHandler) monitorCompletion(projectName string, at *activeTask, settings *config.TrackerSettings, projectPath string) {
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
@@ -159,7 +160,7 @@ func (h *SequentialHandler) monitorCompletion(projectName string, at *activeTask
 			if !m.PaneExists(at.PaneID) {
 				// Pane was killed — treat as completion
 				log.Printf("sequential: pane %s gone for task %s, treating as completed", at.PaneID, at.TaskID)
-				h.handleTaskCompletion(projectName, at, projectConfig, projectPath)
+				h.handleTaskCompletion(projectName, at, settings, projectPath)
 				return
 			}
 
@@ -173,27 +174,29 @@ func (h *SequentialHandler) monitorCompletion(projectName string, at *activeTask
 				continue
 			}
 			log.Printf("sequential: agent finished for task %s (pane command: %q)", at.TaskID, cmd)
-			h.handleTaskCompletion(projectName, at, projectConfig, projectPath)
+			h.handleTaskCompletion(projectName, at, settings, projectPath)
 			return
 		}
 	}
 }
 
 // handleTaskCompletion processes a completed sequential task
-func (h *SequentialHandler) handleTaskCompletion(projectName string, at *activeTask, projectConfig *config.ProjectClickUpConfig, projectPath string) {
-	doneStatus := projectConfig.GetDoneStatus()
-
-	// Move task to done status in ClickUp
-	if err := h.client.UpdateTaskStatus(at.TaskID, doneStatus); err != nil {
-		log.Printf("sequential: failed to update task %s to %q: %v", at.TaskID, doneStatus, err)
-	} else {
-		log.Printf("sequential: moved task %s to %q", at.TaskID, doneStatus)
-	}
+func (h *SequentialHandler) handleTaskCompletion(projectName string, at *activeTask, settings *config.TrackerSettings, projectPath string) {
+	doneStatus := settings.DoneStatus
+
+	if provider := h.provider(at); provider != nil {
+		// Move task to done status in the tracker
+		if err := provider.UpdateStatus(at.TaskID, doneStatus); err != nil {
+			log.Printf("sequential: failed to update task %s to %q: %v", at.TaskID, doneStatus, err)
+		} else {
+			log.Printf("sequential: moved task %s to %q", at.TaskID, doneStatus)
+		}
 
-	// Add completion comment
-	comment := "Task completed by conductor agent (sequential mode). Changes committed directly to main branch."
-	if err := h.client.AddTaskComment(at.TaskID, comment); err != nil {
-		log.Printf("sequential: failed to add comment to task %s: %v", at.TaskID, err)
+		// Add completion comment
+		comment := "Task completed by conductor agent (sequential mode). Changes committed directly to main branch."
+		if err := provider.AddComment(at.TaskID, comment); err != nil {
+			log.Printf("sequential: failed to add comment to task %s: %v", at.TaskID, err)
+		}
 	}
 
 	// NOTE: tmux window is NOT closed — user reviews manually
@@ -205,16 +208,35 @@ func (h *SequentialHandler) handleTaskCompletion(projectName string, at *activeT
 	h.saveState()
 
 	// Auto-pick next task if enabled
-	if projectConfig.AutoPick {
-		h.autoPickNext(projectName, projectConfig, projectPath)
+	if settings.AutoPick {
+		h.autoPickNext(projectName, settings, projectPath)
+	}
+}
+
+// provider returns the issue tracker of an active task
+func (h *SequentialHandler) provider(at *activeTask) tracker.Provider {
+	name := at.Provider
+	if name == "" {
+		name = config.TrackerClickUp
+	}
+	provider := h.providers[name]
+	if provider == nil {
+		log.Printf("sequential: no %s provider for task %s", name, at.TaskID)
 	}
+	return provider
 }
 
 // autoPickNext uses the AI picker to select and start the next task
-func (h *SequentialHandler) autoPickNext(projectName string, projectConfig *config.ProjectClickUpConfig, projectPath string) {
+func (h *SequentialHandler) autoPickNext(projectName string, settings *config.TrackerSettings, projectPath string) {
+	provider := h.providers[settings.Provider]
+	if provider == nil {
+		log.Printf("sequential: no %s provider to auto-pick from for project %s", settings.Provider, projectName)
+		return
+	}
+
 	log.Printf("sequential: auto-picking next task for project %s", projectName)
 
-	task, err := h.picker.PickNextTask(projectConfig.ListID, projectConfig.GetReadyStatus())
+	task, err := NewTaskPicker(provider).PickNextTask(settings.ListID, settings.ReadyStatus)
 	if err != nil {
 		log.Printf("sequential: no next task available for %s: %v", projectName, err)
 		return
@@ -223,30 +245,27 @@ func (h *SequentialHandler) autoPickNext(projectName string, projectConfig *conf
 	log.Printf("sequential: auto-picked task %q (ID: %s) for project %s", task.Name, task.ID, projectName)
 
 	// Move picked task to trigger status so the flow is consistent
-	triggerStatus := projectConfig.TriggerStatus
-	if triggerStatus == "" {
-		triggerStatus = "in progress"
-	}
-	if err := h.client.UpdateTaskStatus(task.ID, triggerStatus); err != nil {
+	triggerStatus := settings.TriggerStatus
+	if err := provider.UpdateStatus(task.ID, triggerStatus); err != nil {
 		log.Printf("sequential: failed to move picked task %s to %q: %v", task.ID, triggerStatus, err)
 		return
 	}
 
 	// Start the task directly
-	if err := h.StartTask(projectName, task, projectConfig, projectPath); err != nil {
+	if err := h.StartTask(projectName, task, settings, projectPath); err != nil {
 		log.Printf("sequential: failed to start auto-picked task %s: %v", task.ID, err)
 	}
 }
 
 // InitialAutoPick triggers auto-pick for idle sequential+autoPick projects
-func (h *SequentialHandler) InitialAutoPick(projectName string, projectConfig *config.ProjectClickUpConfig, projectPath string) {
-	if !projectConfig.AutoPick || projectConfig.GetMode() != config.AgentModeSequential {
+func (h *SequentialHandler) InitialAutoPick(projectName string, settings *config.TrackerSettings, projectPath string) {
+	if !settings.AutoPick || settings.Mode != config.AgentModeSequential {
 		return
 	}
 	if h.HasActiveTask(projectName) {
 		return
 	}
-	h.autoPickNext(projectName, projectConfig, projectPath)
+	h.autoPickNext(projectName, settings, projectPath)
 }
 
 // State persistence
@@ -309,9 +328,14 @@ func (h *SequentialHandler) recoverState() {
 		if !mux.Current().PaneExists(at.PaneID) {
 			log.Printf("sequential: recovering stale task %s for project %s (pane gone)", at.TaskID, projectName)
 			// Mark as done since pane is gone (Claude likely finished)
-			doneStatus := "done" // Use default since we don't have project config here
-			if err := h.client.UpdateTaskStatus(at.TaskID, doneStatus); err != nil {
-				log.Printf("sequential: failed to update recovered task %s: %v", at.TaskID, err)
+			doneStatus := at.DoneStatus
+			if doneStatus == "" {
+				doneStatus = "done" // state saved before the done status was recorded
+			}
+			if provider := h.provider(at); provider != nil {
+				if err := provider.UpdateStatus(at.TaskID, doneStatus); err != nil {
+					log.Printf("sequential: failed to update recovered task %s: %v", at.TaskID, err)
+				}
 			}
 			delete(h.activeTasks, projectName)
 		}
@@ -5,27 +5,27 @@ import (
 	"log"
 	"time"
 
-	"github.com/hammashamzah/conductor/internal/clickup"
 	"github.com/hammashamzah/conductor/internal/github"
 	"github.com/hammashamzah/conductor/internal/store"
+	"github.com/hammashamzah/conductor/internal/tracker"
 )
 
 // PRWatcher monitors worktrees created by the agent for PR creation
 type PRWatcher struct {
-	store         *store.Store
-	clickupClient *clickup.Client
-	interval      time.Duration
+	store     *store.Store
+	providers map[string]tracker.Provider
+	interval  time.Duration
 }
 
 // NewPRWatcher creates a new PR watcher
-func NewPRWatcher(s *store.Store, client *clickup.Client, interval time.Duration) *PRWatcher {
+func NewPRWatcher(s *store.Store, providers map[string]tracker.Provider, interval time.Duration) *PRWatcher {
 	if interval == 0 {
 		interval = 60 * time.Second
 	}
 	return &PRWatcher{
-		store:         s,
-		clickupClient: client,
-		interval:      interval,
+		store:     s,
+		providers: providers,
+		interval:  interval,
 	}
 }
 
@@ -54,8 +54,8 @@ func (w *PRWatcher) checkForPRs() {
 		}
 
 		for worktreeName, worktree := range project.Worktrees {
-			// Only watch agent-created worktrees (have ClickUp task ID)
-			if worktree.ClickUpTaskID == "" {
+			// Only watch agent-created worktrees (have a task ID)
+			if worktree.TaskID == "" {
 				continue
 			}
 			// Skip archived or already-PR'd worktrees
@@ -81,19 +81,29 @@ func (w *PRWatcher) checkForPRs() {
 			// PR found! Update store
 			_ = w.store.SetWorktreePRs(projectName, worktreeName, prs)
 
-			// Update ClickUp task
+			// Update the task
 			pr := prs[0]
-			log.Printf("watcher: PR #%d created for task %s (%s/%s)", pr.Number, worktree.ClickUpTaskID, projectName, worktreeName)
+			log.Printf("watcher: PR #%d created for task %s (%s/%s)", pr.Number, worktree.TaskID, projectName, worktreeName)
 
-			// Move task to "in review"
-			if err := w.clickupClient.UpdateTaskStatus(worktree.ClickUpTaskID, "in review"); err != nil {
-				log.Printf("watcher: failed to update ClickUp task status: %v", err)
+			provider := w.providers[worktree.TaskProvider]
+			if provider == nil {
+				log.Printf("watcher: no %q provider to update task %s", worktree.TaskProvider, worktree.TaskID)
+				continue
+			}
+
+			// Move task to the review status
+			reviewStatus := "in review"
+			if settings := projectTracker(project); settings != nil && settings.Provider == worktree.TaskProvider {
+				reviewStatus = settings.ReviewStatus
+			}
+			if err := provider.UpdateStatus(worktree.TaskID, reviewStatus); err != nil {
+				log.Printf("watcher: failed to update %s task status: %v", provider.Name(), err)
 			}
 
 			// Add comment with PR URL
 			comment := "PR created: " + pr.URL
-			if err := w.clickupClient.AddTaskComment(worktree.ClickUpTaskID, comment); err != nil {
-				log.Printf("watcher: failed to add ClickUp comment: %v", err)
+			if err := provider.AddComment(worktree.TaskID, comment); err != nil {
+				log.Printf("watcher: failed to add %s comment: %v", provider.Name(), err)
 			}
 		}
 	}
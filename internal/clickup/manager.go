@@ -12,9 +12,18 @@ import (
 	"time"
 
 	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/secrets"
 	"github.com/hammashamzah/conductor/internal/tunnel"
 )
 
+// webhookSecretName is the secret the registered webhook's signing secret is
+// stored under, so conductor.json only holds a secret:// reference to it
+const webhookSecretName = "clickup-webhook-secret"
+
+// deliveryDedupWindow is how long webhook history item IDs are remembered.
+// ClickUp retries failed deliveries for well under a day.
+const deliveryDedupWindow = 24 * time.Hour
+
 // Manager orchestrates webhook vs polling and event fan-out
 type Manager struct {
 	client        *Client
@@ -25,8 +34,12 @@ type Manager struct {
 	tunnelMgr     *tunnel.Manager
 	tunnelState   *config.TunnelState
 
-	mu        sync.Mutex
-	processed map[string]string // taskID -> last processed status (deduplication)
+	mu sync.Mutex
+	// triggerStatuses are the lowercased statuses that dispatch a task
+	triggerStatuses map[string]bool
+	processed       map[string]string    // taskID -> last processed status (deduplication)
+	deliveries      map[string]time.Time // webhook history item ID -> when it was seen
+	duplicates      int64
 
 	// onEvent is called when a deduplicated task event is received
 	onEvent func(TaskEvent)
@@ -35,17 +48,39 @@ type Manager struct {
 	cancel context.CancelFunc
 }
 
-// NewManager creates a new ClickUp event manager
+// NewManager creates a new ClickUp event manager.
+// A secret reference in APIToken is resolved here; clickupCfg keeps the reference.
 func NewManager(clickupCfg *config.ClickUpConfig, tunnelMgr *tunnel.Manager) *Manager {
 	ctx, cancel := context.WithCancel(context.Background())
+	token, err := secrets.Resolve(clickupCfg.APIToken)
+	if err != nil {
+		log.Printf("failed to resolve ClickUp API token: %v", err)
+	}
 	return &Manager{
-		client:    NewClient(clickupCfg.APIToken),
-		cfg:       clickupCfg,
-		eventCh:   make(chan TaskEvent, 100),
-		tunnelMgr: tunnelMgr,
-		processed: make(map[string]string),
-		ctx:       ctx,
-		cancel:    cancel,
+		client:     NewClient(token),
+		cfg:        clickupCfg,
+		eventCh:    make(chan TaskEvent, 100),
+		tunnelMgr:  tunnelMgr,
+		processed:  make(map[string]string),
+		deliveries: make(map[string]time.Time),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// AddTriggerStatuses adds statuses that dispatch a task, for projects that
+// override the global trigger status. Status changes to other statuses are
+// only recorded.
+func (m *Manager) AddTriggerStatuses(statuses ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.triggerStatuses == nil {
+		m.triggerStatuses = make(map[string]bool)
+	}
+	for _, s := range statuses {
+		if s != "" {
+			m.triggerStatuses[strings.ToLower(s)] = true
+		}
 	}
 }
 
@@ -63,6 +98,7 @@ func (m *Manager) Start(listIDs []string) error {
 	if triggerStatus == "" {
 		triggerStatus = "in progress"
 	}
+	m.AddTriggerStatuses(triggerStatus)
 
 	webhookPort := m.cfg.WebhookPort
 	if webhookPort == 0 {
@@ -93,10 +129,15 @@ func (m *Manager) Start(listIDs []string) error {
 
 	m.poller = NewPoller(m.client, listIDs, triggerStatus, pollInterval, m.eventCh)
 
-	// Restore poller state from persisted dedup map
+	// Restore poller state from a copy of the persisted dedup map; the poller
+	// updates its own copy from its goroutine
 	m.mu.Lock()
-	m.poller.SetLastSeen(m.processed)
+	lastSeen := make(map[string]string, len(m.processed))
+	for k, v := range m.processed {
+		lastSeen[k] = v
+	}
 	m.mu.Unlock()
+	m.poller.SetLastSeen(lastSeen)
 
 	go m.poller.Start(m.ctx)
 
@@ -116,7 +157,7 @@ func (m *Manager) Stop() error {
 			log.Printf("failed to deregister webhook: %v", err)
 		} else {
 			m.cfg.WebhookID = ""
-			m.cfg.WebhookSecret = ""
+			m.forgetWebhookSecret()
 		}
 	}
 
@@ -133,9 +174,27 @@ func (m *Manager) Stop() error {
 	// Save dedup state
 	m.saveState()
 
+	if m.webhookServer != nil {
+		stats := m.WebhookStats()
+		log.Printf("webhook deliveries: %d accepted, %d rejected (bad signature), %d malformed, %d duplicates",
+			stats.Accepted, stats.Rejected, stats.Malformed, stats.Duplicates)
+	}
+
 	return nil
 }
 
+// WebhookStats returns webhook delivery counts since Start
+func (m *Manager) WebhookStats() WebhookStats {
+	var stats WebhookStats
+	if m.webhookServer != nil {
+		stats = m.webhookServer.Stats()
+	}
+	m.mu.Lock()
+	stats.Duplicates = m.duplicates
+	m.mu.Unlock()
+	return stats
+}
+
 // Mode returns "webhook", "polling", or "inactive"
 func (m *Manager) Mode() string {
 	if m.webhookServer != nil {
@@ -155,7 +214,11 @@ func (m *Manager) Client() *Client {
 // startWebhookMode sets up the webhook server with a Cloudflare tunnel
 func (m *Manager) startWebhookMode(port int) error {
 	// Start local HTTP server
-	m.webhookServer = NewWebhookServer(port, m.cfg.WebhookSecret, m.eventCh)
+	webhookSecret, err := secrets.Resolve(m.cfg.WebhookSecret)
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook secret: %w", err)
+	}
+	m.webhookServer = NewWebhookServer(port, webhookSecret, m.eventCh)
 	if err := m.webhookServer.Start(m.ctx); err != nil {
 		return fmt.Errorf("failed to start webhook server: %w", err)
 	}
@@ -180,11 +243,40 @@ func (m *Manager) startWebhookMode(port int) error {
 	}
 
 	m.cfg.WebhookID = reg.ID
-	m.cfg.WebhookSecret = reg.Secret
+	m.rememberWebhookSecret(reg.Secret)
+	m.webhookServer.SetSecret(reg.Secret)
 
 	return nil
 }
 
+// rememberWebhookSecret stores a registered webhook's signing secret in the
+// secrets store and points the config at it. If it can't be stored, the
+// config keeps no secret rather than the plaintext.
+func (m *Manager) rememberWebhookSecret(secret string) {
+	m.cfg.WebhookSecret = ""
+	backend, err := secrets.Default()
+	if err != nil {
+		log.Printf("failed to open secrets store for webhook secret: %v", err)
+		return
+	}
+	if err := backend.Set(webhookSecretName, secret); err != nil {
+		log.Printf("failed to store webhook secret: %v", err)
+		return
+	}
+	m.cfg.WebhookSecret = secrets.Ref(webhookSecretName)
+}
+
+// forgetWebhookSecret clears the webhook secret from the config and, if
+// conductor stored it, from the secrets store
+func (m *Manager) forgetWebhookSecret() {
+	if m.cfg.WebhookSecret == secrets.Ref(webhookSecretName) {
+		if backend, err := secrets.Default(); err == nil {
+			_ = backend.Delete(webhookSecretName)
+		}
+	}
+	m.cfg.WebhookSecret = ""
+}
+
 // processEvents reads from eventCh, deduplicates, and dispatches
 func (m *Manager) processEvents() {
 	for {
@@ -192,15 +284,9 @@ func (m *Manager) processEvents() {
 		case <-m.ctx.Done():
 			return
 		case event := <-m.eventCh:
-			// Deduplicate
-			m.mu.Lock()
-			lastStatus, seen := m.processed[event.TaskID]
-			if seen && lastStatus == strings.ToLower(event.NewStatus) {
-				m.mu.Unlock()
+			if !m.accept(event) {
 				continue
 			}
-			m.processed[event.TaskID] = strings.ToLower(event.NewStatus)
-			m.mu.Unlock()
 
 			// Enrich event with full task data if not present
 			if event.Task == nil {
@@ -220,14 +306,56 @@ func (m *Manager) processEvents() {
 			if m.onEvent != nil {
 				m.onEvent(event)
 			}
+
+			// Persist dedup state so a restart doesn't dispatch the task again
+			m.saveState()
 		}
 	}
 }
 
+// accept deduplicates an event and reports whether to dispatch it. A webhook
+// history item is handled once however often ClickUp retries it, and a task
+// is dispatched once per entry into the trigger status, whether the webhook
+// or the poller saw it first. Other status changes are recorded, not
+// dispatched, so a later move back to the trigger status counts as new.
+func (m *Manager) accept(event TaskEvent) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if event.DeliveryID != "" {
+		if _, seen := m.deliveries[event.DeliveryID]; seen {
+			m.duplicates++
+			log.Printf("dropping duplicate webhook delivery %s for task %s", event.DeliveryID, event.TaskID)
+			return false
+		}
+		m.deliveries[event.DeliveryID] = time.Now()
+	}
+
+	status := strings.ToLower(event.NewStatus)
+	lastStatus, seen := m.processed[event.TaskID]
+	if seen && lastStatus == status {
+		return false
+	}
+	m.processed[event.TaskID] = status
+
+	return m.triggerStatuses[status]
+}
+
 // agentState represents persisted dedup state
 type agentState struct {
 	Processed map[string]string `json:"processed"`
-	SavedAt   time.Time         `json:"savedAt"`
+	// Deliveries are webhook history item IDs seen within deliveryDedupWindow
+	Deliveries map[string]time.Time `json:"deliveries,omitempty"`
+	SavedAt    time.Time            `json:"savedAt"`
+}
+
+// pruneDeliveries forgets history items older than the dedup window
+func pruneDeliveries(deliveries map[string]time.Time, now time.Time) {
+	for id, seenAt := range deliveries {
+		if now.Sub(seenAt) > deliveryDedupWindow {
+			delete(deliveries, id)
+		}
+	}
 }
 
 func (m *Manager) stateFilePath() string {
@@ -245,17 +373,19 @@ func (m *Manager) saveState() {
 	}
 
 	m.mu.Lock()
+	now := time.Now()
+	pruneDeliveries(m.deliveries, now)
 	state := agentState{
-		Processed: m.processed,
-		SavedAt:   time.Now(),
+		Processed:  m.processed,
+		Deliveries: m.deliveries,
+		SavedAt:    now,
 	}
-	m.mu.Unlock()
-
 	data, err := json.MarshalIndent(state, "", "  ")
+	m.mu.Unlock()
 	if err != nil {
 		return
 	}
-	_ = os.WriteFile(path, data, 0644)
+	_ = config.WriteFileAtomic(path, data, 0644)
 }
 
 func (m *Manager) loadState() {
@@ -279,5 +409,10 @@ func (m *Manager) loadState() {
 	if m.processed == nil {
 		m.processed = make(map[string]string)
 	}
+	m.deliveries = state.Deliveries
+	if m.deliveries == nil {
+		m.deliveries = make(map[string]time.Time)
+	}
+	pruneDeliveries(m.deliveries, time.Now())
 	m.mu.Unlock()
 }
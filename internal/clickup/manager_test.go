@@ -0,0 +1,73 @@
+package clickup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	t.Setenv("CONDUCTOR_CONFIG_DIR", t.TempDir())
+	m := NewManager(&config.ClickUpConfig{}, nil)
+	t.Cleanup(m.cancel)
+	m.AddTriggerStatuses("In Progress")
+	return m
+}
+
+func TestManagerAccept(t *testing.T) {
+	m := newTestManager(t)
+
+	webhook := TaskEvent{TaskID: "86abc", NewStatus: "in progress", DeliveryID: "h1"}
+	assert.True(t, m.accept(webhook))
+
+	// ClickUp retrying the delivery
+	assert.False(t, m.accept(webhook))
+	// The poller seeing the same transition
+	assert.False(t, m.accept(TaskEvent{TaskID: "86abc", NewStatus: "in progress"}))
+
+	// Other statuses are recorded but not dispatched...
+	assert.False(t, m.accept(TaskEvent{TaskID: "86abc", NewStatus: "in review", DeliveryID: "h2"}))
+	// ...so moving back to the trigger status dispatches again
+	assert.True(t, m.accept(TaskEvent{TaskID: "86abc", NewStatus: "In Progress", DeliveryID: "h3"}))
+
+	assert.Equal(t, int64(1), m.WebhookStats().Duplicates)
+}
+
+func TestManagerDedupStatePersists(t *testing.T) {
+	m := newTestManager(t)
+	require.True(t, m.accept(TaskEvent{TaskID: "86abc", NewStatus: "in progress", DeliveryID: "h1"}))
+	m.deliveries["old"] = time.Now().Add(-2 * deliveryDedupWindow)
+	m.saveState()
+
+	restarted := NewManager(&config.ClickUpConfig{}, nil)
+	defer restarted.cancel()
+	restarted.AddTriggerStatuses("in progress")
+	restarted.loadState()
+
+	assert.Contains(t, restarted.deliveries, "h1")
+	assert.NotContains(t, restarted.deliveries, "old", "deliveries outside the window are forgotten")
+	assert.False(t, restarted.accept(TaskEvent{TaskID: "86abc", NewStatus: "in progress", DeliveryID: "h1"}))
+	assert.False(t, restarted.accept(TaskEvent{TaskID: "86abc", NewStatus: "in progress"}))
+}
+
+func TestManagerWebhookSecretKeptOutOfConfig(t *testing.T) {
+	m := newTestManager(t)
+
+	m.rememberWebhookSecret("whsec-123")
+	assert.Equal(t, secrets.Ref(webhookSecretName), m.cfg.WebhookSecret)
+	resolved, err := secrets.Resolve(m.cfg.WebhookSecret)
+	require.NoError(t, err)
+	assert.Equal(t, "whsec-123", resolved)
+
+	m.forgetWebhookSecret()
+	assert.Empty(t, m.cfg.WebhookSecret)
+	backend, err := secrets.Default()
+	require.NoError(t, err)
+	_, err = backend.Get(webhookSecretName)
+	assert.ErrorIs(t, err, secrets.ErrNotFound)
+}
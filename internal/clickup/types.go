@@ -53,6 +53,8 @@ type WebhookPayload struct {
 
 // HistoryItem represents a change in a webhook event
 type HistoryItem struct {
+	// ID is unique per change and stays the same when ClickUp retries a delivery
+	ID     string      `json:"id"`
 	Field  string      `json:"field"`
 	Before StatusValue `json:"before"`
 	After  StatusValue `json:"after"`
@@ -83,6 +85,9 @@ type TaskEvent struct {
 	NewStatus string
 	OldStatus string
 	Timestamp time.Time
+	// DeliveryID identifies the webhook history item the event came from
+	// (empty for polled events)
+	DeliveryID string
 }
 
 // Comment represents a ClickUp task comment
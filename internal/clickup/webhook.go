@@ -2,23 +2,53 @@ package clickup
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
-// WebhookServer listens for ClickUp webhook events
+// maxWebhookBody caps the size of a webhook request body
+const maxWebhookBody = 1 << 20
+
+// WebhookStats counts webhook deliveries by outcome
+type WebhookStats struct {
+	Accepted int64
+	// Rejected deliveries had a missing or wrong X-Signature
+	Rejected int64
+	// Malformed deliveries were signed correctly but couldn't be parsed
+	Malformed int64
+	// Duplicates are history items seen before (retries), counted by Manager
+	Duplicates int64
+}
+
+// WebhookServer listens for ClickUp webhook events. Deliveries must carry an
+// X-Signature header, the hex HMAC-SHA256 of the body keyed with the secret
+// ClickUp returned when the webhook was registered.
 type WebhookServer struct {
 	port     int
-	secret   string
 	eventCh  chan<- TaskEvent
 	server   *http.Server
 	listener net.Listener
+
+	mu     sync.RWMutex
+	secret string
+
+	accepted  atomic.Int64
+	rejected  atomic.Int64
+	malformed atomic.Int64
 }
 
-// NewWebhookServer creates a new webhook HTTP server
+// NewWebhookServer creates a new webhook HTTP server. Until a secret is set,
+// every delivery is rejected.
 func NewWebhookServer(port int, secret string, eventCh chan<- TaskEvent) *WebhookServer {
 	return &WebhookServer{
 		port:    port,
@@ -27,6 +57,22 @@ func NewWebhookServer(port int, secret string, eventCh chan<- TaskEvent) *Webhoo
 	}
 }
 
+// SetSecret sets the secret deliveries are verified with
+func (ws *WebhookServer) SetSecret(secret string) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.secret = secret
+}
+
+// Stats returns the delivery counts since the server started
+func (ws *WebhookServer) Stats() WebhookStats {
+	return WebhookStats{
+		Accepted:  ws.accepted.Load(),
+		Rejected:  ws.rejected.Load(),
+		Malformed: ws.malformed.Load(),
+	}
+}
+
 // Start starts the webhook HTTP server
 func (ws *WebhookServer) Start(ctx context.Context) error {
 	mux := http.NewServeMux()
@@ -75,27 +121,47 @@ func (ws *WebhookServer) Port() int {
 	return ws.port
 }
 
-// handleWebhook processes incoming ClickUp webhook events
+// handleWebhook verifies and processes incoming ClickUp webhook events
 func (ws *WebhookServer) handleWebhook(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		ws.malformed.Add(1)
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	ws.mu.RLock()
+	secret := ws.secret
+	ws.mu.RUnlock()
+	if !VerifySignature(secret, body, r.Header.Get("X-Signature")) {
+		n := ws.rejected.Add(1)
+		log.Printf("webhook: rejected delivery from %s: missing or invalid signature (%d rejected)", r.RemoteAddr, n)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
 	var payload WebhookPayload
-	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+	if err := json.Unmarshal(body, &payload); err != nil {
+		ws.malformed.Add(1)
 		http.Error(w, "invalid payload", http.StatusBadRequest)
 		return
 	}
+	ws.accepted.Add(1)
 
 	// Process status change events
 	if payload.Event == "taskStatusUpdated" {
-		for _, item := range payload.HistoryItems {
+		for i, item := range payload.HistoryItems {
 			if item.Field == "status" {
 				event := TaskEvent{
-					TaskID:    payload.TaskID,
-					NewStatus: item.After.Status,
-					OldStatus: item.Before.Status,
+					TaskID:     payload.TaskID,
+					NewStatus:  item.After.Status,
+					OldStatus:  item.Before.Status,
+					DeliveryID: deliveryID(payload, i, body),
 				}
 
 				select {
@@ -109,3 +175,28 @@ func (ws *WebhookServer) handleWebhook(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// VerifySignature reports whether signature is the hex HMAC-SHA256 of body
+// keyed with secret. An empty secret verifies nothing.
+func VerifySignature(secret string, body []byte, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// deliveryID identifies a history item across ClickUp's retries: its ID, or
+// a hash of the body for payloads without one
+func deliveryID(payload WebhookPayload, index int, body []byte) string {
+	if id := payload.HistoryItems[index].ID; id != "" {
+		return id
+	}
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf("%x:%d", sum[:8], index)
+}
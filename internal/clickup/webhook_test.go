@@ -0,0 +1,70 @@
+package clickup
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const statusPayload = `{"event":"taskStatusUpdated","task_id":"86abc","webhook_id":"wh1",
+"history_items":[{"id":"h1","field":"status","before":{"status":"to do"},"after":{"status":"in progress"}}]}`
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(statusPayload)
+	sig := sign("s3cret", statusPayload)
+
+	assert.True(t, VerifySignature("s3cret", body, sig))
+	assert.True(t, VerifySignature("s3cret", body, strings.ToUpper(sig)))
+	assert.False(t, VerifySignature("other", body, sig))
+	assert.False(t, VerifySignature("s3cret", []byte(statusPayload+" "), sig), "body was tampered with")
+	assert.False(t, VerifySignature("s3cret", body, ""))
+	assert.False(t, VerifySignature("s3cret", body, "not-hex"))
+	assert.False(t, VerifySignature("", body, sign("", statusPayload)), "no secret, nothing verifies")
+}
+
+func TestHandleWebhook(t *testing.T) {
+	eventCh := make(chan TaskEvent, 10)
+	ws := NewWebhookServer(0, "", eventCh)
+
+	post := func(body, signature string) int {
+		req := httptest.NewRequest(http.MethodPost, "/clickup-webhook", strings.NewReader(body))
+		if signature != "" {
+			req.Header.Set("X-Signature", signature)
+		}
+		rec := httptest.NewRecorder()
+		ws.handleWebhook(rec, req)
+		return rec.Code
+	}
+
+	// Before registration there is no secret to verify against
+	assert.Equal(t, http.StatusUnauthorized, post(statusPayload, sign("", statusPayload)))
+
+	ws.SetSecret("s3cret")
+	assert.Equal(t, http.StatusUnauthorized, post(statusPayload, ""))
+	assert.Equal(t, http.StatusUnauthorized, post(statusPayload, sign("guess", statusPayload)))
+	assert.Empty(t, eventCh)
+
+	assert.Equal(t, http.StatusBadRequest, post("{", sign("s3cret", "{")))
+	assert.Equal(t, http.StatusOK, post(statusPayload, sign("s3cret", statusPayload)))
+
+	require.Len(t, eventCh, 1)
+	event := <-eventCh
+	assert.Equal(t, "86abc", event.TaskID)
+	assert.Equal(t, "in progress", event.NewStatus)
+	assert.Equal(t, "h1", event.DeliveryID)
+
+	assert.Equal(t, WebhookStats{Accepted: 1, Rejected: 3, Malformed: 1}, ws.Stats())
+}
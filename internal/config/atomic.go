@@ -0,0 +1,205 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// lockTimeout bounds how long a writer waits for another process's lock
+	lockTimeout = 10 * time.Second
+	// maxBackups is how many rolling backups of conductor.json are kept
+	maxBackups = 5
+	// backupInterval is the minimum age of the newest backup before another is taken
+	backupInterval = 10 * time.Minute
+)
+
+// WriteFileAtomic writes data to a temp file in the same directory, syncs it
+// and renames it over path, so readers never see a partially written file
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	cleanup := func() { _ = os.Remove(tmpPath) }
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		cleanup()
+		return err
+	}
+	return nil
+}
+
+// LockFile takes an exclusive lock on path, waiting up to timeout, and returns
+// the function that releases it. Used to keep other conductor processes out of
+// long-running work such as a database sync.
+func LockFile(path string, timeout time.Duration) (unlock func() error, err error) {
+	lock, err := lockFile(path, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return lock.unlock, nil
+}
+
+// UpdateConfigFile performs a locked read-modify-write of conductor.json.
+// fn receives the current file contents (nil if the file doesn't exist),
+// migrated to CurrentVersion, and returns the bytes to write. The write is atomic and a rolling backup of the
+// previous contents is kept in ~/.conductor/backups.
+func UpdateConfigFile(fn func(current []byte) ([]byte, error)) error {
+	dir, err := ConductorDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create conductor directory: %w", err)
+	}
+
+	path, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+
+	lock, err := lockFile(path+".lock", lockTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to lock config: %w", err)
+	}
+	defer func() { _ = lock.unlock() }()
+
+	current, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	// Upgrade files written by an older conductor so fn sees the current schema,
+	// and never overwrite one written by a newer conductor
+	original := current
+	var migrations []Migration
+	if current != nil {
+		if current, migrations, err = MigrateConfigData(current); err != nil {
+			return err
+		}
+	}
+
+	data, err := fn(current)
+	if err != nil {
+		return err
+	}
+
+	if len(migrations) > 0 {
+		if err := writeMigrationBackup(configFile, original, migrations[0].Version-1); err != nil {
+			return fmt.Errorf("failed to back up config before migrating: %w", err)
+		}
+	}
+	if original != nil {
+		// Backups are best-effort; a failed backup must not block the save
+		_ = backupConfig(dir, original)
+	}
+
+	if err := WriteFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// BackupDir returns the directory holding rolling conductor.json backups
+func BackupDir() (string, error) {
+	dir, err := ConductorDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "backups"), nil
+}
+
+// ListBackups returns the backup files, newest first
+func ListBackups() ([]string, error) {
+	dir, err := BackupDir()
+	if err != nil {
+		return nil, err
+	}
+	return listBackups(dir)
+}
+
+// backupConfig rotates conductor.json.1..N in the backups directory and writes
+// data as the newest backup, unless the newest backup is recent
+func backupConfig(conductorDir string, data []byte) error {
+	dir := filepath.Join(conductorDir, "backups")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	newest := filepath.Join(dir, configFile+".1")
+	if info, err := os.Stat(newest); err == nil && time.Since(info.ModTime()) < backupInterval {
+		return nil
+	}
+
+	for i := maxBackups - 1; i >= 1; i-- {
+		from := filepath.Join(dir, configFile+"."+strconv.Itoa(i))
+		to := filepath.Join(dir, configFile+"."+strconv.Itoa(i+1))
+		if _, err := os.Stat(from); err == nil {
+			if err := os.Rename(from, to); err != nil {
+				return err
+			}
+		}
+	}
+	return WriteFileAtomic(newest, data, 0644)
+}
+
+// listBackups returns the numbered backups in dir, newest (lowest number) first
+func listBackups(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	type backup struct {
+		n    int
+		path string
+	}
+	var backups []backup
+	for _, e := range entries {
+		suffix, ok := strings.CutPrefix(e.Name(), configFile+".")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(suffix)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{n, filepath.Join(dir, e.Name())})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].n < backups[j].n })
+
+	paths := make([]string, len(backups))
+	for i, b := range backups {
+		paths[i] = b.path
+	}
+	return paths, nil
+}
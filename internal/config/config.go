@@ -79,6 +79,40 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
+	// Files written by older versions are upgraded in memory; the next save
+	// writes the new schema (backing up the original first)
+	data, _, err = MigrateConfigData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+// Save writes the config to disk, replacing whatever is there.
+// The write is atomic and made under the config file lock.
+func Save(cfg *Config) error {
+	data, err := Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	return UpdateConfigFile(func([]byte) ([]byte, error) {
+		return data, nil
+	})
+}
+
+// Marshal encodes the config the way it is stored on disk
+func Marshal(cfg *Config) ([]byte, error) {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return data, nil
+}
+
+// Parse decodes config file contents, filling in defaults for missing sections
+func Parse(data []byte) (*Config, error) {
 	var cfg Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
@@ -100,34 +134,6 @@ func Load() (*Config, error) {
 	return &cfg, nil
 }
 
-// Save writes the config to disk
-func Save(cfg *Config) error {
-	dir, err := ConductorDir()
-	if err != nil {
-		return err
-	}
-
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create conductor directory: %w", err)
-	}
-
-	path, err := ConfigPath()
-	if err != nil {
-		return err
-	}
-
-	data, err := json.MarshalIndent(cfg, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
-	}
-
-	if err := os.WriteFile(path, data, 0644); err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
-	}
-
-	return nil
-}
-
 // Init creates a new config file with defaults
 func Init() error {
 	if Exists() {
@@ -150,6 +156,12 @@ func LoadProjectConfig(projectPath string) (*ProjectConfig, error) {
 		return nil, fmt.Errorf("failed to read project config: %w", err)
 	}
 
+	// Older files are upgraded in memory; 'conductor config migrate' rewrites them
+	data, _, err = MigrateProjectConfigData(data)
+	if err != nil {
+		return nil, err
+	}
+
 	var cfg ProjectConfig
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse project config: %w", err)
@@ -161,13 +173,14 @@ func LoadProjectConfig(projectPath string) (*ProjectConfig, error) {
 // SaveProjectConfig writes a project's conductor.json
 func SaveProjectConfig(projectPath string, cfg *ProjectConfig) error {
 	path := filepath.Join(projectPath, "conductor.json")
+	cfg.Version = CurrentProjectVersion
 
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal project config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := WriteFileAtomic(path, data, 0644); err != nil {
 		return fmt.Errorf("failed to write project config: %w", err)
 	}
 
@@ -13,7 +13,7 @@ import (
 func TestNewConfig(t *testing.T) {
 	cfg := NewConfig()
 
-	assert.Equal(t, 1, cfg.Version)
+	assert.Equal(t, CurrentVersion, cfg.Version)
 	assert.NotNil(t, cfg.Projects)
 	assert.NotNil(t, cfg.PortAllocations)
 	assert.Equal(t, 0, len(cfg.Projects))
@@ -168,3 +168,45 @@ func TestNewWorktree_Root(t *testing.T) {
 	assert.True(t, wt.IsRoot)
 	assert.Equal(t, []int{3100}, wt.Ports)
 }
+
+func TestDefaults_LocalDatabaseURL(t *testing.T) {
+	defaults := Defaults{
+		LocalPostgresURL: "postgresql://localhost/postgres",
+		LocalMySQLURL:    "mysql://root@localhost/",
+	}
+
+	assert.Equal(t, defaults.LocalPostgresURL, defaults.LocalDatabaseURL(nil))
+	assert.Equal(t, defaults.LocalPostgresURL, defaults.LocalDatabaseURL(&DatabaseConfig{}))
+	assert.Equal(t, defaults.LocalMySQLURL, defaults.LocalDatabaseURL(&DatabaseConfig{Driver: DatabaseDriverMySQL}))
+}
+
+func TestProjectConfig_Tracker(t *testing.T) {
+	project := &Project{GitHubOwner: "acme", GitHubRepo: "web"}
+
+	assert.Nil(t, (&ProjectConfig{}).Tracker(project))
+	assert.Nil(t, (&ProjectConfig{GitHubIssues: &ProjectGitHubIssuesConfig{}}).Tracker(&Project{}), "no repo to watch")
+
+	clickup := (&ProjectConfig{ClickUp: &ProjectClickUpConfig{ListID: "901", Mode: AgentModeSequential}}).Tracker(project)
+	require.NotNil(t, clickup)
+	assert.Equal(t, TrackerClickUp, clickup.Provider)
+	assert.Equal(t, "901", clickup.ListID)
+	assert.Equal(t, "in progress", clickup.TriggerStatus)
+	assert.Equal(t, "to do", clickup.ReadyStatus)
+	assert.Equal(t, "done", clickup.DoneStatus)
+	assert.Equal(t, AgentModeSequential, clickup.Mode)
+
+	gh := (&ProjectConfig{GitHubIssues: &ProjectGitHubIssuesConfig{AutoPick: true, MaxConcurrentAgents: 2}}).Tracker(project)
+	require.NotNil(t, gh)
+	assert.Equal(t, TrackerGitHub, gh.Provider)
+	assert.Equal(t, "acme/web", gh.ListID)
+	assert.Equal(t, "conductor", gh.TriggerStatus)
+	assert.Equal(t, "ready", gh.ReadyStatus)
+	assert.Equal(t, "closed", gh.DoneStatus)
+	assert.Equal(t, AgentModeParallel, gh.Mode)
+	assert.True(t, gh.AutoPick)
+	assert.Equal(t, 2, gh.MaxConcurrentAgents)
+
+	gh = (&ProjectConfig{GitHubIssues: &ProjectGitHubIssuesConfig{Repo: "acme/api", TriggerLabel: "agent"}}).Tracker(project)
+	assert.Equal(t, "acme/api", gh.ListID)
+	assert.Equal(t, "agent", gh.TriggerStatus)
+}
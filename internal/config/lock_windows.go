@@ -0,0 +1,47 @@
+//go:build windows
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// staleLockAge is how old a lock file must be before it is assumed abandoned
+const staleLockAge = 30 * time.Second
+
+// fileLock is a lock file created exclusively (Windows has no flock)
+type fileLock struct {
+	path string
+}
+
+// lockFile takes an exclusive lock on path, waiting up to timeout
+func lockFile(path string, timeout time.Duration) (*fileLock, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, _ = fmt.Fprintf(f, "%d\n", os.Getpid())
+			_ = f.Close()
+			return &fileLock{path: path}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		// Remove locks left behind by a crashed process
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for %s", path)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// unlock releases the lock
+func (l *fileLock) unlock() error {
+	return os.Remove(l.path)
+}
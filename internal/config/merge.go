@@ -0,0 +1,180 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// absent marks a key missing from one side of a three-way merge
+type absent struct{}
+
+// MergeResult is the outcome of MergeConfig
+type MergeResult struct {
+	Data []byte
+	// Conflicts lists the JSON paths changed differently on both sides;
+	// ours was kept for each of them
+	Conflicts []string
+	// PortConflicts lists the ports both sides allocated, to different
+	// worktrees. Keeping ours would give a port to two worktrees.
+	PortConflicts []int
+	// WorktreeConflicts lists the worktrees ("project/worktree") both sides
+	// created independently
+	WorktreeConflicts []string
+}
+
+// MergeConfig three-way merges two diverged versions of conductor.json.
+// base is the content both sides started from, theirs is what another process
+// wrote to disk and ours is this process's version. Objects are merged key by
+// key; any other value (including arrays such as a worktree's ports) is taken
+// from whichever side changed it, with ours winning when both did.
+func MergeConfig(base, theirs, ours []byte) (*MergeResult, error) {
+	var b, t, o any
+	if err := decodeJSON(base, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse base config: %w", err)
+	}
+	if err := decodeJSON(theirs, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse on-disk config: %w", err)
+	}
+	if err := decodeJSON(ours, &o); err != nil {
+		return nil, fmt.Errorf("failed to parse in-memory config: %w", err)
+	}
+
+	result := &MergeResult{}
+	var conflicts [][]string
+	merged := merge3(b, t, o, nil, &conflicts)
+	for _, path := range conflicts {
+		result.Conflicts = append(result.Conflicts, "."+strings.Join(path, "."))
+	}
+	result.PortConflicts, result.WorktreeConflicts = ownershipConflicts(conflicts, b, t, o)
+
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal merged config: %w", err)
+	}
+
+	// Round-trip through Config so the result has the canonical field order
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if result.Data, err = Marshal(cfg); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SameConfig reports whether two encodings hold the same configuration
+func SameConfig(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var x, y any
+	if decodeJSON(a, &x) != nil || decodeJSON(b, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+func decodeJSON(data []byte, v *any) error {
+	if len(data) == 0 {
+		*v = map[string]any{}
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func merge3(base, theirs, ours any, path []string, conflicts *[][]string) any {
+	if reflect.DeepEqual(ours, base) {
+		return theirs
+	}
+	if reflect.DeepEqual(theirs, base) || reflect.DeepEqual(theirs, ours) {
+		return ours
+	}
+
+	t, tok := theirs.(map[string]any)
+	o, ook := ours.(map[string]any)
+	if tok && ook {
+		b, _ := base.(map[string]any)
+		merged := make(map[string]any, len(o))
+		keys := make(map[string]bool, len(t)+len(o))
+		for k := range t {
+			keys[k] = true
+		}
+		for k := range o {
+			keys[k] = true
+		}
+		for k := range keys {
+			v := merge3(lookup(b, k), lookup(t, k), lookup(o, k), append(path[:len(path):len(path)], k), conflicts)
+			if _, gone := v.(absent); !gone {
+				merged[k] = v
+			}
+		}
+		return merged
+	}
+
+	*conflicts = append(*conflicts, path)
+	return ours
+}
+
+// ownershipConflicts picks out the conflicts that keeping ours can't settle:
+// ports allocated on both sides, and worktrees created on both sides
+func ownershipConflicts(conflicts [][]string, base, theirs, ours any) ([]int, []string) {
+	var ports []int
+	var worktrees []string
+	seenPorts := make(map[int]bool)
+	seenWorktrees := make(map[string]bool)
+	for _, path := range conflicts {
+		switch {
+		case len(path) >= 2 && path[0] == "portAllocations":
+			port, err := strconv.Atoi(path[1])
+			if err != nil || seenPorts[port] {
+				continue
+			}
+			// A port freed on one side and reassigned on the other is not shared
+			if !present(theirs, path[:2]...) || !present(ours, path[:2]...) {
+				continue
+			}
+			seenPorts[port] = true
+			ports = append(ports, port)
+
+		case len(path) >= 4 && path[0] == "projects" && path[2] == "worktrees":
+			key := path[1] + "/" + path[3]
+			if seenWorktrees[key] || present(base, path[:4]...) {
+				continue
+			}
+			seenWorktrees[key] = true
+			worktrees = append(worktrees, key)
+		}
+	}
+	sort.Ints(ports)
+	sort.Strings(worktrees)
+	return ports, worktrees
+}
+
+// present reports whether the object path exists in a decoded JSON value
+func present(v any, path ...string) bool {
+	for _, k := range path {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return false
+		}
+		if v, ok = m[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func lookup(m map[string]any, key string) any {
+	if v, ok := m[key]; ok {
+		return v
+	}
+	return absent{}
+}
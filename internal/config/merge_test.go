@@ -0,0 +1,146 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustMarshal(t *testing.T, cfg *Config) []byte {
+	t.Helper()
+	data, err := Marshal(cfg)
+	require.NoError(t, err)
+	return data
+}
+
+func TestMergeConfig_KeepsBothSidesChanges(t *testing.T) {
+	base := NewConfig()
+	base.Projects["app"] = NewProject("/app", 1)
+
+	theirs, err := Parse(mustMarshal(t, base))
+	require.NoError(t, err)
+	theirs.Projects["app"].Worktrees["tokyo"] = NewWorktree("/wt/tokyo", "tokyo", false, []int{3100})
+	theirs.PortAllocations["3100"] = &PortAlloc{Project: "app", Worktree: "tokyo"}
+
+	ours, err := Parse(mustMarshal(t, base))
+	require.NoError(t, err)
+	ours.Projects["app"].Worktrees["paris"] = NewWorktree("/wt/paris", "paris", false, []int{3101})
+	ours.PortAllocations["3101"] = &PortAlloc{Project: "app", Worktree: "paris"}
+	ours.Defaults.OpenWith = "terminal"
+
+	result, err := MergeConfig(mustMarshal(t, base), mustMarshal(t, theirs), mustMarshal(t, ours))
+	require.NoError(t, err)
+	assert.Empty(t, result.Conflicts)
+
+	merged, err := Parse(result.Data)
+	require.NoError(t, err)
+	assert.Contains(t, merged.Projects["app"].Worktrees, "tokyo")
+	assert.Contains(t, merged.Projects["app"].Worktrees, "paris")
+	assert.Len(t, merged.PortAllocations, 2)
+	assert.Equal(t, "terminal", merged.Defaults.OpenWith)
+}
+
+func TestMergeConfig_DeletionOnOneSide(t *testing.T) {
+	base := NewConfig()
+	base.Projects["app"] = NewProject("/app", 1)
+	base.Projects["old"] = NewProject("/old", 1)
+
+	theirs, err := Parse(mustMarshal(t, base))
+	require.NoError(t, err)
+	delete(theirs.Projects, "old")
+
+	ours, err := Parse(mustMarshal(t, base))
+	require.NoError(t, err)
+	ours.Projects["app"].DefaultPortsPerWorktree = 3
+
+	result, err := MergeConfig(mustMarshal(t, base), mustMarshal(t, theirs), mustMarshal(t, ours))
+	require.NoError(t, err)
+
+	merged, err := Parse(result.Data)
+	require.NoError(t, err)
+	assert.NotContains(t, merged.Projects, "old")
+	assert.Equal(t, 3, merged.Projects["app"].DefaultPortsPerWorktree)
+}
+
+func TestMergeConfig_ConflictKeepsOurs(t *testing.T) {
+	base := NewConfig()
+
+	theirs := NewConfig()
+	theirs.Defaults.OpenWith = "vscode"
+	ours := NewConfig()
+	ours.Defaults.OpenWith = "terminal"
+
+	result, err := MergeConfig(mustMarshal(t, base), mustMarshal(t, theirs), mustMarshal(t, ours))
+	require.NoError(t, err)
+	assert.Equal(t, []string{".defaults.openWith"}, result.Conflicts)
+
+	merged, err := Parse(result.Data)
+	require.NoError(t, err)
+	assert.Equal(t, "terminal", merged.Defaults.OpenWith)
+}
+
+func TestMergeConfig_ReportsOwnershipConflicts(t *testing.T) {
+	base := NewConfig()
+	base.Projects["app"] = NewProject("/app", 1)
+	base.Projects["app"].Worktrees["rome"] = NewWorktree("/wt/rome", "rome", false, nil)
+
+	// Both sides allocate port 3100 to different worktrees and create "oslo"
+	theirs, err := Parse(mustMarshal(t, base))
+	require.NoError(t, err)
+	theirs.Projects["app"].Worktrees["tokyo"] = NewWorktree("/wt/tokyo", "tokyo", false, []int{3100})
+	theirs.PortAllocations["3100"] = &PortAlloc{Project: "app", Worktree: "tokyo"}
+	theirs.Projects["app"].Worktrees["oslo"] = NewWorktree("/wt/oslo", "main", false, nil)
+	theirs.Projects["app"].Worktrees["rome"].Branch = "theirs"
+
+	ours, err := Parse(mustMarshal(t, base))
+	require.NoError(t, err)
+	ours.Projects["app"].Worktrees["paris"] = NewWorktree("/wt/paris", "paris", false, []int{3100})
+	ours.PortAllocations["3100"] = &PortAlloc{Project: "app", Worktree: "paris"}
+	ours.Projects["app"].Worktrees["oslo"] = NewWorktree("/wt/oslo", "feature", false, nil)
+	ours.Projects["app"].Worktrees["rome"].Branch = "ours"
+
+	result, err := MergeConfig(mustMarshal(t, base), mustMarshal(t, theirs), mustMarshal(t, ours))
+	require.NoError(t, err)
+	assert.Equal(t, []int{3100}, result.PortConflicts)
+	// An existing worktree changed on both sides is an ordinary conflict
+	assert.Equal(t, []string{"app/oslo"}, result.WorktreeConflicts)
+	assert.Contains(t, result.Conflicts, ".projects.app.worktrees.rome.branch")
+}
+
+func TestUpdateConfigFile_AtomicWriteAndBackups(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CONDUCTOR_CONFIG_DIR", dir)
+
+	require.NoError(t, Save(NewConfig()))
+	backups, err := ListBackups()
+	require.NoError(t, err)
+	assert.Empty(t, backups, "nothing to back up on first write")
+
+	cfg := NewConfig()
+	cfg.Defaults.OpenWith = "terminal"
+	require.NoError(t, Save(cfg))
+
+	loaded, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "terminal", loaded.Defaults.OpenWith)
+
+	backups, err = ListBackups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	prev, err := os.ReadFile(backups[0])
+	require.NoError(t, err)
+	old, err := Parse(prev)
+	require.NoError(t, err)
+	assert.Equal(t, "iterm", old.Defaults.OpenWith)
+
+	// No temp files are left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.NotContains(t, e.Name(), ".tmp-")
+	}
+	assert.FileExists(t, filepath.Join(dir, "conductor.json.lock"))
+}
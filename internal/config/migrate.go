@@ -0,0 +1,224 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Migration upgrades a config document by one schema version.
+// Migrations operate on the raw JSON so they can read fields that no longer
+// exist in the Go types.
+type Migration struct {
+	// Version is the schema version the migration upgrades to
+	Version     int
+	Description string
+	Apply       func(doc map[string]any) error
+}
+
+// configMigrations upgrade ~/.conductor/conductor.json, in order
+var configMigrations = []Migration{
+	{
+		Version:     2,
+		Description: "remove deprecated Cloudflare credentials from defaults.tunnel (cloudflared tunnel login is used instead)",
+		Apply: func(doc map[string]any) error {
+			defaults, _ := doc["defaults"].(map[string]any)
+			tunnel, _ := defaults["tunnel"].(map[string]any)
+			for _, key := range []string{"cloudflareToken", "accountId", "zoneId"} {
+				delete(tunnel, key)
+			}
+			return nil
+		},
+	},
+	{
+		Version:     3,
+		Description: "move worktree ClickUp task links (clickupTaskId, clickupTaskUrl) to tracker-neutral taskProvider, taskId and taskUrl",
+		Apply: func(doc map[string]any) error {
+			projects, _ := doc["projects"].(map[string]any)
+			for _, p := range projects {
+				project, _ := p.(map[string]any)
+				worktrees, _ := project["worktrees"].(map[string]any)
+				for _, w := range worktrees {
+					wt, _ := w.(map[string]any)
+					if wt == nil {
+						continue
+					}
+					if id, _ := wt["clickupTaskId"].(string); id != "" {
+						wt["taskProvider"] = TrackerClickUp
+						wt["taskId"] = id
+						if url, _ := wt["clickupTaskUrl"].(string); url != "" {
+							wt["taskUrl"] = url
+						}
+					}
+					delete(wt, "clickupTaskId")
+					delete(wt, "clickupTaskUrl")
+				}
+			}
+			return nil
+		},
+	},
+}
+
+// projectConfigMigrations upgrade a project's committed conductor.json, in order
+var projectConfigMigrations = []Migration{
+	{
+		Version:     1,
+		Description: "drop per-machine fields from tooling (detectedAt, proofShotReady, trustLayerInit are tracked in ~/.conductor/conductor.json)",
+		Apply: func(doc map[string]any) error {
+			tooling, _ := doc["tooling"].(map[string]any)
+			for _, key := range []string{"detectedAt", "proofShotReady", "trustLayerInit"} {
+				delete(tooling, key)
+			}
+			return nil
+		},
+	},
+}
+
+// CurrentVersion is the conductor.json schema version this build writes
+var CurrentVersion = configMigrations[len(configMigrations)-1].Version
+
+// CurrentProjectVersion is the project conductor.json schema version this build writes
+var CurrentProjectVersion = projectConfigMigrations[len(projectConfigMigrations)-1].Version
+
+// NewerVersionError is returned for files written by a newer conductor
+type NewerVersionError struct {
+	File      string // "config" or "project config"
+	Version   int
+	Supported int
+}
+
+func (e *NewerVersionError) Error() string {
+	return fmt.Sprintf("%s has schema version %d but this conductor supports up to %d; upgrade conductor",
+		e.File, e.Version, e.Supported)
+}
+
+// MigrateConfigData upgrades conductor.json contents to CurrentVersion.
+// Returns the migrated data and the migrations applied (none if up to date).
+func MigrateConfigData(data []byte) ([]byte, []Migration, error) {
+	// Files from before versioning are version 1
+	out, applied, err := migrateData(data, configMigrations, 1, "config")
+	if err != nil || len(applied) == 0 {
+		return data, applied, err
+	}
+
+	// Canonical field order
+	cfg, err := Parse(out)
+	if err != nil {
+		return nil, nil, err
+	}
+	out, err = Marshal(cfg)
+	return out, applied, err
+}
+
+// MigrateProjectConfigData upgrades a project conductor.json to CurrentProjectVersion.
+// Unknown keys are preserved since the file is shared through the repo.
+func MigrateProjectConfigData(data []byte) ([]byte, []Migration, error) {
+	return migrateData(data, projectConfigMigrations, 0, "project config")
+}
+
+// migrateData applies every migration newer than the document's version
+func migrateData(data []byte, migrations []Migration, defaultVersion int, name string) ([]byte, []Migration, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	version := defaultVersion
+	if v, ok := doc["version"].(json.Number); ok {
+		n, err := v.Int64()
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid version in %s: %s", name, v)
+		}
+		version = int(n)
+	}
+
+	current := migrations[len(migrations)-1].Version
+	if version > current {
+		return nil, nil, &NewerVersionError{File: name, Version: version, Supported: current}
+	}
+
+	var applied []Migration
+	for _, m := range migrations {
+		if m.Version <= version {
+			continue
+		}
+		if err := m.Apply(doc); err != nil {
+			return nil, nil, fmt.Errorf("migration to version %d failed: %w", m.Version, err)
+		}
+		doc["version"] = m.Version
+		applied = append(applied, m)
+	}
+	if len(applied) == 0 {
+		return data, nil, nil
+	}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, applied, nil
+}
+
+// MigrateConfigFile upgrades conductor.json on disk, keeping a copy of the old
+// file in ~/.conductor/backups/conductor.json.v<old>. Returns the migrations applied.
+// Any save upgrades the file the same way; this just forces it.
+func MigrateConfigFile() ([]Migration, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	_, applied, err := MigrateConfigData(data)
+	if err != nil || len(applied) == 0 {
+		return nil, err
+	}
+
+	err = UpdateConfigFile(func(current []byte) ([]byte, error) {
+		return current, nil
+	})
+	return applied, err
+}
+
+// MigrateProjectConfigFile upgrades a project's conductor.json in place, keeping
+// a copy of the old file in ~/.conductor/backups. Returns the migrations applied.
+func MigrateProjectConfigFile(projectName, projectPath string) ([]Migration, error) {
+	path := filepath.Join(projectPath, "conductor.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	migrated, applied, err := MigrateProjectConfigData(data)
+	if err != nil || len(applied) == 0 {
+		return nil, err
+	}
+	if err := writeMigrationBackup(projectName+"."+configFile, data, applied[0].Version-1); err != nil {
+		return nil, fmt.Errorf("failed to back up project config before migrating: %w", err)
+	}
+	if err := WriteFileAtomic(path, migrated, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write project config: %w", err)
+	}
+	return applied, nil
+}
+
+// writeMigrationBackup keeps the pre-migration contents as <name>.v<version>
+func writeMigrationBackup(name string, data []byte, version int) error {
+	dir, err := BackupDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return WriteFileAtomic(filepath.Join(dir, fmt.Sprintf("%s.v%d", name, version)), data, 0644)
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const legacyConfig = `{
+  "version": 1,
+  "defaults": {
+    "portRangeStart": 3100,
+    "portRangeEnd": 3999,
+    "portsPerWorktree": 1,
+    "tunnel": {
+      "domain": "example.com",
+      "cloudflareToken": "secret",
+      "accountId": "acct",
+      "zoneId": "zone"
+    }
+  },
+  "projects": {},
+  "portAllocations": {}
+}`
+
+func TestMigrateConfigData_StripsTunnelCredentials(t *testing.T) {
+	out, applied, err := MigrateConfigData([]byte(legacyConfig))
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	assert.Equal(t, 2, applied[0].Version)
+
+	assert.NotContains(t, string(out), "cloudflareToken")
+	assert.NotContains(t, string(out), "zoneId")
+
+	cfg, err := Parse(out)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion, cfg.Version)
+	assert.Equal(t, "example.com", cfg.Defaults.Tunnel.Domain)
+}
+
+func TestMigrateConfigData_MovesClickUpTaskLinks(t *testing.T) {
+	legacy := `{
+  "version": 2,
+  "defaults": {"portRangeStart": 3100, "portRangeEnd": 3999, "portsPerWorktree": 1},
+  "projects": {
+    "app": {
+      "path": "/src/app",
+      "worktrees": {
+        "tokyo": {"path": "/wt/tokyo", "branch": "feature/86abc-login", "clickupTaskId": "86abc", "clickupTaskUrl": "https://app.clickup.com/t/86abc"},
+        "paris": {"path": "/wt/paris", "branch": "fix"}
+      }
+    }
+  },
+  "portAllocations": {}
+}`
+	out, applied, err := MigrateConfigData([]byte(legacy))
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, 3, applied[0].Version)
+	assert.NotContains(t, string(out), "clickupTask")
+
+	cfg, err := Parse(out)
+	require.NoError(t, err)
+	tokyo := cfg.Projects["app"].Worktrees["tokyo"]
+	assert.Equal(t, TrackerClickUp, tokyo.TaskProvider)
+	assert.Equal(t, "86abc", tokyo.TaskID)
+	assert.Equal(t, "https://app.clickup.com/t/86abc", tokyo.TaskURL)
+	assert.Empty(t, cfg.Projects["app"].Worktrees["paris"].TaskProvider)
+}
+
+func TestMigrateConfigData_UpToDate(t *testing.T) {
+	data, err := Marshal(NewConfig())
+	require.NoError(t, err)
+
+	out, applied, err := MigrateConfigData(data)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.Equal(t, data, out)
+}
+
+func TestMigrateConfigData_RefusesNewerVersion(t *testing.T) {
+	_, _, err := MigrateConfigData([]byte(`{"version": 999}`))
+
+	var newer *NewerVersionError
+	require.ErrorAs(t, err, &newer)
+	assert.Equal(t, 999, newer.Version)
+	assert.Equal(t, CurrentVersion, newer.Supported)
+}
+
+func TestLoad_MigratesInMemoryAndSaveBacksUp(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CONDUCTOR_CONFIG_DIR", dir)
+	path := filepath.Join(dir, configFile)
+	require.NoError(t, os.WriteFile(path, []byte(legacyConfig), 0644))
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion, cfg.Version)
+
+	// Loading alone leaves the file untouched
+	onDisk, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, legacyConfig, string(onDisk))
+
+	require.NoError(t, Save(cfg))
+
+	onDisk, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(onDisk), "cloudflareToken")
+
+	backup, err := os.ReadFile(filepath.Join(dir, "backups", configFile+".v1"))
+	require.NoError(t, err)
+	assert.Equal(t, legacyConfig, string(backup))
+}
+
+func TestMigrateConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CONDUCTOR_CONFIG_DIR", dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, configFile), []byte(legacyConfig), 0644))
+
+	applied, err := MigrateConfigFile()
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+
+	applied, err = MigrateConfigFile()
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+func TestSave_RefusesToOverwriteNewerVersion(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CONDUCTOR_CONFIG_DIR", dir)
+	newer := []byte(`{"version": 999}`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, configFile), newer, 0644))
+
+	_, err := Load()
+	require.Error(t, err)
+
+	err = Save(NewConfig())
+	var newerErr *NewerVersionError
+	require.ErrorAs(t, err, &newerErr)
+
+	onDisk, err := os.ReadFile(filepath.Join(dir, configFile))
+	require.NoError(t, err)
+	assert.Equal(t, newer, onDisk)
+}
+
+func TestMigrateProjectConfigFile(t *testing.T) {
+	t.Setenv("CONDUCTOR_CONFIG_DIR", t.TempDir())
+	projectDir := t.TempDir()
+	legacy := `{
+  "scripts": {"setup": "make"},
+  "tooling": {"framework": "nextjs", "detectedAt": "2024-01-01T00:00:00Z", "proofShotReady": true},
+  "custom": "kept"
+}`
+	path := filepath.Join(projectDir, "conductor.json")
+	require.NoError(t, os.WriteFile(path, []byte(legacy), 0644))
+
+	// Loading migrates in memory only
+	loaded, err := LoadProjectConfig(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentProjectVersion, loaded.Version)
+	onDisk, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, legacy, string(onDisk))
+
+	applied, err := MigrateProjectConfigFile("app", projectDir)
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+
+	onDisk, err = os.ReadFile(path)
+	require.NoError(t, err)
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(onDisk, &doc))
+	assert.Equal(t, "kept", doc["custom"])
+	assert.Equal(t, map[string]any{"framework": "nextjs"}, doc["tooling"])
+
+	backupDir, err := BackupDir()
+	require.NoError(t, err)
+	backup, err := os.ReadFile(filepath.Join(backupDir, "app.conductor.json.v0"))
+	require.NoError(t, err)
+	assert.Equal(t, legacy, string(backup))
+
+	// Running again is a no-op
+	applied, err = MigrateProjectConfigFile("app", projectDir)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
@@ -4,25 +4,117 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+
+	"github.com/hammashamzah/conductor/internal/portprobe"
 )
 
-// AllocatePorts finds and allocates N consecutive free ports
+// portInUse probes the host for a listener on a port (replaced in tests)
+var portInUse = portprobe.InUse
+
+// AllocatePorts finds and allocates N consecutive free ports.
+// Projects with a reserved PortRange allocate inside it; other projects
+// allocate from the global range, skipping every project's reserved range.
 func (c *Config) AllocatePorts(project, worktree string, count int) ([]int, error) {
 	if count <= 0 {
 		return nil, fmt.Errorf("port count must be positive")
 	}
 
-	// Get all used ports as a sorted slice
-	usedPorts := c.getUsedPorts()
+	start, end := c.PortRangeFor(project)
 
 	// Find first gap of `count` consecutive ports
-	ports := c.findConsecutivePorts(usedPorts, count)
+	ports := c.findConsecutivePortsIn(start, end, count, func(port int) bool {
+		_, used := c.PortAllocations[strconv.Itoa(port)]
+		return used || !c.PortAllowed(project, port)
+	})
 	if ports == nil {
 		return nil, fmt.Errorf("no %d consecutive ports available in range %d-%d",
-			count, c.Defaults.PortRangeStart, c.Defaults.PortRangeEnd)
+			count, start, end)
+	}
+
+	c.assignPorts(project, worktree, ports)
+	return ports, nil
+}
+
+// AllocateWorktreePorts allocates ports for a worktree on the given branch.
+// When the project has StickyPorts enabled, the block last handed to the
+// branch (to the worktree name if branch is empty) is reused if it is still
+// free, so URLs such as OAuth redirects survive archiving and restoring the
+// worktree or creating another one for the branch.
+func (c *Config) AllocateWorktreePorts(project, worktree, branch string, count int) ([]int, error) {
+	proj, ok := c.Projects[project]
+	if !ok || !proj.StickyPorts {
+		return c.AllocatePorts(project, worktree, count)
+	}
+
+	key := portHistoryKey(worktree, branch)
+	ports := c.previousPortBlock(proj, project, key, count)
+	if ports != nil {
+		c.assignPorts(project, worktree, ports)
+	} else {
+		var err error
+		if ports, err = c.AllocatePorts(project, worktree, count); err != nil {
+			return nil, err
+		}
+	}
+
+	proj.PrunePortHistory()
+	if proj.PortHistory == nil {
+		proj.PortHistory = make(map[string][]int)
+	}
+	proj.PortHistory[key] = append([]int(nil), ports...)
+
+	return ports, nil
+}
+
+// PrunePortHistory forgets the port blocks of branches that no worktree of
+// the project (active or archived) is on any more
+func (p *Project) PrunePortHistory() {
+	if len(p.PortHistory) == 0 {
+		return
+	}
+	live := make(map[string]bool, len(p.Worktrees))
+	for name, wt := range p.Worktrees {
+		live[portHistoryKey(name, wt.Branch)] = true
+	}
+	for key := range p.PortHistory {
+		if !live[key] {
+			delete(p.PortHistory, key)
+		}
+	}
+	if len(p.PortHistory) == 0 {
+		p.PortHistory = nil
 	}
+}
+
+// portHistoryKey is the PortHistory key of a worktree: its branch, or its
+// name if it has none
+func portHistoryKey(worktree, branch string) string {
+	if branch == "" {
+		return worktree
+	}
+	return branch
+}
 
-	// Allocate the ports
+// previousPortBlock returns the block remembered under key if all of its
+// ports are still free
+func (c *Config) previousPortBlock(proj *Project, project, key string, count int) []int {
+	prev := proj.PortHistory[key]
+	if len(prev) == 0 {
+		return nil
+	}
+	ports := make([]int, count)
+	for i := range ports {
+		port := prev[0] + i
+		if _, used := c.PortAllocations[strconv.Itoa(port)]; used || !c.PortAllowed(project, port) || portInUse(port) {
+			return nil
+		}
+		ports[i] = port
+	}
+	return ports
+}
+
+// assignPorts records the allocation of ports to a worktree
+func (c *Config) assignPorts(project, worktree string, ports []int) {
 	for i, port := range ports {
 		c.PortAllocations[strconv.Itoa(port)] = &PortAlloc{
 			Project:  project,
@@ -30,8 +122,30 @@ func (c *Config) AllocatePorts(project, worktree string, count int) ([]int, erro
 			Index:    i,
 		}
 	}
+}
+
+// PortRangeFor returns the range a project allocates from: its reserved
+// PortRange if set, the global range otherwise
+func (c *Config) PortRangeFor(project string) (start, end int) {
+	if proj, ok := c.Projects[project]; ok && proj.PortRange != nil {
+		return proj.PortRange.Start, proj.PortRange.End
+	}
+	return c.Defaults.PortRangeStart, c.Defaults.PortRangeEnd
+}
 
-	return ports, nil
+// PortAllowed reports whether a port may be allocated to a project: it must be
+// inside the project's range and not inside another project's reserved range
+func (c *Config) PortAllowed(project string, port int) bool {
+	start, end := c.PortRangeFor(project)
+	if port < start || port > end {
+		return false
+	}
+	for name, proj := range c.Projects {
+		if name != project && proj.PortRange.Contains(port) {
+			return false
+		}
+	}
+	return true
 }
 
 // FreePorts removes port allocations
@@ -102,22 +216,28 @@ func (c *Config) getUsedPorts() []int {
 	return ports
 }
 
-// findConsecutivePorts finds the first gap of N consecutive ports
+// findConsecutivePorts finds the first gap of N consecutive ports in the global
+// range that are neither in usedPorts nor already bound on the host
 func (c *Config) findConsecutivePorts(usedPorts []int, count int) []int {
-	start := c.Defaults.PortRangeStart
-	end := c.Defaults.PortRangeEnd
-
 	// Create a set for O(1) lookup
 	usedSet := make(map[int]bool)
 	for _, p := range usedPorts {
 		usedSet[p] = true
 	}
 
+	return c.findConsecutivePortsIn(c.Defaults.PortRangeStart, c.Defaults.PortRangeEnd, count, func(port int) bool {
+		return usedSet[port]
+	})
+}
+
+// findConsecutivePortsIn finds the first gap of N consecutive ports in start-end
+// for which taken returns false and that are not already bound on the host
+func (c *Config) findConsecutivePortsIn(start, end, count int, taken func(int) bool) []int {
 	// Scan for consecutive free ports
 	for port := start; port <= end-count+1; port++ {
 		found := true
 		for i := 0; i < count; i++ {
-			if usedSet[port+i] {
+			if taken(port+i) || portInUse(port+i) {
 				found = false
 				// Skip to after the used port
 				port = port + i
@@ -0,0 +1,296 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// PortIssueKind classifies an inconsistency found by DiagnosePorts
+type PortIssueKind string
+
+const (
+	// PortIssueOrphaned is an allocation whose project or worktree no longer exists (or is archived)
+	PortIssueOrphaned PortIssueKind = "orphaned"
+	// PortIssueMissingWorktree is a worktree whose directory was removed outside conductor
+	PortIssueMissingWorktree PortIssueKind = "missing-worktree"
+	// PortIssueDuplicateIndex is two allocations with the same index for one worktree
+	PortIssueDuplicateIndex PortIssueKind = "duplicate-index"
+	// PortIssueMismatch is a worktree port that is not allocated to it, or vice versa
+	PortIssueMismatch PortIssueKind = "mismatch"
+	// PortIssueConflict is a port listed by more than one worktree
+	PortIssueConflict PortIssueKind = "conflict"
+	// PortIssueOutOfRange is a port outside its project's allowed range
+	PortIssueOutOfRange PortIssueKind = "out-of-range"
+)
+
+// PortIssue describes one inconsistency between PortAllocations, worktree
+// port lists and the worktrees on disk
+type PortIssue struct {
+	Kind     PortIssueKind
+	Port     int // 0 when the issue concerns a whole worktree
+	Project  string
+	Worktree string
+	Detail   string
+}
+
+// DiagnosePorts cross-checks PortAllocations against each worktree's Ports
+// and the worktree directories on disk. Issues are sorted by port.
+func (c *Config) DiagnosePorts() []PortIssue {
+	var issues []PortIssue
+
+	// Worktrees removed by hand
+	for _, key := range c.sortedWorktreeKeys() {
+		wt := c.Projects[key.project].Worktrees[key.worktree]
+		if worktreeMissingOnDisk(wt) {
+			issues = append(issues, PortIssue{
+				Kind:     PortIssueMissingWorktree,
+				Project:  key.project,
+				Worktree: key.worktree,
+				Detail:   fmt.Sprintf("directory %s no longer exists", wt.Path),
+			})
+		}
+	}
+
+	// Allocations that don't match a live worktree
+	type indexKey struct {
+		project, worktree string
+		index             int
+	}
+	seenIndex := make(map[indexKey]int)
+	for _, port := range c.getUsedPorts() {
+		alloc := c.PortAllocations[strconv.Itoa(port)]
+		if !c.PortAllowed(alloc.Project, port) {
+			issues = append(issues, c.outOfRangeIssue(alloc.Project, alloc.Worktree, port))
+		}
+
+		wt := c.liveWorktree(alloc.Project, alloc.Worktree)
+		if wt == nil {
+			issues = append(issues, PortIssue{
+				Kind: PortIssueOrphaned, Port: port, Project: alloc.Project, Worktree: alloc.Worktree,
+				Detail: "allocated to a worktree that no longer exists",
+			})
+			continue
+		}
+
+		k := indexKey{alloc.Project, alloc.Worktree, alloc.Index}
+		if other, dup := seenIndex[k]; dup {
+			issues = append(issues, PortIssue{
+				Kind: PortIssueDuplicateIndex, Port: port, Project: alloc.Project, Worktree: alloc.Worktree,
+				Detail: fmt.Sprintf("index %d is also allocated to port %d", alloc.Index, other),
+			})
+		} else {
+			seenIndex[k] = port
+		}
+
+		if alloc.Index >= len(wt.Ports) || wt.Ports[alloc.Index] != port {
+			issues = append(issues, PortIssue{
+				Kind: PortIssueMismatch, Port: port, Project: alloc.Project, Worktree: alloc.Worktree,
+				Detail: fmt.Sprintf("allocated as index %d but not in the worktree's ports %v", alloc.Index, wt.Ports),
+			})
+		}
+	}
+
+	// Worktree ports that the allocation map doesn't credit to them
+	claimedBy := make(map[int]string)
+	for _, key := range c.sortedWorktreeKeys() {
+		wt := c.Projects[key.project].Worktrees[key.worktree]
+		if wt.Archived {
+			continue
+		}
+		owner := key.project + "/" + key.worktree
+		for _, port := range wt.Ports {
+			if prev, ok := claimedBy[port]; ok {
+				issues = append(issues, PortIssue{
+					Kind: PortIssueConflict, Port: port, Project: key.project, Worktree: key.worktree,
+					Detail: fmt.Sprintf("also listed by %s", prev),
+				})
+			} else {
+				claimedBy[port] = owner
+			}
+
+			alloc, ok := c.PortAllocations[strconv.Itoa(port)]
+			switch {
+			case !ok:
+				issues = append(issues, PortIssue{
+					Kind: PortIssueMismatch, Port: port, Project: key.project, Worktree: key.worktree,
+					Detail: "listed by the worktree but not allocated",
+				})
+			case alloc.Project != key.project || alloc.Worktree != key.worktree:
+				issues = append(issues, PortIssue{
+					Kind: PortIssueMismatch, Port: port, Project: key.project, Worktree: key.worktree,
+					Detail: fmt.Sprintf("listed by the worktree but allocated to %s/%s", alloc.Project, alloc.Worktree),
+				})
+			}
+			// Allocated ports were already range-checked above
+			if !ok && !c.PortAllowed(key.project, port) {
+				issues = append(issues, c.outOfRangeIssue(key.project, key.worktree, port))
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Port < issues[j].Port
+	})
+	return issues
+}
+
+// RepairPorts rebuilds PortAllocations from the worktrees' own port lists:
+//   - worktrees whose directory is gone are marked archived and their ports freed
+//   - stale, duplicate and orphaned allocations are dropped
+//   - ports that conflict with another worktree or fall outside the configured
+//     range are replaced by a fresh consecutive block
+//
+// The repair is computed in full before anything is changed, so on error the
+// config is left untouched. Returns a description of each change made.
+func (c *Config) RepairPorts() ([]string, error) {
+	var changes []string
+	keys := c.sortedWorktreeKeys()
+
+	missing := make(map[worktreeKey]bool)
+	for _, key := range keys {
+		if worktreeMissingOnDisk(c.Projects[key.project].Worktrees[key.worktree]) {
+			missing[key] = true
+			changes = append(changes, fmt.Sprintf("%s/%s: directory missing, marked archived and freed its ports", key.project, key.worktree))
+		}
+	}
+
+	// Claim ports for live worktrees. Ports whose existing allocation already agrees
+	// win over conflicting claims, so a correctly allocated worktree keeps its ports.
+	allocs := make(map[string]*PortAlloc)
+	claim := func(key worktreeKey, agreeing bool) {
+		wt := c.Projects[key.project].Worktrees[key.worktree]
+		for i, port := range wt.Ports {
+			portStr := strconv.Itoa(port)
+			alloc := c.PortAllocations[portStr]
+			agrees := alloc != nil && alloc.Project == key.project && alloc.Worktree == key.worktree && alloc.Index == i
+			if agrees != agreeing || !c.PortAllowed(key.project, port) {
+				continue
+			}
+			if _, taken := allocs[portStr]; taken {
+				continue
+			}
+			allocs[portStr] = &PortAlloc{Project: key.project, Worktree: key.worktree, Index: i}
+		}
+	}
+	var live []worktreeKey
+	for _, key := range keys {
+		wt := c.Projects[key.project].Worktrees[key.worktree]
+		if !wt.Archived && !missing[key] && len(wt.Ports) > 0 {
+			live = append(live, key)
+		}
+	}
+	for _, key := range live {
+		claim(key, true)
+	}
+	for _, key := range live {
+		claim(key, false)
+	}
+
+	// Worktrees that couldn't keep all of their ports get a new block
+	scratch := &Config{Defaults: c.Defaults, Projects: c.Projects, PortAllocations: allocs}
+	newPorts := make(map[worktreeKey][]int)
+	for _, key := range live {
+		wt := c.Projects[key.project].Worktrees[key.worktree]
+		intact := true
+		for i, port := range wt.Ports {
+			alloc := allocs[strconv.Itoa(port)]
+			if alloc == nil || alloc.Project != key.project || alloc.Worktree != key.worktree || alloc.Index != i {
+				intact = false
+				break
+			}
+		}
+		if intact {
+			continue
+		}
+		for i, port := range wt.Ports {
+			if alloc := allocs[strconv.Itoa(port)]; alloc != nil && alloc.Project == key.project && alloc.Worktree == key.worktree && alloc.Index == i {
+				delete(allocs, strconv.Itoa(port))
+			}
+		}
+		ports, err := scratch.AllocatePorts(key.project, key.worktree, len(wt.Ports))
+		if err != nil {
+			return nil, fmt.Errorf("failed to reallocate ports for %s/%s: %w", key.project, key.worktree, err)
+		}
+		newPorts[key] = ports
+		changes = append(changes, fmt.Sprintf("%s/%s: reallocated ports %v -> %v (rerun setup to update .env)", key.project, key.worktree, wt.Ports, ports))
+	}
+
+	for portStr, alloc := range c.PortAllocations {
+		kept, ok := allocs[portStr]
+		if !ok || kept.Project != alloc.Project || kept.Worktree != alloc.Worktree || kept.Index != alloc.Index {
+			changes = append(changes, fmt.Sprintf("port %s: dropped allocation to %s/%s (index %d)", portStr, alloc.Project, alloc.Worktree, alloc.Index))
+		}
+	}
+
+	// Apply
+	for key := range missing {
+		wt := c.Projects[key.project].Worktrees[key.worktree]
+		wt.Archived = true
+		wt.ArchivedAt = time.Now()
+		wt.Ports = nil
+	}
+	for key, ports := range newPorts {
+		c.Projects[key.project].Worktrees[key.worktree].Ports = ports
+	}
+	c.PortAllocations = allocs
+
+	sort.Strings(changes)
+	return changes, nil
+}
+
+// outOfRangeIssue reports a port outside the range its project may allocate from
+func (c *Config) outOfRangeIssue(project, worktree string, port int) PortIssue {
+	start, end := c.PortRangeFor(project)
+	detail := fmt.Sprintf("outside configured range %d-%d", start, end)
+	if port >= start && port <= end {
+		detail = "inside another project's reserved range"
+	}
+	return PortIssue{Kind: PortIssueOutOfRange, Port: port, Project: project, Worktree: worktree, Detail: detail}
+}
+
+type worktreeKey struct {
+	project, worktree string
+}
+
+// sortedWorktreeKeys returns every project/worktree pair in a stable order
+func (c *Config) sortedWorktreeKeys() []worktreeKey {
+	var keys []worktreeKey
+	for projectName, project := range c.Projects {
+		for worktreeName := range project.Worktrees {
+			keys = append(keys, worktreeKey{projectName, worktreeName})
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].project != keys[j].project {
+			return keys[i].project < keys[j].project
+		}
+		return keys[i].worktree < keys[j].worktree
+	})
+	return keys
+}
+
+// liveWorktree returns a non-archived worktree, or nil
+func (c *Config) liveWorktree(projectName, worktreeName string) *Worktree {
+	project, ok := c.Projects[projectName]
+	if !ok {
+		return nil
+	}
+	wt, ok := project.Worktrees[worktreeName]
+	if !ok || wt.Archived {
+		return nil
+	}
+	return wt
+}
+
+// worktreeMissingOnDisk reports whether a live worktree's directory was removed.
+// Worktrees still being created have no directory yet and are skipped.
+func worktreeMissingOnDisk(wt *Worktree) bool {
+	if wt.Archived || wt.Path == "" || wt.SetupStatus == SetupStatusCreating {
+		return false
+	}
+	_, err := os.Stat(wt.Path)
+	return os.IsNotExist(err)
+}
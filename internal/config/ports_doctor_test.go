@@ -0,0 +1,131 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// doctorConfig returns a config with one healthy worktree ("app/tokyo" on 3100-3101)
+func doctorConfig(t *testing.T) *Config {
+	cfg := NewConfig()
+	cfg.Projects["app"] = &Project{
+		Path: t.TempDir(),
+		Worktrees: map[string]*Worktree{
+			"tokyo": {Path: t.TempDir(), Branch: "tokyo"},
+		},
+	}
+	ports, err := cfg.AllocatePorts("app", "tokyo", 2)
+	require.NoError(t, err)
+	cfg.Projects["app"].Worktrees["tokyo"].Ports = ports
+	return cfg
+}
+
+func issueKinds(issues []PortIssue) []PortIssueKind {
+	kinds := make([]PortIssueKind, len(issues))
+	for i, issue := range issues {
+		kinds[i] = issue.Kind
+	}
+	return kinds
+}
+
+func TestDiagnosePorts_Healthy(t *testing.T) {
+	cfg := doctorConfig(t)
+
+	assert.Empty(t, cfg.DiagnosePorts())
+
+	changes, err := cfg.RepairPorts()
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDiagnosePorts_OrphanedAllocation(t *testing.T) {
+	cfg := doctorConfig(t)
+	cfg.PortAllocations["3150"] = &PortAlloc{Project: "app", Worktree: "deleted", Index: 0}
+
+	issues := cfg.DiagnosePorts()
+	require.Len(t, issues, 1)
+	assert.Equal(t, PortIssueOrphaned, issues[0].Kind)
+	assert.Equal(t, 3150, issues[0].Port)
+
+	_, err := cfg.RepairPorts()
+	require.NoError(t, err)
+	assert.NotContains(t, cfg.PortAllocations, "3150")
+	assert.Empty(t, cfg.DiagnosePorts())
+}
+
+func TestDiagnosePorts_MissingWorktreeDirectory(t *testing.T) {
+	cfg := doctorConfig(t)
+	cfg.Projects["app"].Worktrees["tokyo"].Path = "/nonexistent/tokyo"
+
+	issues := cfg.DiagnosePorts()
+	require.Len(t, issues, 1)
+	assert.Equal(t, PortIssueMissingWorktree, issues[0].Kind)
+
+	_, err := cfg.RepairPorts()
+	require.NoError(t, err)
+	wt := cfg.Projects["app"].Worktrees["tokyo"]
+	assert.True(t, wt.Archived)
+	assert.Empty(t, wt.Ports)
+	assert.Empty(t, cfg.PortAllocations)
+}
+
+func TestDiagnosePorts_DuplicateIndexAndMismatch(t *testing.T) {
+	cfg := doctorConfig(t)
+	cfg.PortAllocations["3105"] = &PortAlloc{Project: "app", Worktree: "tokyo", Index: 0}
+
+	kinds := issueKinds(cfg.DiagnosePorts())
+	assert.Contains(t, kinds, PortIssueDuplicateIndex)
+	assert.Contains(t, kinds, PortIssueMismatch)
+
+	_, err := cfg.RepairPorts()
+	require.NoError(t, err)
+	assert.Equal(t, []int{3100, 3101}, cfg.Projects["app"].Worktrees["tokyo"].Ports)
+	assert.Len(t, cfg.PortAllocations, 2)
+	assert.Empty(t, cfg.DiagnosePorts())
+}
+
+func TestDiagnosePorts_ConflictReallocatesLoser(t *testing.T) {
+	cfg := doctorConfig(t)
+	cfg.Projects["app"].Worktrees["paris"] = &Worktree{Path: t.TempDir(), Branch: "paris", Ports: []int{3101, 3102}}
+	cfg.PortAllocations["3102"] = &PortAlloc{Project: "app", Worktree: "paris", Index: 1}
+
+	kinds := issueKinds(cfg.DiagnosePorts())
+	assert.Contains(t, kinds, PortIssueConflict)
+
+	changes, err := cfg.RepairPorts()
+	require.NoError(t, err)
+	assert.NotEmpty(t, changes)
+
+	// tokyo's allocation agreed with its ports, so it keeps them
+	assert.Equal(t, []int{3100, 3101}, cfg.Projects["app"].Worktrees["tokyo"].Ports)
+	assert.Equal(t, []int{3102, 3103}, cfg.Projects["app"].Worktrees["paris"].Ports)
+	assert.Empty(t, cfg.DiagnosePorts())
+}
+
+func TestDiagnosePorts_OutOfRange(t *testing.T) {
+	cfg := doctorConfig(t)
+	cfg.Defaults.PortRangeStart = 4000
+	cfg.Defaults.PortRangeEnd = 4100
+
+	kinds := issueKinds(cfg.DiagnosePorts())
+	assert.Equal(t, []PortIssueKind{PortIssueOutOfRange, PortIssueOutOfRange}, kinds)
+
+	_, err := cfg.RepairPorts()
+	require.NoError(t, err)
+	assert.Equal(t, []int{4000, 4001}, cfg.Projects["app"].Worktrees["tokyo"].Ports)
+	assert.Empty(t, cfg.DiagnosePorts())
+}
+
+func TestRepairPorts_LeavesConfigUntouchedOnError(t *testing.T) {
+	cfg := doctorConfig(t)
+	cfg.Defaults.PortRangeStart = 4000
+	cfg.Defaults.PortRangeEnd = 4000 // Too small for tokyo's two ports
+	cfg.PortAllocations["3150"] = &PortAlloc{Project: "app", Worktree: "deleted", Index: 0}
+
+	_, err := cfg.RepairPorts()
+	require.Error(t, err)
+	assert.Contains(t, cfg.PortAllocations, "3150")
+	assert.Equal(t, []int{3100, 3101}, cfg.Projects["app"].Worktrees["tokyo"].Ports)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"os"
 	"strconv"
 	"testing"
 
@@ -8,6 +9,12 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// TestMain stubs the host probe so allocations don't depend on what is running locally
+func TestMain(m *testing.M) {
+	portInUse = func(int) bool { return false }
+	os.Exit(m.Run())
+}
+
 func TestAllocatePorts_EmptyConfig(t *testing.T) {
 	cfg := NewConfig()
 
@@ -69,6 +76,100 @@ func TestAllocatePorts_RangeExhaustion(t *testing.T) {
 	assert.Contains(t, err.Error(), "no 2 consecutive ports")
 }
 
+func TestAllocatePorts_SkipsPortsInUseOnHost(t *testing.T) {
+	cfg := NewConfig()
+
+	orig := portInUse
+	defer func() { portInUse = orig }()
+	portInUse = func(port int) bool { return port == 3101 }
+
+	// 3101 is held by another process, so the first free run of 2 starts at 3102
+	ports, err := cfg.AllocatePorts("project", "wt", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3102, 3103}, ports)
+	assert.NotContains(t, cfg.PortAllocations, "3101")
+}
+
+func TestAllocatePorts_ReservedRanges(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Projects["api"] = &Project{PortRange: &PortRange{Start: 3100, End: 3109}}
+	cfg.Projects["web"] = &Project{}
+
+	// The reserved project allocates inside its range
+	ports, err := cfg.AllocatePorts("api", "wt1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3100, 3101}, ports)
+
+	// Other projects skip the reserved range
+	ports, err = cfg.AllocatePorts("web", "wt1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3110, 3111}, ports)
+
+	// The reserved range is all the project gets
+	_, err = cfg.AllocatePorts("api", "wt2", 9)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "range 3100-3109")
+}
+
+func TestAllocateWorktreePorts_Sticky(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Projects["app"] = &Project{StickyPorts: true}
+
+	first, err := cfg.AllocateWorktreePorts("app", "tokyo", "feature/login", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3100, 3101}, first)
+
+	// Something else takes the low ports while the worktree is gone
+	cfg.FreeWorktreePorts("app", "tokyo")
+	_, err = cfg.AllocatePorts("app", "other", 1)
+	require.NoError(t, err)
+
+	// 3100 is taken, so the old block can't be reused
+	second, err := cfg.AllocateWorktreePorts("app", "paris", "feature/login", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3101, 3102}, second)
+
+	// Once free, the branch gets its latest block back under a new worktree name
+	cfg.FreeWorktreePorts("app", "paris")
+	cfg.FreeWorktreePorts("app", "other")
+	third, err := cfg.AllocateWorktreePorts("app", "rome", "feature/login", 2)
+	require.NoError(t, err)
+	assert.Equal(t, second, third)
+}
+
+func TestAllocateWorktreePorts_PrunesHistoryOfGoneWorktrees(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Projects["app"] = &Project{
+		StickyPorts: true,
+		Worktrees: map[string]*Worktree{
+			"tokyo": {Branch: "feature/login", Archived: true},
+			"paris": {},
+		},
+		PortHistory: map[string][]int{
+			"feature/login": {3200, 3201},
+			"paris":         {3300},
+			"feature/gone":  {3400},
+		},
+	}
+
+	_, err := cfg.AllocateWorktreePorts("app", "rome", "feature/new", 1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]int{
+		"feature/login": {3200, 3201}, // archived worktrees keep theirs for restore
+		"paris":         {3300},       // no branch: keyed by worktree name
+		"feature/new":   {3100},
+	}, cfg.Projects["app"].PortHistory)
+}
+
+func TestAllocateWorktreePorts_NotStickyByDefault(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Projects["app"] = &Project{}
+
+	_, err := cfg.AllocateWorktreePorts("app", "tokyo", "main", 2)
+	require.NoError(t, err)
+	assert.Nil(t, cfg.Projects["app"].PortHistory)
+}
+
 func TestAllocatePorts_InvalidCount(t *testing.T) {
 	cfg := NewConfig()
 
@@ -0,0 +1,491 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Setting is one leaf value of a Config or ProjectConfig, addressed by the
+// dotted path of its JSON field names (e.g. "defaults.tmux.disableCc")
+type Setting struct {
+	Path  string
+	Value any
+	// Secret settings should be masked when displayed
+	Secret bool
+}
+
+// settingEnums lists the accepted values of settings that take a fixed set
+var settingEnums = map[string][]string{
+	"defaults.openWith":      {"iterm", "terminal", "wezterm"}, // opener.TerminalType
+	"defaults.multiplexer":   {"auto", "tmux", "herdr"},        // mux.Kind
+	"defaults.archiveSafety": {string(ArchiveSafetyBlock), string(ArchiveSafetyStash), string(ArchiveSafetyPush)},
+	"clickup.mode":           {string(AgentModeParallel), string(AgentModeSequential)},
+	"githubIssues.mode":      {string(AgentModeParallel), string(AgentModeSequential)},
+	"auth.type":              {"none", "dev-bypass", "email-password", "oauth"},
+	"dependencyCache.method": {DependencyCacheAuto, DependencyCacheReflink, DependencyCacheHardlink, DependencyCacheCopy},
+}
+
+// secretSettings hold credentials
+var secretSettings = map[string]bool{
+	"defaults.localPostgresUrl":      true,
+	"defaults.localMysqlUrl":         true,
+	"defaults.clickup.apiToken":      true,
+	"defaults.clickup.webhookSecret": true,
+}
+
+// secretSettingPatterns match credentials below the managed projects section,
+// whose paths include project and worktree names
+var secretSettingPatterns = []string{
+	"projects.*.database.source",
+	"projects.*.database.cloneUrl",
+	"projects.*.database.devUrl",
+	"projects.*.database.devUrlExternal",
+	"projects.*.worktrees.*.databaseUrl",
+}
+
+// managedSettings are maintained by conductor itself and can't be set by hand
+var managedSettings = []string{
+	"version",
+	"portAllocations",
+	"projects",
+	"updates.lastCheck",
+	"updates.lastVersion",
+}
+
+// IsSecretSetting reports whether the setting at path holds a credential
+func IsSecretSetting(settingPath string) bool {
+	if secretSettings[settingPath] {
+		return true
+	}
+	for _, pattern := range secretSettingPatterns {
+		if ok, _ := path.Match(pattern, settingPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SettingEnum returns the accepted values of an enum setting, or nil
+func SettingEnum(path string) []string {
+	return settingEnums[path]
+}
+
+// ListSettings returns every leaf setting of cfg (a *Config or *ProjectConfig)
+// in path order. Managed settings are left out.
+func ListSettings(cfg any) []Setting {
+	var settings []Setting
+	var walk func(v reflect.Value, path string)
+	walk = func(v reflect.Value, path string) {
+		if path != "" && isManagedSetting(path) {
+			return
+		}
+		switch v.Kind() {
+		case reflect.Pointer:
+			if !v.IsNil() {
+				walk(v.Elem(), path)
+			}
+			return
+		case reflect.Struct:
+			if v.Type() != reflect.TypeOf(time.Time{}) {
+				for _, f := range jsonFields(v.Type()) {
+					walk(v.FieldByIndex(f.index), joinPath(path, f.name))
+				}
+				return
+			}
+		case reflect.Map:
+			if v.Type().Elem().Kind() == reflect.String {
+				keys := v.MapKeys()
+				sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+				for _, k := range keys {
+					walk(v.MapIndex(k), joinPath(path, k.String()))
+				}
+			}
+			return
+		}
+		settings = append(settings, Setting{Path: path, Value: v.Interface(), Secret: secretSettings[path]})
+	}
+	walk(reflect.ValueOf(cfg), "")
+	return settings
+}
+
+// GetSetting returns the value at path in cfg (a *Config or *ProjectConfig).
+// Sections return their whole value.
+func GetSetting(cfg any, path string) (any, error) {
+	v, err := resolveSetting(reflect.ValueOf(cfg), path, false)
+	if err != nil {
+		return nil, err
+	}
+	if !v.IsValid() {
+		return nil, nil
+	}
+	return v.Interface(), nil
+}
+
+// SetSetting parses value according to the type of the setting at path and
+// stores it in cfg, creating optional sections as needed. Lists accept a JSON
+// array or comma-separated values.
+func SetSetting(cfg any, path, value string) error {
+	if isManagedSetting(path) {
+		return fmt.Errorf("'%s' is managed by conductor and can't be set", path)
+	}
+	if allowed := settingEnums[path]; allowed != nil && !slices.Contains(allowed, value) {
+		return fmt.Errorf("invalid value '%s' for %s (expected one of: %s)", value, path, strings.Join(allowed, ", "))
+	}
+
+	// Parse before touching cfg so a bad value leaves it unchanged
+	t, err := settingType(reflect.TypeOf(cfg), path)
+	if err != nil {
+		return err
+	}
+	parsed := reflect.New(t).Elem()
+	if err := parseSettingValue(parsed, path, value); err != nil {
+		return err
+	}
+
+	parent, key, err := splitSettingPath(cfg, path)
+	if err != nil {
+		return err
+	}
+	if parent.Kind() == reflect.Map {
+		if parent.IsNil() {
+			parent.Set(reflect.MakeMap(parent.Type()))
+		}
+		parent.SetMapIndex(reflect.ValueOf(key), parsed)
+		return nil
+	}
+
+	field, err := resolveSetting(reflect.ValueOf(cfg), path, true)
+	if err != nil {
+		return err
+	}
+	field.Set(parsed)
+	return nil
+}
+
+// UnsetSetting resets the setting at path to its zero value (removing it from
+// the file when the field is optional)
+func UnsetSetting(cfg any, path string) error {
+	if isManagedSetting(path) {
+		return fmt.Errorf("'%s' is managed by conductor and can't be unset", path)
+	}
+
+	field, err := resolveSetting(reflect.ValueOf(cfg), path, false)
+	if err != nil {
+		return err
+	}
+
+	// Map entries (e.g. scripts.setup) are deleted
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		parent, err := resolveSetting(reflect.ValueOf(cfg), path[:i], false)
+		if err != nil {
+			return err
+		}
+		for parent.Kind() == reflect.Pointer && !parent.IsNil() {
+			parent = parent.Elem()
+		}
+		if parent.Kind() == reflect.Map {
+			if !field.IsValid() {
+				return fmt.Errorf("'%s' is not set", path)
+			}
+			parent.SetMapIndex(reflect.ValueOf(path[i+1:]), reflect.Value{})
+			return nil
+		}
+	}
+
+	if !field.IsValid() {
+		return nil // Inside a section that isn't set
+	}
+	field.Set(reflect.Zero(field.Type()))
+	return nil
+}
+
+// Validate checks the global settings for values conductor can't use.
+// Returns one message per problem found.
+func (c *Config) Validate() []string {
+	var problems []string
+	problems = append(problems, validateEnums(c)...)
+
+	d := c.Defaults
+	if d.PortRangeStart < 1 || d.PortRangeEnd > 65535 || d.PortRangeStart > d.PortRangeEnd {
+		problems = append(problems, fmt.Sprintf("defaults.portRangeStart/portRangeEnd: invalid range %d-%d", d.PortRangeStart, d.PortRangeEnd))
+	}
+	if d.PortsPerWorktree < 0 {
+		problems = append(problems, "defaults.portsPerWorktree: must not be negative")
+	}
+	if d.MaxConcurrentAgents < 0 {
+		problems = append(problems, "defaults.maxConcurrentAgents: must not be negative")
+	}
+	if d.ClickUp != nil {
+		if d.ClickUp.WebhookPort < 0 || d.ClickUp.WebhookPort > 65535 {
+			problems = append(problems, fmt.Sprintf("defaults.clickup.webhookPort: invalid port %d", d.ClickUp.WebhookPort))
+		}
+		if d.ClickUp.PollInterval < 0 {
+			problems = append(problems, "defaults.clickup.pollInterval: must not be negative")
+		}
+	}
+	if c.Updates.CheckInterval != "" {
+		if _, err := time.ParseDuration(c.Updates.CheckInterval); err != nil {
+			problems = append(problems, fmt.Sprintf("updates.checkInterval: %v", err))
+		}
+	}
+
+	names := make([]string, 0, len(c.Projects))
+	for name := range c.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r := c.Projects[name].PortRange
+		if r == nil {
+			continue
+		}
+		if r.Start > r.End || r.Start < d.PortRangeStart || r.End > d.PortRangeEnd {
+			problems = append(problems, fmt.Sprintf("projects.%s.portRange: %d-%d is not inside the global range %d-%d",
+				name, r.Start, r.End, d.PortRangeStart, d.PortRangeEnd))
+		}
+	}
+
+	return problems
+}
+
+// Validate checks a project's conductor.json for values conductor can't use
+func (p *ProjectConfig) Validate() []string {
+	problems := validateEnums(p)
+	if p.Ports.Default < 0 {
+		problems = append(problems, "ports.default: must not be negative")
+	}
+	if p.Ports.Default > 0 && len(p.Ports.Labels) > p.Ports.Default {
+		problems = append(problems, fmt.Sprintf("ports.labels: %d labels for %d ports", len(p.Ports.Labels), p.Ports.Default))
+	}
+	if p.EnvFile != nil && !filepath.IsLocal(p.EnvFile.GetPath()) {
+		problems = append(problems, fmt.Sprintf("envFile.path: '%s' must be a relative path inside the worktree", p.EnvFile.Path))
+	}
+	for i, rule := range p.Files {
+		if rule.Pattern == "" || !filepath.IsLocal(rule.Pattern) {
+			problems = append(problems, fmt.Sprintf("files[%d].pattern: '%s' must be a glob relative to the project root", i, rule.Pattern))
+		} else if _, err := path.Match(filepath.ToSlash(rule.Pattern), ""); err != nil {
+			problems = append(problems, fmt.Sprintf("files[%d].pattern: invalid glob '%s'", i, rule.Pattern))
+		}
+		if mode := rule.GetMode(); mode != FileModeCopy && mode != FileModeSymlink && mode != FileModeReflink {
+			problems = append(problems, fmt.Sprintf("files[%d].mode: invalid value '%s' (expected one of: copy, symlink, reflink)", i, rule.Mode))
+		}
+	}
+	if p.ClickUp != nil && p.ClickUp.MaxConcurrentAgents < 0 {
+		problems = append(problems, "clickup.maxConcurrentAgents: must not be negative")
+	}
+	if p.GitHubIssues != nil && p.GitHubIssues.MaxConcurrentAgents < 0 {
+		problems = append(problems, "githubIssues.maxConcurrentAgents: must not be negative")
+	}
+	if p.GitHubIssues != nil && p.GitHubIssues.Repo != "" {
+		if owner, repo, ok := strings.Cut(p.GitHubIssues.Repo, "/"); !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+			problems = append(problems, fmt.Sprintf("githubIssues.repo: '%s' must be owner/repo", p.GitHubIssues.Repo))
+		}
+	}
+	return problems
+}
+
+// validateEnums reports enum settings holding values outside their set.
+// Empty values mean "use the default" and are accepted.
+func validateEnums(cfg any) []string {
+	var problems []string
+	for _, s := range ListSettings(cfg) {
+		allowed := settingEnums[s.Path]
+		value := fmt.Sprint(s.Value)
+		if allowed != nil && value != "" && !slices.Contains(allowed, value) {
+			problems = append(problems, fmt.Sprintf("%s: invalid value '%s' (expected one of: %s)", s.Path, value, strings.Join(allowed, ", ")))
+		}
+	}
+	return problems
+}
+
+// resolveSetting walks path from root. With create, nil pointers along the way
+// are allocated; otherwise an unset optional section yields an invalid Value.
+func resolveSetting(root reflect.Value, path string, create bool) (reflect.Value, error) {
+	if path == "" {
+		return reflect.Value{}, fmt.Errorf("empty setting path")
+	}
+
+	v := root
+	for _, part := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !create {
+					_, err := settingType(root.Type(), path)
+					return reflect.Value{}, err
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+
+		switch {
+		case v.Kind() == reflect.Struct && v.Type() != reflect.TypeOf(time.Time{}):
+			f, ok := lookupJSONField(v.Type(), part)
+			if !ok {
+				return reflect.Value{}, fmt.Errorf("unknown setting '%s'", path)
+			}
+			v = v.FieldByIndex(f.index)
+		case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+			entry := v.MapIndex(reflect.ValueOf(part))
+			if !entry.IsValid() {
+				return reflect.Value{}, nil
+			}
+			v = entry
+		default:
+			return reflect.Value{}, fmt.Errorf("unknown setting '%s'", path)
+		}
+	}
+	return v, nil
+}
+
+// settingType returns the type of the setting at path under t
+func settingType(t reflect.Type, path string) (reflect.Type, error) {
+	for _, part := range strings.Split(path, ".") {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch {
+		case t.Kind() == reflect.Struct && t != reflect.TypeOf(time.Time{}):
+			f, ok := lookupJSONField(t, part)
+			if !ok {
+				return nil, fmt.Errorf("unknown setting '%s'", path)
+			}
+			t = t.FieldByIndex(f.index).Type
+		case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+			t = t.Elem()
+		default:
+			return nil, fmt.Errorf("unknown setting '%s'", path)
+		}
+	}
+	return t, nil
+}
+
+// splitSettingPath returns the value holding the last path element and that
+// element's name, allocating optional sections along the way
+func splitSettingPath(cfg any, path string) (reflect.Value, string, error) {
+	i := strings.LastIndex(path, ".")
+	if i < 0 {
+		return reflect.ValueOf(cfg).Elem(), path, nil
+	}
+	parent, err := resolveSetting(reflect.ValueOf(cfg), path[:i], true)
+	if err != nil {
+		return reflect.Value{}, "", err
+	}
+	if !parent.IsValid() {
+		return reflect.Value{}, "", fmt.Errorf("unknown setting '%s'", path)
+	}
+	for parent.Kind() == reflect.Pointer {
+		if parent.IsNil() {
+			parent.Set(reflect.New(parent.Type().Elem()))
+		}
+		parent = parent.Elem()
+	}
+	return parent, path[i+1:], nil
+}
+
+// parseSettingValue converts value to the type of v and stores it
+func parseSettingValue(v reflect.Value, path, value string) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s must be an integer, got '%s'", path, value)
+		}
+		v.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false, got '%s'", path, value)
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("%s can't be set from the command line", path)
+		}
+		var items []string
+		if strings.HasPrefix(strings.TrimSpace(value), "[") {
+			if err := json.Unmarshal([]byte(value), &items); err != nil {
+				return fmt.Errorf("%s must be a list of strings: %w", path, err)
+			}
+		} else if value != "" {
+			for _, item := range strings.Split(value, ",") {
+				items = append(items, strings.TrimSpace(item))
+			}
+		}
+		v.Set(reflect.ValueOf(items).Convert(v.Type()))
+	case reflect.Struct, reflect.Map:
+		return fmt.Errorf("'%s' is a section; set one of its fields instead", path)
+	default:
+		return fmt.Errorf("%s can't be set from the command line", path)
+	}
+	return nil
+}
+
+type jsonField struct {
+	name  string
+	index []int
+}
+
+// jsonFields returns the exported fields of t with their JSON names
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag := f.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n, _, _ := strings.Cut(tag, ","); n != "" {
+				name = n
+			}
+		}
+		fields = append(fields, jsonField{name: name, index: f.Index})
+	}
+	return fields
+}
+
+func lookupJSONField(t reflect.Type, name string) (jsonField, bool) {
+	for _, f := range jsonFields(t) {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return jsonField{}, false
+}
+
+func isManagedSetting(path string) bool {
+	for _, m := range managedSettings {
+		if path == m || strings.HasPrefix(path, m+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetSetting_TypeChecked(t *testing.T) {
+	cfg := NewConfig()
+
+	require.NoError(t, SetSetting(cfg, "defaults.portRangeStart", "4000"))
+	assert.Equal(t, 4000, cfg.Defaults.PortRangeStart)
+
+	require.NoError(t, SetSetting(cfg, "defaults.tmux.disableCc", "true"))
+	assert.True(t, cfg.Defaults.Tmux.DisableCC)
+
+	assert.ErrorContains(t, SetSetting(cfg, "defaults.portRangeStart", "abc"), "must be an integer")
+	assert.ErrorContains(t, SetSetting(cfg, "defaults.tmux.disableCc", "maybe"), "must be true or false")
+	assert.ErrorContains(t, SetSetting(cfg, "defaults.nope", "1"), "unknown setting")
+	assert.ErrorContains(t, SetSetting(cfg, "defaults.tmux", "x"), "is a section")
+	assert.ErrorContains(t, SetSetting(cfg, "projects", "x"), "managed by conductor")
+}
+
+func TestSetSetting_Enums(t *testing.T) {
+	cfg := NewConfig()
+
+	require.NoError(t, SetSetting(cfg, "defaults.multiplexer", "herdr"))
+	assert.Equal(t, "herdr", cfg.Defaults.Multiplexer)
+
+	require.NoError(t, SetSetting(cfg, "defaults.archiveSafety", "stash"))
+	assert.Equal(t, ArchiveSafetyStash, cfg.Defaults.ArchiveSafety)
+
+	err := SetSetting(cfg, "defaults.openWith", "emacs")
+	assert.ErrorContains(t, err, "expected one of: iterm, terminal, wezterm")
+}
+
+func TestSetSetting_CreatesOptionalSections(t *testing.T) {
+	cfg := NewConfig()
+
+	// A bad value must not leave an empty section behind
+	require.Error(t, SetSetting(cfg, "defaults.clickup.webhookPort", "abc"))
+	assert.Nil(t, cfg.Defaults.ClickUp)
+
+	require.NoError(t, SetSetting(cfg, "defaults.clickup.teamId", "123"))
+	require.NotNil(t, cfg.Defaults.ClickUp)
+	assert.Equal(t, "123", cfg.Defaults.ClickUp.TeamID)
+}
+
+func TestSetSetting_ProjectConfig(t *testing.T) {
+	cfg := &ProjectConfig{}
+
+	require.NoError(t, SetSetting(cfg, "scripts.setup", "make setup"))
+	assert.Equal(t, "make setup", cfg.Scripts["setup"])
+
+	require.NoError(t, SetSetting(cfg, "ports.labels", "web, api"))
+	assert.Equal(t, []string{"web", "api"}, cfg.Ports.Labels)
+
+	require.NoError(t, SetSetting(cfg, "ports.labels", `["a","b","c"]`))
+	assert.Equal(t, []string{"a", "b", "c"}, cfg.Ports.Labels)
+
+	require.NoError(t, SetSetting(cfg, "clickup.mode", "sequential"))
+	assert.Equal(t, AgentModeSequential, cfg.ClickUp.Mode)
+
+	require.NoError(t, UnsetSetting(cfg, "scripts.setup"))
+	assert.NotContains(t, cfg.Scripts, "setup")
+	assert.Error(t, UnsetSetting(cfg, "scripts.setup"))
+}
+
+func TestGetAndUnsetSetting(t *testing.T) {
+	cfg := NewConfig()
+
+	value, err := GetSetting(cfg, "defaults.openWith")
+	require.NoError(t, err)
+	assert.Equal(t, "iterm", value)
+
+	// Unset optional section
+	value, err = GetSetting(cfg, "defaults.clickup.apiToken")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+	require.NoError(t, UnsetSetting(cfg, "defaults.clickup.apiToken"))
+	assert.Nil(t, cfg.Defaults.ClickUp)
+
+	require.NoError(t, UnsetSetting(cfg, "defaults.openWith"))
+	assert.Empty(t, cfg.Defaults.OpenWith)
+
+	_, err = GetSetting(cfg, "defaults.bogus")
+	assert.Error(t, err)
+}
+
+func TestListSettings(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Defaults.ClickUp = &ClickUpConfig{APIToken: "pk_secret"}
+
+	byPath := make(map[string]Setting)
+	for _, s := range ListSettings(cfg) {
+		byPath[s.Path] = s
+	}
+
+	assert.Equal(t, 3100, byPath["defaults.portRangeStart"].Value)
+	assert.True(t, byPath["defaults.clickup.apiToken"].Secret)
+	assert.False(t, byPath["defaults.clickup.teamId"].Secret)
+	assert.NotContains(t, byPath, "version")
+	assert.NotContains(t, byPath, "updates.lastCheck")
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := NewConfig()
+	assert.Empty(t, cfg.Validate())
+
+	cfg.Defaults.Multiplexer = "screen"
+	cfg.Defaults.PortRangeStart = 5000
+	cfg.Updates.CheckInterval = "soon"
+	cfg.Projects["app"] = &Project{PortRange: &PortRange{Start: 1000, End: 1100}}
+
+	problems := cfg.Validate()
+	assert.Len(t, problems, 4)
+
+	proj := &ProjectConfig{Ports: PortConfig{Default: 1, Labels: []string{"web", "api"}}}
+	assert.Len(t, proj.Validate(), 1)
+
+	proj = &ProjectConfig{Files: []FileRule{
+		{Pattern: ".env*"},
+		{Pattern: "**/.cache", Mode: FileModeSymlink},
+		{Pattern: "../secrets"},
+		{Pattern: "config/[", Mode: "hardlink"},
+	}}
+	assert.Len(t, proj.Validate(), 3)
+
+	proj = &ProjectConfig{GitHubIssues: &ProjectGitHubIssuesConfig{Repo: "acme", Mode: "serial", MaxConcurrentAgents: -1}}
+	assert.Len(t, proj.Validate(), 3)
+}
+
+func TestIsSecretSetting(t *testing.T) {
+	assert.True(t, IsSecretSetting("defaults.clickup.apiToken"))
+	assert.True(t, IsSecretSetting("projects.app.database.source"))
+	assert.True(t, IsSecretSetting("projects.app.database.devUrlExternal"))
+	assert.True(t, IsSecretSetting("projects.app.worktrees.feature-x.databaseUrl"))
+	assert.False(t, IsSecretSetting("projects.app.database.type"))
+	assert.False(t, IsSecretSetting("defaults.clickup.teamId"))
+}
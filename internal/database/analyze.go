@@ -12,6 +12,10 @@ import (
 
 // GetTableInfo retrieves information about all tables in the database
 func GetTableInfo(connStr string) ([]TableInfo, error) {
+	return DriverFor(connStr).GetTableInfo(connStr)
+}
+
+func (postgresDriver) GetTableInfo(connStr string) ([]TableInfo, error) {
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
 		return nil, err
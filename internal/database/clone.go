@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -118,14 +119,15 @@ func CloneToWorktreeWithMigrations(localURL, dbName, goldenPath, schemaPath, wor
 		return nil, err
 	}
 
-	// Check if worktree uses Prisma migrations
-	if !HasPrismaMigrations(worktreePath) {
-		result.RecommendedAction = "Database cloned. No Prisma migrations detected in worktree."
+	// Check if worktree uses a migration tool we understand
+	provider := MigrationProviderFor(worktreePath)
+	if provider == nil {
+		result.RecommendedAction = "Database cloned. No supported migrations detected in worktree."
 		return result, nil
 	}
 
 	// Detect migration state
-	state, err := DetectMigrationState(result.DatabaseURL, worktreePath)
+	state, err := DetectMigrationStateWith(provider, result.DatabaseURL, worktreePath)
 	if err != nil {
 		// Non-fatal - clone succeeded, just couldn't detect migration state
 		result.RecommendedAction = fmt.Sprintf("Database cloned. Could not detect migration state: %v", err)
@@ -169,19 +171,24 @@ func GetWorktreeMigrationStatus(localURL, dbName, worktreePath string) (*Migrati
 		return nil, fmt.Errorf("database %s does not exist", dbName)
 	}
 
-	// Check if worktree uses Prisma
-	if !HasPrismaMigrations(worktreePath) {
+	// Check if worktree uses a migration tool we understand
+	provider := MigrationProviderFor(worktreePath)
+	if provider == nil {
 		return &MigrationState{
 			Compatibility:     MigrationUnknown,
-			RecommendedAction: "No Prisma migrations detected in worktree",
+			RecommendedAction: "No supported migrations detected in worktree (Prisma, Drizzle, Rails, Django, Alembic, goose, golang-migrate)",
 		}, nil
 	}
 
-	return DetectMigrationState(dbURL, worktreePath)
+	return DetectMigrationStateWith(provider, dbURL, worktreePath)
 }
 
 // CreateDatabase creates a new database
 func CreateDatabase(localURL string, dbName string) error {
+	return DriverFor(localURL).CreateDatabase(localURL, dbName)
+}
+
+func (postgresDriver) CreateDatabase(localURL string, dbName string) error {
 	// Connect to postgres database (or template1) to create new database
 	info, err := ParseConnectionString(localURL)
 	if err != nil {
@@ -224,8 +231,121 @@ func CreateDatabase(localURL string, dbName string) error {
 	return nil
 }
 
+// errTemplateBusy is why a template copy is refused while others are connected
+var errTemplateBusy = errors.New("other sessions are connected to it")
+
+// CreateDatabaseFromTemplate creates dbName as a server-side copy of templateDB.
+// The copy needs the template to itself; connections to it are never
+// terminated, so with anyone connected it fails with errTemplateBusy and the
+// caller can fall back to pg_dump.
+func CreateDatabaseFromTemplate(ctx context.Context, localURL, dbName, templateDB string) error {
+	info, err := ParseConnectionString(localURL)
+	if err != nil {
+		return err
+	}
+	info.Database = "postgres"
+	adminURL := BuildConnectionString(info)
+
+	db, err := sql.Open("postgres", adminURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	safeName := sanitizeDBName(dbName)
+	safeTemplate := sanitizeDBName(templateDB)
+
+	var exists bool
+	err = db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", safeName).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check database existence: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("database %s already exists", safeName)
+	}
+
+	// CREATE DATABASE ... TEMPLATE requires that nobody else is connected to
+	// the template. Someone connecting after this check makes the CREATE fail.
+	var sessions int
+	err = db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM pg_stat_activity
+		WHERE datname = $1 AND pid <> pg_backend_pid()
+	`, safeTemplate).Scan(&sessions)
+	if err != nil {
+		return fmt.Errorf("failed to check connections to %s: %w", safeTemplate, err)
+	}
+	if sessions > 0 {
+		return fmt.Errorf("database %s busy (%d session(s)): %w", safeTemplate, sessions, errTemplateBusy)
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", quoteIdentifier(safeName), quoteIdentifier(safeTemplate)))
+	if err != nil {
+		return fmt.Errorf("CREATE DATABASE TEMPLATE failed: %w", err)
+	}
+	return nil
+}
+
+// SetTemplateDatabase sets or clears a database's IS_TEMPLATE flag. A template
+// can be cloned by any role with CREATEDB, but must be unmarked before it is dropped.
+func SetTemplateDatabase(localURL, dbName string, isTemplate bool) error {
+	info, err := ParseConnectionString(localURL)
+	if err != nil {
+		return err
+	}
+	info.Database = "postgres"
+	adminURL := BuildConnectionString(info)
+
+	db, err := sql.Open("postgres", adminURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s IS_TEMPLATE %t", quoteIdentifier(sanitizeDBName(dbName)), isTemplate))
+	if err != nil {
+		return fmt.Errorf("failed to update template flag: %w", err)
+	}
+	return nil
+}
+
+// isTemplateDatabase reports whether a database has its IS_TEMPLATE flag set
+func isTemplateDatabase(localURL, dbName string) (bool, error) {
+	info, err := ParseConnectionString(localURL)
+	if err != nil {
+		return false, err
+	}
+	info.Database = "postgres"
+	adminURL := BuildConnectionString(info)
+
+	db, err := sql.Open("postgres", adminURL)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var isTemplate bool
+	err = db.QueryRowContext(ctx, "SELECT datistemplate FROM pg_database WHERE datname = $1", sanitizeDBName(dbName)).Scan(&isTemplate)
+	if err != nil {
+		return false, fmt.Errorf("failed to check template flag: %w", err)
+	}
+	return isTemplate, nil
+}
+
 // DropDatabase drops a database
 func DropDatabase(localURL string, dbName string) error {
+	return DriverFor(localURL).DropDatabase(localURL, dbName)
+}
+
+func (postgresDriver) DropDatabase(localURL string, dbName string) error {
 	// Connect to postgres database to drop
 	info, err := ParseConnectionString(localURL)
 	if err != nil {
@@ -253,6 +373,9 @@ func DropDatabase(localURL string, dbName string) error {
 		WHERE datname = '%s' AND pid <> pg_backend_pid()
 	`, safeName))
 
+	// Template databases (the golden copy) can't be dropped until unmarked
+	_, _ = db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s IS_TEMPLATE false", quoteIdentifier(safeName)))
+
 	// Drop database
 	_, err = db.ExecContext(ctx, fmt.Sprintf("DROP DATABASE IF EXISTS %s", quoteIdentifier(safeName)))
 	if err != nil {
@@ -264,6 +387,10 @@ func DropDatabase(localURL string, dbName string) error {
 
 // DatabaseExists checks if a database exists
 func DatabaseExists(localURL string, dbName string) (bool, error) {
+	return DriverFor(localURL).DatabaseExists(localURL, dbName)
+}
+
+func (postgresDriver) DatabaseExists(localURL string, dbName string) (bool, error) {
 	info, err := ParseConnectionString(localURL)
 	if err != nil {
 		return false, err
@@ -294,6 +421,10 @@ func DatabaseExists(localURL string, dbName string) (bool, error) {
 
 // ListDatabases lists all databases matching a pattern
 func ListDatabases(localURL string, pattern string) ([]string, error) {
+	return DriverFor(localURL).ListDatabases(localURL, pattern)
+}
+
+func (postgresDriver) ListDatabases(localURL string, pattern string) ([]string, error) {
 	info, err := ParseConnectionString(localURL)
 	if err != nil {
 		return nil, err
@@ -343,8 +474,52 @@ func GenerateDBName(projectName string, port int, pattern string) string {
 	return sanitizeDBName(name)
 }
 
+// DumpDatabase writes a plain SQL dump of a database to outputPath using the
+// server's dump tool (pg_dump or mysqldump)
+func DumpDatabase(ctx context.Context, dbURL string, outputPath string) error {
+	return DriverFor(dbURL).DumpDatabase(ctx, dbURL, outputPath)
+}
+
+func (postgresDriver) DumpDatabase(ctx context.Context, dbURL string, outputPath string) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create dump directory: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "pg_dump", dbURL, "--no-owner", "--no-acl", "-f", outputPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		_ = os.Remove(outputPath)
+		return fmt.Errorf("pg_dump failed: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// RestoreDatabaseFromDump creates a database and loads a SQL dump produced by DumpDatabase
+// The database is dropped again if the restore fails
+func RestoreDatabaseFromDump(localURL string, dbName string, dumpPath string) error {
+	if _, err := os.Stat(dumpPath); err != nil {
+		return fmt.Errorf("dump file not found: %w", err)
+	}
+
+	if err := CreateDatabase(localURL, dbName); err != nil {
+		return err
+	}
+
+	if err := restoreDump(BuildWorktreeURL(localURL, dbName), dumpPath); err != nil {
+		_ = DropDatabase(localURL, dbName)
+		return err
+	}
+
+	return nil
+}
+
 // restoreDump restores a SQL dump to a database
 func restoreDump(targetURL string, dumpPath string) error {
+	return DriverFor(targetURL).RestoreDump(targetURL, dumpPath)
+}
+
+func (postgresDriver) RestoreDump(targetURL string, dumpPath string) error {
 	// Use psql to restore the dump
 	cmd := exec.Command("psql", targetURL, "-f", dumpPath)
 	cmd.Env = append(os.Environ(), "PGOPTIONS=-c client_min_messages=warning")
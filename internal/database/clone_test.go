@@ -1,9 +1,12 @@
 package database
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGenerateDBName(t *testing.T) {
@@ -136,3 +139,32 @@ func TestQuoteIdentifier(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateDatabaseFromTemplate_LeavesConnectedSessionsAlone(t *testing.T) {
+	if err := ValidateConnection(testDBURL); err != nil {
+		t.Skipf("Test database not available: %v", err)
+	}
+	const template, copyName = "conductor_template_busy", "conductor_template_busy_copy"
+	_ = DropDatabase(testDBURL, copyName)
+	_ = DropDatabase(testDBURL, template)
+	require.NoError(t, CreateDatabase(testDBURL, template))
+	t.Cleanup(func() {
+		_ = DropDatabase(testDBURL, copyName)
+		_ = DropDatabase(testDBURL, template)
+	})
+
+	// An idle session, e.g. a sync's psql between statements
+	session, err := openDB(BuildWorktreeURL(testDBURL, template))
+	require.NoError(t, err)
+	require.NoError(t, session.Ping())
+
+	err = CreateDatabaseFromTemplate(context.Background(), testDBURL, copyName, template)
+	assert.ErrorIs(t, err, errTemplateBusy)
+	assert.NoError(t, session.Ping(), "the session must not be terminated")
+
+	// Once the session has gone the copy goes ahead
+	require.NoError(t, session.Close())
+	assert.Eventually(t, func() bool {
+		return CreateDatabaseFromTemplate(context.Background(), testDBURL, copyName, template) == nil
+	}, 5*time.Second, 50*time.Millisecond)
+}
@@ -2,34 +2,49 @@ package database
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"net/url"
 	"os/exec"
 	"strings"
 	"time"
 
+	"github.com/hammashamzah/conductor/internal/config"
 	_ "github.com/lib/pq"
 )
 
-// ParseConnectionString parses a PostgreSQL connection URL into components
+// urlScheme is the URL scheme, default port and SSL query parameter used in
+// a driver's connection strings
+type urlScheme struct{ scheme, port, sslParam string }
+
+// urlSchemes maps driver names to their connection string format
+var urlSchemes = map[string]urlScheme{
+	config.DatabaseDriverPostgres: {"postgresql", "5432", "sslmode"},
+	config.DatabaseDriverMySQL:    {"mysql", "3306", "ssl-mode"},
+}
+
+// ParseConnectionString parses a PostgreSQL or MySQL connection URL into components
 func ParseConnectionString(connStr string) (*ConnectionInfo, error) {
 	u, err := url.Parse(connStr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid connection string: %w", err)
 	}
 
-	if u.Scheme != "postgresql" && u.Scheme != "postgres" {
-		return nil, fmt.Errorf("invalid scheme: expected postgresql or postgres, got %s", u.Scheme)
+	driver, ok := driverSchemes[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("invalid scheme: expected postgresql, postgres, mysql or mariadb, got %s", u.Scheme)
 	}
+	scheme := urlSchemes[driver]
 
 	info := &ConnectionInfo{
 		Host: u.Hostname(),
 		Port: u.Port(),
 	}
+	if driver != config.DatabaseDriverPostgres {
+		info.Driver = driver
+	}
 
 	if info.Port == "" {
-		info.Port = "5432"
+		info.Port = scheme.port
 	}
 
 	if u.User != nil {
@@ -41,15 +56,25 @@ func ParseConnectionString(connStr string) (*ConnectionInfo, error) {
 	info.Database = strings.TrimPrefix(u.Path, "/")
 
 	// Parse SSL mode from query params
-	if sslMode := u.Query().Get("sslmode"); sslMode != "" {
+	if sslMode := u.Query().Get(scheme.sslParam); sslMode != "" {
 		info.SSLMode = sslMode
 	}
 
 	return info, nil
 }
 
+// schemeFor returns the connection string format for a parsed connection
+func schemeFor(info *ConnectionInfo) urlScheme {
+	if s, ok := urlSchemes[info.Driver]; ok {
+		return s
+	}
+	return urlSchemes[config.DatabaseDriverPostgres]
+}
+
 // BuildConnectionString builds a connection string from components
 func BuildConnectionString(info *ConnectionInfo) string {
+	scheme := schemeFor(info)
+
 	var userPart string
 	if info.User != "" {
 		if info.Password != "" {
@@ -61,13 +86,13 @@ func BuildConnectionString(info *ConnectionInfo) string {
 
 	port := info.Port
 	if port == "" {
-		port = "5432"
+		port = scheme.port
 	}
 
-	connStr := fmt.Sprintf("postgresql://%s%s:%s/%s", userPart, info.Host, port, info.Database)
+	connStr := fmt.Sprintf("%s://%s%s:%s/%s", scheme.scheme, userPart, info.Host, port, info.Database)
 
 	if info.SSLMode != "" {
-		connStr += "?sslmode=" + info.SSLMode
+		connStr += "?" + scheme.sslParam + "=" + info.SSLMode
 	}
 
 	return connStr
@@ -87,7 +112,7 @@ func BuildWorktreeURL(localURL, dbName string) string {
 
 // ValidateConnection tests if a connection string is valid and can connect
 func ValidateConnection(connStr string) error {
-	db, err := sql.Open("postgres", connStr)
+	db, err := openDB(connStr)
 	if err != nil {
 		return fmt.Errorf("failed to open connection: %w", err)
 	}
@@ -106,7 +131,7 @@ func ValidateConnection(connStr string) error {
 // IsReadOnly checks if the connection has write permissions
 // Returns true if read-only, false if has write access
 func IsReadOnly(connStr string) (bool, error) {
-	db, err := sql.Open("postgres", connStr)
+	db, err := openDB(connStr)
 	if err != nil {
 		return false, fmt.Errorf("failed to open connection: %w", err)
 	}
@@ -115,11 +140,18 @@ func IsReadOnly(connStr string) (bool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Try to create a temporary table - this will fail if read-only
-	_, err = db.ExecContext(ctx, `
-		CREATE TEMP TABLE _conductor_write_test (id int);
-		DROP TABLE _conductor_write_test;
-	`)
+	// Try to create a temporary table - this will fail if read-only.
+	// MySQL runs one statement per Exec, so both go over the same session.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	_, err = conn.ExecContext(ctx, `CREATE TEMPORARY TABLE _conductor_write_test (id int)`)
+	if err == nil {
+		_, _ = conn.ExecContext(ctx, `DROP TABLE _conductor_write_test`)
+	}
 
 	if err != nil {
 		// Check if error indicates read-only
@@ -136,9 +168,9 @@ func IsReadOnly(connStr string) (bool, error) {
 	return false, nil
 }
 
-// GetPostgresVersion returns the PostgreSQL server version
+// GetPostgresVersion returns the server version (PostgreSQL or MySQL)
 func GetPostgresVersion(connStr string) (string, error) {
-	db, err := sql.Open("postgres", connStr)
+	db, err := openDB(connStr)
 	if err != nil {
 		return "", fmt.Errorf("failed to open connection: %w", err)
 	}
@@ -193,6 +225,7 @@ func MaskConnectionString(connStr string) string {
 	}
 
 	// Build manually to avoid URL encoding the asterisks
+	scheme := schemeFor(info)
 	var userPart string
 	if info.User != "" {
 		if info.Password != "" {
@@ -204,13 +237,13 @@ func MaskConnectionString(connStr string) string {
 
 	port := info.Port
 	if port == "" {
-		port = "5432"
+		port = scheme.port
 	}
 
-	result := fmt.Sprintf("postgresql://%s%s:%s/%s", userPart, info.Host, port, info.Database)
+	result := fmt.Sprintf("%s://%s%s:%s/%s", scheme.scheme, userPart, info.Host, port, info.Database)
 
 	if info.SSLMode != "" {
-		result += "?sslmode=" + info.SSLMode
+		result += "?" + scheme.sslParam + "=" + info.SSLMode
 	}
 
 	return result
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"strings"
+
+	"github.com/hammashamzah/conductor/internal/config"
+)
+
+// Driver is the engine-specific half of the golden copy workflow: managing
+// databases on a server, analyzing a source, dumping and restoring, and the
+// golden sync and worktree clone themselves. The package-level functions
+// (CreateDatabase, SyncToGoldenDB, CloneFromGoldenDB, ...) pick the driver
+// from the connection URL's scheme, so callers never choose one directly.
+type Driver interface {
+	// Name is the engine name stored in DatabaseConfig.Driver
+	Name() string
+	// Open connects to the database a URL points at
+	Open(connStr string) (*sql.DB, error)
+
+	CreateDatabase(localURL, dbName string) error
+	DropDatabase(localURL, dbName string) error
+	DatabaseExists(localURL, dbName string) (bool, error)
+	// ListDatabases lists databases whose name matches a LIKE pattern
+	ListDatabases(localURL, pattern string) ([]string, error)
+	DatabaseSize(localURL, dbName string) (int64, error)
+
+	// GetTableInfo lists the source's tables with sizes and row estimates
+	GetTableInfo(connStr string) ([]TableInfo, error)
+
+	// DumpDatabase writes a plain SQL dump that RestoreDump can load
+	DumpDatabase(ctx context.Context, dbURL, outputPath string) error
+	RestoreDump(targetURL, dumpPath string) error
+
+	// SyncToGolden copies the source into the project's golden database
+	SyncToGolden(ctx context.Context, sourceURL, localURL, projectName string, cfg *DatabaseConfig, progress ProgressFunc) (*GoldenDBSyncResult, error)
+	// CloneFromGolden creates a worktree database from the golden database
+	CloneFromGolden(ctx context.Context, localURL, projectName, worktreeDBName string, progress ProgressFunc) (CloneMethod, error)
+
+	// GoldenSyncInfo fills in the latest sync record of an existing golden database
+	GoldenSyncInfo(goldenURL string, info *GoldenDBSyncInfo) error
+	// LoadGoldenMetadata reads the latest sync record, or nil if there is none
+	LoadGoldenMetadata(goldenURL string) (*SyncMetadata, error)
+}
+
+// URL schemes accepted for each driver
+var driverSchemes = map[string]string{
+	"postgresql": config.DatabaseDriverPostgres,
+	"postgres":   config.DatabaseDriverPostgres,
+	"mysql":      config.DatabaseDriverMySQL,
+	"mariadb":    config.DatabaseDriverMySQL,
+}
+
+// DriverName returns the driver name for a connection URL: "mysql" for
+// mysql:// and mariadb:// URLs, "postgres" otherwise
+func DriverName(connStr string) string {
+	if u, err := url.Parse(connStr); err == nil {
+		if name, ok := driverSchemes[strings.ToLower(u.Scheme)]; ok {
+			return name
+		}
+	}
+	return config.DatabaseDriverPostgres
+}
+
+// DriverFor returns the driver for a connection URL
+func DriverFor(connStr string) Driver {
+	if DriverName(connStr) == config.DatabaseDriverMySQL {
+		return mysqlDriver{}
+	}
+	return postgresDriver{}
+}
+
+// openDB connects to a database with the driver matching its URL
+func openDB(connStr string) (*sql.DB, error) {
+	return DriverFor(connStr).Open(connStr)
+}
+
+// postgresDriver implements Driver with lib/pq, pg_dump and psql. Its methods
+// live next to the package-level functions that dispatch to them.
+type postgresDriver struct{}
+
+func (postgresDriver) Name() string { return config.DatabaseDriverPostgres }
+
+func (postgresDriver) Open(connStr string) (*sql.DB, error) {
+	return sql.Open("postgres", connStr)
+}
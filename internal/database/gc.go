@@ -0,0 +1,279 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/secrets"
+)
+
+// Kinds of orphaned databases
+const (
+	OrphanWorktree = "worktree" // named like a project's worktree databases
+	OrphanSnapshot = "snapshot" // a snapshot copy no worktree records
+	OrphanRemote   = "remote"   // a dev_* database this machine created on a remote server
+)
+
+// OrphanedDatabase is a database conductor created for a worktree (or a
+// worktree snapshot) that no worktree in the config refers to anymore
+type OrphanedDatabase struct {
+	Name      string
+	Kind      string
+	Project   string // project whose naming pattern matched (empty for snapshots)
+	SizeBytes int64
+
+	// Remote server the database lives on (remote kind only)
+	SSHHost string
+	DevURL  string
+}
+
+// Location describes where the database lives, for display
+func (o OrphanedDatabase) Location() string {
+	if o.Kind == OrphanRemote {
+		return o.SSHHost
+	}
+	return "local"
+}
+
+// FindOrphanedDatabases lists databases that look like they belong to a
+// worktree but are not referenced by any worktree in cfg. Local databases are
+// matched against each project's DBNamePattern (and snapshot copies against
+// the snap_ prefix). localURL is the local PostgreSQL server and may be
+// empty to skip it.
+//
+// With remote set, remote servers are searched over SSH too. They are shared
+// with other machines, so only dev_* databases this machine created (see
+// RecordRemoteDatabase) are considered there.
+//
+// Archived worktrees don't count: archive dumps the database before dropping
+// it and restore clones the dump into a fresh one, so a database still named
+// by an archived worktree is one whose drop failed.
+func FindOrphanedDatabases(ctx context.Context, cfg *config.Config, localURL string, remote bool) ([]OrphanedDatabase, error) {
+	active := activeDatabaseNames(cfg)
+	seen := make(map[string]bool)
+	var orphans []OrphanedDatabase
+
+	if localURL != "" {
+		for _, projectName := range sortedKeys(cfg.Projects) {
+			project := cfg.Projects[projectName]
+			// MySQL worktree databases live on the local MySQL server, which isn't swept
+			if project.Database == nil || project.Database.Mode == config.DatabaseModeRemote || project.Database.IsMySQL() {
+				continue
+			}
+			pattern := project.Database.DBNamePattern
+			names, err := ListDatabases(localURL, dbNamePatternLike(projectName, pattern))
+			if err != nil {
+				return nil, err
+			}
+			re := dbNamePatternRegexp(projectName, pattern)
+			for _, name := range names {
+				if active[name] || seen[name] || !re.MatchString(name) {
+					continue
+				}
+				seen[name] = true
+				size, _ := databaseSize(localURL, name)
+				orphans = append(orphans, OrphanedDatabase{Name: name, Kind: OrphanWorktree, Project: projectName, SizeBytes: size})
+			}
+		}
+
+		names, err := ListDatabases(localURL, `snap\_%`)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if active[name] || seen[name] {
+				continue
+			}
+			seen[name] = true
+			size, _ := databaseSize(localURL, name)
+			orphans = append(orphans, OrphanedDatabase{Name: name, Kind: OrphanSnapshot, SizeBytes: size})
+		}
+	}
+
+	if !remote {
+		return orphans, nil
+	}
+
+	// Remote dev databases live on shared servers: list each server once
+	servers := make(map[string]bool)
+	for _, projectName := range sortedKeys(cfg.Projects) {
+		project := cfg.Projects[projectName]
+		if project.Database == nil || project.Database.Mode != config.DatabaseModeRemote || project.Database.SSHHost == "" {
+			continue
+		}
+		dbConfig, err := secrets.ResolveDatabaseConfig(project.Database)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", projectName, err)
+		}
+		if dbConfig.DevURL == "" {
+			continue
+		}
+		key := dbConfig.SSHHost + "\x00" + dbConfig.DevURL
+		if servers[key] {
+			continue
+		}
+		servers[key] = true
+
+		created, err := RemoteDatabasesCreated(dbConfig.SSHHost)
+		if err != nil {
+			return nil, err
+		}
+		if len(created) == 0 {
+			continue
+		}
+		dbs, err := remoteListDevDatabases(ctx, dbConfig.SSHHost, dbConfig.DevURL)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", dbConfig.SSHHost, err)
+		}
+		for _, db := range dbs {
+			if active[db.Name] || !created[db.Name] {
+				continue
+			}
+			db.Kind = OrphanRemote
+			db.SSHHost = dbConfig.SSHHost
+			db.DevURL = dbConfig.DevURL
+			orphans = append(orphans, db)
+		}
+	}
+
+	return orphans, nil
+}
+
+// DropOrphanedDatabase drops a database found by FindOrphanedDatabases
+func DropOrphanedDatabase(ctx context.Context, localURL string, orphan OrphanedDatabase) error {
+	if orphan.Kind == OrphanRemote {
+		return RemoteDropDatabase(ctx, orphan.SSHHost, orphan.DevURL, orphan.Name)
+	}
+	return DropDatabase(localURL, orphan.Name)
+}
+
+// activeDatabaseNames returns every database a live worktree in cfg refers
+// to: worktree databases, snapshot copies and golden copies
+func activeDatabaseNames(cfg *config.Config) map[string]bool {
+	active := make(map[string]bool)
+	for projectName, project := range cfg.Projects {
+		active[GoldenDBName(projectName)] = true
+		for _, wt := range project.Worktrees {
+			if wt.Archived {
+				continue
+			}
+			if wt.DatabaseName != "" {
+				active[wt.DatabaseName] = true
+				active[sanitizeDBName(wt.DatabaseName)] = true
+			}
+			for _, snap := range wt.DatabaseSnapshots {
+				if snap.DatabaseName != "" {
+					active[snap.DatabaseName] = true
+				}
+			}
+		}
+	}
+	return active
+}
+
+// dbNamePatternParts splits a DBNamePattern into literal text and {port}
+// placeholders, substituting the project name like GenerateDBName does
+func dbNamePatternParts(projectName, pattern string) []string {
+	if pattern == "" {
+		pattern = DefaultDBNamePattern
+	}
+	pattern = strings.ReplaceAll(pattern, "{project}", projectName)
+
+	var parts []string
+	for i, literal := range strings.Split(pattern, "{port}") {
+		if i > 0 {
+			parts = append(parts, "{port}")
+		}
+		if literal != "" {
+			parts = append(parts, literal)
+		}
+	}
+	return parts
+}
+
+// invalidDBNameChars matches the characters sanitizeDBName replaces
+var invalidDBNameChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// sanitizeDBNameLiteral applies sanitizeDBName's character mapping to a
+// fragment of a name. A leading digit gets sanitizeDBName's "_" prefix.
+func sanitizeDBNameLiteral(s string, first bool) string {
+	s = strings.ToLower(invalidDBNameChars.ReplaceAllString(s, "_"))
+	if first && s != "" && s[0] >= '0' && s[0] <= '9' {
+		s = "_" + s
+	}
+	return s
+}
+
+// dbNamePatternRegexp returns a regexp matching the names GenerateDBName
+// produces for a project, with {port} matching any port
+func dbNamePatternRegexp(projectName, pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i, part := range dbNamePatternParts(projectName, pattern) {
+		if part != "{port}" {
+			b.WriteString(regexp.QuoteMeta(sanitizeDBNameLiteral(part, i == 0)))
+			continue
+		}
+		if i == 0 {
+			b.WriteString("_")
+		}
+		b.WriteString("[0-9]+")
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// dbNamePatternLike returns a LIKE pattern that pre-filters databases for
+// dbNamePatternRegexp
+func dbNamePatternLike(projectName, pattern string) string {
+	var b strings.Builder
+	for i, part := range dbNamePatternParts(projectName, pattern) {
+		if part == "{port}" {
+			b.WriteString("%")
+			continue
+		}
+		b.WriteString(strings.ReplaceAll(sanitizeDBNameLiteral(part, i == 0), "_", `\_`))
+	}
+	return b.String()
+}
+
+// remoteListDevDatabases lists dev_* databases and their sizes on a remote
+// server via SSH
+func remoteListDevDatabases(ctx context.Context, sshHost, devURL string) ([]OrphanedDatabase, error) {
+	devParsed, err := url.Parse(devURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid devUrl: %w", err)
+	}
+	adminURL := buildAdminURL(devParsed)
+
+	query := `SELECT datname, pg_database_size(datname) FROM pg_database WHERE datname LIKE 'dev\_%' ORDER BY datname`
+	psqlCmd := fmt.Sprintf(`psql "%s" -t -A -F '|' -c %q`, adminURL, query)
+
+	output, err := exec.CommandContext(ctx, "ssh", sshHost, psqlCmd).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list databases: %w", err)
+	}
+	return parseRemoteDatabaseList(string(output)), nil
+}
+
+// parseRemoteDatabaseList parses "name|size" lines from psql -t -A output
+func parseRemoteDatabaseList(output string) []OrphanedDatabase {
+	var dbs []OrphanedDatabase
+	for _, line := range strings.Split(output, "\n") {
+		name, sizeStr, ok := strings.Cut(strings.TrimSpace(line), "|")
+		if !ok || name == "" {
+			continue
+		}
+		size, _ := strconv.ParseInt(sizeStr, 10, 64)
+		dbs = append(dbs, OrphanedDatabase{Name: name, SizeBytes: size})
+	}
+	sort.Slice(dbs, func(i, j int) bool { return dbs[i].Name < dbs[j].Name })
+	return dbs
+}
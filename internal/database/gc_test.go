@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBNamePatternRegexp(t *testing.T) {
+	tests := []struct {
+		name     string
+		project  string
+		pattern  string
+		matches  []string
+		excludes []string
+	}{
+		{
+			name:     "default pattern",
+			project:  "My-App",
+			matches:  []string{"my_app_3100", "my_app_42"},
+			excludes: []string{"my_app_golden", "my_app_", "other_3100", "my_app_3100_x"},
+		},
+		{
+			name:     "custom pattern",
+			project:  "shop",
+			pattern:  "wt_{project}_{port}_db",
+			matches:  []string{"wt_shop_3100_db"},
+			excludes: []string{"shop_golden", "wt_shop__db", "wt_shop_3100"},
+		},
+		{
+			name:     "leading digit",
+			project:  "9lives",
+			matches:  []string{"_9lives_3100"},
+			excludes: []string{"9lives_3100"},
+		},
+		{
+			name:     "leading port",
+			project:  "app",
+			pattern:  "{port}-{project}",
+			matches:  []string{"_3100_app"},
+			excludes: []string{"3100_app"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := dbNamePatternRegexp(tt.project, tt.pattern)
+			for _, name := range tt.matches {
+				assert.True(t, re.MatchString(name), "%s should match %s", name, re)
+			}
+			for _, name := range tt.excludes {
+				assert.False(t, re.MatchString(name), "%s should not match %s", name, re)
+			}
+		})
+	}
+
+	// Names GenerateDBName produces are always matched
+	name := GenerateDBName("Web.App", 3100, "")
+	assert.True(t, dbNamePatternRegexp("Web.App", "").MatchString(name), name)
+}
+
+func TestDBNamePatternLike(t *testing.T) {
+	assert.Equal(t, `my\_app\_%`, dbNamePatternLike("My-App", ""))
+	assert.Equal(t, `wt\_shop\_%\_db`, dbNamePatternLike("shop", "wt_{project}_{port}_db"))
+	assert.Equal(t, `%\_app`, dbNamePatternLike("app", "{port}-{project}"))
+	assert.Equal(t, `\_9lives\_%`, dbNamePatternLike("9lives", ""))
+}
+
+func TestActiveDatabaseNames(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Projects["app"] = &config.Project{Worktrees: map[string]*config.Worktree{
+		"main": {DatabaseName: "app_3100", DatabaseSnapshots: []config.DatabaseSnapshot{
+			{Name: "before", DatabaseName: "snap_app_3100_before"},
+		}},
+		"old":     {DatabaseName: "app_3200", Archived: true},
+		"no-db":   {},
+		"unclean": {DatabaseName: "App-3300"},
+	}}
+
+	active := activeDatabaseNames(cfg)
+	assert.True(t, active["app_golden"])
+	assert.True(t, active["app_3100"])
+	assert.True(t, active["snap_app_3100_before"])
+	assert.True(t, active["app_3300"], "sanitized names count")
+	assert.False(t, active["app_3200"], "archived worktrees have no live database")
+}
+
+func TestParseRemoteDatabaseList(t *testing.T) {
+	output := "dev_feature|8192\n dev_alpha|1024 \n\nnot a row\n"
+	assert.Equal(t, []OrphanedDatabase{
+		{Name: "dev_alpha", SizeBytes: 1024},
+		{Name: "dev_feature", SizeBytes: 8192},
+	}, parseRemoteDatabaseList(output))
+	assert.Empty(t, parseRemoteDatabaseList(""))
+}
+
+func TestRemoteDatabaseRegistry(t *testing.T) {
+	t.Setenv("CONDUCTOR_CONFIG_DIR", t.TempDir())
+
+	require.NoError(t, RecordRemoteDatabase("root@dev", "dev_tokyo"))
+	require.NoError(t, RecordRemoteDatabase("root@dev", "dev_lima"))
+	require.NoError(t, RecordRemoteDatabase("root@dev", "dev_tokyo"))
+	require.NoError(t, RecordRemoteDatabase("root@other", "dev_oslo"))
+
+	created, err := RemoteDatabasesCreated("root@dev")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"dev_lima": true, "dev_tokyo": true}, created)
+
+	require.NoError(t, ForgetRemoteDatabase("root@dev", "dev_tokyo"))
+	created, err = RemoteDatabasesCreated("root@dev")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"dev_lima": true}, created)
+
+	created, err = RemoteDatabasesCreated("root@unknown")
+	require.NoError(t, err)
+	assert.Empty(t, created)
+}
+
+func TestFindOrphanedDatabases_RemoteOnlyOwnDatabases(t *testing.T) {
+	t.Setenv("CONDUCTOR_CONFIG_DIR", t.TempDir())
+
+	cfg := config.NewConfig()
+	cfg.Projects["app"] = &config.Project{
+		Database: &config.DatabaseConfig{
+			Mode:    config.DatabaseModeRemote,
+			SSHHost: "root@dev",
+			DevURL:  "postgres://dev@localhost:5432/postgres",
+		},
+		Worktrees: map[string]*config.Worktree{},
+	}
+
+	// Without --remote the shared server isn't searched; with it, a machine
+	// that created nothing there has nothing to drop (and runs no SSH)
+	orphans, err := FindOrphanedDatabases(context.Background(), cfg, "", false)
+	require.NoError(t, err)
+	assert.Empty(t, orphans)
+	orphans, err = FindOrphanedDatabases(context.Background(), cfg, "", true)
+	require.NoError(t, err)
+	assert.Empty(t, orphans)
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -62,9 +64,18 @@ func GoldenDBURL(localURL string, projectName string) string {
 	return BuildWorktreeURL(localURL, dbName)
 }
 
-// SyncToGoldenDB syncs from remote source to local golden database using pipe
-// This is much faster than writing to a file first
+// SyncToGoldenDB syncs from remote source to local golden database. The
+// source and the local server must be the same engine.
 func SyncToGoldenDB(ctx context.Context, sourceURL string, localURL string, projectName string, cfg *DatabaseConfig, progress ProgressFunc) (*GoldenDBSyncResult, error) {
+	if source, local := DriverName(sourceURL), DriverName(localURL); source != local {
+		return nil, fmt.Errorf("source database is %s but the local server is %s: configure a local %s server with 'conductor database set-local'", source, local, source)
+	}
+	return DriverFor(sourceURL).SyncToGolden(ctx, sourceURL, localURL, projectName, cfg, progress)
+}
+
+// SyncToGolden pipes pg_dump from the source straight into the golden
+// database, which is much faster than writing to a file first
+func (postgresDriver) SyncToGolden(ctx context.Context, sourceURL string, localURL string, projectName string, cfg *DatabaseConfig, progress ProgressFunc) (*GoldenDBSyncResult, error) {
 	startTime := time.Now()
 	var stepTimes []string // Collect timing for final summary
 
@@ -73,6 +84,11 @@ func SyncToGoldenDB(ctx context.Context, sourceURL string, localURL string, proj
 	if err := CreateGoldenDB(localURL, projectName); err != nil {
 		return nil, fmt.Errorf("failed to create golden DB: %w", err)
 	}
+	// Worktrees must not copy the golden DB while its contents are replaced;
+	// it is marked as a template again once the sync completes
+	if err := SetTemplateDatabase(localURL, GoldenDBName(projectName), false); err != nil {
+		return nil, fmt.Errorf("failed to unmark golden DB as template: %w", err)
+	}
 	stepDuration := time.Since(stepStart).Milliseconds()
 	stepTimes = append(stepTimes, fmt.Sprintf("create_db:%s", formatMs(stepDuration)))
 	if progress != nil {
@@ -101,12 +117,31 @@ func SyncToGoldenDB(ctx context.Context, sourceURL string, localURL string, proj
 		filteredTables = make(map[string]string)
 	}
 
+	// Masked tables are copied separately with masking expressions in place of
+	// the raw columns. Build the select lists up front so a bad rule fails the
+	// sync before anything is copied.
+	maskedTables, err := ParseMaskColumns(cfg.MaskColumns)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range excludedTables {
+		delete(maskedTables, table) // no data is copied
+	}
+	maskedSelects := make(map[string]maskedSelect, len(maskedTables))
+	for _, table := range MaskedTableNames(maskedTables) {
+		selectList, columnList, err := MaskedSelect(ctx, sourceURL, table, maskedTables[table])
+		if err != nil {
+			return nil, fmt.Errorf("invalid masking rules: %w", err)
+		}
+		maskedSelects[table] = maskedSelect{selectList: selectList, columnList: columnList}
+	}
+
 	// Display table sizes summary
 	if progress != nil {
 		progress("")
 		progress("Table sizes (top 20 by size):")
 		progress("─────────────────────────────────────────────────────")
-		displayTableSizes(tables, excludedTables, filteredTables, progress)
+		displayTableSizes(tables, excludedTables, filteredTables, maskedTables, progress)
 		progress("─────────────────────────────────────────────────────")
 		progress("")
 	}
@@ -123,86 +158,52 @@ func SyncToGoldenDB(ctx context.Context, sourceURL string, localURL string, proj
 		progress(fmt.Sprintf("Got row counts (%s)", formatMs(stepDuration)))
 	}
 
-	// Build pg_dump args
-	dumpArgs := []string{
-		sourceURL,
-		"--no-owner",
-		"--no-acl",
-		"--clean",
-		"--if-exists",
+	// Filtered and masked tables are copied separately with WHERE and masking
+	// expressions
+	copyTables := make(map[string]bool, len(filteredTables)+len(maskedTables))
+	for table := range filteredTables {
+		copyTables[table] = true
 	}
-
-	// Add exclusions (fully excluded tables)
-	for _, table := range excludedTables {
-		dumpArgs = append(dumpArgs, "--exclude-table-data="+table)
+	for table := range maskedTables {
+		copyTables[table] = true
 	}
 
-	// Also exclude filtered tables from main dump (we'll copy them separately with WHERE)
-	for table := range filteredTables {
-		dumpArgs = append(dumpArgs, "--exclude-table-data="+table)
+	// With several workers, large tables are copied on their own COPY streams
+	// in parallel rather than one after another inside pg_dump
+	workers := SyncWorkers(cfg)
+	largeTables := make(map[string]bool)
+	if workers > 1 {
+		for _, table := range largeCopyCandidates(tables, excludedTables, copyTables) {
+			largeTables[table] = true
+			copyTables[table] = true
+		}
 	}
 
-	// Build psql args
-	psqlArgs := []string{
-		goldenURL,
-		"--quiet",
+	// pg_dump leaves out the data of excluded and separately copied tables
+	excludeData := append([]string(nil), excludedTables...)
+	for table := range copyTables {
+		excludeData = append(excludeData, table)
 	}
+	sort.Strings(excludeData)
 
 	stepStart = time.Now()
 	if progress != nil {
 		statusMsg := "Syncing to golden DB..."
-		if len(excludedTables) > 0 || len(filteredTables) > 0 {
-			statusMsg = fmt.Sprintf("Syncing (excl %d, filter %d)...",
-				len(excludedTables), len(filteredTables))
+		if len(excludedTables) > 0 || len(filteredTables) > 0 || len(maskedTables) > 0 || len(largeTables) > 0 {
+			statusMsg = fmt.Sprintf("Syncing (excl %d, filter %d, mask %d, parallel %d)...",
+				len(excludedTables), len(filteredTables), len(maskedTables), len(largeTables))
 		}
 		progress(statusMsg)
 	}
 
-	// Create pipe: pg_dump | psql
-	dumpCmd := exec.CommandContext(ctx, "pg_dump", dumpArgs...)
-	psqlCmd := exec.CommandContext(ctx, "psql", psqlArgs...)
-
-	// Connect pg_dump stdout to psql stdin
-	pipe, err := dumpCmd.StdoutPipe()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create pipe: %w", err)
-	}
-	psqlCmd.Stdin = pipe
-
-	// Capture stderr for errors
-	var dumpStderr, psqlStderr strings.Builder
-	dumpCmd.Stderr = &dumpStderr
-	psqlCmd.Stderr = &psqlStderr
-
-	// Start both commands
-	if err := dumpCmd.Start(); err != nil {
-		return nil, fmt.Errorf("failed to start pg_dump: %w", err)
-	}
-	if err := psqlCmd.Start(); err != nil {
-		_ = dumpCmd.Process.Kill()
-		return nil, fmt.Errorf("failed to start psql: %w", err)
-	}
-
-	// Wait for both to complete
-	dumpErr := dumpCmd.Wait()
-	psqlErr := psqlCmd.Wait()
-
-	if dumpErr != nil {
-		if ctx.Err() != nil {
-			return nil, fmt.Errorf("sync cancelled: %w", ctx.Err())
-		}
-		return nil, fmt.Errorf("pg_dump failed: %w\nstderr: %s", dumpErr, dumpStderr.String())
+	// Schema and data first; constraints and indexes wait for the separate
+	// copies. Without the post-data section pg_dump --clean doesn't drop the
+	// previous sync's foreign keys, which would block dropping the tables.
+	if err := dropForeignKeys(ctx, goldenURL); err != nil {
+		return nil, err
 	}
-	if psqlErr != nil {
-		if ctx.Err() != nil {
-			return nil, fmt.Errorf("sync cancelled: %w", ctx.Err())
-		}
-		// psql may have warnings that are not fatal
-		if !strings.Contains(psqlStderr.String(), "ERROR") {
-			// Just warnings, continue
-		} else {
-			return nil, fmt.Errorf("psql failed: %w\nstderr: %s", psqlErr, psqlStderr.String())
-		}
+	if err := restoreDumpToGolden(ctx, goldenDumpArgs(sourceURL, false, excludeData), goldenURL, progress); err != nil {
+		return nil, err
 	}
 	stepDuration = time.Since(stepStart).Milliseconds()
 	stepTimes = append(stepTimes, fmt.Sprintf("pg_dump:%s", formatMs(stepDuration)))
@@ -210,13 +211,13 @@ func SyncToGoldenDB(ctx context.Context, sourceURL string, localURL string, proj
 		progress(fmt.Sprintf("Synced to golden DB (%s)", formatMs(stepDuration)))
 	}
 
-	// Copy filtered tables with WHERE clause (in FK dependency order)
-	if len(filteredTables) > 0 {
+	// Copy filtered, masked and large tables (in FK dependency order)
+	if len(copyTables) > 0 {
 		filterStart := time.Now()
 
-		// Build list of filtered table names
-		tableList := make([]string, 0, len(filteredTables))
-		for table := range filteredTables {
+		// Build list of separately copied table names
+		tableList := make([]string, 0, len(copyTables))
+		for table := range copyTables {
 			tableList = append(tableList, table)
 		}
 
@@ -250,34 +251,90 @@ func SyncToGoldenDB(ctx context.Context, sourceURL string, localURL string, proj
 			}
 		}
 
-		// Sort tables by FK dependency (parents first)
+		// Sort tables by FK dependency (parents first); a child starts only
+		// once its parents are copied, independent tables run in parallel
 		sortedTables := sortTablesByFKDependency(tableList, fks)
+		if progress != nil && workers > 1 && len(sortedTables) > 1 {
+			progress(fmt.Sprintf("Copying %d tables with %d workers", len(sortedTables), workers))
+		}
 
-		// Copy in dependency order
-		for i, table := range sortedTables {
+		var mu sync.Mutex // guards stepTimes and done
+		done := 0
+		copyProgress := serializedProgress(progress)
+		err = copyTablesConcurrently(ctx, sortedTables, fkParents(sortedTables, fks), workers, func(ctx context.Context, table string) error {
 			whereClause := filteredTables[table]
-			if ctx.Err() != nil {
-				return nil, fmt.Errorf("sync cancelled: %w", ctx.Err())
+			selectList, columnList := "*", ""
+			masked, isMasked := maskedSelects[table]
+			if isMasked {
+				selectList, columnList = masked.selectList, masked.columnList
+			} else if largeTables[table] {
+				// List columns explicitly so generated columns are skipped
+				if sel, cols, err := MaskedSelect(ctx, sourceURL, table, nil); err == nil {
+					selectList, columnList = sel, cols
+				}
 			}
-			tableStart := time.Now()
 			// Extract short table name for display
 			shortName := table
 			if idx := strings.LastIndex(table, "."); idx != -1 {
 				shortName = table[idx+1:]
 			}
-			if err := copyFilteredTable(ctx, sourceURL, goldenURL, table, whereClause); err != nil {
+			if copyProgress != nil && workers > 1 {
+				copyProgress(fmt.Sprintf("Copying %s...", shortName))
+			}
+
+			tableStart := time.Now()
+			if err := copyFilteredTable(ctx, sourceURL, goldenURL, table, selectList, columnList, whereClause); err != nil {
+				if ctx.Err() != nil {
+					return fmt.Errorf("sync cancelled: %w", ctx.Err())
+				}
+				// A masked or large table left empty would look like a successful sync
+				if isMasked {
+					return fmt.Errorf("failed to copy masked table %s: %w", table, err)
+				}
+				if largeTables[table] {
+					return fmt.Errorf("failed to copy table %s: %w", table, err)
+				}
 				// Non-fatal, log and continue
-				if progress != nil {
-					progress(fmt.Sprintf("Warning: failed to copy %s: %v", table, err))
+				if copyProgress != nil {
+					copyProgress(fmt.Sprintf("Warning: failed to copy %s: %v", table, err))
 				}
 			}
-			stepDuration = time.Since(tableStart).Milliseconds()
-			stepTimes = append(stepTimes, fmt.Sprintf("%s:%s", shortName, formatMs(stepDuration)))
-			if progress != nil {
-				progress(fmt.Sprintf("Filtered %s [%d/%d] (%s)", shortName, i+1, len(sortedTables), formatMs(stepDuration)))
+			duration := time.Since(tableStart).Milliseconds()
+
+			mu.Lock()
+			done++
+			n := done
+			stepTimes = append(stepTimes, fmt.Sprintf("%s:%s", shortName, formatMs(duration)))
+			mu.Unlock()
+
+			if copyProgress != nil {
+				verb := "Copied"
+				switch {
+				case isMasked:
+					verb = "Masked"
+				case whereClause != "":
+					verb = "Filtered"
+				}
+				copyProgress(fmt.Sprintf("%s %s [%d/%d] (%s)", verb, shortName, n, len(sortedTables), formatMs(duration)))
 			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
-		stepTimes = append(stepTimes, fmt.Sprintf("filter_total:%s", formatMs(time.Since(filterStart).Milliseconds())))
+		stepTimes = append(stepTimes, fmt.Sprintf("copy_total:%s", formatMs(time.Since(filterStart).Milliseconds())))
+	}
+
+	// Indexes, constraints and triggers go on once every table has its data,
+	// so foreign keys to masked, filtered and large tables validate against it
+	stepStart = time.Now()
+	if err := restoreDumpToGolden(ctx, goldenDumpArgs(sourceURL, true, nil), goldenURL, progress); err != nil {
+		return nil, err
+	}
+	stepDuration = time.Since(stepStart).Milliseconds()
+	stepTimes = append(stepTimes, fmt.Sprintf("post_data:%s", formatMs(stepDuration)))
+	if progress != nil {
+		progress(fmt.Sprintf("Added indexes and constraints (%s)", formatMs(stepDuration)))
 	}
 
 	syncDuration := time.Since(startTime)
@@ -296,6 +353,11 @@ func SyncToGoldenDB(ctx context.Context, sourceURL string, localURL string, proj
 		progress(fmt.Sprintf("Updated sync metadata (%s)", formatMs(stepDuration)))
 	}
 
+	// Mark the golden DB as a template so worktree clones can copy it server-side
+	if err := SetTemplateDatabase(localURL, GoldenDBName(projectName), true); err != nil && progress != nil {
+		progress(fmt.Sprintf("Warning: failed to mark golden DB as template, clones will use pg_dump: %v", err))
+	}
+
 	// Build table sizes map
 	tableSizes := make(map[string]int64)
 	for _, t := range tables {
@@ -332,18 +394,32 @@ type GoldenDBSyncResult struct {
 	TableSizes     map[string]int64 `json:"tableSizes"`
 }
 
-// copyFilteredTable copies data from source to golden DB with a WHERE filter
+// maskedSelect is the select list and target column list for copying a masked table
+type maskedSelect struct {
+	selectList string
+	columnList string
+}
+
+// copyFilteredTable copies data from source to golden DB with an optional
+// WHERE filter and select list (masking expressions); columnList names the
+// target columns when the select list is not "*"
 // Uses COPY for efficient data transfer: source COPY TO | golden COPY FROM
-func copyFilteredTable(ctx context.Context, sourceURL, goldenURL, tableName, whereClause string) error {
-	// Build the COPY query with WHERE clause
-	// Format: COPY (SELECT * FROM table WHERE condition) TO STDOUT
-	copyOutQuery := fmt.Sprintf(`COPY (SELECT * FROM %s WHERE %s) TO STDOUT`, tableName, whereClause)
+func copyFilteredTable(ctx context.Context, sourceURL, goldenURL, tableName, selectList, columnList, whereClause string) error {
+	// Build the COPY query
+	// Format: COPY (SELECT cols FROM table WHERE condition) TO STDOUT
+	copyOutQuery := fmt.Sprintf(`COPY (SELECT %s FROM %s) TO STDOUT`, selectList, tableName)
+	if whereClause != "" {
+		copyOutQuery = fmt.Sprintf(`COPY (SELECT %s FROM %s WHERE %s) TO STDOUT`, selectList, tableName, whereClause)
+	}
 
 	// Source: psql -c "COPY ... TO STDOUT"
 	sourceCmd := exec.CommandContext(ctx, "psql", sourceURL, "-c", copyOutQuery)
 
-	// Golden: psql -c "COPY table FROM STDIN"
+	// Golden: psql -c "COPY table (cols) FROM STDIN"
 	copyInQuery := fmt.Sprintf(`COPY %s FROM STDIN`, tableName)
+	if columnList != "" {
+		copyInQuery = fmt.Sprintf(`COPY %s (%s) FROM STDIN`, tableName, columnList)
+	}
 	goldenCmd := exec.CommandContext(ctx, "psql", goldenURL, "-c", copyInQuery)
 
 	// Connect source stdout to golden stdin
@@ -458,17 +534,112 @@ func maskPassword(url string) string {
 	return url
 }
 
-// CloneFromGoldenDB clones the golden database to a worktree database using pipe
-func CloneFromGoldenDB(ctx context.Context, localURL string, projectName string, worktreeDBName string, progress ProgressFunc) error {
+// CloneMethod reports how a worktree database was copied from the golden database
+type CloneMethod string
+
+const (
+	// CloneMethodTemplate is a server-side CREATE DATABASE ... TEMPLATE copy
+	CloneMethodTemplate CloneMethod = "template"
+	// CloneMethodDump is a pg_dump | psql copy, used when the template is busy
+	CloneMethodDump CloneMethod = "pg_dump"
+)
+
+// CloneFromGoldenDB clones the golden database to a worktree database and
+// reports how it was copied
+func CloneFromGoldenDB(ctx context.Context, localURL string, projectName string, worktreeDBName string, progress ProgressFunc) (CloneMethod, error) {
+	return DriverFor(localURL).CloneFromGolden(ctx, localURL, projectName, worktreeDBName, progress)
+}
+
+// CloneFromGolden copies the golden DB server-side as a template, and falls
+// back to piping pg_dump into psql when that fails or a sync is replacing the
+// golden DB. The project's sync lock is held for the template copy, so a sync
+// can't start mid-copy; sessions connected to the golden DB are never
+// terminated and make the copy fall back instead.
+func (postgresDriver) CloneFromGolden(ctx context.Context, localURL string, projectName string, worktreeDBName string, progress ProgressFunc) (CloneMethod, error) {
 	// Check golden DB exists
 	exists, err := GoldenDBExists(localURL, projectName)
 	if err != nil {
-		return fmt.Errorf("failed to check golden DB: %w", err)
+		return "", fmt.Errorf("failed to check golden DB: %w", err)
 	}
 	if !exists {
-		return fmt.Errorf("golden database does not exist for project %s - run sync first", projectName)
+		return "", fmt.Errorf("golden database does not exist for project %s - run sync first", projectName)
+	}
+
+	// Fast path: server-side copy
+	if progress != nil {
+		progress("Cloning from golden DB template...")
+	}
+	start := time.Now()
+	templateErr := cloneGoldenTemplate(ctx, localURL, projectName, worktreeDBName)
+	if templateErr == nil {
+		// pg_dump leaves the sync metadata out of clones; match that
+		worktreeURL := BuildWorktreeURL(localURL, worktreeDBName)
+		if db, err := sql.Open("postgres", worktreeURL); err == nil {
+			_, _ = db.ExecContext(ctx, "DROP TABLE IF EXISTS "+ConductorSyncTable)
+			_ = db.Close()
+		}
+		if progress != nil {
+			progress(fmt.Sprintf("Clone completed via template (%s)", formatMs(time.Since(start).Milliseconds())))
+		}
+		return CloneMethodTemplate, nil
+	}
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("clone cancelled: %w", ctx.Err())
+	}
+	if progress != nil {
+		progress(fmt.Sprintf("Template clone unavailable, falling back to pg_dump: %v", templateErr))
+	}
+
+	if err := cloneGoldenDBViaDump(ctx, localURL, projectName, worktreeDBName, progress); err != nil {
+		return "", err
+	}
+	return CloneMethodDump, nil
+}
+
+// errGoldenSyncing is why a clone skips the template copy while a sync runs
+var errGoldenSyncing = errors.New("golden DB is being synced")
+
+// errGoldenBusy is why a clone skips the template copy while others are
+// connected to the golden DB
+var errGoldenBusy = errors.New("golden DB busy")
+
+// cloneGoldenTemplate copies the golden DB with CREATE DATABASE ... TEMPLATE.
+// It refuses while a sync runs: either the sync lock is held, or the golden DB
+// isn't marked as a template (a sync is running or the last one failed).
+func cloneGoldenTemplate(ctx context.Context, localURL, projectName, worktreeDBName string) error {
+	unlock, err := lockTemplateClone(projectName)
+	if err != nil {
+		return err
 	}
+	defer func() { _ = unlock() }()
 
+	isTemplate, err := isTemplateDatabase(localURL, GoldenDBName(projectName))
+	if err != nil {
+		return err
+	}
+	if !isTemplate {
+		return errGoldenSyncing
+	}
+	err = CreateDatabaseFromTemplate(ctx, localURL, worktreeDBName, GoldenDBName(projectName))
+	if errors.Is(err, errTemplateBusy) {
+		return fmt.Errorf("%w: %w", errGoldenBusy, err)
+	}
+	return err
+}
+
+// lockTemplateClone takes the project's sync lock for a template copy,
+// failing with errGoldenSyncing if a sync holds it
+func lockTemplateClone(projectName string) (func() error, error) {
+	unlock, err := lockProjectSync(projectName, 0)
+	if err != nil {
+		return nil, errGoldenSyncing
+	}
+	return unlock, nil
+}
+
+// cloneGoldenDBViaDump clones the golden database into a new worktree database
+// by piping pg_dump into psql
+func cloneGoldenDBViaDump(ctx context.Context, localURL string, projectName string, worktreeDBName string, progress ProgressFunc) error {
 	// Create worktree DB
 	if progress != nil {
 		progress("Creating worktree database...")
@@ -547,7 +718,7 @@ func CloneFromGoldenDB(ctx context.Context, localURL string, projectName string,
 	}
 
 	if progress != nil {
-		progress("Clone completed")
+		progress("Clone completed via pg_dump")
 	}
 
 	return nil
@@ -581,18 +752,20 @@ func ReinitializeDatabaseV3(ctx context.Context, localURL string, projectName st
 	}
 
 	// Clone from golden database
-	if err := CloneFromGoldenDB(ctx, localURL, projectName, worktreeDBName, progress); err != nil {
+	method, err := CloneFromGoldenDB(ctx, localURL, projectName, worktreeDBName, progress)
+	if err != nil {
 		return nil, err
 	}
 
 	result := &ReinitV3Result{
 		DatabaseName: worktreeDBName,
+		CloneMethod:  method,
 	}
 
-	// Check migration status if worktree uses Prisma
-	if HasPrismaMigrations(worktreePath) {
+	// Check migration status if worktree uses a supported migration tool
+	if provider := MigrationProviderFor(worktreePath); provider != nil {
 		dbURL := BuildWorktreeURL(localURL, worktreeDBName)
-		state, err := DetectMigrationState(dbURL, worktreePath)
+		state, err := DetectMigrationStateWith(provider, dbURL, worktreePath)
 		if err == nil {
 			result.MigrationState = state
 		}
@@ -604,6 +777,7 @@ func ReinitializeDatabaseV3(ctx context.Context, localURL string, projectName st
 // ReinitV3Result contains the result of a V3 database reinitialization
 type ReinitV3Result struct {
 	DatabaseName   string
+	CloneMethod    CloneMethod
 	MigrationState *MigrationState
 }
 
@@ -617,10 +791,20 @@ func GetGoldenDBSyncInfo(localURL string, projectName string) (*GoldenDBSyncInfo
 		return nil, nil
 	}
 
-	goldenURL := GoldenDBURL(localURL, projectName)
+	info := &GoldenDBSyncInfo{
+		GoldenDBName: GoldenDBName(projectName),
+		Exists:       true,
+	}
+	if err := DriverFor(localURL).GoldenSyncInfo(GoldenDBURL(localURL, projectName), info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (postgresDriver) GoldenSyncInfo(goldenURL string, info *GoldenDBSyncInfo) error {
 	db, err := sql.Open("postgres", goldenURL)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer func() { _ = db.Close() }()
 
@@ -633,18 +817,10 @@ func GetGoldenDBSyncInfo(localURL string, projectName string) (*GoldenDBSyncInfo
 		)
 	`).Scan(&tableExists)
 	if err != nil || !tableExists {
-		return &GoldenDBSyncInfo{
-			GoldenDBName: GoldenDBName(projectName),
-			Exists:       true,
-		}, nil
+		return nil
 	}
 
 	// Get latest sync record
-	info := &GoldenDBSyncInfo{
-		GoldenDBName: GoldenDBName(projectName),
-		Exists:       true,
-	}
-
 	err = db.QueryRow(`
 		SELECT synced_at, source_url, sync_duration_ms, is_incremental
 		FROM _conductor_sync
@@ -652,19 +828,14 @@ func GetGoldenDBSyncInfo(localURL string, projectName string) (*GoldenDBSyncInfo
 		LIMIT 1
 	`).Scan(&info.LastSyncAt, &info.SourceURL, &info.SyncDurationMs, &info.IsIncremental)
 	if err != nil && err != sql.ErrNoRows {
-		return nil, err
+		return err
 	}
 
 	// Get table count
-	err = db.QueryRow(`
+	return db.QueryRow(`
 		SELECT COUNT(*) FROM information_schema.tables
 		WHERE table_schema = 'public' AND table_type = 'BASE TABLE'
 	`).Scan(&info.TableCount)
-	if err != nil {
-		return nil, err
-	}
-
-	return info, nil
 }
 
 // GoldenDBSyncInfo contains information about the golden database
@@ -731,6 +902,126 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%dd", days)
 }
 
+// goldenDumpArgs returns the pg_dump arguments for one pass of a golden sync.
+// The first pass recreates the schema and loads the data of every table not
+// in excludeData; the post-data pass adds indexes, constraints and triggers
+// after the tables copied separately have been filled.
+func goldenDumpArgs(sourceURL string, postData bool, excludeData []string) []string {
+	args := []string{sourceURL, "--no-owner", "--no-acl"}
+	if postData {
+		return append(args, "--section=post-data")
+	}
+	args = append(args, "--clean", "--if-exists", "--section=pre-data", "--section=data")
+	for _, table := range excludeData {
+		args = append(args, "--exclude-table-data="+table)
+	}
+	return args
+}
+
+// dropForeignKeys drops every foreign key constraint in a database
+func dropForeignKeys(ctx context.Context, dbURL string) error {
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	_, err = db.ExecContext(ctx, `
+		DO $$
+		DECLARE r record;
+		BEGIN
+			FOR r IN
+				SELECT conrelid::regclass AS tbl, conname FROM pg_constraint
+				WHERE contype = 'f' AND connamespace NOT IN ('pg_catalog'::regnamespace, 'information_schema'::regnamespace)
+			LOOP
+				EXECUTE format('ALTER TABLE %s DROP CONSTRAINT IF EXISTS %I', r.tbl, r.conname);
+			END LOOP;
+		END $$
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to drop foreign keys of the previous sync: %w", err)
+	}
+	return nil
+}
+
+// restoreDumpToGolden pipes pg_dump into psql against the golden DB.
+// Statements psql failed to run are reported through progress.
+func restoreDumpToGolden(ctx context.Context, dumpArgs []string, goldenURL string, progress ProgressFunc) error {
+	dumpCmd := exec.CommandContext(ctx, "pg_dump", dumpArgs...)
+	psqlCmd := exec.CommandContext(ctx, "psql", goldenURL, "--quiet")
+
+	// Connect pg_dump stdout to psql stdin
+	pipe, err := dumpCmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create pipe: %w", err)
+	}
+	psqlCmd.Stdin = pipe
+
+	// Capture stderr for errors
+	var dumpStderr, psqlStderr strings.Builder
+	dumpCmd.Stderr = &dumpStderr
+	psqlCmd.Stderr = &psqlStderr
+
+	// Start both commands
+	if err := dumpCmd.Start(); err != nil {
+		return fmt.Errorf("failed to start pg_dump: %w", err)
+	}
+	if err := psqlCmd.Start(); err != nil {
+		_ = dumpCmd.Process.Kill()
+		return fmt.Errorf("failed to start psql: %w", err)
+	}
+
+	// Wait for both to complete
+	dumpErr := dumpCmd.Wait()
+	psqlErr := psqlCmd.Wait()
+
+	if dumpErr != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("sync cancelled: %w", ctx.Err())
+		}
+		return fmt.Errorf("pg_dump failed: %w\nstderr: %s", dumpErr, dumpStderr.String())
+	}
+	if psqlErr != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("sync cancelled: %w", ctx.Err())
+		}
+		// psql may have warnings that are not fatal
+		if strings.Contains(psqlStderr.String(), "ERROR") {
+			return fmt.Errorf("psql failed: %w\nstderr: %s", psqlErr, psqlStderr.String())
+		}
+	}
+	// psql carries on past failed statements, e.g. a constraint that didn't
+	// validate; report them rather than leave a golden copy missing objects
+	if errs := psqlErrorLines(psqlStderr.String()); len(errs) > 0 && progress != nil {
+		progress(fmt.Sprintf("Warning: %d statement(s) failed while restoring the dump:", len(errs)))
+		for _, line := range errs {
+			progress("  " + line)
+		}
+	}
+	return nil
+}
+
+// maxReportedPsqlErrors caps the psql errors a sync prints
+const maxReportedPsqlErrors = 10
+
+// psqlErrorLines returns the ERROR lines of psql's stderr, at most
+// maxReportedPsqlErrors of them
+func psqlErrorLines(stderr string) []string {
+	var lines []string
+	for _, line := range strings.Split(stderr, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.Contains(line, "ERROR:") {
+			continue
+		}
+		if len(lines) == maxReportedPsqlErrors {
+			lines = append(lines, "...")
+			break
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
 // formatMs formats milliseconds in a human-readable way
 // < 1000ms: "123ms"
 // >= 1000ms: "1:23" (m:ss) or "12:34" (mm:ss)
@@ -748,7 +1039,7 @@ func formatMs(ms int64) string {
 }
 
 // displayTableSizes shows a formatted table of table sizes at sync start
-func displayTableSizes(tables []TableInfo, excludedTables []string, filteredTables map[string]string, progress ProgressFunc) {
+func displayTableSizes(tables []TableInfo, excludedTables []string, filteredTables map[string]string, maskedTables map[string]TableMasks, progress ProgressFunc) {
 	if progress == nil || len(tables) == 0 {
 		return
 	}
@@ -796,10 +1087,16 @@ func displayTableSizes(tables []TableInfo, excludedTables []string, filteredTabl
 
 		// Determine status
 		status := "sync"
+		_, isFiltered := filteredTables[t.Schema+"."+t.Name]
+		_, isMasked := maskedTables[t.Schema+"."+t.Name]
 		if excludedMap[t.Schema+"."+t.Name] {
 			status = "EXCLUDE"
-		} else if _, isFiltered := filteredTables[t.Schema+"."+t.Name]; isFiltered {
+		} else if isFiltered && isMasked {
+			status = "FILTER+MASK"
+		} else if isFiltered {
 			status = "FILTER"
+		} else if isMasked {
+			status = "MASK"
 		}
 
 		progress(fmt.Sprintf("  %-*s  %10s  %10d  %s",
@@ -832,8 +1129,10 @@ func LoadGoldenDBMetadata(localURL string, projectName string) (*SyncMetadata, e
 	if !exists {
 		return nil, nil
 	}
+	return DriverFor(localURL).LoadGoldenMetadata(GoldenDBURL(localURL, projectName))
+}
 
-	goldenURL := GoldenDBURL(localURL, projectName)
+func (postgresDriver) LoadGoldenMetadata(goldenURL string) (*SyncMetadata, error) {
 	db, err := sql.Open("postgres", goldenURL)
 	if err != nil {
 		return nil, err
@@ -882,21 +1181,7 @@ func GetGoldenDBSize(localURL string, projectName string) (int64, error) {
 	if !exists {
 		return 0, nil
 	}
-
-	goldenURL := GoldenDBURL(localURL, projectName)
-	db, err := sql.Open("postgres", goldenURL)
-	if err != nil {
-		return 0, err
-	}
-	defer func() { _ = db.Close() }()
-
-	dbName := GoldenDBName(projectName)
-	var size int64
-	err = db.QueryRow(`SELECT pg_database_size($1)`, dbName).Scan(&size)
-	if err != nil {
-		return 0, err
-	}
-	return size, nil
+	return databaseSize(localURL, GoldenDBName(projectName))
 }
 
 // sortTablesByFKDependency returns tables ordered so parents come before children
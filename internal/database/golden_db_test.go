@@ -1,9 +1,13 @@
 package database
 
 import (
+	"context"
+	"os/exec"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSortTablesByFKDependency(t *testing.T) {
@@ -408,3 +412,106 @@ func indexOf(slice []string, item string) int {
 	}
 	return -1
 }
+
+func TestLockTemplateClone_RefusedWhileSyncing(t *testing.T) {
+	t.Setenv("CONDUCTOR_CONFIG_DIR", t.TempDir())
+
+	// A sync holds the project's lock
+	unlockSync, err := lockProjectSync("shop", 0)
+	require.NoError(t, err)
+
+	_, err = lockTemplateClone("shop")
+	assert.ErrorIs(t, err, errGoldenSyncing)
+
+	// Other projects are unaffected
+	unlockOther, err := lockTemplateClone("blog")
+	require.NoError(t, err)
+	require.NoError(t, unlockOther())
+
+	require.NoError(t, unlockSync())
+
+	// A template copy in progress keeps a sync from starting
+	unlockClone, err := lockTemplateClone("shop")
+	require.NoError(t, err)
+	_, err = lockProjectSync("shop", 0)
+	assert.Error(t, err)
+	require.NoError(t, unlockClone())
+
+	unlockSync, err = lockProjectSync("shop", 0)
+	require.NoError(t, err)
+	require.NoError(t, unlockSync())
+}
+
+func TestCloneGoldenTemplate_SkippedWhileSyncing(t *testing.T) {
+	t.Setenv("CONDUCTOR_CONFIG_DIR", t.TempDir())
+
+	unlockSync, err := lockProjectSync("shop", 0)
+	require.NoError(t, err)
+	defer func() { _ = unlockSync() }()
+
+	// The golden DB is never touched, so no server is needed
+	err = cloneGoldenTemplate(context.Background(), "postgresql://localhost:1/postgres", "shop", "shop_3100")
+	assert.ErrorIs(t, err, errGoldenSyncing)
+}
+
+func TestSyncToGolden_KeepsForeignKeyToMaskedTable(t *testing.T) {
+	if err := ValidateConnection(testDBURL); err != nil {
+		t.Skipf("Test database not available: %v", err)
+	}
+	if _, err := exec.LookPath("pg_dump"); err != nil {
+		t.Skip("pg_dump not installed")
+	}
+	const source, project = "conductor_mask_fk_src", "conductor-mask-fk"
+	_ = DropDatabase(testDBURL, source)
+	require.NoError(t, CreateDatabase(testDBURL, source))
+	t.Cleanup(func() {
+		_ = DropDatabase(testDBURL, source)
+		_ = SetTemplateDatabase(testDBURL, GoldenDBName(project), false)
+		_ = DropGoldenDB(testDBURL, project)
+	})
+	sourceURL := BuildWorktreeURL(testDBURL, source)
+
+	db, err := openDB(sourceURL)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		CREATE TABLE users (id int PRIMARY KEY, email text NOT NULL);
+		CREATE TABLE orders (id int PRIMARY KEY, user_id int NOT NULL REFERENCES users (id));
+		INSERT INTO users VALUES (1, 'ada@example.com');
+		INSERT INTO orders VALUES (1, 1);
+	`)
+	_ = db.Close()
+	require.NoError(t, err)
+
+	cfg := &DatabaseConfig{MaskColumns: map[string]string{"users.email": "email_domain"}}
+	// Sync twice: the second run replaces the tables the first one created
+	for range 2 {
+		_, err = SyncToGoldenDB(context.Background(), sourceURL, testDBURL, project, cfg, nil)
+		require.NoError(t, err)
+	}
+
+	golden, err := openDB(GoldenDBURL(testDBURL, project))
+	require.NoError(t, err)
+	defer func() { _ = golden.Close() }()
+	var fks int
+	require.NoError(t, golden.QueryRow(`SELECT COUNT(*) FROM pg_constraint WHERE contype = 'f' AND conrelid = 'orders'::regclass`).Scan(&fks))
+	assert.Equal(t, 1, fks, "orders_user_id_fkey should survive the masked copy of users")
+}
+
+func TestSyncProjectWithTrigger_WaitsForSyncLock(t *testing.T) {
+	t.Setenv("CONDUCTOR_CONFIG_DIR", t.TempDir())
+	wait := templateCloneWait
+	templateCloneWait = 50 * time.Millisecond
+	t.Cleanup(func() { templateCloneWait = wait })
+
+	// Another manager (e.g. the scheduler's) is syncing the project
+	unlockSync, err := lockProjectSync("shop", 0)
+	require.NoError(t, err)
+	defer func() { _ = unlockSync() }()
+
+	var messages []string
+	_, err = NewManager("postgresql://localhost:1/postgres", "").SyncProjectWithTrigger(context.Background(), "shop", &DatabaseConfig{}, SyncTriggerManual, func(msg string) {
+		messages = append(messages, msg)
+	})
+	assert.ErrorContains(t, err, "sync already in progress")
+	assert.Equal(t, []string{"Waiting for another sync or clone of the golden DB to finish..."}, messages)
+}
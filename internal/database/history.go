@@ -0,0 +1,293 @@
+package database
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hammashamzah/conductor/internal/config"
+)
+
+// What started a sync
+const (
+	SyncTriggerManual   = "manual"
+	SyncTriggerSchedule = "schedule"
+)
+
+// Sync modes. Golden database syncs always recreate the golden copy.
+const (
+	SyncModeFull        = "full"
+	SyncModeIncremental = "incremental"
+)
+
+// Sync outcomes
+const (
+	SyncOutcomeSuccess = "success"
+	SyncOutcomeFailed  = "failed"
+)
+
+// SyncHistoryEntry records one golden database sync, successful or not
+type SyncHistoryEntry struct {
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Mode       string    `json:"mode"`
+	Outcome    string    `json:"outcome"`
+	Error      string    `json:"error,omitempty"`
+
+	// Trigger is what started the sync (manual or schedule), Actor who ran it (user@host)
+	Trigger string `json:"trigger"`
+	Actor   string `json:"actor,omitempty"`
+
+	Source         string   `json:"source,omitempty"` // masked source URL
+	TableCount     int      `json:"tableCount,omitempty"`
+	ExcludedTables []string `json:"excludedTables,omitempty"`
+	FilteredTables []string `json:"filteredTables,omitempty"`
+
+	// SourceRowCounts are the source's rows per table when the sync ran, and
+	// RowDeltas their change from the previous sync (tables that didn't change
+	// are left out). Filtered, masked and excluded tables count their source
+	// rows too, not what the golden copy received.
+	SourceRowCounts map[string]int64 `json:"sourceRowCounts,omitempty"`
+	RowDeltas       map[string]int64 `json:"rowDeltas,omitempty"`
+
+	// Schema hashes of the golden database before and after the sync
+	// (SchemaBefore is empty for the first sync)
+	SchemaBefore string `json:"schemaBefore,omitempty"`
+	SchemaAfter  string `json:"schemaAfter,omitempty"`
+}
+
+// Duration returns how long the sync ran
+func (e *SyncHistoryEntry) Duration() time.Duration {
+	return e.FinishedAt.Sub(e.StartedAt)
+}
+
+// SchemaChanged reports whether the sync changed the golden copy's schema
+func (e *SyncHistoryEntry) SchemaChanged() bool {
+	return e.SchemaBefore != "" && e.SchemaAfter != "" && e.SchemaBefore != e.SchemaAfter
+}
+
+// NetRowDelta sums the row deltas over all tables
+func (e *SyncHistoryEntry) NetRowDelta() int64 {
+	var total int64
+	for _, d := range e.RowDeltas {
+		total += d
+	}
+	return total
+}
+
+// SchemaSummary describes the schema change for display
+func (e *SyncHistoryEntry) SchemaSummary() string {
+	switch {
+	case e.SchemaAfter == "":
+		return "-"
+	case e.SchemaBefore == "":
+		return "new"
+	case e.SchemaChanged():
+		return "changed"
+	default:
+		return "same"
+	}
+}
+
+// Details returns a multi-line description of the entry
+func (e *SyncHistoryEntry) Details() []string {
+	lines := []string{
+		fmt.Sprintf("Started:   %s", e.StartedAt.Local().Format("2006-01-02 15:04:05")),
+		fmt.Sprintf("Finished:  %s (%s)", e.FinishedAt.Local().Format("2006-01-02 15:04:05"), formatDuration(e.Duration())),
+		fmt.Sprintf("Outcome:   %s", e.Outcome),
+		fmt.Sprintf("Mode:      %s", e.Mode),
+		fmt.Sprintf("Trigger:   %s", describeTrigger(e.Trigger, e.Actor)),
+	}
+	if e.Source != "" {
+		lines = append(lines, fmt.Sprintf("Source:    %s", e.Source))
+	}
+	if e.Error != "" {
+		lines = append(lines, fmt.Sprintf("Error:     %s", e.Error))
+	}
+	if e.SchemaAfter != "" {
+		lines = append(lines, fmt.Sprintf("Schema:    %s (%s → %s)", e.SchemaSummary(), shortHash(e.SchemaBefore), shortHash(e.SchemaAfter)))
+	}
+	if e.TableCount > 0 {
+		lines = append(lines, fmt.Sprintf("Tables:    %d", e.TableCount))
+	}
+	if len(e.ExcludedTables) > 0 {
+		lines = append(lines, fmt.Sprintf("Excluded:  %s", strings.Join(e.ExcludedTables, ", ")))
+	}
+	if len(e.FilteredTables) > 0 {
+		lines = append(lines, fmt.Sprintf("Filtered:  %s", strings.Join(e.FilteredTables, ", ")))
+	}
+
+	if len(e.RowDeltas) > 0 {
+		lines = append(lines, fmt.Sprintf("Source row changes (net %s):", formatDelta(e.NetRowDelta())))
+		tables := make([]string, 0, len(e.RowDeltas))
+		for t := range e.RowDeltas {
+			tables = append(tables, t)
+		}
+		// Biggest changes first
+		sort.Slice(tables, func(i, j int) bool {
+			di, dj := abs64(e.RowDeltas[tables[i]]), abs64(e.RowDeltas[tables[j]])
+			if di != dj {
+				return di > dj
+			}
+			return tables[i] < tables[j]
+		})
+		for _, t := range tables {
+			lines = append(lines, fmt.Sprintf("  %-40s %10s  (%d rows)", t, formatDelta(e.RowDeltas[t]), e.SourceRowCounts[t]))
+		}
+	} else if e.Outcome == SyncOutcomeSuccess {
+		lines = append(lines, "Source row changes: none")
+	}
+	return lines
+}
+
+// rowDeltas returns the per-table change in row counts between two syncs.
+// Tables that appeared count all their rows, tables that went away count
+// their old rows as removed.
+func rowDeltas(before, after map[string]int64) map[string]int64 {
+	deltas := make(map[string]int64)
+	for t, n := range after {
+		if d := n - before[t]; d != 0 {
+			deltas[t] = d
+		}
+	}
+	for t, n := range before {
+		if _, ok := after[t]; !ok && n != 0 {
+			deltas[t] = -n
+		}
+	}
+	return deltas
+}
+
+// SyncHistoryPath returns the file a project's sync history is appended to
+func SyncHistoryPath(projectName string) (string, error) {
+	dir, err := config.ConductorDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dbsync", projectName, "history.jsonl"), nil
+}
+
+// AppendSyncHistory appends an entry to a project's sync history
+func AppendSyncHistory(projectName string, entry *SyncHistoryEntry) error {
+	path, err := SyncHistoryPath(projectName)
+	if err != nil {
+		return err
+	}
+	return appendSyncHistoryFile(path, entry)
+}
+
+func appendSyncHistoryFile(path string, entry *SyncHistoryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open sync history: %w", err)
+	}
+	// One write per record, so concurrent appends don't interleave
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write sync history: %w", err)
+	}
+	return f.Close()
+}
+
+// LoadSyncHistory returns a project's sync history, newest first. limit <= 0
+// returns every entry.
+func LoadSyncHistory(projectName string, limit int) ([]SyncHistoryEntry, error) {
+	path, err := SyncHistoryPath(projectName)
+	if err != nil {
+		return nil, err
+	}
+	return loadSyncHistoryFile(path, limit)
+}
+
+func loadSyncHistoryFile(path string, limit int) ([]SyncHistoryEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open sync history: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []SyncHistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // row counts for big schemas
+	for scanner.Scan() {
+		var entry SyncHistoryEntry
+		// Skip a torn line from a crash mid-append rather than losing the history
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read sync history: %w", err)
+	}
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// syncActor identifies who is running a sync as user@host
+func syncActor() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		name = u.Username
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		name += "@" + host
+	}
+	return name
+}
+
+// describeTrigger formats a trigger and actor for display
+func describeTrigger(trigger, actor string) string {
+	if actor == "" {
+		return trigger
+	}
+	return trigger + " by " + actor
+}
+
+// shortHash abbreviates a schema hash for display
+func shortHash(hash string) string {
+	if hash == "" {
+		return "none"
+	}
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+// formatDelta formats a row count change with its sign
+func formatDelta(d int64) string {
+	if d > 0 {
+		return fmt.Sprintf("+%d", d)
+	}
+	return fmt.Sprintf("%d", d)
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowDeltas(t *testing.T) {
+	before := map[string]int64{"users": 10, "orders": 5, "legacy": 3, "empty": 0}
+	after := map[string]int64{"users": 12, "orders": 5, "events": 7, "empty": 0}
+
+	assert.Equal(t, map[string]int64{
+		"users":  2,
+		"events": 7,
+		"legacy": -3,
+	}, rowDeltas(before, after))
+
+	assert.Equal(t, map[string]int64{"users": 1}, rowDeltas(nil, map[string]int64{"users": 1}))
+	assert.Empty(t, rowDeltas(after, after))
+}
+
+func TestSyncHistoryFile_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dbsync", "shop", "history.jsonl")
+
+	entries, err := loadSyncHistoryFile(path, 0)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "missing file is an empty history")
+
+	start := time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, appendSyncHistoryFile(path, &SyncHistoryEntry{
+			StartedAt:  start.Add(time.Duration(i) * time.Hour),
+			FinishedAt: start.Add(time.Duration(i)*time.Hour + time.Minute),
+			Mode:       SyncModeFull,
+			Outcome:    SyncOutcomeSuccess,
+			Trigger:    SyncTriggerSchedule,
+			TableCount: i + 1,
+		}))
+	}
+
+	entries, err = loadSyncHistoryFile(path, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, 3, entries[0].TableCount, "newest first")
+	assert.Equal(t, 1, entries[2].TableCount)
+	assert.Equal(t, time.Minute, entries[0].Duration())
+
+	entries, err = loadSyncHistoryFile(path, 2)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, 3, entries[0].TableCount)
+	assert.Equal(t, 2, entries[1].TableCount)
+}
+
+func TestSyncHistoryFile_SkipsTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	require.NoError(t, appendSyncHistoryFile(path, &SyncHistoryEntry{Outcome: SyncOutcomeSuccess, TableCount: 1}))
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"outcome":"succ` + "\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	require.NoError(t, appendSyncHistoryFile(path, &SyncHistoryEntry{Outcome: SyncOutcomeFailed, Error: "boom"}))
+
+	entries, err := loadSyncHistoryFile(path, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, SyncOutcomeFailed, entries[0].Outcome)
+	assert.Equal(t, 1, entries[1].TableCount)
+}
+
+func TestSyncHistoryEntry_SchemaSummary(t *testing.T) {
+	tests := []struct {
+		before, after string
+		want          string
+		changed       bool
+	}{
+		{"", "", "-", false},
+		{"", "abc", "new", false},
+		{"abc", "abc", "same", false},
+		{"abc", "def", "changed", true},
+	}
+	for _, tt := range tests {
+		e := &SyncHistoryEntry{SchemaBefore: tt.before, SchemaAfter: tt.after}
+		assert.Equal(t, tt.want, e.SchemaSummary(), "%q -> %q", tt.before, tt.after)
+		assert.Equal(t, tt.changed, e.SchemaChanged(), "%q -> %q", tt.before, tt.after)
+	}
+}
+
+func TestSyncHistoryEntry_Details(t *testing.T) {
+	e := &SyncHistoryEntry{
+		StartedAt:       time.Now().Add(-90 * time.Second),
+		FinishedAt:      time.Now(),
+		Mode:            SyncModeFull,
+		Outcome:         SyncOutcomeSuccess,
+		Trigger:         SyncTriggerManual,
+		Actor:           "alice@laptop",
+		SchemaBefore:    "0123456789abcdef",
+		SchemaAfter:     "fedcba9876543210",
+		SourceRowCounts: map[string]int64{"users": 120, "orders": 40},
+		RowDeltas:       map[string]int64{"users": 20, "orders": -100},
+	}
+	details := strings.Join(e.Details(), "\n")
+
+	assert.Contains(t, details, "manual by alice@laptop")
+	assert.Contains(t, details, "changed (0123456789ab → fedcba987654)")
+	assert.Contains(t, details, "Source row changes (net -80):")
+	// Biggest change listed first
+	assert.Less(t, strings.Index(details, "orders"), strings.Index(details, "users"))
+
+	e.RowDeltas = nil
+	assert.Contains(t, strings.Join(e.Details(), "\n"), "Source row changes: none")
+
+	e.Outcome = SyncOutcomeFailed
+	e.Error = "connection refused"
+	details = strings.Join(e.Details(), "\n")
+	assert.Contains(t, details, "Error:     connection refused")
+	assert.NotContains(t, details, "row changes")
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -35,8 +36,32 @@ func (m *Manager) SyncProjectWithProgress(projectName string, cfg *DatabaseConfi
 }
 
 // SyncProjectWithProgressCtx syncs with progress callback and context for cancellation
-// Uses V3 (golden database) approach - pipes directly to local PostgreSQL for speed
+// Uses V3 (golden database) approach - pipes directly to the local server for speed
 func (m *Manager) SyncProjectWithProgressCtx(ctx context.Context, projectName string, cfg *DatabaseConfig, progress ProgressFunc) (*SyncMetadata, error) {
+	return m.SyncProjectWithTrigger(ctx, projectName, cfg, SyncTriggerManual, progress)
+}
+
+// SyncProjectWithTrigger syncs like SyncProjectWithProgressCtx and records
+// the sync, with what triggered it, in the project's sync history. It holds
+// the project's sync lock, so syncs from any process and manager run one at a
+// time and never while a worktree copies the golden DB.
+func (m *Manager) SyncProjectWithTrigger(ctx context.Context, projectName string, cfg *DatabaseConfig, trigger string, progress ProgressFunc) (*SyncMetadata, error) {
+	unlock, err := lockProjectSync(projectName, 0)
+	if err != nil {
+		if progress != nil {
+			progress("Waiting for another sync or clone of the golden DB to finish...")
+		}
+		if unlock, err = lockProjectSync(projectName, templateCloneWait); err != nil {
+			return nil, fmt.Errorf("sync already in progress for project %s", projectName)
+		}
+	}
+	defer func() { _ = unlock() }()
+
+	return m.syncLocked(ctx, projectName, cfg, trigger, progress)
+}
+
+// syncLocked runs a sync with the project's sync lock held
+func (m *Manager) syncLocked(ctx context.Context, projectName string, cfg *DatabaseConfig, trigger string, progress ProgressFunc) (*SyncMetadata, error) {
 	m.mu.Lock()
 	if m.syncing[projectName] {
 		m.mu.Unlock()
@@ -51,12 +76,46 @@ func (m *Manager) SyncProjectWithProgressCtx(ctx context.Context, projectName st
 		m.mu.Unlock()
 	}()
 
+	entry := &SyncHistoryEntry{
+		StartedAt: time.Now(),
+		Mode:      SyncModeFull,
+		Trigger:   trigger,
+		Actor:     syncActor(),
+		Source:    MaskConnectionString(cfg.Source),
+	}
+	for table := range cfg.FilterTables {
+		entry.FilteredTables = append(entry.FilteredTables, table)
+	}
+	sort.Strings(entry.FilteredTables)
+
+	// Capture the golden copy as it was before the sync replaces it
+	var previousRows map[string]int64
+	if exists, _ := GoldenDBExists(m.localURL, projectName); exists {
+		if metadata, err := LoadGoldenDBMetadata(m.localURL, projectName); err == nil && metadata != nil {
+			previousRows = metadata.RowCounts
+		}
+		entry.SchemaBefore, _ = computeSchemaHash(GoldenDBURL(m.localURL, projectName))
+	}
+
 	// Use V3 (golden database) approach - much faster than file-based
 	result, err := SyncToGoldenDB(ctx, cfg.Source, m.localURL, projectName, cfg, progress)
+	entry.FinishedAt = time.Now()
 	if err != nil {
+		entry.Outcome = SyncOutcomeFailed
+		entry.Error = err.Error()
+		recordSyncHistory(projectName, entry, progress)
 		return nil, err
 	}
 
+	entry.Outcome = SyncOutcomeSuccess
+	entry.TableCount = result.TableCount
+	entry.ExcludedTables = append([]string(nil), result.ExcludedTables...)
+	sort.Strings(entry.ExcludedTables)
+	entry.SourceRowCounts = result.RowCounts
+	entry.RowDeltas = rowDeltas(previousRows, result.RowCounts)
+	entry.SchemaAfter, _ = computeSchemaHash(result.GoldenDBURL)
+	recordSyncHistory(projectName, entry, progress)
+
 	// Convert to SyncMetadata for backwards compatibility
 	return &SyncMetadata{
 		LastSyncAt:     time.Now(),
@@ -69,6 +128,14 @@ func (m *Manager) SyncProjectWithProgressCtx(ctx context.Context, projectName st
 	}, nil
 }
 
+// recordSyncHistory appends a sync to the history. A failure to record is
+// reported but doesn't fail the sync.
+func recordSyncHistory(projectName string, entry *SyncHistoryEntry, progress ProgressFunc) {
+	if err := AppendSyncHistory(projectName, entry); err != nil && progress != nil {
+		progress(fmt.Sprintf("Warning: failed to record sync history: %v", err))
+	}
+}
+
 // CheckSyncNeeded checks if a sync is needed for a project (V3 only)
 func (m *Manager) CheckSyncNeeded(projectName string, cfg *DatabaseConfig) (*SyncCheckResult, error) {
 	return CheckGoldenDBSyncNeeded(m.localURL, projectName, DefaultSyncCooldown)
@@ -82,7 +149,7 @@ func (m *Manager) IsSyncing(projectName string) bool {
 }
 
 // CloneForWorktree creates a database for a worktree from the golden copy (V3 only)
-func (m *Manager) CloneForWorktree(projectName string, dbName string) error {
+func (m *Manager) CloneForWorktree(projectName string, dbName string) (CloneMethod, error) {
 	return CloneFromGoldenDB(context.Background(), m.localURL, projectName, dbName, nil)
 }
 
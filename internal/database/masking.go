@@ -0,0 +1,455 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Mask rule kinds for DatabaseConfig.MaskColumns
+const (
+	MaskNull        = "null"         // replace with NULL
+	MaskHash        = "hash"         // replace with the md5 of the value
+	MaskEmailDomain = "email_domain" // user_<hash>@<original domain>
+	MaskFake        = "fake"         // fake:<kind>, a realistic replacement value
+	MaskStatic      = "static"       // static:<value>, the same value for every row
+)
+
+// maskHashSlot is the number of hex digits of md5 used to pick fake values
+// (28 bits always fits a positive int)
+const maskHashSlot = 7
+
+var maskFirstNames = []string{
+	"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda",
+	"William", "Elizabeth", "David", "Barbara", "Richard", "Susan", "Joseph", "Jessica",
+	"Thomas", "Sarah", "Charles", "Karen", "Daniel", "Nancy", "Matthew", "Lisa",
+}
+
+var maskLastNames = []string{
+	"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis",
+	"Rodriguez", "Martinez", "Hernandez", "Lopez", "Wilson", "Anderson", "Thomas", "Taylor",
+	"Moore", "Jackson", "Martin", "Lee", "Thompson", "White", "Harris", "Clark",
+}
+
+var maskStreets = []string{
+	"Main St", "Oak Ave", "Pine St", "Maple Ave", "Cedar Ln", "Elm St", "Lake Rd", "Hill St",
+	"Park Ave", "Washington St", "River Rd", "Sunset Blvd",
+}
+
+var maskCities = []string{
+	"Springfield", "Riverside", "Fairview", "Franklin", "Greenville", "Bristol", "Clinton",
+	"Georgetown", "Salem", "Madison", "Arlington", "Ashland",
+}
+
+var maskCompanies = []string{
+	"Acme Corp", "Globex", "Initech", "Umbrella Inc", "Hooli", "Stark Industries",
+	"Wayne Enterprises", "Vandelay Industries", "Soylent Co", "Cyberdyne Systems",
+}
+
+// maskFakeKinds lists the values accepted after "fake:"
+var maskFakeKinds = []string{"first_name", "last_name", "name", "email", "username", "phone", "address", "city", "company", "text"}
+
+// MaskRule is a parsed column masking rule
+type MaskRule struct {
+	Kind string
+	Arg  string // fake kind or static value
+}
+
+// ParseMaskRule parses a masking rule: "null", "hash", "email_domain",
+// "fake:<kind>" or "static:<value>"
+func ParseMaskRule(rule string) (MaskRule, error) {
+	kind, arg, hasArg := strings.Cut(strings.TrimSpace(rule), ":")
+	switch kind {
+	case MaskNull, MaskHash, MaskEmailDomain:
+		if hasArg {
+			return MaskRule{}, fmt.Errorf("invalid mask rule %q: %s takes no argument", rule, kind)
+		}
+		return MaskRule{Kind: kind}, nil
+	case MaskFake:
+		for _, k := range maskFakeKinds {
+			if arg == k {
+				return MaskRule{Kind: kind, Arg: arg}, nil
+			}
+		}
+		return MaskRule{}, fmt.Errorf("invalid mask rule %q: fake kind must be one of %s", rule, strings.Join(maskFakeKinds, ", "))
+	case MaskStatic:
+		if !hasArg {
+			return MaskRule{}, fmt.Errorf("invalid mask rule %q: expected static:<value>", rule)
+		}
+		return MaskRule{Kind: kind, Arg: arg}, nil
+	}
+	return MaskRule{}, fmt.Errorf("invalid mask rule %q: expected null, hash, email_domain, fake:<kind> or static:<value>", rule)
+}
+
+// String returns the rule in config syntax
+func (r MaskRule) String() string {
+	if r.Kind == MaskFake || r.Kind == MaskStatic {
+		return r.Kind + ":" + r.Arg
+	}
+	return r.Kind
+}
+
+// needsText reports whether the rule produces text, so the column must be text-like
+func (r MaskRule) needsText() bool {
+	return r.Kind != MaskNull && r.Kind != MaskStatic
+}
+
+// TableMasks maps column names to masking rules for one table
+type TableMasks map[string]MaskRule
+
+// ParseMaskColumns parses DatabaseConfig.MaskColumns into per-table rules keyed
+// by "schema.table". Keys are "schema.table.column", or "table.column" for
+// tables in the public schema.
+func ParseMaskColumns(maskColumns map[string]string) (map[string]TableMasks, error) {
+	result := make(map[string]TableMasks)
+	for key, ruleStr := range maskColumns {
+		table, column, err := SplitMaskColumn(key)
+		if err != nil {
+			return nil, err
+		}
+		rule, err := ParseMaskRule(ruleStr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		if result[table] == nil {
+			result[table] = make(TableMasks)
+		}
+		result[table][column] = rule
+	}
+	return result, nil
+}
+
+// SplitMaskColumn splits a MaskColumns key into "schema.table" and column
+func SplitMaskColumn(key string) (table string, column string, err error) {
+	parts := strings.Split(key, ".")
+	for _, p := range parts {
+		if p == "" {
+			return "", "", fmt.Errorf("invalid masked column %q: expected [schema.]table.column", key)
+		}
+	}
+	switch len(parts) {
+	case 2:
+		return "public." + parts[0], parts[1], nil
+	case 3:
+		return parts[0] + "." + parts[1], parts[2], nil
+	}
+	return "", "", fmt.Errorf("invalid masked column %q: expected [schema.]table.column", key)
+}
+
+// MaskedTableNames returns the tables with masked columns, sorted
+func MaskedTableNames(masks map[string]TableMasks) []string {
+	names := make([]string, 0, len(masks))
+	for name := range masks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// maskColumnInfo describes a source column for building a masked select list
+type maskColumnInfo struct {
+	Name      string
+	DataType  string // information_schema data_type
+	UDTName   string // underlying type name, e.g. citext for USER-DEFINED
+	MaxLength int    // character_maximum_length, 0 if unbounded
+}
+
+// isTextType reports whether a column type can hold masked text values
+func isTextType(dataType string, udtName string) bool {
+	switch dataType {
+	case "text", "character varying", "character":
+		return true
+	case "USER-DEFINED":
+		return udtName == "citext"
+	}
+	return false
+}
+
+// maskSQLExpr returns a SQL expression producing the masked value of a quoted
+// column. Replacements are derived from an md5 of the original value, so they
+// are stable across syncs and equal inputs (e.g. the same email in two
+// tables) mask to equal outputs.
+func maskSQLExpr(rule MaskRule, col string) string {
+	var expr string
+	switch rule.Kind {
+	case MaskNull:
+		return "NULL"
+	case MaskStatic:
+		return quoteLiteral(rule.Arg)
+	case MaskHash:
+		expr = fmt.Sprintf("md5(%s::text)", col)
+	case MaskEmailDomain:
+		expr = fmt.Sprintf("'user_' || substr(md5(%s::text), 1, 10) || '@' || split_part(%s::text, '@', 2)", col, col)
+	case MaskFake:
+		expr = fakeSQLExpr(rule.Arg, col)
+	}
+	return fmt.Sprintf("CASE WHEN %s IS NULL THEN NULL ELSE %s END", col, expr)
+}
+
+// fakeSQLExpr returns an expression for a fake:<kind> replacement
+func fakeSQLExpr(kind string, col string) string {
+	switch kind {
+	case "first_name":
+		return pickSQLExpr(maskFirstNames, col, 1)
+	case "last_name":
+		return pickSQLExpr(maskLastNames, col, 1)
+	case "name":
+		return pickSQLExpr(maskFirstNames, col, 1) + " || ' ' || " + pickSQLExpr(maskLastNames, col, 1+maskHashSlot)
+	case "email":
+		return fmt.Sprintf("'user_' || substr(md5(%s::text), 1, 10) || '@example.com'", col)
+	case "username":
+		return fmt.Sprintf("'user_' || substr(md5(%s::text), 1, 10)", col)
+	case "phone":
+		return fmt.Sprintf("'+1555' || lpad((%s %% 10000000)::text, 7, '0')", hashIntSQLExpr(col, 1))
+	case "address":
+		return fmt.Sprintf("(1 + %s %% 9999)::text || ' ' || %s", hashIntSQLExpr(col, 1), pickSQLExpr(maskStreets, col, 1+maskHashSlot))
+	case "city":
+		return pickSQLExpr(maskCities, col, 1)
+	case "company":
+		return pickSQLExpr(maskCompanies, col, 1)
+	default: // text
+		return "'Lorem ipsum dolor sit amet'"
+	}
+}
+
+// hashIntSQLExpr returns a non-negative integer taken from maskHashSlot hex
+// digits of the value's md5, starting at offset (1-based)
+func hashIntSQLExpr(col string, offset int) string {
+	return fmt.Sprintf("('x' || substr(md5(%s::text), %d, %d))::bit(%d)::int", col, offset, maskHashSlot, maskHashSlot*4)
+}
+
+// pickSQLExpr returns an expression choosing one of values by the value's hash
+func pickSQLExpr(values []string, col string, offset int) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quoteLiteral(v)
+	}
+	return fmt.Sprintf("(ARRAY[%s])[1 + %s %% %d]", strings.Join(quoted, ", "), hashIntSQLExpr(col, offset), len(values))
+}
+
+// quoteLiteral quotes a PostgreSQL string literal
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// buildMaskedSelect returns the select list and matching column list for
+// copying a table with masks applied. Masked columns are replaced by their
+// masking expression (truncated to the column's length limit) and aliased to
+// the original name. Errors if a masked column does not exist or a text rule
+// targets a non-text column.
+func buildMaskedSelect(table string, columns []maskColumnInfo, masks TableMasks) (selectList string, columnList string, err error) {
+	known := make(map[string]bool, len(columns))
+	selects := make([]string, 0, len(columns))
+	names := make([]string, 0, len(columns))
+	for _, c := range columns {
+		known[c.Name] = true
+		col := quoteIdentifier(c.Name)
+		names = append(names, col)
+
+		rule, masked := masks[c.Name]
+		if !masked {
+			selects = append(selects, col)
+			continue
+		}
+		if rule.needsText() && !isTextType(c.DataType, c.UDTName) {
+			return "", "", fmt.Errorf("cannot apply mask %q to %s.%s: column type %s is not text (use null or static)", rule, table, c.Name, c.DataType)
+		}
+		expr := maskSQLExpr(rule, col)
+		if c.MaxLength > 0 && rule.Kind != MaskNull {
+			expr = fmt.Sprintf("left(%s, %d)", expr, c.MaxLength)
+		}
+		selects = append(selects, fmt.Sprintf("%s AS %s", expr, col))
+	}
+
+	var missing []string
+	for name := range masks {
+		if !known[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", "", fmt.Errorf("masked columns not found in %s: %s", table, strings.Join(missing, ", "))
+	}
+
+	return strings.Join(selects, ", "), strings.Join(names, ", "), nil
+}
+
+// getMaskColumnInfo returns a table's non-generated columns in order
+func getMaskColumnInfo(ctx context.Context, db *sql.DB, table string) ([]maskColumnInfo, error) {
+	schema, name := "public", table
+	if idx := strings.Index(table, "."); idx != -1 {
+		schema, name = table[:idx], table[idx+1:]
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT column_name, data_type, udt_name, COALESCE(character_maximum_length, 0)
+		FROM information_schema.columns
+		WHERE table_schema = $1 AND table_name = $2
+		  AND is_generated = 'NEVER'
+		ORDER BY ordinal_position
+	`, schema, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns of %s: %w", table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var columns []maskColumnInfo
+	for rows.Next() {
+		var c maskColumnInfo
+		if err := rows.Scan(&c.Name, &c.DataType, &c.UDTName, &c.MaxLength); err != nil {
+			return nil, err
+		}
+		columns = append(columns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s not found in source database", table)
+	}
+	return columns, nil
+}
+
+// MaskedSelect connects to the source and builds the select list and column
+// list for copying a table with masks applied
+func MaskedSelect(ctx context.Context, connStr string, table string, masks TableMasks) (selectList string, columnList string, err error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return "", "", err
+	}
+	defer func() { _ = db.Close() }()
+
+	columns, err := getMaskColumnInfo(ctx, db, table)
+	if err != nil {
+		return "", "", err
+	}
+	return buildMaskedSelect(table, columns, masks)
+}
+
+// PIIColumn is a column that likely holds personal data
+type PIIColumn struct {
+	Schema        string
+	Table         string
+	Column        string
+	DataType      string
+	SuggestedRule string
+	Reason        string
+}
+
+// Key returns the MaskColumns key for the column
+func (p PIIColumn) Key() string {
+	return p.Schema + "." + p.Table + "." + p.Column
+}
+
+// piiPattern matches column names that likely hold personal data
+type piiPattern struct {
+	match  func(name string) bool
+	rule   string // suggested rule for text columns
+	reason string
+}
+
+func nameIs(names ...string) func(string) bool {
+	return func(name string) bool {
+		for _, n := range names {
+			if name == n {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func nameContains(parts ...string) func(string) bool {
+	return func(name string) bool {
+		for _, p := range parts {
+			if strings.Contains(name, p) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// piiPatterns are checked in order; the first match wins
+var piiPatterns = []piiPattern{
+	{nameContains("password", "passwd", "secret", "token", "api_key", "apikey"), MaskHash, "credential"},
+	{nameContains("ssn", "social_security", "tax_id", "taxid", "passport", "national_id", "driver_license", "drivers_license"), MaskNull, "government ID"},
+	{nameContains("card_number", "cardnumber", "credit_card", "iban", "account_number", "routing_number", "cvv"), MaskNull, "financial"},
+	{nameContains("email"), MaskFake + ":email", "email address"},
+	{nameContains("phone", "mobile", "fax"), MaskFake + ":phone", "phone number"},
+	{nameIs("first_name", "firstname", "given_name", "givenname"), MaskFake + ":first_name", "name"},
+	{nameIs("last_name", "lastname", "surname", "family_name", "familyname"), MaskFake + ":last_name", "name"},
+	{nameIs("name", "full_name", "fullname", "display_name", "displayname", "contact_name", "customer_name"), MaskFake + ":name", "name"},
+	{nameIs("username", "user_name", "login", "handle"), MaskFake + ":username", "username"},
+	{nameContains("address", "street", "postal_code", "postcode", "zip"), MaskFake + ":address", "address"},
+	{nameContains("birth", "dob"), MaskNull, "date of birth"},
+	{nameIs("ip", "ip_address", "ipaddress", "last_ip", "current_sign_in_ip", "last_sign_in_ip", "remote_ip"), MaskNull, "IP address"},
+	{nameContains("latitude", "longitude"), MaskNull, "location"},
+}
+
+// suggestPIIRule returns the suggested rule and reason for a column, or "" if
+// the column does not look like personal data
+func suggestPIIRule(column string, dataType string, udtName string) (rule string, reason string) {
+	name := strings.ToLower(column)
+	if dataType == "inet" || dataType == "cidr" {
+		return MaskNull, "IP address"
+	}
+	for _, p := range piiPatterns {
+		if !p.match(name) {
+			continue
+		}
+		// A text rule matching a non-text column (e.g. email_verified boolean)
+		// is a false positive
+		if r, err := ParseMaskRule(p.rule); err == nil && r.needsText() && !isTextType(dataType, udtName) {
+			return "", ""
+		}
+		return p.rule, p.reason
+	}
+	return "", ""
+}
+
+// SuggestPIIColumns scans the source schema for columns whose names and types
+// suggest personal data, with a suggested masking rule for each
+func SuggestPIIColumns(connStr string) ([]PIIColumn, error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT c.table_schema, c.table_name, c.column_name, c.data_type, c.udt_name
+		FROM information_schema.columns c
+		JOIN information_schema.tables t
+			ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+		WHERE c.table_schema NOT IN ('pg_catalog', 'information_schema')
+		  AND t.table_type = 'BASE TABLE'
+		  AND c.is_generated = 'NEVER'
+		ORDER BY c.table_schema, c.table_name, c.ordinal_position
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []PIIColumn
+	for rows.Next() {
+		var c PIIColumn
+		var udtName string
+		if err := rows.Scan(&c.Schema, &c.Table, &c.Column, &c.DataType, &udtName); err != nil {
+			return nil, err
+		}
+		c.SuggestedRule, c.Reason = suggestPIIRule(c.Column, c.DataType, udtName)
+		if c.SuggestedRule != "" {
+			result = append(result, c)
+		}
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,151 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMaskRule(t *testing.T) {
+	tests := []struct {
+		rule     string
+		expected MaskRule
+		wantErr  bool
+	}{
+		{rule: "null", expected: MaskRule{Kind: MaskNull}},
+		{rule: "hash", expected: MaskRule{Kind: MaskHash}},
+		{rule: " email_domain ", expected: MaskRule{Kind: MaskEmailDomain}},
+		{rule: "fake:phone", expected: MaskRule{Kind: MaskFake, Arg: "phone"}},
+		{rule: "static:redacted", expected: MaskRule{Kind: MaskStatic, Arg: "redacted"}},
+		{rule: "static:", expected: MaskRule{Kind: MaskStatic, Arg: ""}},
+		{rule: "static:a:b", expected: MaskRule{Kind: MaskStatic, Arg: "a:b"}},
+		{rule: "static", wantErr: true},
+		{rule: "hash:md5", wantErr: true},
+		{rule: "fake:dragon", wantErr: true},
+		{rule: "fake", wantErr: true},
+		{rule: "scramble", wantErr: true},
+		{rule: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := ParseMaskRule(tt.rule)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rule)
+		})
+	}
+}
+
+func TestParseMaskColumns(t *testing.T) {
+	masks, err := ParseMaskColumns(map[string]string{
+		"users.email":              "email_domain",
+		"public.users.phone":       "fake:phone",
+		"billing.customers.tax_id": "null",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]TableMasks{
+		"public.users": {
+			"email": {Kind: MaskEmailDomain},
+			"phone": {Kind: MaskFake, Arg: "phone"},
+		},
+		"billing.customers": {
+			"tax_id": {Kind: MaskNull},
+		},
+	}, masks)
+	assert.Equal(t, []string{"billing.customers", "public.users"}, MaskedTableNames(masks))
+
+	_, err = ParseMaskColumns(map[string]string{"email": "hash"})
+	assert.Error(t, err)
+	_, err = ParseMaskColumns(map[string]string{"a.b.c.d": "hash"})
+	assert.Error(t, err)
+	_, err = ParseMaskColumns(map[string]string{"users..email": "hash"})
+	assert.Error(t, err)
+	_, err = ParseMaskColumns(map[string]string{"users.email": "shuffle"})
+	assert.Error(t, err)
+}
+
+func TestBuildMaskedSelect(t *testing.T) {
+	columns := []maskColumnInfo{
+		{Name: "id", DataType: "bigint"},
+		{Name: "email", DataType: "character varying", MaxLength: 40},
+		{Name: "birth_date", DataType: "date"},
+		{Name: "nick", DataType: "USER-DEFINED", UDTName: "citext"},
+	}
+
+	selectList, columnList, err := buildMaskedSelect("public.users", columns, TableMasks{
+		"email":      {Kind: MaskEmailDomain},
+		"birth_date": {Kind: MaskNull},
+		"nick":       {Kind: MaskFake, Arg: "username"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `"id", "email", "birth_date", "nick"`, columnList)
+	assert.Contains(t, selectList, `"id", left(CASE WHEN "email" IS NULL THEN NULL ELSE 'user_' || substr(md5("email"::text), 1, 10) || '@' || split_part("email"::text, '@', 2) END, 40) AS "email"`)
+	assert.Contains(t, selectList, `NULL AS "birth_date"`)
+	assert.Contains(t, selectList, `END AS "nick"`)
+	assert.NotContains(t, selectList, `"id", "email"`)
+
+	t.Run("text rule on non-text column", func(t *testing.T) {
+		_, _, err := buildMaskedSelect("public.users", columns, TableMasks{"birth_date": {Kind: MaskHash}})
+		assert.ErrorContains(t, err, "not text")
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		_, _, err := buildMaskedSelect("public.users", columns, TableMasks{"phone": {Kind: MaskNull}})
+		assert.ErrorContains(t, err, "phone")
+	})
+}
+
+func TestMaskSQLExpr(t *testing.T) {
+	assert.Equal(t, "NULL", maskSQLExpr(MaskRule{Kind: MaskNull}, `"x"`))
+	assert.Equal(t, "'it''s hidden'", maskSQLExpr(MaskRule{Kind: MaskStatic, Arg: "it's hidden"}, `"x"`))
+	assert.Equal(t, `CASE WHEN "x" IS NULL THEN NULL ELSE md5("x"::text) END`, maskSQLExpr(MaskRule{Kind: MaskHash}, `"x"`))
+
+	// Every fake kind produces an expression
+	for _, kind := range maskFakeKinds {
+		expr := maskSQLExpr(MaskRule{Kind: MaskFake, Arg: kind}, `"x"`)
+		assert.Contains(t, expr, `CASE WHEN "x" IS NULL`, kind)
+	}
+
+	name := fakeSQLExpr("name", `"x"`)
+	assert.Contains(t, name, "'James'")
+	assert.Contains(t, name, "'Smith'")
+	assert.Contains(t, name, `substr(md5("x"::text), 8, 7))::bit(28)::int % 24`)
+}
+
+func TestSuggestPIIRule(t *testing.T) {
+	tests := []struct {
+		column   string
+		dataType string
+		udtName  string
+		rule     string
+	}{
+		{"email", "character varying", "varchar", "fake:email"},
+		{"billing_email", "text", "text", "fake:email"},
+		{"phone_number", "text", "text", "fake:phone"},
+		{"first_name", "text", "text", "fake:first_name"},
+		{"Name", "text", "text", "fake:name"},
+		{"street_address", "text", "text", "fake:address"},
+		{"password_digest", "character varying", "varchar", "hash"},
+		{"ssn", "text", "text", "null"},
+		{"date_of_birth", "date", "date", "null"},
+		{"last_sign_in_ip", "inet", "inet", "null"},
+		{"email_verified", "boolean", "bool", ""},
+		{"nick", "USER-DEFINED", "citext", ""},
+		{"id", "bigint", "int8", ""},
+		{"created_at", "timestamp with time zone", "timestamptz", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			rule, _ := suggestPIIRule(tt.column, tt.dataType, tt.udtName)
+			assert.Equal(t, tt.rule, rule)
+		})
+	}
+}
@@ -2,7 +2,6 @@ package database
 
 import (
 	"crypto/sha256"
-	"database/sql"
 	"encoding/hex"
 	"fmt"
 	"os"
@@ -34,25 +33,34 @@ const (
 	MigrationUnknown MigrationCompatibility = "unknown"
 )
 
-// PrismaMigration represents a migration record from _prisma_migrations table
-type PrismaMigration struct {
+// AppliedMigration is a migration recorded in a migration tool's bookkeeping
+// table. Fields a tool doesn't record are left empty.
+type AppliedMigration struct {
 	ID                string     `json:"id"`
 	MigrationName     string     `json:"migrationName"`
 	Checksum          string     `json:"checksum"`
 	AppliedAt         time.Time  `json:"appliedAt"`
 	AppliedStepsCount int        `json:"appliedStepsCount"`
 	RolledBackAt      *time.Time `json:"rolledBackAt,omitempty"`
+	// Dirty marks a migration that failed part-way (golang-migrate)
+	Dirty bool `json:"dirty,omitempty"`
 }
 
+// PrismaMigration is a migration record from the _prisma_migrations table
+type PrismaMigration = AppliedMigration
+
 // MigrationState represents the comparison between DB and worktree migrations
 type MigrationState struct {
 	// Compatibility is the overall state (forward, diverged, behind, synced)
 	Compatibility MigrationCompatibility `json:"compatibility"`
 
+	// Tool is the migration tool the state was read with (e.g. "prisma")
+	Tool string `json:"tool,omitempty"`
+
 	// AppliedMigrations are migrations in the database
-	AppliedMigrations []PrismaMigration `json:"appliedMigrations"`
+	AppliedMigrations []AppliedMigration `json:"appliedMigrations"`
 
-	// WorktreeMigrations are migrations in the worktree's prisma/migrations/
+	// WorktreeMigrations are migrations in the worktree's migrations folder
 	WorktreeMigrations []string `json:"worktreeMigrations"`
 
 	// PendingMigrations are in worktree but not applied (forward case)
@@ -71,62 +79,14 @@ type MigrationState struct {
 // Note: MigrationBaseline is defined in types.go
 
 // GetAppliedMigrations queries the _prisma_migrations table from a database
-func GetAppliedMigrations(dbURL string) ([]PrismaMigration, error) {
-	db, err := sql.Open("postgres", dbURL)
+func GetAppliedMigrations(dbURL string) ([]AppliedMigration, error) {
+	db, err := openDB(dbURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 	defer func() { _ = db.Close() }()
 
-	// Check if _prisma_migrations table exists
-	var exists bool
-	err = db.QueryRow(`
-		SELECT EXISTS (
-			SELECT FROM information_schema.tables
-			WHERE table_schema = 'public'
-			AND table_name = '_prisma_migrations'
-		)
-	`).Scan(&exists)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check for _prisma_migrations table: %w", err)
-	}
-
-	if !exists {
-		// No migrations table - database hasn't been initialized with Prisma
-		return []PrismaMigration{}, nil
-	}
-
-	// Query all migrations, ordered by applied_at
-	rows, err := db.Query(`
-		SELECT id, migration_name, checksum, started_at, applied_steps_count, rolled_back_at
-		FROM _prisma_migrations
-		WHERE rolled_back_at IS NULL
-		ORDER BY started_at ASC
-	`)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query migrations: %w", err)
-	}
-	defer func() { _ = rows.Close() }()
-
-	var migrations []PrismaMigration
-	for rows.Next() {
-		var m PrismaMigration
-		var rolledBackAt sql.NullTime
-		err := rows.Scan(&m.ID, &m.MigrationName, &m.Checksum, &m.AppliedAt, &m.AppliedStepsCount, &rolledBackAt)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan migration row: %w", err)
-		}
-		if rolledBackAt.Valid {
-			m.RolledBackAt = &rolledBackAt.Time
-		}
-		migrations = append(migrations, m)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating migration rows: %w", err)
-	}
-
-	return migrations, nil
+	return prismaProvider{}.AppliedMigrations(db, "")
 }
 
 // GetWorktreeMigrations lists migration directories from prisma/migrations/
@@ -188,27 +148,45 @@ func ComputeMigrationChecksum(worktreePath, migrationName string) (string, error
 	return hex.EncodeToString(hash[:]), nil
 }
 
-// DetectMigrationState compares database migrations with worktree migrations
+// DetectMigrationState compares database migrations with worktree migrations,
+// using the migration tool detected in the worktree
 func DetectMigrationState(dbURL, worktreePath string) (*MigrationState, error) {
+	provider := MigrationProviderFor(worktreePath)
+	if provider == nil {
+		return nil, fmt.Errorf("no supported migration tool detected in %s", worktreePath)
+	}
+	return DetectMigrationStateWith(provider, dbURL, worktreePath)
+}
+
+// DetectMigrationStateWith compares database migrations with worktree
+// migrations as recorded by the given migration tool
+func DetectMigrationStateWith(provider MigrationProvider, dbURL, worktreePath string) (*MigrationState, error) {
 	// Get applied migrations from database
-	applied, err := GetAppliedMigrations(dbURL)
+	db, err := openDB(dbURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	applied, err := provider.AppliedMigrations(db, worktreePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
 	}
 
 	// Get worktree migrations from filesystem
-	worktree, err := GetWorktreeMigrations(worktreePath)
+	worktree, err := provider.WorktreeMigrations(worktreePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get worktree migrations: %w", err)
 	}
 
 	state := &MigrationState{
+		Tool:               provider.Name(),
 		AppliedMigrations:  applied,
 		WorktreeMigrations: worktree,
 	}
 
 	// Build maps for comparison
-	appliedMap := make(map[string]PrismaMigration)
+	appliedMap := make(map[string]AppliedMigration)
 	for _, m := range applied {
 		appliedMap[m.MigrationName] = m
 	}
@@ -232,16 +210,21 @@ func DetectMigrationState(dbURL, worktreePath string) (*MigrationState, error) {
 		}
 	}
 
-	// Check for divergent migrations (same name but different checksum)
+	// Check for divergent migrations (same name but different checksum, or
+	// left half-applied)
 	for _, m := range applied {
-		if worktreeSet[m.MigrationName] {
-			worktreeChecksum, err := ComputeMigrationChecksum(worktreePath, m.MigrationName)
+		if m.Dirty {
+			state.DivergentMigrations = append(state.DivergentMigrations, m.MigrationName)
+			continue
+		}
+		if worktreeSet[m.MigrationName] && m.Checksum != "" {
+			worktreeChecksum, err := provider.Checksum(worktreePath, m.MigrationName)
 			if err != nil {
 				// Can't compute checksum, mark as potentially divergent
 				state.DivergentMigrations = append(state.DivergentMigrations, m.MigrationName)
 				continue
 			}
-			if worktreeChecksum != m.Checksum {
+			if worktreeChecksum != "" && worktreeChecksum != m.Checksum {
 				state.DivergentMigrations = append(state.DivergentMigrations, m.MigrationName)
 			}
 		}
@@ -291,11 +274,12 @@ func getRecommendedAction(state *MigrationState) string {
 		return "Database is up to date with worktree migrations"
 
 	case MigrationForward:
+		command := deployCommandFor(state.Tool)
 		count := len(state.PendingMigrations)
 		if count == 1 {
-			return "Run 'prisma migrate deploy' to apply 1 pending migration"
+			return fmt.Sprintf("Run '%s' to apply 1 pending migration", command)
 		}
-		return fmt.Sprintf("Run 'prisma migrate deploy' to apply %d pending migrations", count)
+		return fmt.Sprintf("Run '%s' to apply %d pending migrations", command, count)
 
 	case MigrationBehind:
 		count := len(state.ExtraMigrations)
@@ -316,7 +300,7 @@ func getRecommendedAction(state *MigrationState) string {
 }
 
 // CreateMigrationBaseline creates a baseline from applied migrations
-func CreateMigrationBaseline(migrations []PrismaMigration) *MigrationBaseline {
+func CreateMigrationBaseline(migrations []AppliedMigration) *MigrationBaseline {
 	if len(migrations) == 0 {
 		return &MigrationBaseline{
 			TotalMigrations: 0,
@@ -386,11 +370,25 @@ func HasPrismaMigrations(worktreePath string) bool {
 	return err == nil
 }
 
-// GetMigrationBaselineFromDB creates a baseline directly from a database
+// GetMigrationBaselineFromDB creates a baseline directly from a database,
+// using the first migration tool whose bookkeeping table has entries
 func GetMigrationBaselineFromDB(dbURL string) (*MigrationBaseline, error) {
-	migrations, err := GetAppliedMigrations(dbURL)
+	db, err := openDB(dbURL)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	for _, provider := range migrationProviders {
+		migrations, err := provider.AppliedMigrations(db, "")
+		if err != nil {
+			return nil, err
+		}
+		if len(migrations) > 0 {
+			baseline := CreateMigrationBaseline(migrations)
+			baseline.Tool = provider.Name()
+			return baseline, nil
+		}
 	}
-	return CreateMigrationBaseline(migrations), nil
+	return CreateMigrationBaseline(nil), nil
 }
@@ -0,0 +1,931 @@
+package database
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hammashamzah/conductor/internal/detect"
+)
+
+// MigrationProvider reads one migration tool's migration files and the
+// bookkeeping table it keeps in the database
+type MigrationProvider interface {
+	// Name is the tool's name as reported by detect (e.g. "prisma")
+	Name() string
+
+	// DeployCommand is the command that applies pending migrations
+	DeployCommand() string
+
+	// WorktreeMigrations lists the migrations on disk, oldest first
+	WorktreeMigrations(worktreePath string) ([]string, error)
+
+	// Checksum returns a worktree migration's checksum as the tool records it
+	// in the database, or "" if the tool doesn't record checksums
+	Checksum(worktreePath, name string) (string, error)
+
+	// AppliedMigrations reads the applied migrations from the bookkeeping
+	// table, oldest first, using the names WorktreeMigrations would return.
+	// Returns an empty list if the table doesn't exist. worktreePath may be ""
+	// when no worktree is at hand (e.g. capturing the golden baseline).
+	AppliedMigrations(db *sql.DB, worktreePath string) ([]AppliedMigration, error)
+}
+
+// migrationProviders are tried in this order when no worktree is at hand
+var migrationProviders = []MigrationProvider{
+	prismaProvider{},
+	drizzleProvider{},
+	railsProvider{},
+	djangoProvider{},
+	alembicProvider{},
+	gooseProvider{},
+	golangMigrateProvider{},
+}
+
+// GetMigrationProvider returns the provider for a detect.MigrationTool* name
+func GetMigrationProvider(name string) MigrationProvider {
+	for _, p := range migrationProviders {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// MigrationProviderFor returns the provider for the migration tool detected
+// in a worktree, or nil if it uses none conductor understands
+func MigrationProviderFor(worktreePath string) MigrationProvider {
+	info, err := detect.DetectProject(worktreePath)
+	if err != nil || info.MigrationTool == "" {
+		return nil
+	}
+	return GetMigrationProvider(info.MigrationTool)
+}
+
+// deployCommandFor returns the apply command for a tool, defaulting to Prisma's
+func deployCommandFor(tool string) string {
+	if p := GetMigrationProvider(tool); p != nil {
+		return p.DeployCommand()
+	}
+	return prismaProvider{}.DeployCommand()
+}
+
+// relationExists reports whether a (optionally schema-qualified) table exists
+func relationExists(db *sql.DB, name string) (bool, error) {
+	var exists bool
+	var err error
+	if isMySQLConn(db) {
+		// MySQL has no schemas: a qualifier names another database
+		schema, table := "", name
+		if idx := strings.LastIndex(name, "."); idx != -1 {
+			schema, table = name[:idx], name[idx+1:]
+		}
+		err = db.QueryRow(`
+			SELECT COUNT(*) > 0 FROM information_schema.tables
+			WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND table_name = ?
+		`, schema, table).Scan(&exists)
+	} else {
+		err = db.QueryRow(`SELECT to_regclass($1) IS NOT NULL`, name).Scan(&exists)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check for %s table: %w", name, err)
+	}
+	return exists, nil
+}
+
+// golangMigrateTable reports whether schema_migrations has golang-migrate's
+// shape (version bigint, dirty bool) rather than Rails' (a varchar version
+// and nothing else). Both tools keep their bookkeeping under that name.
+func golangMigrateTable(db *sql.DB) (bool, error) {
+	var dirty bool
+	var err error
+	if isMySQLConn(db) {
+		err = db.QueryRow(`
+			SELECT COUNT(*) > 0 FROM information_schema.columns
+			WHERE table_schema = DATABASE() AND table_name = 'schema_migrations' AND column_name = 'dirty'
+		`).Scan(&dirty)
+	} else {
+		err = db.QueryRow(`
+			SELECT EXISTS (
+				SELECT 1 FROM pg_attribute
+				WHERE attrelid = to_regclass('schema_migrations') AND attname = 'dirty' AND NOT attisdropped
+			)
+		`).Scan(&dirty)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect schema_migrations: %w", err)
+	}
+	return dirty, nil
+}
+
+// isMySQLConn reports whether db was opened with the MySQL driver
+func isMySQLConn(db *sql.DB) bool {
+	_, ok := db.Driver().(*mysql.MySQLDriver)
+	return ok
+}
+
+// fileChecksum returns the hex SHA256 of a file's contents
+func fileChecksum(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read migration file: %w", err)
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// ---------------------------------------------------------------------------
+// Prisma: prisma/migrations/<name>/migration.sql, _prisma_migrations
+// ---------------------------------------------------------------------------
+
+type prismaProvider struct{}
+
+func (prismaProvider) Name() string          { return detect.MigrationToolPrisma }
+func (prismaProvider) DeployCommand() string { return "prisma migrate deploy" }
+
+func (prismaProvider) WorktreeMigrations(worktreePath string) ([]string, error) {
+	return GetWorktreeMigrations(worktreePath)
+}
+
+func (prismaProvider) Checksum(worktreePath, name string) (string, error) {
+	return ComputeMigrationChecksum(worktreePath, name)
+}
+
+func (prismaProvider) AppliedMigrations(db *sql.DB, _ string) ([]AppliedMigration, error) {
+	exists, err := relationExists(db, "_prisma_migrations")
+	if err != nil || !exists {
+		// No migrations table - database hasn't been initialized with Prisma
+		return []AppliedMigration{}, err
+	}
+
+	rows, err := db.Query(`
+		SELECT id, migration_name, checksum, started_at, applied_steps_count, rolled_back_at
+		FROM _prisma_migrations
+		WHERE rolled_back_at IS NULL
+		ORDER BY started_at ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query migrations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var migrations []AppliedMigration
+	for rows.Next() {
+		var m AppliedMigration
+		var rolledBackAt sql.NullTime
+		if err := rows.Scan(&m.ID, &m.MigrationName, &m.Checksum, &m.AppliedAt, &m.AppliedStepsCount, &rolledBackAt); err != nil {
+			return nil, fmt.Errorf("failed to scan migration row: %w", err)
+		}
+		if rolledBackAt.Valid {
+			m.RolledBackAt = &rolledBackAt.Time
+		}
+		migrations = append(migrations, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating migration rows: %w", err)
+	}
+	return migrations, nil
+}
+
+// ---------------------------------------------------------------------------
+// Drizzle: <out>/meta/_journal.json + <out>/<tag>.sql, drizzle.__drizzle_migrations
+// ---------------------------------------------------------------------------
+
+type drizzleProvider struct{}
+
+// drizzleJournal is drizzle-kit's meta/_journal.json
+type drizzleJournal struct {
+	Entries []struct {
+		Idx  int    `json:"idx"`
+		When int64  `json:"when"`
+		Tag  string `json:"tag"`
+	} `json:"entries"`
+}
+
+var drizzleOutPattern = regexp.MustCompile(`["']?out["']?\s*:\s*["']([^"']+)["']`)
+
+func (drizzleProvider) Name() string          { return detect.MigrationToolDrizzle }
+func (drizzleProvider) DeployCommand() string { return "drizzle-kit migrate" }
+
+// dir returns the migrations folder from drizzle.config.* ("out"), default "drizzle"
+func (drizzleProvider) dir(worktreePath string) string {
+	configs, _ := filepath.Glob(filepath.Join(worktreePath, "drizzle.config.*"))
+	for _, path := range configs {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if m := drizzleOutPattern.FindSubmatch(content); m != nil {
+			return filepath.Join(worktreePath, filepath.FromSlash(string(m[1])))
+		}
+	}
+	return filepath.Join(worktreePath, "drizzle")
+}
+
+func (p drizzleProvider) journal(worktreePath string) (*drizzleJournal, error) {
+	data, err := os.ReadFile(filepath.Join(p.dir(worktreePath), "meta", "_journal.json"))
+	if os.IsNotExist(err) {
+		return &drizzleJournal{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read drizzle journal: %w", err)
+	}
+	var journal drizzleJournal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return nil, fmt.Errorf("failed to parse drizzle journal: %w", err)
+	}
+	sort.SliceStable(journal.Entries, func(i, j int) bool { return journal.Entries[i].Idx < journal.Entries[j].Idx })
+	return &journal, nil
+}
+
+func (p drizzleProvider) WorktreeMigrations(worktreePath string) ([]string, error) {
+	journal, err := p.journal(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	migrations := []string{}
+	for _, e := range journal.Entries {
+		migrations = append(migrations, e.Tag)
+	}
+	return migrations, nil
+}
+
+// Checksum matches drizzle-kit: SHA256 of the migration's SQL file
+func (p drizzleProvider) Checksum(worktreePath, name string) (string, error) {
+	return fileChecksum(filepath.Join(p.dir(worktreePath), name+".sql"))
+}
+
+// AppliedMigrations maps drizzle's rows (hash, created_at) back to journal
+// tags by their timestamp, falling back to the file hash
+func (p drizzleProvider) AppliedMigrations(db *sql.DB, worktreePath string) ([]AppliedMigration, error) {
+	table := "drizzle.__drizzle_migrations"
+	exists, err := relationExists(db, table)
+	if err == nil && !exists {
+		table = "__drizzle_migrations"
+		exists, err = relationExists(db, table)
+	}
+	if err != nil || !exists {
+		return []AppliedMigration{}, err
+	}
+
+	tagByWhen := make(map[int64]string)
+	tagByHash := make(map[string]string)
+	if worktreePath != "" {
+		if journal, err := p.journal(worktreePath); err == nil {
+			for _, e := range journal.Entries {
+				tagByWhen[e.When] = e.Tag
+				if sum, err := p.Checksum(worktreePath, e.Tag); err == nil {
+					tagByHash[sum] = e.Tag
+				}
+			}
+		}
+	}
+
+	rows, err := db.Query(`SELECT id, hash, created_at FROM ` + table + ` ORDER BY created_at ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query migrations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var migrations []AppliedMigration
+	for rows.Next() {
+		var id, createdAt int64
+		var hash string
+		if err := rows.Scan(&id, &hash, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan migration row: %w", err)
+		}
+		name, ok := tagByWhen[createdAt]
+		if !ok {
+			if name, ok = tagByHash[hash]; !ok {
+				name = strconv.FormatInt(createdAt, 10)
+			}
+		}
+		migrations = append(migrations, AppliedMigration{
+			ID:            strconv.FormatInt(id, 10),
+			MigrationName: name,
+			Checksum:      hash,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating migration rows: %w", err)
+	}
+	return migrations, nil
+}
+
+// ---------------------------------------------------------------------------
+// Numbered migration files shared by Rails, goose and golang-migrate
+// ---------------------------------------------------------------------------
+
+// versionedFile is a migration file named <version>_<description>.<ext>
+type versionedFile struct {
+	version int64
+	name    string // file name without extension(s)
+}
+
+// listVersionedMigrations returns the files in dir matching pattern (whose
+// first group is the migration name and second its numeric version), ordered
+// by version
+func listVersionedMigrations(dir string, pattern *regexp.Regexp) ([]versionedFile, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	var files []versionedFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		m := pattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(m[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		files = append(files, versionedFile{version: version, name: m[1]})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].version < files[j].version })
+	return files, nil
+}
+
+func versionedNames(files []versionedFile) []string {
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.name
+	}
+	return names
+}
+
+// versionedName names an applied version after its worktree file, or after
+// the bare version when the worktree doesn't have it
+func versionedName(version int64, files []versionedFile) string {
+	for _, f := range files {
+		if f.version == version {
+			return f.name
+		}
+	}
+	return strconv.FormatInt(version, 10)
+}
+
+// goMigrationDirs are where goose and golang-migrate projects conventionally
+// keep their migrations
+var goMigrationDirs = []string{"migrations", "db/migrations", "database/migrations", "sql/migrations", "db/migration"}
+
+// findMigrationDir returns the first of dirs under worktreePath that has
+// migration files matching pattern, or "" if none does
+func findMigrationDir(worktreePath string, dirs []string, pattern *regexp.Regexp) string {
+	for _, dir := range dirs {
+		path := filepath.Join(worktreePath, filepath.FromSlash(dir))
+		if files, err := listVersionedMigrations(path, pattern); err == nil && len(files) > 0 {
+			return path
+		}
+	}
+	return ""
+}
+
+// ---------------------------------------------------------------------------
+// Rails: db/migrate/<version>_<name>.rb, schema_migrations
+// ---------------------------------------------------------------------------
+
+type railsProvider struct{}
+
+var railsMigrationFile = regexp.MustCompile(`^((\d+)_.+)\.rb$`)
+
+func (railsProvider) Name() string          { return detect.MigrationToolRails }
+func (railsProvider) DeployCommand() string { return "bin/rails db:migrate" }
+
+func (railsProvider) files(worktreePath string) ([]versionedFile, error) {
+	return listVersionedMigrations(filepath.Join(worktreePath, "db", "migrate"), railsMigrationFile)
+}
+
+func (p railsProvider) WorktreeMigrations(worktreePath string) ([]string, error) {
+	files, err := p.files(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	return versionedNames(files), nil
+}
+
+func (railsProvider) Checksum(string, string) (string, error) { return "", nil }
+
+func (p railsProvider) AppliedMigrations(db *sql.DB, worktreePath string) ([]AppliedMigration, error) {
+	exists, err := relationExists(db, "schema_migrations")
+	if err != nil || !exists {
+		return []AppliedMigration{}, err
+	}
+	if golangMigrate, err := golangMigrateTable(db); err != nil || golangMigrate {
+		return []AppliedMigration{}, err
+	}
+	var files []versionedFile
+	if worktreePath != "" {
+		if files, err = p.files(worktreePath); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `SELECT version::text FROM schema_migrations`
+	if isMySQLConn(db) {
+		query = `SELECT CAST(version AS CHAR) FROM schema_migrations`
+	}
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query migrations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var versions []int64
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, fmt.Errorf("failed to scan migration row: %w", err)
+		}
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			versions = append(versions, n)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating migration rows: %w", err)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	migrations := make([]AppliedMigration, len(versions))
+	for i, v := range versions {
+		migrations[i] = AppliedMigration{ID: strconv.FormatInt(v, 10), MigrationName: versionedName(v, files)}
+	}
+	return migrations, nil
+}
+
+// ---------------------------------------------------------------------------
+// goose: <dir>/<version>_<name>.sql|.go, goose_db_version
+// ---------------------------------------------------------------------------
+
+type gooseProvider struct{}
+
+var gooseMigrationFile = regexp.MustCompile(`^((\d+)_[^.]+)\.(?:sql|go)$`)
+
+func (gooseProvider) Name() string          { return detect.MigrationToolGoose }
+func (gooseProvider) DeployCommand() string { return "goose up" }
+
+func (gooseProvider) files(worktreePath string) ([]versionedFile, error) {
+	dir := findMigrationDir(worktreePath, goMigrationDirs, gooseMigrationFile)
+	if dir == "" {
+		return nil, nil
+	}
+	files, err := listVersionedMigrations(dir, gooseMigrationFile)
+	if err != nil {
+		return nil, err
+	}
+	// Go migrations may sit next to their tests
+	migrations := files[:0]
+	for _, f := range files {
+		if !strings.HasSuffix(f.name, "_test") {
+			migrations = append(migrations, f)
+		}
+	}
+	return migrations, nil
+}
+
+func (p gooseProvider) WorktreeMigrations(worktreePath string) ([]string, error) {
+	files, err := p.files(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	return versionedNames(files), nil
+}
+
+func (gooseProvider) Checksum(string, string) (string, error) { return "", nil }
+
+// AppliedMigrations replays goose's log: the latest row per version says
+// whether it is applied (version 0 is goose's own marker row)
+func (p gooseProvider) AppliedMigrations(db *sql.DB, worktreePath string) ([]AppliedMigration, error) {
+	exists, err := relationExists(db, "goose_db_version")
+	if err != nil || !exists {
+		return []AppliedMigration{}, err
+	}
+	var files []versionedFile
+	if worktreePath != "" {
+		if files, err = p.files(worktreePath); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := db.Query(`SELECT version_id, is_applied FROM goose_db_version ORDER BY id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query migrations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	applied := make(map[int64]bool)
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		if err := rows.Scan(&version, &isApplied); err != nil {
+			return nil, fmt.Errorf("failed to scan migration row: %w", err)
+		}
+		if version != 0 {
+			applied[version] = isApplied
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating migration rows: %w", err)
+	}
+
+	var versions []int64
+	for v, ok := range applied {
+		if ok {
+			versions = append(versions, v)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	migrations := make([]AppliedMigration, len(versions))
+	for i, v := range versions {
+		migrations[i] = AppliedMigration{ID: strconv.FormatInt(v, 10), MigrationName: versionedName(v, files)}
+	}
+	return migrations, nil
+}
+
+// ---------------------------------------------------------------------------
+// golang-migrate: <dir>/<version>_<name>.up.sql, schema_migrations (version, dirty)
+// ---------------------------------------------------------------------------
+
+type golangMigrateProvider struct{}
+
+var golangMigrateFile = regexp.MustCompile(`^((\d+)_.+)\.up\.sql$`)
+
+func (golangMigrateProvider) Name() string          { return detect.MigrationToolGolangMigrate }
+func (golangMigrateProvider) DeployCommand() string { return "migrate up" }
+
+func (golangMigrateProvider) files(worktreePath string) ([]versionedFile, error) {
+	dir := findMigrationDir(worktreePath, goMigrationDirs, golangMigrateFile)
+	if dir == "" {
+		return nil, nil
+	}
+	return listVersionedMigrations(dir, golangMigrateFile)
+}
+
+func (p golangMigrateProvider) WorktreeMigrations(worktreePath string) ([]string, error) {
+	files, err := p.files(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	return versionedNames(files), nil
+}
+
+func (golangMigrateProvider) Checksum(string, string) (string, error) { return "", nil }
+
+// AppliedMigrations expands golang-migrate's single current version into the
+// worktree migrations up to it; a version the worktree lacks is reported as is
+func (p golangMigrateProvider) AppliedMigrations(db *sql.DB, worktreePath string) ([]AppliedMigration, error) {
+	exists, err := relationExists(db, "schema_migrations")
+	if err != nil || !exists {
+		return []AppliedMigration{}, err
+	}
+	if golangMigrate, err := golangMigrateTable(db); err != nil || !golangMigrate {
+		return []AppliedMigration{}, err
+	}
+	var files []versionedFile
+	if worktreePath != "" {
+		if files, err = p.files(worktreePath); err != nil {
+			return nil, err
+		}
+	}
+
+	var current int64
+	var dirty bool
+	err = db.QueryRow(`SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&current, &dirty)
+	if err == sql.ErrNoRows {
+		return []AppliedMigration{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query migrations: %w", err)
+	}
+
+	var migrations []AppliedMigration
+	known := false
+	for _, f := range files {
+		if f.version > current {
+			break
+		}
+		known = known || f.version == current
+		migrations = append(migrations, AppliedMigration{ID: strconv.FormatInt(f.version, 10), MigrationName: f.name})
+	}
+	if !known {
+		migrations = append(migrations, AppliedMigration{ID: strconv.FormatInt(current, 10), MigrationName: versionedName(current, files)})
+	}
+	migrations[len(migrations)-1].Dirty = dirty
+	return migrations, nil
+}
+
+// ---------------------------------------------------------------------------
+// Django: <app>/migrations/NNNN_<name>.py, django_migrations
+// ---------------------------------------------------------------------------
+
+type djangoProvider struct{}
+
+var djangoMigrationFile = regexp.MustCompile(`^\d{4}_\w+\.py$`)
+
+// djangoSkipDirs are never searched for app migrations
+var djangoSkipDirs = map[string]bool{
+	".git": true, "node_modules": true, "venv": true, ".venv": true, "env": true,
+	"site-packages": true, "__pycache__": true, ".tox": true,
+}
+
+func (djangoProvider) Name() string          { return detect.MigrationToolDjango }
+func (djangoProvider) DeployCommand() string { return "python manage.py migrate" }
+
+// WorktreeMigrations returns "app.migration" names for every app package
+// with a migrations directory, ordered by app then migration
+func (djangoProvider) WorktreeMigrations(worktreePath string) ([]string, error) {
+	migrations := []string{}
+	err := filepath.WalkDir(worktreePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if djangoSkipDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+		if d.Name() != "migrations" {
+			return nil
+		}
+		if _, err := os.Stat(filepath.Join(path, "__init__.py")); err != nil {
+			return nil
+		}
+
+		app := filepath.Base(filepath.Dir(path))
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil
+		}
+		for _, e := range entries {
+			if !e.IsDir() && djangoMigrationFile.MatchString(e.Name()) {
+				migrations = append(migrations, app+"."+strings.TrimSuffix(e.Name(), ".py"))
+			}
+		}
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan django migrations: %w", err)
+	}
+	sort.Strings(migrations)
+	return migrations, nil
+}
+
+func (djangoProvider) Checksum(string, string) (string, error) { return "", nil }
+
+// AppliedMigrations only reports apps the worktree has migrations for, so
+// Django's own apps (auth, contenttypes, ...) don't show up as extra
+func (p djangoProvider) AppliedMigrations(db *sql.DB, worktreePath string) ([]AppliedMigration, error) {
+	exists, err := relationExists(db, "django_migrations")
+	if err != nil || !exists {
+		return []AppliedMigration{}, err
+	}
+
+	var apps map[string]bool
+	if worktreePath != "" {
+		names, err := p.WorktreeMigrations(worktreePath)
+		if err != nil {
+			return nil, err
+		}
+		apps = make(map[string]bool)
+		for _, name := range names {
+			apps[strings.SplitN(name, ".", 2)[0]] = true
+		}
+	}
+
+	rows, err := db.Query(`SELECT id, app, name, applied FROM django_migrations ORDER BY app, name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query migrations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var migrations []AppliedMigration
+	for rows.Next() {
+		var id int64
+		var app, name string
+		var m AppliedMigration
+		if err := rows.Scan(&id, &app, &name, &m.AppliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan migration row: %w", err)
+		}
+		if apps != nil && !apps[app] {
+			continue
+		}
+		m.ID = strconv.FormatInt(id, 10)
+		m.MigrationName = app + "." + name
+		migrations = append(migrations, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating migration rows: %w", err)
+	}
+	return migrations, nil
+}
+
+// ---------------------------------------------------------------------------
+// Alembic: <script_location>/versions/*.py, alembic_version (current heads)
+// ---------------------------------------------------------------------------
+
+type alembicProvider struct{}
+
+var (
+	alembicScriptLocation = regexp.MustCompile(`(?m)^\s*script_location\s*=\s*(.+?)\s*$`)
+	alembicRevision       = regexp.MustCompile(`(?m)^revision(?:\s*:[^=\n]+)?\s*=\s*['"]([^'"]+)['"]`)
+	alembicDownRevision   = regexp.MustCompile(`(?m)^down_revision(?:\s*:[^=\n]+)?\s*=\s*(.+)$`)
+	quotedString          = regexp.MustCompile(`['"]([^'"]+)['"]`)
+)
+
+// alembicRevisionInfo is one revision file's place in the revision graph
+type alembicRevisionInfo struct {
+	id   string
+	down []string
+}
+
+func (alembicProvider) Name() string          { return detect.MigrationToolAlembic }
+func (alembicProvider) DeployCommand() string { return "alembic upgrade head" }
+
+// versionsDir reads script_location from alembic.ini (default "alembic")
+func (alembicProvider) versionsDir(worktreePath string) string {
+	location := "alembic"
+	if content, err := os.ReadFile(filepath.Join(worktreePath, "alembic.ini")); err == nil {
+		if m := alembicScriptLocation.FindSubmatch(content); m != nil {
+			location = strings.TrimPrefix(string(m[1]), "%(here)s/")
+		}
+	}
+	return filepath.Join(worktreePath, filepath.FromSlash(location), "versions")
+}
+
+func (p alembicProvider) revisions(worktreePath string) ([]alembicRevisionInfo, error) {
+	dir := p.versionsDir(worktreePath)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alembic versions: %w", err)
+	}
+
+	var revisions []alembicRevisionInfo
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".py") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		m := alembicRevision.FindSubmatch(content)
+		if m == nil {
+			continue
+		}
+		rev := alembicRevisionInfo{id: string(m[1])}
+		if d := alembicDownRevision.FindSubmatch(content); d != nil {
+			for _, q := range quotedString.FindAllSubmatch(d[1], -1) {
+				rev.down = append(rev.down, string(q[1]))
+			}
+		}
+		revisions = append(revisions, rev)
+	}
+	return orderAlembicRevisions(revisions), nil
+}
+
+// orderAlembicRevisions sorts revisions so each comes after its parents
+// (ties broken by id, for a stable order across branches)
+func orderAlembicRevisions(revisions []alembicRevisionInfo) []alembicRevisionInfo {
+	byID := make(map[string]alembicRevisionInfo, len(revisions))
+	pending := make(map[string]int, len(revisions))
+	children := make(map[string][]string)
+	for _, r := range revisions {
+		byID[r.id] = r
+	}
+	for _, r := range revisions {
+		for _, d := range r.down {
+			if _, ok := byID[d]; ok {
+				pending[r.id]++
+				children[d] = append(children[d], r.id)
+			}
+		}
+	}
+
+	var ready []string
+	for _, r := range revisions {
+		if pending[r.id] == 0 {
+			ready = append(ready, r.id)
+		}
+	}
+
+	ordered := make([]alembicRevisionInfo, 0, len(revisions))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		id := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, byID[id])
+		for _, child := range children[id] {
+			pending[child]--
+			if pending[child] == 0 {
+				ready = append(ready, child)
+			}
+		}
+	}
+	return ordered
+}
+
+func (p alembicProvider) WorktreeMigrations(worktreePath string) ([]string, error) {
+	revisions, err := p.revisions(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(revisions))
+	for i, r := range revisions {
+		names[i] = r.id
+	}
+	return names, nil
+}
+
+func (alembicProvider) Checksum(string, string) (string, error) { return "", nil }
+
+// AppliedMigrations expands alembic's current heads into every revision they
+// descend from in the worktree's graph; heads the worktree lacks are reported
+// as is
+func (p alembicProvider) AppliedMigrations(db *sql.DB, worktreePath string) ([]AppliedMigration, error) {
+	exists, err := relationExists(db, "alembic_version")
+	if err != nil || !exists {
+		return []AppliedMigration{}, err
+	}
+
+	rows, err := db.Query(`SELECT version_num FROM alembic_version ORDER BY version_num`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query migrations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var heads []string
+	for rows.Next() {
+		var head string
+		if err := rows.Scan(&head); err != nil {
+			return nil, fmt.Errorf("failed to scan migration row: %w", err)
+		}
+		heads = append(heads, head)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating migration rows: %w", err)
+	}
+
+	var revisions []alembicRevisionInfo
+	if worktreePath != "" {
+		if revisions, err = p.revisions(worktreePath); err != nil {
+			return nil, err
+		}
+	}
+	byID := make(map[string]alembicRevisionInfo, len(revisions))
+	for _, r := range revisions {
+		byID[r.id] = r
+	}
+
+	// Walk down from each head to find everything applied
+	applied := make(map[string]bool)
+	var unknown []string
+	stack := append([]string(nil), heads...)
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if applied[id] {
+			continue
+		}
+		applied[id] = true
+		r, ok := byID[id]
+		if !ok {
+			unknown = append(unknown, id)
+			continue
+		}
+		stack = append(stack, r.down...)
+	}
+
+	var migrations []AppliedMigration
+	for _, r := range revisions {
+		if applied[r.id] {
+			migrations = append(migrations, AppliedMigration{ID: r.id, MigrationName: r.id})
+		}
+	}
+	sort.Strings(unknown)
+	for _, id := range unknown {
+		migrations = append(migrations, AppliedMigration{ID: id, MigrationName: id})
+	}
+	return migrations, nil
+}
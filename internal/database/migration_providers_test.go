@@ -0,0 +1,193 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hammashamzah/conductor/internal/detect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestMigrationProviders_WorktreeMigrations(t *testing.T) {
+	tests := []struct {
+		name     string
+		tool     string
+		files    map[string]string
+		expected []string
+	}{
+		{
+			name: "drizzle journal order with custom out dir",
+			tool: detect.MigrationToolDrizzle,
+			files: map[string]string{
+				"package.json":                     `{}`,
+				"drizzle.config.ts":                `export default defineConfig({ out: "./db/migrations", dialect: "postgresql" })`,
+				"db/migrations/meta/_journal.json": `{"entries":[{"idx":1,"when":1700000001000,"tag":"0001_users"},{"idx":0,"when":1700000000000,"tag":"0000_init"}]}`,
+				"db/migrations/0000_init.sql":      "CREATE TABLE a ();",
+				"db/migrations/0001_users.sql":     "CREATE TABLE users ();",
+			},
+			expected: []string{"0000_init", "0001_users"},
+		},
+		{
+			name: "rails",
+			tool: detect.MigrationToolRails,
+			files: map[string]string{
+				"Gemfile":                                   "gem 'rails'",
+				"db/migrate/20240102000000_add_email.rb":    "",
+				"db/migrate/20240101000000_create_users.rb": "",
+				"db/migrate/README":                         "",
+			},
+			expected: []string{"20240101000000_create_users", "20240102000000_add_email"},
+		},
+		{
+			name: "django apps",
+			tool: detect.MigrationToolDjango,
+			files: map[string]string{
+				"manage.py":                               "",
+				"requirements.txt":                        "django",
+				"shop/migrations/__init__.py":             "",
+				"shop/migrations/0001_initial.py":         "",
+				"shop/migrations/0002_order_total.py":     "",
+				"accounts/migrations/__init__.py":         "",
+				"accounts/migrations/0001_initial.py":     "",
+				".venv/lib/django/migrations/__init__.py": "",
+				".venv/lib/django/migrations/0001_x.py":   "",
+			},
+			expected: []string{"accounts.0001_initial", "shop.0001_initial", "shop.0002_order_total"},
+		},
+		{
+			name: "alembic revision graph",
+			tool: detect.MigrationToolAlembic,
+			files: map[string]string{
+				"pyproject.toml":                "",
+				"alembic.ini":                   "[alembic]\nscript_location = %(here)s/migrations\n",
+				"migrations/versions/c_add.py":  "revision: str = 'ccc'\ndown_revision: Union[str, None] = 'bbb'\n",
+				"migrations/versions/a_init.py": "revision = 'bbb'\ndown_revision = 'aaa'\n",
+				"migrations/versions/b_base.py": "revision = 'aaa'\ndown_revision = None\n",
+			},
+			expected: []string{"aaa", "bbb", "ccc"},
+		},
+		{
+			name: "goose",
+			tool: detect.MigrationToolGoose,
+			files: map[string]string{
+				"go.mod":                                "module x\nrequire github.com/pressly/goose/v3 v3.20.0",
+				"db/migrations/00002_add_email.go":      "",
+				"db/migrations/00002_add_email_test.go": "",
+				"db/migrations/00001_init.sql":          "",
+			},
+			expected: []string{"00001_init", "00002_add_email"},
+		},
+		{
+			name: "golang-migrate",
+			tool: detect.MigrationToolGolangMigrate,
+			files: map[string]string{
+				"go.mod":                       "module x\nrequire github.com/golang-migrate/migrate/v4 v4.17.0",
+				"migrations/10_users.up.sql":   "",
+				"migrations/10_users.down.sql": "",
+				"migrations/2_init.up.sql":     "",
+				"migrations/2_init.down.sql":   "",
+			},
+			expected: []string{"2_init", "10_users"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, tt.files)
+
+			provider := MigrationProviderFor(dir)
+			require.NotNil(t, provider)
+			assert.Equal(t, tt.tool, provider.Name())
+
+			migrations, err := provider.WorktreeMigrations(dir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, migrations)
+		})
+	}
+}
+
+func TestMigrationProviderFor_None(t *testing.T) {
+	assert.Nil(t, MigrationProviderFor(writeFiles(t, map[string]string{"go.mod": "module x"})))
+}
+
+func TestDrizzleChecksum(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"drizzle/0000_init.sql": "CREATE TABLE a ();"})
+
+	sum, err := drizzleProvider{}.Checksum(dir, "0000_init")
+	require.NoError(t, err)
+	expected, err := fileChecksum(filepath.Join(dir, "drizzle", "0000_init.sql"))
+	require.NoError(t, err)
+	assert.Equal(t, expected, sum)
+}
+
+func TestOrderAlembicRevisions_Merge(t *testing.T) {
+	ordered := orderAlembicRevisions([]alembicRevisionInfo{
+		{id: "merge", down: []string{"left", "right"}},
+		{id: "right", down: []string{"base"}},
+		{id: "left", down: []string{"base"}},
+		{id: "base"},
+	})
+
+	ids := make([]string, len(ordered))
+	for i, r := range ordered {
+		ids[i] = r.id
+	}
+	assert.Equal(t, []string{"base", "left", "right", "merge"}, ids)
+}
+
+func TestGetRecommendedAction_UsesToolCommand(t *testing.T) {
+	state := &MigrationState{
+		Compatibility:     MigrationForward,
+		Tool:              detect.MigrationToolRails,
+		PendingMigrations: []string{"20240101000000_create_users"},
+	}
+	assert.Equal(t, "Run 'bin/rails db:migrate' to apply 1 pending migration", getRecommendedAction(state))
+}
+
+func TestGetMigrationBaselineFromDB_SchemaMigrationsShape(t *testing.T) {
+	if err := ValidateConnection(testDBURL); err != nil {
+		t.Skipf("Test database not available: %v", err)
+	}
+	const dbName = "conductor_schema_migrations_test"
+	_ = DropDatabase(testDBURL, dbName)
+	require.NoError(t, CreateDatabase(testDBURL, dbName))
+	t.Cleanup(func() { _ = DropDatabase(testDBURL, dbName) })
+	dbURL := BuildWorktreeURL(testDBURL, dbName)
+
+	db, err := openDB(dbURL)
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	// golang-migrate keeps the current version and a dirty flag
+	_, err = db.Exec(`CREATE TABLE schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO schema_migrations VALUES (3, false)`)
+	require.NoError(t, err)
+	baseline, err := GetMigrationBaselineFromDB(dbURL)
+	require.NoError(t, err)
+	assert.Equal(t, detect.MigrationToolGolangMigrate, baseline.Tool)
+
+	// Rails keeps one varchar row per applied migration
+	_, err = db.Exec(`DROP TABLE schema_migrations`)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE schema_migrations (version varchar NOT NULL PRIMARY KEY)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO schema_migrations VALUES ('20240101000000')`)
+	require.NoError(t, err)
+	baseline, err = GetMigrationBaselineFromDB(dbURL)
+	require.NoError(t, err)
+	assert.Equal(t, detect.MigrationToolRails, baseline.Tool)
+}
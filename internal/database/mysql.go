@@ -0,0 +1,737 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hammashamzah/conductor/internal/config"
+)
+
+// CloneMethodMySQLDump is a mysqldump | mysql copy, the only way MySQL can
+// clone a database
+const CloneMethodMySQLDump CloneMethod = "mysqldump"
+
+// mysqlDriver implements Driver for MySQL and MariaDB with go-sql-driver,
+// mysqldump and the mysql client. Tables are addressed as "<database>.<table>",
+// the database taking the place of PostgreSQL's schema.
+type mysqlDriver struct{}
+
+func (mysqlDriver) Name() string { return config.DatabaseDriverMySQL }
+
+func (mysqlDriver) Open(connStr string) (*sql.DB, error) {
+	dsn, err := mysqlDSN(connStr)
+	if err != nil {
+		return nil, err
+	}
+	return sql.Open("mysql", dsn)
+}
+
+// mysqlTLSModes maps the mysql client's --ssl-mode values to go-sql-driver's tls setting
+var mysqlTLSModes = map[string]string{
+	"DISABLED":        "false",
+	"PREFERRED":       "preferred",
+	"REQUIRED":        "skip-verify",
+	"VERIFY_CA":       "true",
+	"VERIFY_IDENTITY": "true",
+}
+
+// mysqlDSN converts a mysql:// URL into a go-sql-driver DSN
+func mysqlDSN(connStr string) (string, error) {
+	info, err := ParseConnectionString(connStr)
+	if err != nil {
+		return "", err
+	}
+
+	cfg := mysql.NewConfig()
+	cfg.User = info.User
+	cfg.Passwd = info.Password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(info.Host, info.Port)
+	cfg.DBName = info.Database
+	cfg.ParseTime = true
+	if info.SSLMode != "" {
+		tls, ok := mysqlTLSModes[strings.ToUpper(info.SSLMode)]
+		if !ok {
+			return "", fmt.Errorf("invalid ssl-mode %q", info.SSLMode)
+		}
+		cfg.TLSConfig = tls
+	}
+	return cfg.FormatDSN(), nil
+}
+
+// mysqlAdminURL returns the server URL without a database selected
+func mysqlAdminURL(localURL string) (string, error) {
+	info, err := ParseConnectionString(localURL)
+	if err != nil {
+		return "", err
+	}
+	info.Database = ""
+	return BuildConnectionString(info), nil
+}
+
+// mysqlCommand builds a mysql or mysqldump command for a connection URL.
+// The password is passed in MYSQL_PWD so it doesn't show up in ps.
+func mysqlCommand(ctx context.Context, connStr, tool string, args ...string) (*exec.Cmd, error) {
+	info, err := ParseConnectionString(connStr)
+	if err != nil {
+		return nil, err
+	}
+
+	cmdArgs := []string{"--host=" + info.Host, "--port=" + info.Port, "--protocol=TCP"}
+	if info.User != "" {
+		cmdArgs = append(cmdArgs, "--user="+info.User)
+	}
+	if info.SSLMode != "" {
+		cmdArgs = append(cmdArgs, "--ssl-mode="+strings.ToUpper(info.SSLMode))
+	}
+	cmdArgs = append(cmdArgs, args...)
+
+	cmd := exec.CommandContext(ctx, tool, cmdArgs...)
+	cmd.Env = os.Environ()
+	if info.Password != "" {
+		cmd.Env = append(cmd.Env, "MYSQL_PWD="+info.Password)
+	}
+	return cmd, nil
+}
+
+// mysqlDumpArgs are passed to every mysqldump: a consistent InnoDB snapshot,
+// no tablespace statements (which need the PROCESS privilege)
+var mysqlDumpArgs = []string{"--single-transaction", "--no-tablespaces"}
+
+// mysqlPipe runs mysqldump against sourceURL with args and loads its output
+// into targetURL's database with the mysql client
+func mysqlPipe(ctx context.Context, sourceURL, targetURL string, args ...string) error {
+	target, err := ParseConnectionString(targetURL)
+	if err != nil {
+		return err
+	}
+	dump, err := mysqlCommand(ctx, sourceURL, "mysqldump", append(append([]string{}, mysqlDumpArgs...), args...)...)
+	if err != nil {
+		return err
+	}
+	load, err := mysqlCommand(ctx, targetURL, "mysql", target.Database)
+	if err != nil {
+		return err
+	}
+	return pipeCommands(dump, load)
+}
+
+// pipeCommands runs src | dst and returns the first failure with its stderr
+func pipeCommands(src, dst *exec.Cmd) error {
+	pipe, err := src.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create pipe: %w", err)
+	}
+	dst.Stdin = pipe
+
+	var srcStderr, dstStderr strings.Builder
+	src.Stderr = &srcStderr
+	dst.Stderr = &dstStderr
+
+	srcName, dstName := filepath.Base(src.Path), filepath.Base(dst.Path)
+	if err := src.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", srcName, err)
+	}
+	if err := dst.Start(); err != nil {
+		_ = src.Process.Kill()
+		_ = src.Wait()
+		return fmt.Errorf("failed to start %s: %w", dstName, err)
+	}
+
+	// Close our end of the pipe once dst exits so a failed load can't leave
+	// src blocked on a full pipe
+	dstErr := dst.Wait()
+	_ = pipe.Close()
+	srcErr := src.Wait()
+
+	if dstErr != nil {
+		return fmt.Errorf("%s failed: %w\nstderr: %s", dstName, dstErr, dstStderr.String())
+	}
+	if srcErr != nil {
+		return fmt.Errorf("%s failed: %w\nstderr: %s", srcName, srcErr, srcStderr.String())
+	}
+	return nil
+}
+
+// quoteMySQLIdentifier quotes a MySQL identifier
+func quoteMySQLIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// mysqlTableName strips the database from a "<database>.<table>" name, so
+// exclusions and filters can be written either way
+func mysqlTableName(name string) string {
+	if idx := strings.LastIndex(name, "."); idx != -1 {
+		return name[idx+1:]
+	}
+	return name
+}
+
+// withAdminDB runs fn on a connection to the server with no database selected
+func (d mysqlDriver) withAdminDB(localURL string, timeout time.Duration, fn func(ctx context.Context, db *sql.DB) error) error {
+	adminURL, err := mysqlAdminURL(localURL)
+	if err != nil {
+		return err
+	}
+	db, err := d.Open(adminURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return fn(ctx, db)
+}
+
+func (d mysqlDriver) CreateDatabase(localURL, dbName string) error {
+	safeName := sanitizeDBName(dbName)
+	return d.withAdminDB(localURL, 30*time.Second, func(ctx context.Context, db *sql.DB) error {
+		exists, err := mysqlDatabaseExists(ctx, db, safeName)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("database %s already exists", safeName)
+		}
+		if _, err := db.ExecContext(ctx, "CREATE DATABASE "+quoteMySQLIdentifier(safeName)); err != nil {
+			return fmt.Errorf("failed to create database: %w", err)
+		}
+		return nil
+	})
+}
+
+func (d mysqlDriver) DropDatabase(localURL, dbName string) error {
+	safeName := sanitizeDBName(dbName)
+	return d.withAdminDB(localURL, 30*time.Second, func(ctx context.Context, db *sql.DB) error {
+		// DROP DATABASE waits for other sessions' metadata locks, so end them first
+		// (needs CONNECTION_ADMIN; without it the drop just waits)
+		rows, err := db.QueryContext(ctx, `SELECT id FROM information_schema.processlist WHERE db = ? AND id <> CONNECTION_ID()`, safeName)
+		if err == nil {
+			var ids []int64
+			for rows.Next() {
+				var id int64
+				if rows.Scan(&id) == nil {
+					ids = append(ids, id)
+				}
+			}
+			_ = rows.Close()
+			for _, id := range ids {
+				_, _ = db.ExecContext(ctx, fmt.Sprintf("KILL %d", id))
+			}
+		}
+
+		if _, err := db.ExecContext(ctx, "DROP DATABASE IF EXISTS "+quoteMySQLIdentifier(safeName)); err != nil {
+			return fmt.Errorf("failed to drop database: %w", err)
+		}
+		return nil
+	})
+}
+
+func (d mysqlDriver) DatabaseExists(localURL, dbName string) (bool, error) {
+	var exists bool
+	err := d.withAdminDB(localURL, 10*time.Second, func(ctx context.Context, db *sql.DB) error {
+		var err error
+		exists, err = mysqlDatabaseExists(ctx, db, sanitizeDBName(dbName))
+		return err
+	})
+	return exists, err
+}
+
+// mysqlDatabaseExists checks for a database on an open connection
+func mysqlDatabaseExists(ctx context.Context, db *sql.DB, dbName string) (bool, error) {
+	var count int
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM information_schema.schemata WHERE schema_name = ?`, dbName).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check database existence: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (d mysqlDriver) ListDatabases(localURL, pattern string) ([]string, error) {
+	var databases []string
+	err := d.withAdminDB(localURL, 10*time.Second, func(ctx context.Context, db *sql.DB) error {
+		rows, err := db.QueryContext(ctx, `SELECT schema_name FROM information_schema.schemata WHERE schema_name LIKE ? ORDER BY schema_name`, pattern)
+		if err != nil {
+			return fmt.Errorf("failed to list databases: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return err
+			}
+			databases = append(databases, name)
+		}
+		return rows.Err()
+	})
+	return databases, err
+}
+
+func (d mysqlDriver) DatabaseSize(localURL, dbName string) (int64, error) {
+	var size int64
+	err := d.withAdminDB(localURL, 10*time.Second, func(ctx context.Context, db *sql.DB) error {
+		return db.QueryRowContext(ctx, `
+			SELECT COALESCE(SUM(data_length + index_length), 0)
+			FROM information_schema.tables
+			WHERE table_schema = ?
+		`, dbName).Scan(&size)
+	})
+	return size, err
+}
+
+func (d mysqlDriver) GetTableInfo(connStr string) ([]TableInfo, error) {
+	db, err := d.Open(connStr)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	// table_rows is InnoDB's estimate, like PostgreSQL's n_live_tup
+	rows, err := db.QueryContext(ctx, `
+		SELECT
+			t.table_schema,
+			t.table_name,
+			COALESCE(t.data_length + t.index_length, 0) AS size_bytes,
+			COALESCE(t.table_rows, 0) AS row_count,
+			EXISTS(SELECT 1 FROM information_schema.statistics s WHERE s.table_schema = t.table_schema AND s.table_name = t.table_name) AS has_indexes
+		FROM information_schema.tables t
+		WHERE t.table_schema = DATABASE()
+		  AND t.table_type = 'BASE TABLE'
+		ORDER BY size_bytes DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tables []TableInfo
+	for rows.Next() {
+		var t TableInfo
+		if err := rows.Scan(&t.Schema, &t.Name, &t.SizeBytes, &t.RowCount, &t.HasIndexes); err != nil {
+			return nil, err
+		}
+		t.IsAudit = isLikelyAuditTable(t.Name, t.RowCount, t.SizeBytes)
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+// mysqlRowCounts counts the rows of each table with COUNT(*)
+func (d mysqlDriver) mysqlRowCounts(ctx context.Context, connStr string, tables []TableInfo) (map[string]int64, error) {
+	db, err := d.Open(connStr)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	counts := make(map[string]int64, len(tables))
+	for _, t := range tables {
+		var count int64
+		query := "SELECT COUNT(*) FROM " + quoteMySQLIdentifier(t.Name)
+		if err := db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+			continue // skip tables we can't count (permissions, etc.)
+		}
+		counts[t.Schema+"."+t.Name] = count
+	}
+	return counts, nil
+}
+
+func (d mysqlDriver) DumpDatabase(ctx context.Context, dbURL, outputPath string) error {
+	info, err := ParseConnectionString(dbURL)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create dump directory: %w", err)
+	}
+
+	args := append(append([]string{}, mysqlDumpArgs...), "--routines", "--triggers", "--result-file="+outputPath, info.Database)
+	cmd, err := mysqlCommand(ctx, dbURL, "mysqldump", args...)
+	if err != nil {
+		return err
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		_ = os.Remove(outputPath)
+		return fmt.Errorf("mysqldump failed: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+func (d mysqlDriver) RestoreDump(targetURL, dumpPath string) error {
+	info, err := ParseConnectionString(targetURL)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(dumpPath)
+	if err != nil {
+		return fmt.Errorf("failed to open dump: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	cmd, err := mysqlCommand(context.Background(), targetURL, "mysql", info.Database)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = f
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("mysql restore failed: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// SyncToGolden copies the source into a freshly created golden database:
+// the schema (with routines and triggers) first, then the data of every
+// table that isn't excluded in one consistent mysqldump. Filtered tables, and
+// with several workers the large ones, are dumped table by table alongside
+// each other; the dumps turn off foreign key checks, so no ordering is needed.
+func (d mysqlDriver) SyncToGolden(ctx context.Context, sourceURL, localURL, projectName string, cfg *DatabaseConfig, progress ProgressFunc) (*GoldenDBSyncResult, error) {
+	if len(cfg.MaskColumns) > 0 {
+		return nil, fmt.Errorf("column masking is not supported for MySQL sources yet: remove database.maskColumns to sync")
+	}
+	source, err := ParseConnectionString(sourceURL)
+	if err != nil {
+		return nil, err
+	}
+	if source.Database == "" {
+		return nil, fmt.Errorf("source URL must name a database")
+	}
+
+	startTime := time.Now()
+	var stepTimes []string
+	step := func(name, msg string, start time.Time) {
+		ms := time.Since(start).Milliseconds()
+		stepTimes = append(stepTimes, fmt.Sprintf("%s:%s", name, formatMs(ms)))
+		if progress != nil {
+			progress(fmt.Sprintf("%s (%s)", msg, formatMs(ms)))
+		}
+	}
+
+	// Recreate the golden database so tables dropped at the source go away too
+	stepStart := time.Now()
+	goldenName := GoldenDBName(projectName)
+	if err := d.DropDatabase(localURL, goldenName); err != nil {
+		return nil, fmt.Errorf("failed to drop old golden DB: %w", err)
+	}
+	if err := d.CreateDatabase(localURL, goldenName); err != nil {
+		return nil, fmt.Errorf("failed to create golden DB: %w", err)
+	}
+	goldenURL := GoldenDBURL(localURL, projectName)
+	step("create_db", "Created golden database", stepStart)
+
+	stepStart = time.Now()
+	tables, err := d.GetTableInfo(sourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table info: %w", err)
+	}
+	step("analyze", "Analyzed source tables", stepStart)
+
+	excludedTables := determineExclusions(tables, cfg)
+	filteredTables := make(map[string]string, len(cfg.FilterTables))
+	for table, where := range cfg.FilterTables {
+		filteredTables[mysqlTableName(table)] = where
+	}
+
+	if progress != nil {
+		progress("")
+		progress("Table sizes (top 20 by size):")
+		progress("─────────────────────────────────────────────────────")
+		displayTableSizes(tables, excludedTables, cfg.FilterTables, nil, progress)
+		progress("─────────────────────────────────────────────────────")
+		progress("")
+	}
+
+	stepStart = time.Now()
+	rowCounts, err := d.mysqlRowCounts(ctx, sourceURL, tables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get row counts: %w", err)
+	}
+	step("row_counts", "Got row counts", stepStart)
+
+	// Tables left out of the main data dump: excluded ones get no data at all,
+	// the rest are dumped on their own below
+	skipData := make(map[string]bool)
+	for _, table := range excludedTables {
+		skipData[mysqlTableName(table)] = true
+	}
+	var copyTables []string
+	workers := SyncWorkers(cfg)
+	for _, t := range tables {
+		if skipData[t.Name] {
+			continue
+		}
+		if _, filtered := filteredTables[t.Name]; filtered || (workers > 1 && t.SizeBytes >= parallelCopyMinBytes) {
+			copyTables = append(copyTables, t.Name)
+		}
+	}
+	sort.Strings(copyTables)
+	ignoreArgs := make([]string, 0, len(skipData)+len(copyTables))
+	for table := range skipData {
+		ignoreArgs = append(ignoreArgs, "--ignore-table="+source.Database+"."+table)
+	}
+	for _, table := range copyTables {
+		ignoreArgs = append(ignoreArgs, "--ignore-table="+source.Database+"."+table)
+	}
+
+	stepStart = time.Now()
+	if progress != nil {
+		progress(fmt.Sprintf("Syncing to golden DB (excl %d, filter %d, parallel %d)...",
+			len(excludedTables), len(filteredTables), len(copyTables)-len(filteredTables)))
+	}
+	if err := mysqlPipe(ctx, sourceURL, goldenURL, "--no-data", "--routines", "--triggers", source.Database); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("sync cancelled: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("schema sync failed: %w", err)
+	}
+	dataArgs := append([]string{"--no-create-info", "--skip-triggers"}, ignoreArgs...)
+	if err := mysqlPipe(ctx, sourceURL, goldenURL, append(dataArgs, source.Database)...); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("sync cancelled: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("data sync failed: %w", err)
+	}
+	step("mysqldump", "Synced to golden DB", stepStart)
+
+	if len(copyTables) > 0 {
+		copyStart := time.Now()
+		if progress != nil && workers > 1 && len(copyTables) > 1 {
+			progress(fmt.Sprintf("Copying %d tables with %d workers", len(copyTables), workers))
+		}
+
+		var mu sync.Mutex // guards stepTimes and done
+		done := 0
+		copyProgress := serializedProgress(progress)
+		err := copyTablesConcurrently(ctx, copyTables, nil, workers, func(ctx context.Context, table string) error {
+			args := []string{"--no-create-info", "--skip-triggers"}
+			whereClause, filtered := filteredTables[table]
+			if filtered {
+				args = append(args, "--where="+whereClause)
+			}
+
+			tableStart := time.Now()
+			if err := mysqlPipe(ctx, sourceURL, goldenURL, append(args, source.Database, table)...); err != nil {
+				if ctx.Err() != nil {
+					return fmt.Errorf("sync cancelled: %w", ctx.Err())
+				}
+				// A large table left empty would look like a successful sync
+				if !filtered {
+					return fmt.Errorf("failed to copy table %s: %w", table, err)
+				}
+				if copyProgress != nil {
+					copyProgress(fmt.Sprintf("Warning: failed to copy %s: %v", table, err))
+				}
+			}
+			duration := time.Since(tableStart).Milliseconds()
+
+			mu.Lock()
+			done++
+			n := done
+			stepTimes = append(stepTimes, fmt.Sprintf("%s:%s", table, formatMs(duration)))
+			mu.Unlock()
+
+			if copyProgress != nil {
+				verb := "Copied"
+				if filtered {
+					verb = "Filtered"
+				}
+				copyProgress(fmt.Sprintf("%s %s [%d/%d] (%s)", verb, table, n, len(copyTables), formatMs(duration)))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		stepTimes = append(stepTimes, fmt.Sprintf("copy_total:%s", formatMs(time.Since(copyStart).Milliseconds())))
+	}
+
+	syncDuration := time.Since(startTime)
+	stepStart = time.Now()
+	if err := d.updateGoldenMetadata(goldenURL, sourceURL, excludedTables, rowCounts, syncDuration); err != nil && progress != nil {
+		progress(fmt.Sprintf("Warning: failed to update metadata: %v", err))
+	}
+	step("metadata", "Updated sync metadata", stepStart)
+
+	tableSizes := make(map[string]int64, len(tables))
+	for _, t := range tables {
+		tableSizes[t.Schema+"."+t.Name] = t.SizeBytes
+	}
+
+	result := &GoldenDBSyncResult{
+		GoldenDBName:   goldenName,
+		GoldenDBURL:    goldenURL,
+		SyncDurationMs: syncDuration.Milliseconds(),
+		TableCount:     len(tables),
+		ExcludedTables: excludedTables,
+		RowCounts:      rowCounts,
+		TableSizes:     tableSizes,
+	}
+	if progress != nil {
+		progress(fmt.Sprintf("Sync completed in %s [%s]", formatMs(result.SyncDurationMs), strings.Join(stepTimes, ", ")))
+	}
+	return result, nil
+}
+
+// updateGoldenMetadata records a sync in the golden database's _conductor_sync table
+func (d mysqlDriver) updateGoldenMetadata(goldenURL, sourceURL string, excludedTables []string, rowCounts map[string]int64, duration time.Duration) error {
+	db, err := d.Open(goldenURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS ` + ConductorSyncTable + ` (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			synced_at DATETIME(6) NOT NULL,
+			source_url TEXT,
+			excluded_tables JSON,
+			row_counts JSON,
+			sync_duration_ms INT,
+			is_incremental BOOLEAN DEFAULT FALSE
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create metadata table: %w", err)
+	}
+
+	if excludedTables == nil {
+		excludedTables = []string{}
+	}
+	excludedJSON, _ := json.Marshal(excludedTables)
+	rowCountsJSON, _ := json.Marshal(rowCounts)
+
+	_, err = db.Exec(`
+		INSERT INTO `+ConductorSyncTable+` (synced_at, source_url, excluded_tables, row_counts, sync_duration_ms, is_incremental)
+		VALUES (?, ?, ?, ?, ?, false)
+	`, time.Now().UTC(), maskPassword(sourceURL), string(excludedJSON), string(rowCountsJSON), duration.Milliseconds())
+	return err
+}
+
+func (d mysqlDriver) GoldenSyncInfo(goldenURL string, info *GoldenDBSyncInfo) error {
+	db, err := d.Open(goldenURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	var tableExists int
+	err = db.QueryRow(`
+		SELECT COUNT(*) FROM information_schema.tables
+		WHERE table_schema = DATABASE() AND table_name = ?
+	`, ConductorSyncTable).Scan(&tableExists)
+	if err != nil || tableExists == 0 {
+		return nil
+	}
+
+	err = db.QueryRow(`
+		SELECT synced_at, source_url, sync_duration_ms, is_incremental
+		FROM `+ConductorSyncTable+`
+		ORDER BY synced_at DESC
+		LIMIT 1
+	`).Scan(&info.LastSyncAt, &info.SourceURL, &info.SyncDurationMs, &info.IsIncremental)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	return db.QueryRow(`
+		SELECT COUNT(*) FROM information_schema.tables
+		WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' AND table_name <> ?
+	`, ConductorSyncTable).Scan(&info.TableCount)
+}
+
+func (d mysqlDriver) LoadGoldenMetadata(goldenURL string) (*SyncMetadata, error) {
+	db, err := d.Open(goldenURL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	var syncedAt time.Time
+	var excludedTablesJSON, rowCountsJSON []byte
+	var syncDurationMs int64
+	var isIncremental bool
+
+	err = db.QueryRow(`
+		SELECT synced_at, excluded_tables, row_counts, sync_duration_ms, is_incremental
+		FROM `+ConductorSyncTable+`
+		ORDER BY id DESC LIMIT 1
+	`).Scan(&syncedAt, &excludedTablesJSON, &rowCountsJSON, &syncDurationMs, &isIncremental)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var excludedTables []string
+	var rowCounts map[string]int64
+	_ = json.Unmarshal(excludedTablesJSON, &excludedTables)
+	_ = json.Unmarshal(rowCountsJSON, &rowCounts)
+
+	return &SyncMetadata{
+		LastSyncAt:     syncedAt,
+		ExcludedTables: excludedTables,
+		RowCounts:      rowCounts,
+		SyncDurationMs: syncDurationMs,
+		IsIncremental:  isIncremental,
+		SyncVersion:    SyncVersionGoldenDB,
+	}, nil
+}
+
+// CloneFromGolden copies the golden database into a new worktree database
+// with mysqldump | mysql, leaving out the sync metadata
+func (d mysqlDriver) CloneFromGolden(ctx context.Context, localURL, projectName, worktreeDBName string, progress ProgressFunc) (CloneMethod, error) {
+	goldenName := GoldenDBName(projectName)
+	exists, err := d.DatabaseExists(localURL, goldenName)
+	if err != nil {
+		return "", fmt.Errorf("failed to check golden DB: %w", err)
+	}
+	if !exists {
+		return "", fmt.Errorf("golden database does not exist for project %s - run sync first", projectName)
+	}
+
+	if progress != nil {
+		progress("Creating worktree database...")
+	}
+	if err := d.CreateDatabase(localURL, worktreeDBName); err != nil {
+		return "", fmt.Errorf("failed to create worktree DB: %w", err)
+	}
+
+	if progress != nil {
+		progress("Cloning from golden DB...")
+	}
+	start := time.Now()
+	goldenURL := GoldenDBURL(localURL, projectName)
+	worktreeURL := BuildWorktreeURL(localURL, worktreeDBName)
+	err = mysqlPipe(ctx, goldenURL, worktreeURL,
+		"--routines", "--triggers",
+		"--ignore-table="+goldenName+"."+ConductorSyncTable,
+		goldenName)
+	if err != nil {
+		_ = d.DropDatabase(localURL, worktreeDBName)
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("clone cancelled: %w", ctx.Err())
+		}
+		return "", err
+	}
+
+	if progress != nil {
+		progress(fmt.Sprintf("Clone completed via mysqldump (%s)", formatMs(time.Since(start).Milliseconds())))
+	}
+	return CloneMethodMySQLDump, nil
+}
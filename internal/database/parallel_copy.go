@@ -0,0 +1,198 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// DefaultSyncWorkers is how many tables a sync copies at once when the
+// project does not set database.syncWorkers
+const DefaultSyncWorkers = 4
+
+// parallelCopyMinBytes is the size above which a table's data is left out of
+// the main pg_dump and copied on its own COPY stream, alongside other large
+// tables, instead of serially inside the dump
+const parallelCopyMinBytes = 64 << 20
+
+// plainTableNamePattern matches schema-qualified names that need no quoting,
+// so they can be used as-is in pg_dump patterns and COPY statements
+var plainTableNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_$]*\.[a-z_][a-z0-9_$]*$`)
+
+// SyncWorkers returns the number of tables copied concurrently for a project
+func SyncWorkers(cfg *DatabaseConfig) int {
+	if cfg == nil || cfg.SyncWorkers <= 0 {
+		return DefaultSyncWorkers
+	}
+	return cfg.SyncWorkers
+}
+
+// largeCopyCandidates returns the tables big enough to be copied on their own
+// COPY stream: over parallelCopyMinBytes, plain-named, and neither excluded
+// nor already copied separately (filtered or masked)
+func largeCopyCandidates(tables []TableInfo, excludedTables []string, copyTables map[string]bool) []string {
+	excludedSet := make(map[string]bool, len(excludedTables))
+	for _, table := range excludedTables {
+		excludedSet[table] = true
+	}
+	var candidates []string
+	for _, t := range tables {
+		fullName := t.Schema + "." + t.Name
+		if t.SizeBytes < parallelCopyMinBytes || excludedSet[fullName] || copyTables[fullName] || !plainTableNamePattern.MatchString(fullName) {
+			continue
+		}
+		candidates = append(candidates, fullName)
+	}
+	return candidates
+}
+
+// fkParents maps each table to the tables in the set it references through a
+// foreign key. Self-references and tables outside the set are ignored.
+func fkParents(tables []string, fks []ForeignKeyInfo) map[string][]string {
+	inSet := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		inSet[t] = true
+	}
+
+	parents := make(map[string][]string)
+	seen := make(map[[2]string]bool)
+	for _, fk := range fks {
+		child := fk.TableSchema + "." + fk.TableName
+		parent := fk.ReferencedSchema + "." + fk.ReferencedTable
+		if child == parent || !inSet[child] || !inSet[parent] || seen[[2]string{child, parent}] {
+			continue
+		}
+		seen[[2]string{child, parent}] = true
+		parents[child] = append(parents[child], parent)
+	}
+	return parents
+}
+
+// copyTablesConcurrently calls copyTable for every table, running up to
+// workers copies at once. Tables start in the given order (normally from
+// sortTablesByFKDependency), and a table only starts once every parent listed
+// for it in parents has finished, so child rows never arrive before the rows
+// they reference. Tables caught in an FK cycle are released in order once
+// nothing else can run.
+//
+// Each copy gets its own context derived from ctx. The first error cancels
+// the copies still running and is returned; callers that want to tolerate a
+// failed table should handle the error inside copyTable and return nil.
+func copyTablesConcurrently(ctx context.Context, tables []string, parents map[string][]string, workers int, copyTable func(ctx context.Context, table string) error) error {
+	if len(tables) == 0 {
+		return nil
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	order := make(map[string]int, len(tables))
+	for i, t := range tables {
+		order[t] = i
+	}
+
+	// waiting counts unfinished parents; children lists who to release
+	waiting := make(map[string]int, len(tables))
+	children := make(map[string][]string)
+	for _, t := range tables {
+		for _, p := range parents[t] {
+			if _, ok := order[p]; ok && p != t {
+				waiting[t]++
+				children[p] = append(children[p], t)
+			}
+		}
+	}
+
+	var ready []string
+	for _, t := range tables {
+		if waiting[t] == 0 {
+			ready = append(ready, t)
+		}
+	}
+	started := make(map[string]bool, len(tables))
+
+	type result struct {
+		table string
+		err   error
+	}
+	results := make(chan result)
+	running, finished := 0, 0
+	var firstErr error
+
+	for finished < len(tables) {
+		// Launch as many ready tables as there are free workers
+		for firstErr == nil && ctx.Err() == nil && running < workers && len(ready) > 0 {
+			table := ready[0]
+			ready = ready[1:]
+			started[table] = true
+			running++
+			go func() {
+				results <- result{table: table, err: copyTable(ctx, table)}
+			}()
+		}
+
+		if running == 0 {
+			if firstErr != nil || ctx.Err() != nil {
+				break
+			}
+			// Everything left waits on a cycle: release the earliest table
+			for _, t := range tables {
+				if !started[t] {
+					ready = append(ready, t)
+					break
+				}
+			}
+			continue
+		}
+
+		res := <-results
+		running--
+		finished++
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = res.err
+				cancel()
+			}
+			continue
+		}
+		for _, child := range children[res.table] {
+			waiting[child]--
+			if waiting[child] == 0 && !started[child] {
+				ready = append(ready, child)
+			}
+		}
+		sort.Slice(ready, func(i, j int) bool { return order[ready[i]] < order[ready[j]] })
+	}
+
+	// Drain copies still running after a failure
+	for running > 0 {
+		<-results
+		running--
+	}
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if finished < len(tables) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// serializedProgress wraps a ProgressFunc so it can be called from several
+// copy goroutines at once
+func serializedProgress(progress ProgressFunc) ProgressFunc {
+	if progress == nil {
+		return nil
+	}
+	var mu sync.Mutex
+	return func(msg string) {
+		mu.Lock()
+		defer mu.Unlock()
+		progress(msg)
+	}
+}
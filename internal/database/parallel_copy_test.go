@@ -0,0 +1,198 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncWorkers(t *testing.T) {
+	assert.Equal(t, DefaultSyncWorkers, SyncWorkers(nil))
+	assert.Equal(t, DefaultSyncWorkers, SyncWorkers(&DatabaseConfig{}))
+	assert.Equal(t, 1, SyncWorkers(&DatabaseConfig{SyncWorkers: 1}))
+	assert.Equal(t, 8, SyncWorkers(&DatabaseConfig{SyncWorkers: 8}))
+}
+
+func TestFKParents(t *testing.T) {
+	fks := []ForeignKeyInfo{
+		{TableSchema: "public", TableName: "orders", ReferencedSchema: "public", ReferencedTable: "users"},
+		{TableSchema: "public", TableName: "orders", ReferencedSchema: "public", ReferencedTable: "users"}, // composite FK
+		{TableSchema: "public", TableName: "users", ReferencedSchema: "public", ReferencedTable: "users"},  // self-reference
+		{TableSchema: "public", TableName: "orders", ReferencedSchema: "public", ReferencedTable: "shops"}, // outside set
+	}
+
+	parents := fkParents([]string{"public.users", "public.orders"}, fks)
+	assert.Equal(t, map[string][]string{"public.orders": {"public.users"}}, parents)
+}
+
+func TestCopyTablesConcurrently_WaitsForParents(t *testing.T) {
+	tables := []string{"public.users", "public.events", "public.orders", "public.items"}
+	parents := map[string][]string{
+		"public.orders": {"public.users"},
+		"public.items":  {"public.orders", "public.users"},
+	}
+
+	var mu sync.Mutex
+	finished := make(map[string]bool)
+	var violations []string
+
+	err := copyTablesConcurrently(context.Background(), tables, parents, 4, func(ctx context.Context, table string) error {
+		mu.Lock()
+		for _, p := range parents[table] {
+			if !finished[p] {
+				violations = append(violations, table+" started before "+p)
+			}
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		finished[table] = true
+		mu.Unlock()
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+	assert.Len(t, finished, len(tables))
+}
+
+func TestCopyTablesConcurrently_LimitsWorkers(t *testing.T) {
+	tables := []string{"a.t1", "a.t2", "a.t3", "a.t4", "a.t5", "a.t6"}
+
+	var inFlight, maxInFlight int32
+	err := copyTablesConcurrently(context.Background(), tables, nil, 3, func(ctx context.Context, table string) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), maxInFlight)
+}
+
+func TestCopyTablesConcurrently_ErrorCancelsRemaining(t *testing.T) {
+	tables := []string{"public.users", "public.slow", "public.orders"}
+	parents := map[string][]string{"public.orders": {"public.users"}}
+	boom := errors.New("boom")
+
+	var ran sync.Map
+	var slowCancelled atomic.Bool
+	err := copyTablesConcurrently(context.Background(), tables, parents, 2, func(ctx context.Context, table string) error {
+		ran.Store(table, true)
+		switch table {
+		case "public.users":
+			return boom
+		case "public.slow":
+			select {
+			case <-ctx.Done():
+				slowCancelled.Store(true)
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+				return nil
+			}
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, boom)
+	assert.True(t, slowCancelled.Load(), "running copies are cancelled")
+	_, ordersRan := ran.Load("public.orders")
+	assert.False(t, ordersRan, "children of a failed table never start")
+}
+
+func TestCopyTablesConcurrently_ReleasesCycles(t *testing.T) {
+	tables := []string{"public.a", "public.b", "public.c"}
+	parents := map[string][]string{
+		"public.a": {"public.b"},
+		"public.b": {"public.a"},
+		"public.c": {"public.b"},
+	}
+
+	var mu sync.Mutex
+	var order []string
+	err := copyTablesConcurrently(context.Background(), tables, parents, 2, func(ctx context.Context, table string) error {
+		mu.Lock()
+		order = append(order, table)
+		mu.Unlock()
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"public.a", "public.b", "public.c"}, order)
+}
+
+func TestCopyTablesConcurrently_ParentContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls int32
+	err := copyTablesConcurrently(ctx, []string{"a.t1", "a.t2"}, nil, 2, func(ctx context.Context, table string) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, atomic.LoadInt32(&calls))
+}
+
+func TestLargeCopyCandidates(t *testing.T) {
+	big := int64(parallelCopyMinBytes)
+	tables := []TableInfo{
+		{Schema: "public", Name: "users", SizeBytes: big},
+		{Schema: "public", Name: "orders", SizeBytes: big * 2},
+		{Schema: "public", Name: "audit", SizeBytes: big},    // excluded
+		{Schema: "public", Name: "emails", SizeBytes: big},   // masked, copied separately anyway
+		{Schema: "public", Name: "Mixed", SizeBytes: big},    // needs quoting
+		{Schema: "public", Name: "tags", SizeBytes: big - 1}, // small
+	}
+	candidates := largeCopyCandidates(tables, []string{"public.audit"}, map[string]bool{"public.emails": true})
+	assert.Equal(t, []string{"public.users", "public.orders"}, candidates)
+}
+
+func TestGoldenDumpArgs_ConstraintsFollowSeparateCopies(t *testing.T) {
+	// users is masked and orders.user_id references it: the data pass leaves
+	// users empty, so the foreign key must only be added by the post-data pass
+	data := goldenDumpArgs("postgres://src", false, []string{"public.audit", "public.users"})
+	assert.Contains(t, data, "--section=pre-data")
+	assert.Contains(t, data, "--section=data")
+	assert.NotContains(t, data, "--section=post-data")
+	assert.Contains(t, data, "--exclude-table-data=public.users")
+	assert.Contains(t, data, "--clean")
+
+	post := goldenDumpArgs("postgres://src", true, nil)
+	assert.Equal(t, []string{"postgres://src", "--no-owner", "--no-acl", "--section=post-data"}, post)
+}
+
+func TestPsqlErrorLines(t *testing.T) {
+	stderr := "NOTICE:  table \"x\" does not exist, skipping\n" +
+		"psql:<stdin>:120: ERROR:  insert or update on table \"orders\" violates foreign key constraint \"orders_user_id_fkey\"\n" +
+		"DETAIL:  Key (user_id)=(1) is not present in table \"users\".\n"
+	assert.Equal(t, []string{
+		`psql:<stdin>:120: ERROR:  insert or update on table "orders" violates foreign key constraint "orders_user_id_fkey"`,
+	}, psqlErrorLines(stderr))
+	assert.Empty(t, psqlErrorLines("NOTICE: nothing\n"))
+
+	var many strings.Builder
+	for i := 0; i < maxReportedPsqlErrors+5; i++ {
+		many.WriteString("ERROR:  boom\n")
+	}
+	lines := psqlErrorLines(many.String())
+	assert.Len(t, lines, maxReportedPsqlErrors+1)
+	assert.Equal(t, "...", lines[maxReportedPsqlErrors])
+}
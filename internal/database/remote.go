@@ -41,6 +41,8 @@ func RemoteCloneForWorktree(ctx context.Context, sshHost, cloneURL, devURL, targ
 	if err := execSSHSQL(ctx, sshHost, adminURL, createSQL); err != nil {
 		return fmt.Errorf("failed to create database: %w", err)
 	}
+	// Recorded before cloning so gc can find it if the clone is interrupted
+	_ = RecordRemoteDatabase(sshHost, targetDB)
 
 	if progress != nil {
 		progress("Cloning data via pg_dump | psql (server-side)")
@@ -50,7 +52,9 @@ func RemoteCloneForWorktree(ctx context.Context, sshHost, cloneURL, devURL, targ
 	if err := cloneViaSSH(ctx, sshHost, cloneURL, targetURL, excludeTables); err != nil {
 		// Cleanup on failure
 		dropSQL := fmt.Sprintf(`DROP DATABASE IF EXISTS %q`, targetDB)
-		_ = execSSHSQL(ctx, sshHost, adminURL, dropSQL)
+		if execSSHSQL(ctx, sshHost, adminURL, dropSQL) == nil {
+			_ = ForgetRemoteDatabase(sshHost, targetDB)
+		}
 		return fmt.Errorf("clone failed: %w", err)
 	}
 
@@ -292,7 +296,11 @@ func RemoteDropDatabase(ctx context.Context, sshHost, devURL, dbName string) err
 
 	// Drop database (must be separate command - DROP DATABASE cannot run in transaction)
 	dropSQL := fmt.Sprintf(`DROP DATABASE IF EXISTS %q`, dbName)
-	return execSSHSQL(ctx, sshHost, adminURL, dropSQL)
+	if err := execSSHSQL(ctx, sshHost, adminURL, dropSQL); err != nil {
+		return err
+	}
+	_ = ForgetRemoteDatabase(sshHost, dbName)
+	return nil
 }
 
 // RemoteDBExists checks if a database exists on the remote server via SSH
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/hammashamzah/conductor/internal/config"
+)
+
+// Remote dev servers are shared with other machines and teammates, so a dev_*
+// database missing from this config may well be someone else's. This machine
+// records the remote databases it creates, and gc only ever considers those.
+
+// remoteRegistryLockTimeout bounds the wait for another process updating the registry
+const remoteRegistryLockTimeout = 10 * time.Second
+
+// remoteRegistry maps an SSH host to the dev databases created on it
+type remoteRegistry map[string][]string
+
+// remoteRegistryPath returns ~/.conductor/remote-databases.json
+func remoteRegistryPath() (string, error) {
+	dir, err := config.ConductorDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "remote-databases.json"), nil
+}
+
+func loadRemoteRegistry(path string) (remoteRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return remoteRegistry{}, nil
+		}
+		return nil, fmt.Errorf("failed to read remote database registry: %w", err)
+	}
+	reg := remoteRegistry{}
+	if err := json.Unmarshal(data, &reg); err != nil {
+		return nil, fmt.Errorf("failed to parse remote database registry: %w", err)
+	}
+	return reg, nil
+}
+
+// updateRemoteRegistry applies fn to the registry under a file lock
+func updateRemoteRegistry(fn func(reg remoteRegistry)) error {
+	path, err := remoteRegistryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	unlock, err := config.LockFile(path+".lock", remoteRegistryLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = unlock() }()
+
+	reg, err := loadRemoteRegistry(path)
+	if err != nil {
+		return err
+	}
+	fn(reg)
+	data, err := json.MarshalIndent(reg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return config.WriteFileAtomic(path, data, 0644)
+}
+
+// RecordRemoteDatabase notes that this machine created dbName on sshHost
+func RecordRemoteDatabase(sshHost, dbName string) error {
+	return updateRemoteRegistry(func(reg remoteRegistry) {
+		for _, name := range reg[sshHost] {
+			if name == dbName {
+				return
+			}
+		}
+		reg[sshHost] = append(reg[sshHost], dbName)
+		sort.Strings(reg[sshHost])
+	})
+}
+
+// ForgetRemoteDatabase removes dbName on sshHost from the registry once it is dropped
+func ForgetRemoteDatabase(sshHost, dbName string) error {
+	return updateRemoteRegistry(func(reg remoteRegistry) {
+		var kept []string
+		for _, name := range reg[sshHost] {
+			if name != dbName {
+				kept = append(kept, name)
+			}
+		}
+		if len(kept) == 0 {
+			delete(reg, sshHost)
+		} else {
+			reg[sshHost] = kept
+		}
+	})
+}
+
+// RemoteDatabasesCreated returns the databases this machine created on sshHost
+func RemoteDatabasesCreated(sshHost string) (map[string]bool, error) {
+	path, err := remoteRegistryPath()
+	if err != nil {
+		return nil, err
+	}
+	reg, err := loadRemoteRegistry(path)
+	if err != nil {
+		return nil, err
+	}
+	created := make(map[string]bool, len(reg[sshHost]))
+	for _, name := range reg[sshHost] {
+		created[name] = true
+	}
+	return created, nil
+}
@@ -0,0 +1,197 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// Wednesday
+	from := time.Date(2026, 3, 11, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected time.Time
+	}{
+		{
+			name:     "every 15 minutes",
+			expr:     "*/15 * * * *",
+			expected: time.Date(2026, 3, 11, 10, 45, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily macro",
+			expr:     "@daily",
+			expected: time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "nightly at 3am",
+			expr:     "0 3 * * *",
+			expected: time.Date(2026, 3, 12, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekdays range",
+			expr:     "0 9 * * 1-5",
+			expected: time.Date(2026, 3, 12, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "sunday as 7",
+			expr:     "0 0 * * 7",
+			expected: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "list of hours",
+			expr:     "0 6,18 * * *",
+			expected: time.Date(2026, 3, 11, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day of month or day of week",
+			expr:     "0 0 20 * 4",
+			expected: time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "rolls over the year",
+			expr:     "0 0 1 1 *",
+			expected: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "leap day",
+			expr:     "0 0 29 2 *",
+			expected: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "never matches",
+			expr:     "0 0 30 2 *",
+			expected: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, schedule.Next(from))
+		})
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@often"} {
+		_, err := ParseSchedule(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestSyncCooldown(t *testing.T) {
+	from := time.Date(2026, 3, 11, 10, 30, 0, 0, time.UTC)
+
+	hourly, _ := ParseSchedule("@hourly")
+	assert.Equal(t, 30*time.Minute, syncCooldown(hourly, from))
+
+	weekly, _ := ParseSchedule("@weekly")
+	assert.Equal(t, DefaultSyncCooldown, syncCooldown(weekly, from))
+}
+
+type fakeSchedulerStore struct {
+	cfg *config.Config
+}
+
+func (f *fakeSchedulerStore) GetConfigSnapshot() *config.Config { return f.cfg }
+
+func (f *fakeSchedulerStore) SetDatabaseSyncStatus(string, *config.DatabaseSyncStatus) error {
+	return nil
+}
+
+func TestScheduler_RunsDueProjects(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Defaults.LocalPostgresURL = "postgres://localhost:5432/postgres"
+	cfg.Projects["app"] = &config.Project{Database: &config.DatabaseConfig{Source: "postgres://prod/app", SyncSchedule: "0 * * * *"}}
+	cfg.Projects["remote"] = &config.Project{Database: &config.DatabaseConfig{SyncSchedule: "* * * * *", Mode: config.DatabaseModeRemote}}
+	cfg.Projects["broken"] = &config.Project{Database: &config.DatabaseConfig{SyncSchedule: "every day"}}
+
+	now := time.Date(2026, 3, 11, 10, 30, 0, 0, time.UTC)
+	s := NewScheduler(&fakeSchedulerStore{cfg: cfg}, nil)
+	s.now = func() time.Time { return now }
+
+	var mu sync.Mutex
+	var ran []string
+	s.runSync = func(_ context.Context, projectName string, _ *config.Project, _ string, _ *Schedule) {
+		mu.Lock()
+		ran = append(ran, projectName)
+		mu.Unlock()
+	}
+
+	ctx := context.Background()
+	s.check(ctx)
+	assert.Equal(t, time.Date(2026, 3, 11, 11, 0, 0, 0, time.UTC), s.NextRun("app"))
+	assert.True(t, s.NextRun("remote").IsZero())
+	assert.True(t, s.NextRun("broken").IsZero())
+
+	// Not due yet
+	now = now.Add(20 * time.Minute)
+	s.check(ctx)
+	s.running.Wait()
+	assert.Empty(t, ran)
+
+	now = now.Add(10 * time.Minute)
+	s.check(ctx)
+	s.running.Wait()
+	assert.Equal(t, []string{"app"}, ran)
+	assert.Equal(t, time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC), s.NextRun("app"))
+
+	// A changed schedule takes effect on the next check
+	cfg.Projects["app"].Database.SyncSchedule = "30 11 * * *"
+	s.check(ctx)
+	assert.Equal(t, time.Date(2026, 3, 11, 11, 30, 0, 0, time.UTC), s.NextRun("app"))
+}
+
+func TestScheduler_GCDropsAfterTwoSweeps(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Defaults.DatabaseGCSchedule = "0 * * * *"
+	cfg.Projects["app"] = &config.Project{Worktrees: map[string]*config.Worktree{}}
+
+	now := time.Date(2026, 3, 11, 10, 30, 0, 0, time.UTC)
+	s := NewScheduler(&fakeSchedulerStore{cfg: cfg}, nil)
+	s.now = func() time.Time { return now }
+
+	orphans := []OrphanedDatabase{{Name: "app_3100", Kind: OrphanWorktree}, {Name: "app_3200", Kind: OrphanWorktree}}
+	s.findOrphans = func(context.Context, *config.Config, string) ([]OrphanedDatabase, error) {
+		return orphans, nil
+	}
+	var dropped []string
+	s.dropOrphan = func(_ context.Context, _ string, o OrphanedDatabase) error {
+		dropped = append(dropped, o.Name)
+		return nil
+	}
+
+	ctx := context.Background()
+	s.check(ctx)
+	s.running.Wait()
+	assert.Equal(t, time.Date(2026, 3, 11, 11, 0, 0, 0, time.UTC), s.gcNext)
+
+	// First sweep only remembers what it found
+	now = now.Add(30 * time.Minute)
+	s.check(ctx)
+	s.running.Wait()
+	assert.Empty(t, dropped)
+
+	// app_3200 got picked up by a new worktree in the meantime, app_3300
+	// showed up for the first time
+	cfg.Projects["app"].Worktrees["new"] = &config.Worktree{DatabaseName: "app_3200"}
+	orphans = append(orphans, OrphanedDatabase{Name: "app_3300", Kind: OrphanWorktree})
+	now = now.Add(time.Hour)
+	s.check(ctx)
+	s.running.Wait()
+	assert.Equal(t, []string{"app_3100"}, dropped)
+
+	// Clearing the schedule stops sweeps
+	cfg.Defaults.DatabaseGCSchedule = ""
+	s.check(ctx)
+	assert.True(t, s.gcNext.IsZero())
+}
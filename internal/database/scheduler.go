@@ -0,0 +1,363 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/secrets"
+)
+
+// schedulerTick is how often the scheduler checks for due syncs. Cron has
+// minute resolution, so checking more often gains nothing.
+const schedulerTick = time.Minute
+
+// Sync status values stored in config.DatabaseSyncStatus.Status
+const (
+	SyncStateSynced  = "synced"
+	SyncStateSyncing = "syncing"
+	SyncStateFailed  = "failed"
+	SyncStateNever   = "never"
+)
+
+// SchedulerStore is the state the sync scheduler reads schedules from and
+// reports sync results to (implemented by store.Store)
+type SchedulerStore interface {
+	GetConfigSnapshot() *config.Config
+	SetDatabaseSyncStatus(projectName string, status *config.DatabaseSyncStatus) error
+}
+
+// Scheduler runs each project's golden copy sync on its DatabaseConfig.SyncSchedule,
+// and the orphaned database sweep on Defaults.DatabaseGCSchedule
+type Scheduler struct {
+	store SchedulerStore
+	logf  func(format string, args ...any)
+
+	mu       sync.Mutex
+	managers map[string]*Manager  // by local server URL
+	next     map[string]time.Time // project -> next run
+	exprs    map[string]string    // project -> schedule the next run was computed from
+	now      func() time.Time     // overridable for tests
+	runSync  func(ctx context.Context, projectName string, project *config.Project, localURL string, schedule *Schedule)
+	running  sync.WaitGroup
+	disabled map[string]bool // projects whose schedule failed to parse (logged once)
+
+	gcNext      time.Time
+	gcExpr      string
+	gcRunning   bool
+	gcDisabled  bool            // GC schedule failed to parse (logged once)
+	gcSeen      map[string]bool // orphans found by the previous sweep
+	findOrphans func(ctx context.Context, cfg *config.Config, localURL string) ([]OrphanedDatabase, error)
+	dropOrphan  func(ctx context.Context, localURL string, orphan OrphanedDatabase) error
+}
+
+// NewScheduler creates a sync scheduler. logf receives progress and errors;
+// pass nil to discard them (e.g. inside the TUI).
+func NewScheduler(s SchedulerStore, logf func(format string, args ...any)) *Scheduler {
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+	sch := &Scheduler{
+		store:    s,
+		logf:     logf,
+		managers: make(map[string]*Manager),
+		next:     make(map[string]time.Time),
+		exprs:    make(map[string]string),
+		disabled: make(map[string]bool),
+		now:      time.Now,
+
+		findOrphans: findLocalOrphans,
+		dropOrphan:  DropOrphanedDatabase,
+	}
+	sch.runSync = sch.sync
+	return sch
+}
+
+// Start checks for due syncs every minute until ctx is cancelled, then waits
+// for running syncs (which are cancelled with ctx) to return
+func (s *Scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(schedulerTick)
+	defer ticker.Stop()
+	defer s.running.Wait()
+
+	s.check(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.check(ctx)
+		}
+	}
+}
+
+// check starts a sync for every project whose next scheduled run has passed,
+// and the orphaned database sweep when it is due
+func (s *Scheduler) check(ctx context.Context) {
+	cfg := s.store.GetConfigSnapshot()
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for projectName, project := range cfg.Projects {
+		db := project.Database
+		if db == nil || db.SyncSchedule == "" || db.Mode == config.DatabaseModeRemote {
+			delete(s.next, projectName)
+			delete(s.exprs, projectName)
+			continue
+		}
+
+		schedule, err := ParseSchedule(db.SyncSchedule)
+		if err != nil {
+			delete(s.next, projectName)
+			delete(s.exprs, projectName)
+			if !s.disabled[projectName] {
+				s.logf("db scheduler: %s: %v", projectName, err)
+				s.disabled[projectName] = true
+			}
+			continue
+		}
+		delete(s.disabled, projectName)
+
+		// (Re)compute the next run when first seen or when the schedule changed
+		if s.exprs[projectName] != db.SyncSchedule {
+			s.exprs[projectName] = db.SyncSchedule
+			s.next[projectName] = schedule.Next(now)
+		}
+		due := s.next[projectName]
+		if due.IsZero() || now.Before(due) {
+			continue
+		}
+		s.next[projectName] = schedule.Next(now)
+
+		localURL, err := secrets.Resolve(cfg.Defaults.LocalDatabaseURL(db))
+		if err != nil || localURL == "" {
+			s.logf("db scheduler: %s: local database server not configured, skipping scheduled sync", projectName)
+			continue
+		}
+
+		s.running.Add(1)
+		go func(projectName string, project *config.Project) {
+			defer s.running.Done()
+			s.runSync(ctx, projectName, project, localURL, schedule)
+		}(projectName, project)
+	}
+
+	s.checkGC(ctx, cfg, now)
+}
+
+// checkGC starts an orphaned database sweep when Defaults.DatabaseGCSchedule
+// is due. Called with s.mu held.
+func (s *Scheduler) checkGC(ctx context.Context, cfg *config.Config, now time.Time) {
+	expr := cfg.Defaults.DatabaseGCSchedule
+	if expr == "" {
+		s.gcNext, s.gcExpr = time.Time{}, ""
+		return
+	}
+
+	schedule, err := ParseSchedule(expr)
+	if err != nil {
+		s.gcNext, s.gcExpr = time.Time{}, ""
+		if !s.gcDisabled {
+			s.logf("db scheduler: databaseGcSchedule: %v", err)
+			s.gcDisabled = true
+		}
+		return
+	}
+	s.gcDisabled = false
+
+	if s.gcExpr != expr {
+		s.gcExpr = expr
+		s.gcNext = schedule.Next(now)
+	}
+	if s.gcNext.IsZero() || now.Before(s.gcNext) || s.gcRunning {
+		return
+	}
+	s.gcNext = schedule.Next(now)
+
+	localURL, err := secrets.Resolve(cfg.Defaults.LocalPostgresURL)
+	if err != nil {
+		s.logf("db scheduler: gc: %v", err)
+		return
+	}
+
+	s.gcRunning = true
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.gc(ctx, cfg, localURL)
+		s.mu.Lock()
+		s.gcRunning = false
+		s.mu.Unlock()
+	}()
+}
+
+// gc drops orphaned databases. Without anyone to confirm, a database is only
+// dropped once two consecutive sweeps found it orphaned, and only if the
+// current config still doesn't refer to it, so a worktree being created
+// while the sweep runs keeps its database.
+func (s *Scheduler) gc(ctx context.Context, cfg *config.Config, localURL string) {
+	orphans, err := s.findOrphans(ctx, cfg, localURL)
+	if err != nil {
+		s.logf("db scheduler: gc: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	previous := s.gcSeen
+	s.gcSeen = make(map[string]bool, len(orphans))
+	var due []OrphanedDatabase
+	for _, o := range orphans {
+		key := o.Location() + "/" + o.Name
+		s.gcSeen[key] = true
+		if previous[key] {
+			due = append(due, o)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(due) == 0 {
+		return
+	}
+	active := activeDatabaseNames(s.store.GetConfigSnapshot())
+	for _, o := range due {
+		if active[o.Name] {
+			continue
+		}
+		if err := s.dropOrphan(ctx, localURL, o); err != nil {
+			s.logf("db scheduler: gc: failed to drop %s (%s): %v", o.Name, o.Location(), err)
+			continue
+		}
+		s.logf("db scheduler: gc: dropped orphaned database %s (%s, %s)", o.Name, o.Location(), FormatSize(o.SizeBytes))
+	}
+}
+
+// findLocalOrphans is FindOrphanedDatabases without remote servers. Nobody
+// confirms the sweep's drops, and remote servers are shared.
+func findLocalOrphans(ctx context.Context, cfg *config.Config, localURL string) ([]OrphanedDatabase, error) {
+	return FindOrphanedDatabases(ctx, cfg, localURL, false)
+}
+
+// NextRun returns when the scheduler will next sync a project, or the zero
+// time if the project has no (valid) schedule
+func (s *Scheduler) NextRun(projectName string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.next[projectName]
+}
+
+// sync runs one scheduled sync, unless another sync of the project is running
+// (in this or another conductor process) or the golden copy is still fresh
+func (s *Scheduler) sync(ctx context.Context, projectName string, project *config.Project, localURL string, schedule *Schedule) {
+	mgr := s.manager(localURL)
+
+	// The lock is held across the freshness check, so a sync that finished
+	// while this one waited is seen and not repeated
+	unlock, err := lockProjectSync(projectName, templateCloneWait)
+	if err != nil {
+		s.logf("db scheduler: %s: sync already in progress, skipping", projectName)
+		return
+	}
+	defer func() { _ = unlock() }()
+
+	check, err := CheckGoldenDBSyncNeeded(localURL, projectName, syncCooldown(schedule, s.now()))
+	if err != nil {
+		s.logf("db scheduler: %s: %v", projectName, err)
+		return
+	}
+	if !check.NeedsSync {
+		s.logf("db scheduler: %s: skipping scheduled sync, %s", projectName, check.Reason)
+		return
+	}
+
+	dbConfig, err := secrets.ResolveDatabaseConfig(project.Database)
+	if err != nil {
+		s.setStatus(projectName, project.Database.SyncStatus, SyncStateFailed, nil, err)
+		return
+	}
+
+	s.logf("db scheduler: %s: starting scheduled sync (%s)", projectName, check.Reason)
+	s.setStatus(projectName, project.Database.SyncStatus, SyncStateSyncing, nil, nil)
+
+	metadata, err := mgr.syncLocked(ctx, projectName, dbConfig, SyncTriggerSchedule, nil)
+	if err != nil {
+		s.logf("db scheduler: %s: sync failed: %v", projectName, err)
+		s.setStatus(projectName, project.Database.SyncStatus, SyncStateFailed, nil, err)
+		return
+	}
+
+	s.logf("db scheduler: %s: sync completed in %s", projectName, formatMs(metadata.SyncDurationMs))
+	status := &config.DatabaseSyncStatus{
+		LastSyncAt:    metadata.LastSyncAt.Format(time.RFC3339),
+		TableCount:    len(metadata.TableSizes),
+		ExcludedCount: len(metadata.ExcludedTables),
+	}
+	if size, err := GetGoldenDBSize(localURL, projectName); err == nil {
+		status.GoldenCopySize = size
+	}
+	s.setStatus(projectName, nil, SyncStateSynced, status, nil)
+}
+
+// setStatus records a sync state. In-progress and failed states keep the
+// previous sync's details so status output still shows the last good copy.
+func (s *Scheduler) setStatus(projectName string, previous *config.DatabaseSyncStatus, state string, status *config.DatabaseSyncStatus, syncErr error) {
+	if status == nil {
+		status = &config.DatabaseSyncStatus{}
+		if previous != nil {
+			*status = *previous
+		}
+	}
+	status.Status = state
+	status.LastError = ""
+	if syncErr != nil {
+		status.LastError = syncErr.Error()
+	}
+	if err := s.store.SetDatabaseSyncStatus(projectName, status); err != nil {
+		s.logf("db scheduler: %s: failed to record sync status: %v", projectName, err)
+	}
+}
+
+func (s *Scheduler) manager(localURL string) *Manager {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mgr, ok := s.managers[localURL]
+	if !ok {
+		mgr = NewManager(localURL, "")
+		s.managers[localURL] = mgr
+	}
+	return mgr
+}
+
+// syncCooldown is how recent a golden copy must be for a scheduled run to be
+// skipped: half the schedule's interval, capped at DefaultSyncCooldown, so an
+// hourly schedule still syncs hourly
+func syncCooldown(schedule *Schedule, now time.Time) time.Duration {
+	cooldown := schedule.Interval(now) / 2
+	if cooldown <= 0 || cooldown > DefaultSyncCooldown {
+		return DefaultSyncCooldown
+	}
+	return cooldown
+}
+
+// templateCloneWait is how long a sync waits for the sync lock.
+// Worktree template copies hold it briefly; a running sync holds it far longer.
+var templateCloneWait = 2 * time.Minute
+
+// lockProjectSync takes the cross-process lock for a project's golden copy
+// sync, waiting up to timeout
+func lockProjectSync(projectName string, timeout time.Duration) (func() error, error) {
+	dir, err := config.ConductorDir()
+	if err != nil {
+		return nil, err
+	}
+	lockDir := filepath.Join(dir, "locks")
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	return config.LockFile(filepath.Join(lockDir, "dbsync-"+projectName+".lock"), timeout)
+}
@@ -0,0 +1,611 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemaDefinition is a detailed snapshot of a database schema: tables with
+// their columns, plus indexes and constraints. Unlike GetSchemaSnapshot it
+// records full SQL types and definitions, so it can be turned into DDL.
+type SchemaDefinition struct {
+	// Tables keyed by schema.table
+	Tables map[string]*TableSchema
+
+	// Indexes not backing a constraint, keyed by schema.index
+	Indexes map[string]IndexDefinition
+
+	// Constraints (primary key, unique, check, exclusion, foreign key),
+	// keyed by schema.table.constraint
+	Constraints map[string]ConstraintDefinition
+}
+
+// IndexDefinition is an index and the CREATE INDEX statement that defines it
+type IndexDefinition struct {
+	Schema     string `json:"schema"`
+	Table      string `json:"table"`
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+}
+
+// Constraint types as stored in pg_constraint.contype
+const (
+	ConstraintPrimaryKey = "p"
+	ConstraintUnique     = "u"
+	ConstraintCheck      = "c"
+	ConstraintExclusion  = "x"
+	ConstraintForeignKey = "f"
+)
+
+// ConstraintDefinition is a table constraint and its definition
+type ConstraintDefinition struct {
+	Schema     string `json:"schema"`
+	Table      string `json:"table"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Definition string `json:"definition"`
+}
+
+// IndexChange is an index whose definition differs between two schemas
+type IndexChange struct {
+	From IndexDefinition `json:"from"`
+	To   IndexDefinition `json:"to"`
+}
+
+// ConstraintChange is a constraint whose definition differs between two schemas
+type ConstraintChange struct {
+	From ConstraintDefinition `json:"from"`
+	To   ConstraintDefinition `json:"to"`
+}
+
+// ColumnChange is a column whose type, nullability or default differs
+type ColumnChange struct {
+	From ColumnSchema `json:"from"`
+	To   ColumnSchema `json:"to"`
+}
+
+// TableChanges lists the column differences of a table present in both schemas
+type TableChanges struct {
+	Table          string         `json:"table"`
+	AddedColumns   []ColumnSchema `json:"addedColumns,omitempty"`
+	RemovedColumns []ColumnSchema `json:"removedColumns,omitempty"`
+	AlteredColumns []ColumnChange `json:"alteredColumns,omitempty"`
+}
+
+// SchemaComparison is the full difference between two schemas, read as the
+// changes needed to turn the "from" schema into the "to" schema
+type SchemaComparison struct {
+	from *SchemaDefinition
+	to   *SchemaDefinition
+
+	AddedTables   []string       `json:"addedTables,omitempty"`
+	RemovedTables []string       `json:"removedTables,omitempty"`
+	AlteredTables []TableChanges `json:"alteredTables,omitempty"`
+
+	AddedIndexes   []IndexDefinition `json:"addedIndexes,omitempty"`
+	RemovedIndexes []IndexDefinition `json:"removedIndexes,omitempty"`
+	AlteredIndexes []IndexChange     `json:"alteredIndexes,omitempty"`
+
+	AddedConstraints   []ConstraintDefinition `json:"addedConstraints,omitempty"`
+	RemovedConstraints []ConstraintDefinition `json:"removedConstraints,omitempty"`
+	AlteredConstraints []ConstraintChange     `json:"alteredConstraints,omitempty"`
+}
+
+// GetSchemaDefinition reads the tables, columns, indexes and constraints of a
+// database. System schemas, partitions and conductor's own metadata table are
+// skipped.
+func GetSchemaDefinition(connStr string) (*SchemaDefinition, error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	def := &SchemaDefinition{
+		Tables:      make(map[string]*TableSchema),
+		Indexes:     make(map[string]IndexDefinition),
+		Constraints: make(map[string]ConstraintDefinition),
+	}
+
+	const tableFilter = `
+		t.relkind IN ('r', 'p')
+		AND NOT t.relispartition
+		AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+		AND n.nspname NOT LIKE 'pg_toast%'
+		AND n.nspname NOT LIKE 'pg_temp%'
+		AND NOT (n.nspname = 'public' AND t.relname = '` + ConductorSyncTable + `')`
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT
+			n.nspname,
+			t.relname,
+			a.attname,
+			format_type(a.atttypid, a.atttypmod),
+			NOT a.attnotnull,
+			COALESCE(pg_get_expr(d.adbin, d.adrelid), ''),
+			a.attidentity::text,
+			a.attgenerated::text
+		FROM pg_attribute a
+		JOIN pg_class t ON t.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attnum > 0 AND NOT a.attisdropped AND`+tableFilter+`
+		ORDER BY n.nspname, t.relname, a.attnum
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+	for rows.Next() {
+		var schema, table string
+		var col ColumnSchema
+		var identity, generated string
+		if err := rows.Scan(&schema, &table, &col.Name, &col.DataType, &col.IsNullable, &col.DefaultValue, &identity, &generated); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		col.Identity = identity
+		col.Generated = generated == "s"
+
+		fullName := schema + "." + table
+		if def.Tables[fullName] == nil {
+			def.Tables[fullName] = &TableSchema{Name: table, Schema: schema, Columns: []ColumnSchema{}}
+		}
+		def.Tables[fullName].Columns = append(def.Tables[fullName].Columns, col)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.QueryContext(ctx, `
+		SELECT n.nspname, t.relname, i.relname, pg_get_indexdef(x.indexrelid)
+		FROM pg_index x
+		JOIN pg_class i ON i.oid = x.indexrelid
+		JOIN pg_class t ON t.oid = x.indrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE NOT EXISTS (
+			SELECT 1 FROM pg_constraint con
+			WHERE con.conindid = x.indexrelid AND con.contype IN ('p', 'u', 'x')
+		) AND`+tableFilter+`
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes: %w", err)
+	}
+	for rows.Next() {
+		var idx IndexDefinition
+		if err := rows.Scan(&idx.Schema, &idx.Table, &idx.Name, &idx.Definition); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		def.Indexes[idx.Schema+"."+idx.Name] = idx
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.QueryContext(ctx, `
+		SELECT n.nspname, t.relname, con.conname, con.contype::text, pg_get_constraintdef(con.oid)
+		FROM pg_constraint con
+		JOIN pg_class t ON t.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE con.contype IN ('p', 'u', 'c', 'x', 'f') AND`+tableFilter+`
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read constraints: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var con ConstraintDefinition
+		if err := rows.Scan(&con.Schema, &con.Table, &con.Name, &con.Type, &con.Definition); err != nil {
+			return nil, err
+		}
+		def.Constraints[con.Schema+"."+con.Table+"."+con.Name] = con
+	}
+
+	return def, rows.Err()
+}
+
+// CompareDatabaseSchemas reads and compares the schemas of two databases on
+// the local server, e.g. a project's golden copy and a worktree database
+func CompareDatabaseSchemas(localURL, fromDBName, toDBName string) (*SchemaComparison, error) {
+	from, err := GetSchemaDefinition(BuildWorktreeURL(localURL, fromDBName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema of %s: %w", fromDBName, err)
+	}
+	to, err := GetSchemaDefinition(BuildWorktreeURL(localURL, toDBName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema of %s: %w", toDBName, err)
+	}
+	return CompareSchemas(from, to), nil
+}
+
+// CompareSchemas returns the changes that turn the "from" schema into the
+// "to" schema. Table-level changes come from ComputeSchemaDiff; columns,
+// indexes and constraints are compared in detail.
+func CompareSchemas(from, to *SchemaDefinition) *SchemaComparison {
+	tableDiff := ComputeSchemaDiff(from.Tables, to.Tables)
+
+	cmp := &SchemaComparison{
+		from:          from,
+		to:            to,
+		AddedTables:   tableDiff.NewTables,
+		RemovedTables: tableDiff.RemovedTables,
+	}
+	sort.Strings(cmp.AddedTables)
+	sort.Strings(cmp.RemovedTables)
+
+	for _, name := range sortedKeys(to.Tables) {
+		fromTable, ok := from.Tables[name]
+		if !ok {
+			continue
+		}
+		if changes := compareColumns(name, fromTable.Columns, to.Tables[name].Columns); changes != nil {
+			cmp.AlteredTables = append(cmp.AlteredTables, *changes)
+		}
+	}
+
+	for _, key := range sortedKeys(to.Indexes) {
+		idx := to.Indexes[key]
+		old, ok := from.Indexes[key]
+		switch {
+		case !ok:
+			cmp.AddedIndexes = append(cmp.AddedIndexes, idx)
+		case old.Definition != idx.Definition:
+			cmp.AlteredIndexes = append(cmp.AlteredIndexes, IndexChange{From: old, To: idx})
+		}
+	}
+	for _, key := range sortedKeys(from.Indexes) {
+		if _, ok := to.Indexes[key]; !ok {
+			cmp.RemovedIndexes = append(cmp.RemovedIndexes, from.Indexes[key])
+		}
+	}
+
+	for _, key := range sortedKeys(to.Constraints) {
+		con := to.Constraints[key]
+		old, ok := from.Constraints[key]
+		switch {
+		case !ok:
+			cmp.AddedConstraints = append(cmp.AddedConstraints, con)
+		case old.Type != con.Type || old.Definition != con.Definition:
+			cmp.AlteredConstraints = append(cmp.AlteredConstraints, ConstraintChange{From: old, To: con})
+		}
+	}
+	for _, key := range sortedKeys(from.Constraints) {
+		if _, ok := to.Constraints[key]; !ok {
+			cmp.RemovedConstraints = append(cmp.RemovedConstraints, from.Constraints[key])
+		}
+	}
+
+	return cmp
+}
+
+// compareColumns returns the column differences of a table, or nil if none
+func compareColumns(table string, from, to []ColumnSchema) *TableChanges {
+	changes := &TableChanges{Table: table}
+
+	fromCols := make(map[string]ColumnSchema, len(from))
+	for _, col := range from {
+		fromCols[col.Name] = col
+	}
+	toCols := make(map[string]bool, len(to))
+	for _, col := range to {
+		toCols[col.Name] = true
+		old, ok := fromCols[col.Name]
+		switch {
+		case !ok:
+			changes.AddedColumns = append(changes.AddedColumns, col)
+		case old != col:
+			changes.AlteredColumns = append(changes.AlteredColumns, ColumnChange{From: old, To: col})
+		}
+	}
+	for _, col := range from {
+		if !toCols[col.Name] {
+			changes.RemovedColumns = append(changes.RemovedColumns, col)
+		}
+	}
+
+	if len(changes.AddedColumns) == 0 && len(changes.RemovedColumns) == 0 && len(changes.AlteredColumns) == 0 {
+		return nil
+	}
+	return changes
+}
+
+// HasChanges reports whether the two schemas differ
+func (c *SchemaComparison) HasChanges() bool {
+	return len(c.AddedTables) > 0 || len(c.RemovedTables) > 0 || len(c.AlteredTables) > 0 ||
+		len(c.AddedIndexes) > 0 || len(c.RemovedIndexes) > 0 || len(c.AlteredIndexes) > 0 ||
+		len(c.AddedConstraints) > 0 || len(c.RemovedConstraints) > 0 || len(c.AlteredConstraints) > 0
+}
+
+// Lines renders the comparison as a human-readable report, one change per
+// line, prefixed with + (added), - (removed) or ~ (altered)
+func (c *SchemaComparison) Lines() []string {
+	if !c.HasChanges() {
+		return []string{"No schema differences"}
+	}
+
+	var lines []string
+	section := func(title string) {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, title+":")
+	}
+
+	if len(c.AddedTables) > 0 || len(c.RemovedTables) > 0 || len(c.AlteredTables) > 0 {
+		section("Tables")
+		for _, name := range c.AddedTables {
+			lines = append(lines, fmt.Sprintf("  + %s (%d columns)", name, len(c.to.Tables[name].Columns)))
+		}
+		for _, name := range c.RemovedTables {
+			lines = append(lines, "  - "+name)
+		}
+		for _, t := range c.AlteredTables {
+			lines = append(lines, "  ~ "+t.Table)
+			for _, col := range t.AddedColumns {
+				lines = append(lines, fmt.Sprintf("      + column %s %s", col.Name, col.DataType))
+			}
+			for _, col := range t.RemovedColumns {
+				lines = append(lines, fmt.Sprintf("      - column %s %s", col.Name, col.DataType))
+			}
+			for _, ch := range t.AlteredColumns {
+				lines = append(lines, fmt.Sprintf("      ~ column %s: %s", ch.To.Name, describeColumnChange(ch)))
+			}
+		}
+	}
+
+	if len(c.AddedIndexes) > 0 || len(c.RemovedIndexes) > 0 || len(c.AlteredIndexes) > 0 {
+		section("Indexes")
+		for _, idx := range c.AddedIndexes {
+			lines = append(lines, "  + "+idx.Definition)
+		}
+		for _, idx := range c.RemovedIndexes {
+			lines = append(lines, "  - "+idx.Definition)
+		}
+		for _, ch := range c.AlteredIndexes {
+			lines = append(lines, "  ~ "+ch.From.Definition)
+			lines = append(lines, "    → "+ch.To.Definition)
+		}
+	}
+
+	constraintSection := func(title string, foreignKeys bool) {
+		match := func(con ConstraintDefinition) bool {
+			return (con.Type == ConstraintForeignKey) == foreignKeys
+		}
+		var sectionLines []string
+		for _, con := range c.AddedConstraints {
+			if match(con) {
+				sectionLines = append(sectionLines, fmt.Sprintf("  + %s.%s %s %s", con.Schema, con.Table, con.Name, con.Definition))
+			}
+		}
+		for _, con := range c.RemovedConstraints {
+			if match(con) {
+				sectionLines = append(sectionLines, fmt.Sprintf("  - %s.%s %s %s", con.Schema, con.Table, con.Name, con.Definition))
+			}
+		}
+		for _, ch := range c.AlteredConstraints {
+			if match(ch.To) {
+				sectionLines = append(sectionLines, fmt.Sprintf("  ~ %s.%s %s %s", ch.To.Schema, ch.To.Table, ch.To.Name, ch.From.Definition))
+				sectionLines = append(sectionLines, "    → "+ch.To.Definition)
+			}
+		}
+		if len(sectionLines) > 0 {
+			section(title)
+			lines = append(lines, sectionLines...)
+		}
+	}
+	constraintSection("Foreign keys", true)
+	constraintSection("Constraints", false)
+
+	return lines
+}
+
+// describeColumnChange summarizes what changed about a column
+func describeColumnChange(ch ColumnChange) string {
+	var parts []string
+	if ch.From.DataType != ch.To.DataType {
+		parts = append(parts, fmt.Sprintf("type %s → %s", ch.From.DataType, ch.To.DataType))
+	}
+	if ch.From.IsNullable != ch.To.IsNullable {
+		if ch.To.IsNullable {
+			parts = append(parts, "now nullable")
+		} else {
+			parts = append(parts, "now NOT NULL")
+		}
+	}
+	if ch.From.DefaultValue != ch.To.DefaultValue {
+		switch {
+		case ch.To.DefaultValue == "":
+			parts = append(parts, "default dropped")
+		case ch.From.DefaultValue == "":
+			parts = append(parts, "default "+ch.To.DefaultValue)
+		default:
+			parts = append(parts, fmt.Sprintf("default %s → %s", ch.From.DefaultValue, ch.To.DefaultValue))
+		}
+	}
+	if ch.From.Identity != ch.To.Identity || ch.From.Generated != ch.To.Generated {
+		parts = append(parts, "identity/generation changed")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// nextvalPattern extracts the sequence name from a serial column default
+var nextvalPattern = regexp.MustCompile(`^nextval\('(.+)'::regclass\)$`)
+
+// SQLPatch renders the comparison as SQL that turns the "from" schema into
+// the "to" schema. Statements are ordered so dependencies are dropped before
+// and created after the objects they need, and wrapped in a transaction.
+// Changes that cannot be expressed safely are emitted as comments.
+func (c *SchemaComparison) SQLPatch() string {
+	if !c.HasChanges() {
+		return "-- No schema differences\n"
+	}
+
+	var b strings.Builder
+	b.WriteString("BEGIN;\n")
+	stmt := func(format string, args ...any) {
+		b.WriteString(fmt.Sprintf(format, args...))
+		b.WriteString("\n")
+	}
+
+	removedTables := make(map[string]bool, len(c.RemovedTables))
+	for _, name := range c.RemovedTables {
+		removedTables[name] = true
+	}
+
+	// Drop constraints first (foreign keys before the keys they reference),
+	// then indexes. Objects of dropped tables go away with the table.
+	var dropConstraints []ConstraintDefinition
+	dropConstraints = append(dropConstraints, c.RemovedConstraints...)
+	for _, ch := range c.AlteredConstraints {
+		dropConstraints = append(dropConstraints, ch.From)
+	}
+	sort.SliceStable(dropConstraints, func(i, j int) bool {
+		return dropConstraints[i].Type == ConstraintForeignKey && dropConstraints[j].Type != ConstraintForeignKey
+	})
+	for _, con := range dropConstraints {
+		if !removedTables[con.Schema+"."+con.Table] {
+			stmt("ALTER TABLE %s DROP CONSTRAINT %s;", qualifiedName(con.Schema, con.Table), quoteIdentifier(con.Name))
+		}
+	}
+
+	var dropIndexes []IndexDefinition
+	dropIndexes = append(dropIndexes, c.RemovedIndexes...)
+	for _, ch := range c.AlteredIndexes {
+		dropIndexes = append(dropIndexes, ch.From)
+	}
+	for _, idx := range dropIndexes {
+		if !removedTables[idx.Schema+"."+idx.Table] {
+			stmt("DROP INDEX %s;", qualifiedName(idx.Schema, idx.Name))
+		}
+	}
+
+	for _, name := range c.RemovedTables {
+		t := c.from.Tables[name]
+		stmt("DROP TABLE %s;", qualifiedName(t.Schema, t.Name))
+	}
+
+	for _, name := range c.AddedTables {
+		t := c.to.Tables[name]
+		for _, col := range t.Columns {
+			writeSequenceFor(&b, col)
+		}
+		defs := make([]string, len(t.Columns))
+		for i, col := range t.Columns {
+			defs[i] = "    " + columnDefinitionSQL(col)
+		}
+		stmt("CREATE TABLE %s (\n%s\n);", qualifiedName(t.Schema, t.Name), strings.Join(defs, ",\n"))
+	}
+
+	for _, t := range c.AlteredTables {
+		table := c.to.Tables[t.Table]
+		name := qualifiedName(table.Schema, table.Name)
+		for _, col := range t.RemovedColumns {
+			stmt("ALTER TABLE %s DROP COLUMN %s;", name, quoteIdentifier(col.Name))
+		}
+		for _, col := range t.AddedColumns {
+			writeSequenceFor(&b, col)
+			stmt("ALTER TABLE %s ADD COLUMN %s;", name, columnDefinitionSQL(col))
+		}
+		for _, ch := range t.AlteredColumns {
+			col := quoteIdentifier(ch.To.Name)
+			if ch.From.Identity != ch.To.Identity || ch.From.Generated != ch.To.Generated {
+				stmt("-- %s.%s: identity or generation expression changed, review manually", name, col)
+				continue
+			}
+			if ch.From.DataType != ch.To.DataType {
+				stmt("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;", name, col, ch.To.DataType, col, ch.To.DataType)
+			}
+			if ch.From.DefaultValue != ch.To.DefaultValue {
+				if ch.To.DefaultValue == "" {
+					stmt("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", name, col)
+				} else {
+					writeSequenceFor(&b, ch.To)
+					stmt("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", name, col, ch.To.DefaultValue)
+				}
+			}
+			if ch.From.IsNullable != ch.To.IsNullable {
+				if ch.To.IsNullable {
+					stmt("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", name, col)
+				} else {
+					stmt("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", name, col)
+				}
+			}
+		}
+	}
+
+	var createIndexes []IndexDefinition
+	createIndexes = append(createIndexes, c.AddedIndexes...)
+	for _, ch := range c.AlteredIndexes {
+		createIndexes = append(createIndexes, ch.To)
+	}
+	for _, idx := range createIndexes {
+		stmt("%s;", idx.Definition)
+	}
+
+	// Keys must exist before the foreign keys that reference them
+	var addConstraints []ConstraintDefinition
+	addConstraints = append(addConstraints, c.AddedConstraints...)
+	for _, ch := range c.AlteredConstraints {
+		addConstraints = append(addConstraints, ch.To)
+	}
+	sort.SliceStable(addConstraints, func(i, j int) bool {
+		return addConstraints[i].Type != ConstraintForeignKey && addConstraints[j].Type == ConstraintForeignKey
+	})
+	for _, con := range addConstraints {
+		stmt("ALTER TABLE %s ADD CONSTRAINT %s %s;", qualifiedName(con.Schema, con.Table), quoteIdentifier(con.Name), con.Definition)
+	}
+
+	b.WriteString("COMMIT;\n")
+	return b.String()
+}
+
+// columnDefinitionSQL renders a column as used in CREATE TABLE / ADD COLUMN
+func columnDefinitionSQL(col ColumnSchema) string {
+	def := quoteIdentifier(col.Name) + " " + col.DataType
+	switch {
+	case col.Generated:
+		def += " GENERATED ALWAYS AS (" + col.DefaultValue + ") STORED"
+	case col.Identity == "a":
+		def += " GENERATED ALWAYS AS IDENTITY"
+	case col.Identity == "d":
+		def += " GENERATED BY DEFAULT AS IDENTITY"
+	case col.DefaultValue != "":
+		def += " DEFAULT " + col.DefaultValue
+	}
+	if !col.IsNullable {
+		def += " NOT NULL"
+	}
+	return def
+}
+
+// writeSequenceFor creates the sequence a serial column's default draws from
+func writeSequenceFor(b *strings.Builder, col ColumnSchema) {
+	if m := nextvalPattern.FindStringSubmatch(col.DefaultValue); m != nil {
+		fmt.Fprintf(b, "CREATE SEQUENCE IF NOT EXISTS %s;\n", m[1])
+	}
+}
+
+// qualifiedName quotes a schema-qualified object name
+func qualifiedName(schema, name string) string {
+	return quoteIdentifier(schema) + "." + quoteIdentifier(name)
+}
+
+// sortedKeys returns a map's keys in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,193 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func goldenSchemaFixture() *SchemaDefinition {
+	return &SchemaDefinition{
+		Tables: map[string]*TableSchema{
+			"public.users": {Schema: "public", Name: "users", Columns: []ColumnSchema{
+				{Name: "id", DataType: "integer", DefaultValue: "nextval('users_id_seq'::regclass)"},
+				{Name: "email", DataType: "character varying(100)"},
+				{Name: "age", DataType: "integer", IsNullable: true},
+			}},
+			"public.legacy": {Schema: "public", Name: "legacy", Columns: []ColumnSchema{
+				{Name: "id", DataType: "bigint"},
+			}},
+		},
+		Indexes: map[string]IndexDefinition{
+			"public.users_email_idx": {Schema: "public", Table: "users", Name: "users_email_idx",
+				Definition: "CREATE INDEX users_email_idx ON public.users USING btree (email)"},
+			"public.legacy_id_idx": {Schema: "public", Table: "legacy", Name: "legacy_id_idx",
+				Definition: "CREATE INDEX legacy_id_idx ON public.legacy USING btree (id)"},
+		},
+		Constraints: map[string]ConstraintDefinition{
+			"public.users.users_pkey": {Schema: "public", Table: "users", Name: "users_pkey",
+				Type: ConstraintPrimaryKey, Definition: "PRIMARY KEY (id)"},
+		},
+	}
+}
+
+func worktreeSchemaFixture() *SchemaDefinition {
+	return &SchemaDefinition{
+		Tables: map[string]*TableSchema{
+			"public.users": {Schema: "public", Name: "users", Columns: []ColumnSchema{
+				{Name: "id", DataType: "integer", DefaultValue: "nextval('users_id_seq'::regclass)"},
+				{Name: "email", DataType: "text", DefaultValue: "''::text"},
+				{Name: "nickname", DataType: "text", IsNullable: true},
+			}},
+			"public.posts": {Schema: "public", Name: "posts", Columns: []ColumnSchema{
+				{Name: "id", DataType: "bigint", Identity: "d"},
+				{Name: "user_id", DataType: "integer"},
+				{Name: "title", DataType: "text", IsNullable: true},
+			}},
+		},
+		Indexes: map[string]IndexDefinition{
+			"public.users_email_idx": {Schema: "public", Table: "users", Name: "users_email_idx",
+				Definition: "CREATE UNIQUE INDEX users_email_idx ON public.users USING btree (lower(email))"},
+			"public.posts_user_id_idx": {Schema: "public", Table: "posts", Name: "posts_user_id_idx",
+				Definition: "CREATE INDEX posts_user_id_idx ON public.posts USING btree (user_id)"},
+		},
+		Constraints: map[string]ConstraintDefinition{
+			"public.users.users_pkey": {Schema: "public", Table: "users", Name: "users_pkey",
+				Type: ConstraintPrimaryKey, Definition: "PRIMARY KEY (id)"},
+			"public.posts.posts_pkey": {Schema: "public", Table: "posts", Name: "posts_pkey",
+				Type: ConstraintPrimaryKey, Definition: "PRIMARY KEY (id)"},
+			"public.posts.posts_user_id_fkey": {Schema: "public", Table: "posts", Name: "posts_user_id_fkey",
+				Type: ConstraintForeignKey, Definition: "FOREIGN KEY (user_id) REFERENCES users(id)"},
+		},
+	}
+}
+
+func TestCompareSchemas(t *testing.T) {
+	cmp := CompareSchemas(goldenSchemaFixture(), worktreeSchemaFixture())
+	require.True(t, cmp.HasChanges())
+
+	assert.Equal(t, []string{"public.posts"}, cmp.AddedTables)
+	assert.Equal(t, []string{"public.legacy"}, cmp.RemovedTables)
+
+	require.Len(t, cmp.AlteredTables, 1)
+	users := cmp.AlteredTables[0]
+	assert.Equal(t, "public.users", users.Table)
+	require.Len(t, users.AddedColumns, 1)
+	assert.Equal(t, "nickname", users.AddedColumns[0].Name)
+	require.Len(t, users.RemovedColumns, 1)
+	assert.Equal(t, "age", users.RemovedColumns[0].Name)
+	require.Len(t, users.AlteredColumns, 1)
+	assert.Equal(t, "character varying(100)", users.AlteredColumns[0].From.DataType)
+	assert.Equal(t, "text", users.AlteredColumns[0].To.DataType)
+
+	require.Len(t, cmp.AddedIndexes, 1)
+	assert.Equal(t, "posts_user_id_idx", cmp.AddedIndexes[0].Name)
+	require.Len(t, cmp.RemovedIndexes, 1)
+	assert.Equal(t, "legacy_id_idx", cmp.RemovedIndexes[0].Name)
+	require.Len(t, cmp.AlteredIndexes, 1)
+	assert.Equal(t, "users_email_idx", cmp.AlteredIndexes[0].To.Name)
+
+	assert.Len(t, cmp.AddedConstraints, 2)
+	assert.Empty(t, cmp.RemovedConstraints)
+	assert.Empty(t, cmp.AlteredConstraints)
+
+	// Identical schemas have no changes
+	same := CompareSchemas(goldenSchemaFixture(), goldenSchemaFixture())
+	assert.False(t, same.HasChanges())
+	assert.Equal(t, []string{"No schema differences"}, same.Lines())
+}
+
+func TestSchemaComparisonLines(t *testing.T) {
+	report := strings.Join(CompareSchemas(goldenSchemaFixture(), worktreeSchemaFixture()).Lines(), "\n")
+
+	assert.Contains(t, report, "  + public.posts (3 columns)")
+	assert.Contains(t, report, "  - public.legacy")
+	assert.Contains(t, report, "  ~ public.users")
+	assert.Contains(t, report, "      + column nickname text")
+	assert.Contains(t, report, "      - column age integer")
+	assert.Contains(t, report, "      ~ column email: type character varying(100) → text, default ''::text")
+	assert.Contains(t, report, "Foreign keys:\n  + public.posts posts_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id)")
+	assert.Contains(t, report, "Constraints:\n  + public.posts posts_pkey PRIMARY KEY (id)")
+}
+
+func TestSchemaComparisonSQLPatch(t *testing.T) {
+	patch := CompareSchemas(goldenSchemaFixture(), worktreeSchemaFixture()).SQLPatch()
+
+	expected := []string{
+		"BEGIN;",
+		`DROP INDEX "public"."users_email_idx";`,
+		`DROP TABLE "public"."legacy";`,
+		"CREATE TABLE \"public\".\"posts\" (\n" +
+			"    \"id\" bigint GENERATED BY DEFAULT AS IDENTITY NOT NULL,\n" +
+			"    \"user_id\" integer NOT NULL,\n" +
+			"    \"title\" text\n" +
+			");",
+		`ALTER TABLE "public"."users" DROP COLUMN "age";`,
+		`ALTER TABLE "public"."users" ADD COLUMN "nickname" text;`,
+		`ALTER TABLE "public"."users" ALTER COLUMN "email" TYPE text USING "email"::text;`,
+		`ALTER TABLE "public"."users" ALTER COLUMN "email" SET DEFAULT ''::text;`,
+		"CREATE INDEX posts_user_id_idx ON public.posts USING btree (user_id);",
+		"CREATE UNIQUE INDEX users_email_idx ON public.users USING btree (lower(email));",
+		`ALTER TABLE "public"."posts" ADD CONSTRAINT "posts_pkey" PRIMARY KEY (id);`,
+		`ALTER TABLE "public"."posts" ADD CONSTRAINT "posts_user_id_fkey" FOREIGN KEY (user_id) REFERENCES users(id);`,
+		"COMMIT;",
+	}
+
+	// Statements appear in dependency order
+	last := -1
+	for _, stmt := range expected {
+		idx := strings.Index(patch, stmt)
+		require.GreaterOrEqual(t, idx, 0, "missing statement: %s\n%s", stmt, patch)
+		assert.Greater(t, idx, last, "statement out of order: %s", stmt)
+		last = idx
+	}
+
+	// Objects of dropped tables go away with the table
+	assert.NotContains(t, patch, "legacy_id_idx")
+}
+
+func TestSchemaComparisonSQLPatchSerialAndConstraints(t *testing.T) {
+	from := &SchemaDefinition{
+		Tables: map[string]*TableSchema{
+			"public.orders": {Schema: "public", Name: "orders", Columns: []ColumnSchema{
+				{Name: "id", DataType: "integer"},
+			}},
+		},
+		Constraints: map[string]ConstraintDefinition{
+			"public.orders.orders_pkey": {Schema: "public", Table: "orders", Name: "orders_pkey",
+				Type: ConstraintPrimaryKey, Definition: "PRIMARY KEY (id)"},
+			"public.orders.orders_customer_fkey": {Schema: "public", Table: "orders", Name: "orders_customer_fkey",
+				Type: ConstraintForeignKey, Definition: "FOREIGN KEY (id) REFERENCES customers(id)"},
+		},
+	}
+	to := &SchemaDefinition{
+		Tables: map[string]*TableSchema{
+			"public.orders": {Schema: "public", Name: "orders", Columns: []ColumnSchema{
+				{Name: "id", DataType: "integer"},
+				{Name: "number", DataType: "integer", DefaultValue: "nextval('orders_number_seq'::regclass)"},
+			}},
+		},
+		Constraints: map[string]ConstraintDefinition{
+			"public.orders.orders_pkey": {Schema: "public", Table: "orders", Name: "orders_pkey",
+				Type: ConstraintPrimaryKey, Definition: "PRIMARY KEY (id, number)"},
+		},
+	}
+
+	patch := CompareSchemas(from, to).SQLPatch()
+
+	// The foreign key is dropped before the key it may depend on
+	fk := strings.Index(patch, `DROP CONSTRAINT "orders_customer_fkey"`)
+	pk := strings.Index(patch, `DROP CONSTRAINT "orders_pkey"`)
+	require.GreaterOrEqual(t, fk, 0)
+	assert.Greater(t, pk, fk)
+
+	seq := strings.Index(patch, "CREATE SEQUENCE IF NOT EXISTS orders_number_seq;")
+	col := strings.Index(patch, `ADD COLUMN "number" integer DEFAULT nextval('orders_number_seq'::regclass) NOT NULL;`)
+	require.GreaterOrEqual(t, seq, 0, patch)
+	assert.Greater(t, col, seq)
+	assert.Contains(t, patch, `ADD CONSTRAINT "orders_pkey" PRIMARY KEY (id, number);`)
+
+	assert.Equal(t, "-- No schema differences\n", CompareSchemas(to, to).SQLPatch())
+}
@@ -0,0 +1,280 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hammashamzah/conductor/internal/config"
+)
+
+// maxDBNameLength is PostgreSQL's identifier limit (NAMEDATALEN - 1)
+const maxDBNameLength = 63
+
+// snapshotNamePattern limits snapshot names to characters that are safe in
+// database names and file names
+var snapshotNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// ValidateSnapshotName checks that a snapshot name is usable
+func ValidateSnapshotName(name string) error {
+	if !snapshotNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q: use up to 32 lowercase letters, digits, '-' or '_'", name)
+	}
+	return nil
+}
+
+// SnapshotDBName returns the database a template snapshot is kept in, or ""
+// if the name would exceed PostgreSQL's identifier limit
+func SnapshotDBName(worktreeDBName, snapshotName string) string {
+	// sanitizeDBName truncates silently, so check the length first
+	name := "snap_" + worktreeDBName + "_" + snapshotName
+	if len(name) > maxDBNameLength {
+		return ""
+	}
+	return sanitizeDBName(name)
+}
+
+// SnapshotDumpPath returns where a pg_dump snapshot of a worktree database is stored
+func SnapshotDumpPath(projectName, worktreeName, snapshotName string) (string, error) {
+	dir, err := config.ConductorDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "snapshots", projectName, worktreeName, snapshotName+".sql"), nil
+}
+
+// TempSnapshotName returns a throwaway snapshot name for building a
+// replacement of snapshotName. It is never shorter than snapshotName, so a
+// template copy made under it can always be renamed to snapshotName.
+func TempSnapshotName(snapshotName string) string {
+	name := strconv.FormatInt(time.Now().UnixNano(), 36)
+	if len(name) < len(snapshotName) {
+		name += strings.Repeat("0", len(snapshotName)-len(name))
+	}
+	return name
+}
+
+// CreateSnapshot copies a worktree database into a named snapshot. The copy
+// is made server-side with CREATE DATABASE ... TEMPLATE when nobody else is
+// connected to the worktree database, and falls back to a pg_dump file
+// otherwise. Connections to the worktree database are never terminated.
+func CreateSnapshot(ctx context.Context, localURL, projectName, worktreeName, worktreeDBName, snapshotName string, progress ProgressFunc) (*config.DatabaseSnapshot, error) {
+	if err := ValidateSnapshotName(snapshotName); err != nil {
+		return nil, err
+	}
+	exists, err := DatabaseExists(localURL, worktreeDBName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("database %s does not exist", worktreeDBName)
+	}
+
+	snap := &config.DatabaseSnapshot{
+		Name:      snapshotName,
+		CreatedAt: time.Now(),
+	}
+
+	if snapDB := SnapshotDBName(worktreeDBName, snapshotName); snapDB != "" {
+		if progress != nil {
+			progress("Copying database via template...")
+		}
+		templateErr := CreateDatabaseFromTemplate(ctx, localURL, snapDB, worktreeDBName)
+		if templateErr == nil {
+			// Keep the copy pristine: nothing may connect to it, but it can
+			// still be used as a template and dropped
+			_ = setAllowConnections(localURL, snapDB, false)
+			snap.Method = string(CloneMethodTemplate)
+			snap.DatabaseName = snapDB
+			snap.SizeBytes, _ = databaseSize(localURL, snapDB)
+			return snap, nil
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("snapshot cancelled: %w", ctx.Err())
+		}
+		if progress != nil {
+			progress(fmt.Sprintf("Template copy unavailable, falling back to pg_dump: %v", templateErr))
+		}
+	}
+
+	dumpPath, err := SnapshotDumpPath(projectName, worktreeName, snapshotName)
+	if err != nil {
+		return nil, err
+	}
+	if progress != nil {
+		progress("Dumping database...")
+	}
+	if err := DumpDatabase(ctx, BuildWorktreeURL(localURL, worktreeDBName), dumpPath); err != nil {
+		return nil, err
+	}
+	snap.Method = string(CloneMethodDump)
+	snap.DumpPath = dumpPath
+	if info, err := os.Stat(dumpPath); err == nil {
+		snap.SizeBytes = info.Size()
+	}
+	return snap, nil
+}
+
+// RestoreSnapshot replaces a worktree database with a snapshot. The snapshot
+// is first restored into a scratch database, so the worktree database is only
+// dropped once the copy succeeded. Connections to it are terminated.
+func RestoreSnapshot(ctx context.Context, localURL, worktreeDBName string, snap *config.DatabaseSnapshot, progress ProgressFunc) error {
+	const scratchSuffix = "_restore"
+	scratch := sanitizeDBName(worktreeDBName)
+	if len(scratch) > maxDBNameLength-len(scratchSuffix) {
+		scratch = scratch[:maxDBNameLength-len(scratchSuffix)]
+	}
+	scratch += scratchSuffix
+	_ = DropDatabase(localURL, scratch) // leftover from an interrupted restore
+
+	if progress != nil {
+		progress(fmt.Sprintf("Restoring snapshot %s...", snap.Name))
+	}
+	switch snap.Method {
+	case string(CloneMethodTemplate):
+		if err := CreateDatabaseFromTemplate(ctx, localURL, scratch, snap.DatabaseName); err != nil {
+			return fmt.Errorf("failed to copy snapshot database: %w", err)
+		}
+	case string(CloneMethodDump):
+		if err := RestoreDatabaseFromDump(localURL, scratch, snap.DumpPath); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("snapshot %s has unknown method %q", snap.Name, snap.Method)
+	}
+
+	if progress != nil {
+		progress("Replacing worktree database...")
+	}
+	if err := DropDatabase(localURL, worktreeDBName); err != nil {
+		_ = DropDatabase(localURL, scratch)
+		return err
+	}
+	if err := RenameDatabase(localURL, scratch, worktreeDBName); err != nil {
+		return fmt.Errorf("snapshot restored as %s but could not be renamed: %w", scratch, err)
+	}
+	return nil
+}
+
+// DeleteSnapshot removes a snapshot's database or dump file
+func DeleteSnapshot(localURL string, snap *config.DatabaseSnapshot) error {
+	switch snap.Method {
+	case string(CloneMethodTemplate):
+		return DropDatabase(localURL, snap.DatabaseName)
+	case string(CloneMethodDump):
+		if err := os.Remove(snap.DumpPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove snapshot dump: %w", err)
+		}
+	}
+	return nil
+}
+
+// RenameSnapshot moves a snapshot's database or dump file to the location
+// of newName and updates snap to match
+func RenameSnapshot(localURL, projectName, worktreeName, worktreeDBName string, snap *config.DatabaseSnapshot, newName string) error {
+	if err := ValidateSnapshotName(newName); err != nil {
+		return err
+	}
+	switch snap.Method {
+	case string(CloneMethodTemplate):
+		newDB := SnapshotDBName(worktreeDBName, newName)
+		if newDB == "" {
+			return fmt.Errorf("snapshot name %s is too long for a database copy", newName)
+		}
+		if err := RenameDatabase(localURL, snap.DatabaseName, newDB); err != nil {
+			return err
+		}
+		snap.DatabaseName = newDB
+	case string(CloneMethodDump):
+		newPath, err := SnapshotDumpPath(projectName, worktreeName, newName)
+		if err != nil {
+			return err
+		}
+		if err := os.Rename(snap.DumpPath, newPath); err != nil {
+			return fmt.Errorf("failed to rename snapshot dump: %w", err)
+		}
+		snap.DumpPath = newPath
+	default:
+		return fmt.Errorf("snapshot %s has unknown method %q", snap.Name, snap.Method)
+	}
+	snap.Name = newName
+	return nil
+}
+
+// RenameDatabase renames a database. Nobody may be connected to it.
+func RenameDatabase(localURL, oldName, newName string) error {
+	info, err := ParseConnectionString(localURL)
+	if err != nil {
+		return err
+	}
+	info.Database = "postgres"
+	adminURL := BuildConnectionString(info)
+
+	db, err := sql.Open("postgres", adminURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s RENAME TO %s",
+		quoteIdentifier(sanitizeDBName(oldName)), quoteIdentifier(sanitizeDBName(newName))))
+	if err != nil {
+		return fmt.Errorf("failed to rename database: %w", err)
+	}
+	return nil
+}
+
+// setAllowConnections sets a database's ALLOW_CONNECTIONS flag
+func setAllowConnections(localURL, dbName string, allow bool) error {
+	info, err := ParseConnectionString(localURL)
+	if err != nil {
+		return err
+	}
+	info.Database = "postgres"
+	adminURL := BuildConnectionString(info)
+
+	db, err := sql.Open("postgres", adminURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s ALLOW_CONNECTIONS %t", quoteIdentifier(sanitizeDBName(dbName)), allow))
+	return err
+}
+
+// databaseSize returns the on-disk size of a database
+func databaseSize(localURL, dbName string) (int64, error) {
+	return DriverFor(localURL).DatabaseSize(localURL, dbName)
+}
+
+func (postgresDriver) DatabaseSize(localURL, dbName string) (int64, error) {
+	info, err := ParseConnectionString(localURL)
+	if err != nil {
+		return 0, err
+	}
+	info.Database = "postgres"
+	adminURL := BuildConnectionString(info)
+
+	db, err := sql.Open("postgres", adminURL)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = db.Close() }()
+
+	var size int64
+	err = db.QueryRow(`SELECT pg_database_size($1)`, dbName).Scan(&size)
+	return size, err
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSnapshotName(t *testing.T) {
+	valid := []string{"before-migration", "v2", "tui-20260101-120000", "a_b", strings.Repeat("x", 32)}
+	for _, name := range valid {
+		assert.NoError(t, ValidateSnapshotName(name), name)
+	}
+
+	invalid := []string{"", "-leading", "_leading", "Upper", "has space", "dot.name", "../escape", strings.Repeat("x", 33)}
+	for _, name := range invalid {
+		assert.Error(t, ValidateSnapshotName(name), name)
+	}
+}
+
+func TestSnapshotDBName(t *testing.T) {
+	assert.Equal(t, "snap_app_tokyo_before_migration", SnapshotDBName("app_tokyo", "before-migration"))
+
+	// Names that would be truncated by PostgreSQL are rejected so they
+	// can never collide with each other
+	long := strings.Repeat("d", 40)
+	assert.Equal(t, "", SnapshotDBName(long, strings.Repeat("s", 30)))
+	assert.NotEmpty(t, SnapshotDBName(long, "s"))
+}
+
+func TestTempSnapshotName(t *testing.T) {
+	for _, name := range []string{"a", "before-migration", strings.Repeat("x", 32)} {
+		tmp := TempSnapshotName(name)
+		assert.NoError(t, ValidateSnapshotName(tmp), tmp)
+		assert.GreaterOrEqual(t, len(tmp), len(name), tmp)
+		assert.NotEqual(t, name, tmp)
+	}
+}
+
+func TestRenameSnapshot_MovesDumpFile(t *testing.T) {
+	t.Setenv("CONDUCTOR_CONFIG_DIR", t.TempDir())
+
+	oldPath, err := SnapshotDumpPath("proj", "tokyo", "tmp123")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(oldPath), 0755))
+	require.NoError(t, os.WriteFile(oldPath, []byte("-- dump"), 0644))
+
+	snap := &config.DatabaseSnapshot{Name: "tmp123", Method: string(CloneMethodDump), DumpPath: oldPath}
+	require.NoError(t, RenameSnapshot("", "proj", "tokyo", "app_tokyo", snap, "before-agent"))
+
+	newPath, err := SnapshotDumpPath("proj", "tokyo", "before-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "before-agent", snap.Name)
+	assert.Equal(t, newPath, snap.DumpPath)
+	assert.FileExists(t, newPath)
+	assert.NoFileExists(t, oldPath)
+}
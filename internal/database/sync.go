@@ -12,6 +12,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -79,8 +80,15 @@ func SyncFromSourceWithProgressCtxV1(ctx context.Context, cfg *DatabaseConfig, p
 	return fullSyncFromSourceCtx(ctx, cfg, projectName, dbsyncDir, progress)
 }
 
+// errMaskingUnsupported is returned by file-based syncs that write raw
+// pg_dump output and so cannot apply MaskColumns
+var errMaskingUnsupported = fmt.Errorf("column masking is only supported by golden database sync (conductor database sync)")
+
 // fullSyncFromSourceCtx performs a full pg_dump sync
 func fullSyncFromSourceCtx(ctx context.Context, cfg *DatabaseConfig, projectName string, dbsyncDir string, progress ProgressFunc) (*SyncMetadata, error) {
+	if len(cfg.MaskColumns) > 0 {
+		return nil, errMaskingUnsupported
+	}
 	startTime := time.Now()
 
 	if progress != nil {
@@ -429,9 +437,10 @@ func runPgDumpSchemaOnlyCtx(ctx context.Context, connStr string, outputPath stri
 	return nil
 }
 
-// computeSchemaHash computes a hash of the database schema for change detection
+// computeSchemaHash computes a hash of the database schema for change detection.
+// Conductor's own sync metadata table is left out.
 func computeSchemaHash(connStr string) (string, error) {
-	db, err := sql.Open("postgres", connStr)
+	db, err := openDB(connStr)
 	if err != nil {
 		return "", err
 	}
@@ -441,6 +450,10 @@ func computeSchemaHash(connStr string) (string, error) {
 	defer cancel()
 
 	// Query schema information
+	where := "table_schema NOT IN ('pg_catalog', 'information_schema')"
+	if isMySQLConn(db) {
+		where = "table_schema = DATABASE()"
+	}
 	query := `
 		SELECT
 			table_schema,
@@ -449,7 +462,7 @@ func computeSchemaHash(connStr string) (string, error) {
 			data_type,
 			is_nullable
 		FROM information_schema.columns
-		WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
+		WHERE ` + where + ` AND table_name <> '` + ConductorSyncTable + `'
 		ORDER BY table_schema, table_name, ordinal_position
 	`
 
@@ -568,6 +581,11 @@ func SyncIncrementalFromSourceCtx(ctx context.Context, cfg *DatabaseConfig, proj
 	startTime := time.Now()
 	projectDir := filepath.Join(dbsyncDir, projectName)
 
+	maskedTables, err := ParseMaskColumns(cfg.MaskColumns)
+	if err != nil {
+		return nil, err
+	}
+
 	if progress != nil {
 		progress("Loading previous sync state...")
 	}
@@ -673,14 +691,14 @@ func SyncIncrementalFromSourceCtx(ctx context.Context, cfg *DatabaseConfig, proj
 			if prevState != nil && prevState.MaxTimestamp != nil {
 				sinceTs = prevState.MaxTimestamp
 			}
-			rowsDumped, newMaxTs, err = dumpTableIncrementalByTimestamp(ctx, cfg.Source, info, sinceTs, f)
+			rowsDumped, newMaxTs, err = dumpTableIncrementalByTimestamp(ctx, cfg.Source, info, maskedTables[tableName], sinceTs, f)
 		} else if info.PrimaryKey != "" && isIntegerType(info.PrimaryKeyType) {
 			// Use PK-based incremental for tables without timestamps
 			var sincePK *int64
 			if prevState != nil && prevState.MaxPrimaryKey != nil {
 				sincePK = prevState.MaxPrimaryKey
 			}
-			rowsDumped, newMaxPK, err = dumpTableIncrementalByPK(ctx, cfg.Source, info, sincePK, f)
+			rowsDumped, newMaxPK, err = dumpTableIncrementalByPK(ctx, cfg.Source, info, maskedTables[tableName], sincePK, f)
 		} else {
 			// No incremental possible - skip (will be caught in next full sync)
 			if progress != nil {
@@ -744,8 +762,9 @@ func SyncIncrementalFromSourceCtx(ctx context.Context, cfg *DatabaseConfig, proj
 	return metadata, nil
 }
 
-// dumpTableIncrementalByTimestamp dumps rows newer than the given timestamp
-func dumpTableIncrementalByTimestamp(ctx context.Context, connStr string, info *TableIncrementalInfo, since *time.Time, w *os.File) (int64, *time.Time, error) {
+// dumpTableIncrementalByTimestamp dumps rows newer than the given timestamp,
+// applying masks to the selected columns
+func dumpTableIncrementalByTimestamp(ctx context.Context, connStr string, info *TableIncrementalInfo, masks TableMasks, since *time.Time, w *os.File) (int64, *time.Time, error) {
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
 		return 0, nil, err
@@ -755,14 +774,19 @@ func dumpTableIncrementalByTimestamp(ctx context.Context, connStr string, info *
 	fullTable := fmt.Sprintf("%s.%s", quoteIdentifier(info.Schema), quoteIdentifier(info.Name))
 	tsCol := quoteIdentifier(info.TimestampColumn)
 
+	selectList, err := incrementalSelectList(ctx, db, info, masks)
+	if err != nil {
+		return 0, nil, err
+	}
+
 	// Build query
 	var query string
 	var args []interface{}
 	if since != nil {
-		query = fmt.Sprintf("SELECT * FROM %s WHERE %s > $1 ORDER BY %s", fullTable, tsCol, tsCol)
+		query = fmt.Sprintf("SELECT %s FROM %s WHERE %s > $1 ORDER BY %s", selectList, fullTable, tsCol, tsCol)
 		args = []interface{}{*since}
 	} else {
-		query = fmt.Sprintf("SELECT * FROM %s ORDER BY %s", fullTable, tsCol)
+		query = fmt.Sprintf("SELECT %s FROM %s ORDER BY %s", selectList, fullTable, tsCol)
 	}
 
 	rows, err := db.QueryContext(ctx, query, args...)
@@ -817,8 +841,9 @@ func dumpTableIncrementalByTimestamp(ctx context.Context, connStr string, info *
 	return rowCount, maxTs, rows.Err()
 }
 
-// dumpTableIncrementalByPK dumps rows with PK greater than the given value
-func dumpTableIncrementalByPK(ctx context.Context, connStr string, info *TableIncrementalInfo, sincePK *int64, w *os.File) (int64, *int64, error) {
+// dumpTableIncrementalByPK dumps rows with PK greater than the given value,
+// applying masks to the selected columns
+func dumpTableIncrementalByPK(ctx context.Context, connStr string, info *TableIncrementalInfo, masks TableMasks, sincePK *int64, w *os.File) (int64, *int64, error) {
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
 		return 0, nil, err
@@ -828,14 +853,19 @@ func dumpTableIncrementalByPK(ctx context.Context, connStr string, info *TableIn
 	fullTable := fmt.Sprintf("%s.%s", quoteIdentifier(info.Schema), quoteIdentifier(info.Name))
 	pkCol := quoteIdentifier(info.PrimaryKey)
 
+	selectList, err := incrementalSelectList(ctx, db, info, masks)
+	if err != nil {
+		return 0, nil, err
+	}
+
 	// Build query
 	var query string
 	var args []interface{}
 	if sincePK != nil {
-		query = fmt.Sprintf("SELECT * FROM %s WHERE %s > $1 ORDER BY %s", fullTable, pkCol, pkCol)
+		query = fmt.Sprintf("SELECT %s FROM %s WHERE %s > $1 ORDER BY %s", selectList, fullTable, pkCol, pkCol)
 		args = []interface{}{*sincePK}
 	} else {
-		query = fmt.Sprintf("SELECT * FROM %s ORDER BY %s", fullTable, pkCol)
+		query = fmt.Sprintf("SELECT %s FROM %s ORDER BY %s", selectList, fullTable, pkCol)
 	}
 
 	rows, err := db.QueryContext(ctx, query, args...)
@@ -890,6 +920,22 @@ func dumpTableIncrementalByPK(ctx context.Context, connStr string, info *TableIn
 	return rowCount, maxPK, rows.Err()
 }
 
+// incrementalSelectList returns "*", or the masked select list for tables with
+// masked columns. Masked columns keep their names, so the generated INSERTs
+// target the same columns.
+func incrementalSelectList(ctx context.Context, db *sql.DB, info *TableIncrementalInfo, masks TableMasks) (string, error) {
+	if len(masks) == 0 {
+		return "*", nil
+	}
+	table := info.Schema + "." + info.Name
+	columns, err := getMaskColumnInfo(ctx, db, table)
+	if err != nil {
+		return "", err
+	}
+	selectList, _, err := buildMaskedSelect(table, columns, masks)
+	return selectList, err
+}
+
 // generateInsertStatement generates an INSERT statement for a row
 func generateInsertStatement(schema, table string, columns []string, values []interface{}) string {
 	var colNames []string
@@ -968,6 +1014,9 @@ const SyncVersionSeparated = 2
 // SyncV2FromSourceCtx performs a v2 sync with separated schema and per-table data files
 // This allows incremental sync even after schema changes (migrations)
 func SyncV2FromSourceCtx(ctx context.Context, cfg *DatabaseConfig, projectName string, dbsyncDir string, progress ProgressFunc) (*SyncMetadata, error) {
+	if len(cfg.MaskColumns) > 0 {
+		return nil, errMaskingUnsupported
+	}
 	startTime := time.Now()
 	projectDir := filepath.Join(dbsyncDir, projectName)
 	dataDir := filepath.Join(projectDir, "data")
@@ -1062,16 +1111,16 @@ func SyncV2FromSourceCtx(ctx context.Context, cfg *DatabaseConfig, projectName s
 		progress(fmt.Sprintf("Excluding %d tables from data sync (schema only)", len(excludedTables)))
 	}
 
+	// Tables needing a full data dump are collected and dumped concurrently
+	// after the incremental pass
+	var fullSyncTables []string
+
 	// Sync each table that needs it
 	for i, tableName := range filteredTables {
 		if ctx.Err() != nil {
 			return nil, fmt.Errorf("sync cancelled: %w", ctx.Err())
 		}
 
-		if progress != nil {
-			progress(fmt.Sprintf("Syncing %s (%d/%d)...", tableName, i+1, len(filteredTables)))
-		}
-
 		// Check if this is incremental or full table sync
 		prevState := tableSyncState[tableName]
 		info := tableInfo[tableName]
@@ -1082,35 +1131,12 @@ func SyncV2FromSourceCtx(ctx context.Context, cfg *DatabaseConfig, projectName s
 			prevState == nil
 
 		if needsFullTableSync {
-			// Full table data dump
-			dataPath := filepath.Join(dataDir, sanitizeFileName(tableName)+".sql")
-			if err := dumpTableData(ctx, cfg.Source, tableName, dataPath); err != nil {
-				return nil, fmt.Errorf("failed to dump table %s: %w", tableName, err)
+			fullSyncTables = append(fullSyncTables, tableName)
+		} else if prevState != nil && rowCounts[tableName] > prevState.RowCount {
+			if progress != nil {
+				progress(fmt.Sprintf("Syncing %s incrementally (%d/%d)...", tableName, i+1, len(filteredTables)))
 			}
-			tableDataFiles[tableName] = "data/" + sanitizeFileName(tableName) + ".sql"
 
-			// Update sync state
-			state := &TableSyncState{
-				LastSyncedAt: time.Now(),
-				RowCount:     rowCounts[tableName],
-			}
-			if info != nil {
-				state.TimestampColumn = info.TimestampColumn
-				state.PrimaryKeyColumn = info.PrimaryKey
-				// Get max values
-				parts := strings.SplitN(tableName, ".", 2)
-				if len(parts) == 2 {
-					if info.TimestampColumn != "" {
-						maxTs, _ := GetMaxTimestamp(cfg.Source, parts[0], parts[1], info.TimestampColumn)
-						state.MaxTimestamp = maxTs
-					} else if info.PrimaryKey != "" && isIntegerType(info.PrimaryKeyType) {
-						maxPK, _ := GetMaxPrimaryKey(cfg.Source, parts[0], parts[1], info.PrimaryKey)
-						state.MaxPrimaryKey = maxPK
-					}
-				}
-			}
-			tableSyncState[tableName] = state
-		} else if prevState != nil && rowCounts[tableName] > prevState.RowCount {
 			// Incremental sync - only new rows
 			incFileName := fmt.Sprintf("incremental-%s-%s.sql", sanitizeFileName(tableName), time.Now().Format("20060102-150405"))
 			incPath := filepath.Join(projectDir, incFileName)
@@ -1125,9 +1151,9 @@ func SyncV2FromSourceCtx(ctx context.Context, cfg *DatabaseConfig, projectName s
 			}
 
 			if info != nil && info.TimestampColumn != "" && prevState.MaxTimestamp != nil {
-				rowsDumped, newMaxTs, err = dumpTableIncrementalByTimestamp(ctx, cfg.Source, info, prevState.MaxTimestamp, f)
+				rowsDumped, newMaxTs, err = dumpTableIncrementalByTimestamp(ctx, cfg.Source, info, nil, prevState.MaxTimestamp, f)
 			} else if info != nil && info.PrimaryKey != "" && prevState.MaxPrimaryKey != nil {
-				rowsDumped, newMaxPK, err = dumpTableIncrementalByPK(ctx, cfg.Source, info, prevState.MaxPrimaryKey, f)
+				rowsDumped, newMaxPK, err = dumpTableIncrementalByPK(ctx, cfg.Source, info, nil, prevState.MaxPrimaryKey, f)
 			}
 			f.Close()
 
@@ -1155,6 +1181,69 @@ func SyncV2FromSourceCtx(ctx context.Context, cfg *DatabaseConfig, projectName s
 		}
 	}
 
+	// Full table data dumps, in FK order and in parallel. Each table is its
+	// own file, so no table has to wait for another.
+	if len(fullSyncTables) > 0 {
+		fks, _ := GetForeignKeys(cfg.Source, fullSyncTables)
+		sortedTables := sortTablesByFKDependency(fullSyncTables, fks)
+		workers := SyncWorkers(cfg)
+		dumpProgress := serializedProgress(progress)
+		if dumpProgress != nil {
+			dumpProgress(fmt.Sprintf("Dumping %d tables with %d workers...", len(sortedTables), workers))
+		}
+
+		var mu sync.Mutex // guards done
+		done := 0
+		err := copyTablesConcurrently(ctx, sortedTables, nil, workers, func(ctx context.Context, tableName string) error {
+			tableStart := time.Now()
+			dataPath := filepath.Join(dataDir, sanitizeFileName(tableName)+".sql")
+			if err := dumpTableData(ctx, cfg.Source, tableName, dataPath); err != nil {
+				if ctx.Err() != nil {
+					return fmt.Errorf("sync cancelled: %w", ctx.Err())
+				}
+				return fmt.Errorf("failed to dump table %s: %w", tableName, err)
+			}
+			mu.Lock()
+			done++
+			n := done
+			mu.Unlock()
+			if dumpProgress != nil {
+				dumpProgress(fmt.Sprintf("Dumped %s [%d/%d] (%s)", tableName, n, len(sortedTables), formatMs(time.Since(tableStart).Milliseconds())))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tableName := range sortedTables {
+			tableDataFiles[tableName] = "data/" + sanitizeFileName(tableName) + ".sql"
+
+			// Update sync state
+			info := tableInfo[tableName]
+			state := &TableSyncState{
+				LastSyncedAt: time.Now(),
+				RowCount:     rowCounts[tableName],
+			}
+			if info != nil {
+				state.TimestampColumn = info.TimestampColumn
+				state.PrimaryKeyColumn = info.PrimaryKey
+				// Get max values
+				parts := strings.SplitN(tableName, ".", 2)
+				if len(parts) == 2 {
+					if info.TimestampColumn != "" {
+						maxTs, _ := GetMaxTimestamp(cfg.Source, parts[0], parts[1], info.TimestampColumn)
+						state.MaxTimestamp = maxTs
+					} else if info.PrimaryKey != "" && isIntegerType(info.PrimaryKeyType) {
+						maxPK, _ := GetMaxPrimaryKey(cfg.Source, parts[0], parts[1], info.PrimaryKey)
+						state.MaxPrimaryKey = maxPK
+					}
+				}
+			}
+			tableSyncState[tableName] = state
+		}
+	}
+
 	// Remove data files for deleted tables
 	if schemaDiff != nil {
 		for _, tableName := range schemaDiff.RemovedTables {
@@ -41,7 +41,7 @@ type SyncMetadata struct {
 	// Error contains the last error message if sync failed
 	Error string `json:"error,omitempty"`
 
-	// MigrationBaseline tracks Prisma migration state for the golden copy
+	// MigrationBaseline tracks the migration state of the golden copy
 	MigrationBaseline *MigrationBaseline `json:"migrationBaseline,omitempty"`
 
 	// TableSyncState tracks per-table sync state for incremental sync
@@ -98,6 +98,9 @@ type MigrationBaseline struct {
 	// MigrationNames is the ordered list of all migration names
 	MigrationNames []string `json:"migrationNames"`
 
+	// Tool is the migration tool the baseline was read from (e.g. "prisma")
+	Tool string `json:"tool,omitempty"`
+
 	// CapturedAt is when this baseline was recorded
 	CapturedAt time.Time `json:"capturedAt"`
 }
@@ -149,6 +152,8 @@ type ColumnSchema struct {
 	DataType     string `json:"dataType"`
 	IsNullable   bool   `json:"isNullable"`
 	DefaultValue string `json:"defaultValue,omitempty"`
+	Identity     string `json:"identity,omitempty"`  // "a" (always) or "d" (by default) for identity columns
+	Generated    bool   `json:"generated,omitempty"` // DefaultValue is a stored generation expression
 }
 
 // SchemaChange represents a detected change in the source schema
@@ -161,6 +166,7 @@ type SchemaChange struct {
 
 // ConnectionInfo holds parsed connection string components
 type ConnectionInfo struct {
+	Driver   string // "postgres" (default) or "mysql"
 	Host     string
 	Port     string
 	User     string
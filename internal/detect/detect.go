@@ -16,10 +16,31 @@ type ProjectInfo struct {
 	TestFramework  string `json:"testFramework,omitempty"`
 	WebEligible    bool   `json:"webEligible"`
 	UIType         string `json:"uiType"` // "browser", "mobile", "cli", "library", "none"
+	MigrationTool  string `json:"migrationTool,omitempty"`
 }
 
+// Database migration tools reported in ProjectInfo.MigrationTool
+const (
+	MigrationToolPrisma        = "prisma"
+	MigrationToolDrizzle       = "drizzle"
+	MigrationToolRails         = "rails"
+	MigrationToolDjango        = "django"
+	MigrationToolAlembic       = "alembic"
+	MigrationToolGoose         = "goose"
+	MigrationToolGolangMigrate = "golang-migrate"
+)
+
 // DetectProject analyzes a project directory and returns type information
 func DetectProject(projectPath string) (*ProjectInfo, error) {
+	info, err := detectProjectType(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	info.MigrationTool = DetectMigrationTool(projectPath)
+	return info, nil
+}
+
+func detectProjectType(projectPath string) (*ProjectInfo, error) {
 	info := &ProjectInfo{
 		Framework: "unknown",
 		Language:  "unknown",
@@ -95,6 +116,12 @@ func DetectProject(projectPath string) (*ProjectInfo, error) {
 		return info, nil
 	}
 
+	// Check for Ruby project
+	if fileExists(projectPath, "Gemfile") {
+		detectRubyProject(info, projectPath)
+		return info, nil
+	}
+
 	// Check for Python project
 	if fileExists(projectPath, "requirements.txt") || fileExists(projectPath, "pyproject.toml") || fileExists(projectPath, "setup.py") {
 		detectPythonProject(info, projectPath)
@@ -409,6 +436,47 @@ func detectPythonProject(info *ProjectInfo, projectPath string) {
 	}
 }
 
+func detectRubyProject(info *ProjectInfo, projectPath string) {
+	info.Language = "ruby"
+	info.PackageManager = "bundler"
+	info.Framework = "ruby"
+	info.UIType = "none"
+	info.WebEligible = false
+
+	if fileExists(projectPath, "bin/rails") || fileContains(projectPath, "Gemfile", `"rails"`, `'rails'`) {
+		info.Framework = "rails"
+		info.UIType = "browser"
+		info.WebEligible = true
+	}
+	if fileExists(projectPath, ".rspec") || fileExists(projectPath, "spec") {
+		info.TestFramework = "rspec"
+	} else if fileExists(projectPath, "test") {
+		info.TestFramework = "minitest"
+	}
+}
+
+// DetectMigrationTool returns the database migration tool a project uses
+// (one of the MigrationTool constants), or "" if none is recognized
+func DetectMigrationTool(projectPath string) string {
+	switch {
+	case fileExists(projectPath, "prisma/schema.prisma"):
+		return MigrationToolPrisma
+	case hasGlob(projectPath, "drizzle.config.*"):
+		return MigrationToolDrizzle
+	case fileExists(projectPath, "db/migrate") && (fileExists(projectPath, "bin/rails") || fileContains(projectPath, "Gemfile", "rails")):
+		return MigrationToolRails
+	case fileExists(projectPath, "manage.py"):
+		return MigrationToolDjango
+	case fileExists(projectPath, "alembic.ini"):
+		return MigrationToolAlembic
+	case fileContains(projectPath, "go.mod", "github.com/pressly/goose"):
+		return MigrationToolGoose
+	case fileContains(projectPath, "go.mod", "github.com/golang-migrate/migrate"):
+		return MigrationToolGolangMigrate
+	}
+	return ""
+}
+
 // AuthInfo contains detected authentication information
 type AuthInfo struct {
 	Type        string // "none", "dev-bypass", "email-password", "oauth"
@@ -671,6 +739,20 @@ func fileExists(dir, name string) bool {
 	return err == nil
 }
 
+// fileContains reports whether dir/name contains any of the substrings
+func fileContains(dir, name string, substrs ...string) bool {
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return false
+	}
+	for _, s := range substrs {
+		if strings.Contains(string(content), s) {
+			return true
+		}
+	}
+	return false
+}
+
 func hasGlob(dir, pattern string) bool {
 	matches, err := filepath.Glob(filepath.Join(dir, pattern))
 	return err == nil && len(matches) > 0
@@ -263,6 +263,50 @@ func TestDetectPythonBackend(t *testing.T) {
 	assert.False(t, info.WebEligible)
 }
 
+func TestDetectRails(t *testing.T) {
+	dir := setupProject(t, map[string]string{
+		"Gemfile":                           "source 'https://rubygems.org'\ngem 'rails', '~> 7.1'",
+		"db/migrate/20240101000000_init.rb": "",
+		".rspec":                            "",
+	})
+
+	info, err := DetectProject(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "rails", info.Framework)
+	assert.Equal(t, "ruby", info.Language)
+	assert.Equal(t, "bundler", info.PackageManager)
+	assert.Equal(t, "rspec", info.TestFramework)
+	assert.True(t, info.WebEligible)
+	assert.Equal(t, MigrationToolRails, info.MigrationTool)
+}
+
+func TestDetectMigrationTool(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected string
+	}{
+		{"prisma", map[string]string{"package.json": `{}`, "prisma/schema.prisma": ""}, MigrationToolPrisma},
+		{"drizzle", map[string]string{"package.json": `{}`, "drizzle.config.ts": ""}, MigrationToolDrizzle},
+		{"django", map[string]string{"requirements.txt": "django", "manage.py": ""}, MigrationToolDjango},
+		{"alembic", map[string]string{"pyproject.toml": "", "alembic.ini": ""}, MigrationToolAlembic},
+		{"goose", map[string]string{"go.mod": "module x\nrequire github.com/pressly/goose/v3 v3.20.0"}, MigrationToolGoose},
+		{"golang-migrate", map[string]string{"go.mod": "module x\nrequire github.com/golang-migrate/migrate/v4 v4.17.0"}, MigrationToolGolangMigrate},
+		{"none", map[string]string{"go.mod": "module x"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setupProject(t, tt.files)
+			assert.Equal(t, tt.expected, DetectMigrationTool(dir))
+
+			info, err := DetectProject(dir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, info.MigrationTool)
+		})
+	}
+}
+
 func TestDetectPlainHTML(t *testing.T) {
 	dir := setupProject(t, map[string]string{
 		"index.html": "<html><body>Hello</body></html>",
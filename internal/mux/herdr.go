@@ -107,26 +107,27 @@ func (h herdrMux) ListWindowNames() []string {
 }
 
 func (h herdrMux) CreateCodingWindow(project, branch, worktreePath string, agent codingagent.Agent) error {
-	return h.createWindow(project, branch, worktreePath, agent, agent.InteractiveArgs(herdrAgentPrompt()), "")
+	_, err := h.createWindow(project, branch, worktreePath, agent, agent.InteractiveArgs(herdrAgentPrompt()), "")
+	return err
 }
 
-func (h herdrMux) CreateCodingWindowWithTask(project, branch, worktreePath, taskPrompt string, agent codingagent.Agent) error {
+func (h herdrMux) CreateCodingWindowWithTask(project, branch, worktreePath, taskPrompt string, agent codingagent.Agent) (string, error) {
 	return h.createWindow(project, branch, worktreePath, agent,
 		agent.TaskArgs(herdrAgentPrompt(), taskPrompt), " (agent)")
 }
 
 // createWindow builds the worktree workspace: agent pane on the left, dev
-// server pane on the right.
-func (h herdrMux) createWindow(project, branch, worktreePath string, agent codingagent.Agent, agentArgs []string, labelSuffix string) error {
+// server pane on the right. It returns the agent pane's ID.
+func (h herdrMux) createWindow(project, branch, worktreePath string, agent codingagent.Agent, agentArgs []string, labelSuffix string) (string, error) {
 	label := h.WindowName(project, branch)
 	if _, exists := h.workspaceID(label); exists {
-		return fmt.Errorf("workspace %q already exists", label)
+		return "", fmt.Errorf("workspace %q already exists", label)
 	}
 
 	systemPrompt := herdrAgentPrompt()
 	if agent.UsesContextFile() {
 		if err := codingagent.WriteContextFile(worktreePath, systemPrompt); err != nil {
-			return fmt.Errorf("failed to write agent context file: %w", err)
+			return "", fmt.Errorf("failed to write agent context file: %w", err)
 		}
 	}
 
@@ -139,11 +140,11 @@ func (h herdrMux) createWindow(project, branch, worktreePath string, agent codin
 	}
 	if err := h.runJSON(&created, "workspace", "create",
 		"--cwd", worktreePath, "--label", label, "--no-focus"); err != nil {
-		return fmt.Errorf("failed to create herdr workspace: %w", err)
+		return "", fmt.Errorf("failed to create herdr workspace: %w", err)
 	}
 	agentPane := created.Result.RootPane.PaneID
 	if agentPane == "" {
-		return fmt.Errorf("herdr did not return a root pane for workspace %q", label)
+		return "", fmt.Errorf("herdr did not return a root pane for workspace %q", label)
 	}
 
 	// Dev server pane to the right of the agent pane.
@@ -156,7 +157,7 @@ func (h herdrMux) createWindow(project, branch, worktreePath string, agent codin
 	}
 	if err := h.runJSON(&split, "pane", "split", agentPane,
 		"--direction", "right", "--cwd", worktreePath, "--no-focus"); err != nil {
-		return fmt.Errorf("failed to split herdr pane: %w", err)
+		return "", fmt.Errorf("failed to split herdr pane: %w", err)
 	}
 	devPane := split.Result.Pane.PaneID
 
@@ -168,9 +169,9 @@ func (h herdrMux) createWindow(project, branch, worktreePath string, agent codin
 
 	// Start the agent last so it is the pane the user lands on.
 	if err := h.run("pane", "run", agentPane, shellJoin(agentArgs)); err != nil {
-		return fmt.Errorf("failed to start agent in herdr pane: %w", err)
+		return "", fmt.Errorf("failed to start agent in herdr pane: %w", err)
 	}
-	return nil
+	return agentPane, nil
 }
 
 func (h herdrMux) KillWindow(project, branch string) error {
@@ -61,8 +61,8 @@ type Multiplexer interface {
 	// server pane.
 	CreateCodingWindow(project, branch, worktreePath string, agent codingagent.Agent) error
 	// CreateCodingWindowWithTask is CreateCodingWindow with the agent pre-loaded
-	// with a task prompt.
-	CreateCodingWindowWithTask(project, branch, worktreePath, taskPrompt string, agent codingagent.Agent) error
+	// with a task prompt. It returns the ID of the agent's pane.
+	CreateCodingWindowWithTask(project, branch, worktreePath, taskPrompt string, agent codingagent.Agent) (string, error)
 	// KillWindow closes a worktree's window.
 	KillWindow(project, branch string) error
 	// FocusWindow brings a worktree's window to the foreground.
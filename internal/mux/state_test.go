@@ -38,8 +38,8 @@ func (f *fakeMux) UpdateTabTitles([]*session.Session) {}
 func (f *fakeMux) TracksAgentStatus() bool            { return false }
 
 func (f *fakeMux) CreateCodingWindow(p, b, w string, a codingagent.Agent) error { return nil }
-func (f *fakeMux) CreateCodingWindowWithTask(p, b, w, t string, a codingagent.Agent) error {
-	return nil
+func (f *fakeMux) CreateCodingWindowWithTask(p, b, w, t string, a codingagent.Agent) (string, error) {
+	return "", nil
 }
 func (f *fakeMux) StartAgentPane(w, d string, argv []string, title string) (string, error) {
 	return "", nil
@@ -37,7 +37,7 @@ func (tmuxMux) CreateCodingWindow(project, branch, worktreePath string, agent co
 	return tmux.CreateCodingWindow(project, branch, worktreePath, agent)
 }
 
-func (tmuxMux) CreateCodingWindowWithTask(project, branch, worktreePath, taskPrompt string, agent codingagent.Agent) error {
+func (tmuxMux) CreateCodingWindowWithTask(project, branch, worktreePath, taskPrompt string, agent codingagent.Agent) (string, error) {
 	return tmux.CreateCodingWindowWithTask(project, branch, worktreePath, taskPrompt, agent)
 }
 
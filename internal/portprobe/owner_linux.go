@@ -0,0 +1,98 @@
+//go:build linux
+
+package portprobe
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// tcpListenState is the st column value for LISTEN in /proc/net/tcp
+const tcpListenState = "0A"
+
+// findOwner maps the port to a socket inode via /proc/net/tcp{,6} and then
+// scans /proc/<pid>/fd for the process holding that socket
+func findOwner(port int) (*Process, error) {
+	inodes := make(map[string]bool)
+	for _, table := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		if err := listenInodes(table, port, inodes); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if len(inodes) == 0 {
+		return nil, nil
+	}
+
+	procs, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range procs {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join("/proc", entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue // Process exited or belongs to another user
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			if inodes[strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")] {
+				return processInfo(pid), nil
+			}
+		}
+	}
+
+	// Something is listening but we can't see which process (e.g. owned by root)
+	return &Process{Command: "unknown"}, nil
+}
+
+// listenInodes adds the inodes of sockets listening on port in a /proc/net/tcp table
+func listenInodes(table string, port int, inodes map[string]bool) error {
+	f, err := os.Open(table)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // Skip header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != tcpListenState {
+			continue
+		}
+		// local_address is HEXIP:HEXPORT
+		i := strings.LastIndex(fields[1], ":")
+		if i < 0 {
+			continue
+		}
+		p, err := strconv.ParseInt(fields[1][i+1:], 16, 32)
+		if err != nil || int(p) != port {
+			continue
+		}
+		inodes[fields[9]] = true
+	}
+	return scanner.Err()
+}
+
+// processInfo reads the command name and working directory of a process
+func processInfo(pid int) *Process {
+	dir := filepath.Join("/proc", strconv.Itoa(pid))
+	p := &Process{PID: pid}
+	if comm, err := os.ReadFile(filepath.Join(dir, "comm")); err == nil {
+		p.Command = strings.TrimSpace(string(comm))
+	}
+	if cwd, err := os.Readlink(filepath.Join(dir, "cwd")); err == nil {
+		p.Cwd = cwd
+	}
+	return p
+}
@@ -0,0 +1,64 @@
+//go:build !linux && !windows
+
+package portprobe
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// findOwner asks lsof for the process listening on the port
+func findOwner(port int) (*Process, error) {
+	out, err := exec.Command("lsof", "-nP", fmt.Sprintf("-iTCP:%d", port), "-sTCP:LISTEN", "-Fpc").Output()
+	if err != nil {
+		// lsof exits 1 when nothing matches
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lsof failed: %w", err)
+	}
+
+	p := parseLsof(string(out))
+	if p == nil {
+		return nil, nil
+	}
+	if cwdOut, err := exec.Command("lsof", "-a", "-p", strconv.Itoa(p.PID), "-d", "cwd", "-Fn").Output(); err == nil {
+		for _, line := range strings.Split(string(cwdOut), "\n") {
+			if strings.HasPrefix(line, "n") {
+				p.Cwd = line[1:]
+			}
+		}
+	}
+	return p, nil
+}
+
+// parseLsof extracts the first process from lsof -F output (p<pid> / c<command> lines)
+func parseLsof(out string) *Process {
+	var p *Process
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		switch line[0] {
+		case 'p':
+			if p != nil {
+				return p
+			}
+			pid, err := strconv.Atoi(line[1:])
+			if err != nil {
+				continue
+			}
+			p = &Process{PID: pid}
+		case 'c':
+			if p != nil {
+				p.Command = line[1:]
+			}
+		}
+	}
+	return p
+}
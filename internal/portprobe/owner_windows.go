@@ -0,0 +1,8 @@
+//go:build windows
+
+package portprobe
+
+// findOwner is not implemented on Windows; InUse still reports occupied ports
+func findOwner(port int) (*Process, error) {
+	return nil, nil
+}
@@ -0,0 +1,55 @@
+// Package portprobe checks whether TCP ports are actually free on the host and
+// identifies the processes listening on them.
+package portprobe
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+)
+
+// Process describes a process listening on a port
+type Process struct {
+	PID     int
+	Command string
+	// Cwd is the working directory of the process ("" if unknown)
+	Cwd string
+}
+
+// String returns "pid 1234 (command)"
+func (p *Process) String() string {
+	if p == nil {
+		return "-"
+	}
+	return fmt.Sprintf("pid %d (%s)", p.PID, p.Command)
+}
+
+// RunsIn reports whether the process's working directory is dir or inside it
+func (p *Process) RunsIn(dir string) bool {
+	if p == nil || p.Cwd == "" || dir == "" {
+		return false
+	}
+	rel, err := filepath.Rel(dir, p.Cwd)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// InUse reports whether something is already listening on the TCP port.
+// Both the wildcard and loopback addresses are tried because a server bound
+// only to 127.0.0.1 does not always prevent binding the wildcard address.
+func InUse(port int) bool {
+	for _, host := range []string{"", "127.0.0.1"} {
+		ln, err := net.Listen("tcp", net.JoinHostPort(host, fmt.Sprint(port)))
+		if err != nil {
+			return true
+		}
+		_ = ln.Close()
+	}
+	return false
+}
+
+// Owner returns the process listening on the TCP port, or nil if no listener
+// was found. Lookup is best-effort and may fail for processes owned by other users.
+func Owner(port int) (*Process, error) {
+	return findOwner(port)
+}
@@ -0,0 +1,44 @@
+package portprobe
+
+import (
+	"net"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInUse_DetectsListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	assert.True(t, InUse(port))
+
+	require.NoError(t, ln.Close())
+	assert.False(t, InUse(port))
+}
+
+func TestOwner_FindsOwnProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("owner lookup not supported on windows")
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	p, err := Owner(port)
+	if err != nil {
+		t.Skipf("owner lookup unavailable: %v", err)
+	}
+	require.NotNil(t, p)
+	assert.Equal(t, os.Getpid(), p.PID)
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	assert.True(t, p.RunsIn(cwd))
+	assert.False(t, p.RunsIn(t.TempDir()))
+}
@@ -7,10 +7,17 @@ import (
 	"strings"
 
 	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/secrets"
 )
 
-// BuildEnv creates environment variables for script execution
-func BuildEnv(projectName string, project *config.Project, worktreeName string, worktree *config.Worktree, projectConfig *config.ProjectConfig) []string {
+// BuildEnv creates environment variables for script execution. It fails if a
+// secret reference in the database settings cannot be resolved.
+func BuildEnv(projectName string, project *config.Project, worktreeName string, worktree *config.Worktree, projectConfig *config.ProjectConfig) ([]string, error) {
+	dbURL, source, err := databaseEnv(project, worktree)
+	if err != nil {
+		return nil, err
+	}
+
 	env := os.Environ()
 
 	// Basic info
@@ -66,19 +73,20 @@ func BuildEnv(projectName string, project *config.Project, worktreeName string,
 	// Database environment variables (only if database is configured for worktree)
 	if worktree.DatabaseName != "" {
 		env = append(env, fmt.Sprintf("CONDUCTOR_DB_NAME=%s", worktree.DatabaseName))
-		env = append(env, fmt.Sprintf("CONDUCTOR_DB_URL=%s", worktree.DatabaseURL))
-		env = append(env, fmt.Sprintf("DATABASE_URL=%s", worktree.DatabaseURL)) // Common convention
+		env = append(env, fmt.Sprintf("CONDUCTOR_DB_URL=%s", dbURL))
+		env = append(env, fmt.Sprintf("DATABASE_URL=%s", dbURL)) // Common convention
 	}
 
 	// Source database reference (read-only, for reference in scripts)
-	if project.Database != nil && project.Database.Source != "" {
-		env = append(env, fmt.Sprintf("CONDUCTOR_DB_SOURCE=%s", project.Database.Source))
+	if source != "" {
+		env = append(env, fmt.Sprintf("CONDUCTOR_DB_SOURCE=%s", source))
 	}
 
-	// ClickUp task environment variables
-	if worktree.ClickUpTaskID != "" {
-		env = append(env, fmt.Sprintf("CONDUCTOR_TASK_ID=%s", worktree.ClickUpTaskID))
-		env = append(env, fmt.Sprintf("CONDUCTOR_TASK_URL=%s", worktree.ClickUpTaskURL))
+	// Issue-tracker task environment variables
+	if worktree.TaskID != "" {
+		env = append(env, fmt.Sprintf("CONDUCTOR_TASK_PROVIDER=%s", worktree.TaskProvider))
+		env = append(env, fmt.Sprintf("CONDUCTOR_TASK_ID=%s", worktree.TaskID))
+		env = append(env, fmt.Sprintf("CONDUCTOR_TASK_URL=%s", worktree.TaskURL))
 	}
 
 	// Tooling detection environment variables
@@ -117,11 +125,16 @@ func BuildEnv(projectName string, project *config.Project, worktreeName string,
 		}
 	}
 
-	return env
+	return env, nil
 }
 
 // GetEnvMap returns environment as a map for display
-func GetEnvMap(projectName string, project *config.Project, worktreeName string, worktree *config.Worktree, projectConfig *config.ProjectConfig) map[string]string {
+func GetEnvMap(projectName string, project *config.Project, worktreeName string, worktree *config.Worktree, projectConfig *config.ProjectConfig) (map[string]string, error) {
+	dbURL, source, err := databaseEnv(project, worktree)
+	if err != nil {
+		return nil, err
+	}
+
 	result := make(map[string]string)
 
 	result["CONDUCTOR_PROJECT_NAME"] = projectName
@@ -169,19 +182,20 @@ func GetEnvMap(projectName string, project *config.Project, worktreeName string,
 	// Database environment variables (only if database is configured for worktree)
 	if worktree.DatabaseName != "" {
 		result["CONDUCTOR_DB_NAME"] = worktree.DatabaseName
-		result["CONDUCTOR_DB_URL"] = worktree.DatabaseURL
-		result["DATABASE_URL"] = worktree.DatabaseURL
+		result["CONDUCTOR_DB_URL"] = dbURL
+		result["DATABASE_URL"] = dbURL
 	}
 
 	// Source database reference
-	if project.Database != nil && project.Database.Source != "" {
-		result["CONDUCTOR_DB_SOURCE"] = project.Database.Source
+	if source != "" {
+		result["CONDUCTOR_DB_SOURCE"] = source
 	}
 
-	// ClickUp task environment variables
-	if worktree.ClickUpTaskID != "" {
-		result["CONDUCTOR_TASK_ID"] = worktree.ClickUpTaskID
-		result["CONDUCTOR_TASK_URL"] = worktree.ClickUpTaskURL
+	// Issue-tracker task environment variables
+	if worktree.TaskID != "" {
+		result["CONDUCTOR_TASK_PROVIDER"] = worktree.TaskProvider
+		result["CONDUCTOR_TASK_ID"] = worktree.TaskID
+		result["CONDUCTOR_TASK_URL"] = worktree.TaskURL
 	}
 
 	// Tooling detection environment variables
@@ -220,5 +234,36 @@ func GetEnvMap(projectName string, project *config.Project, worktreeName string,
 		}
 	}
 
-	return result
+	return result, nil
+}
+
+// databaseEnv returns the worktree's database URL with its password filled in
+// from the server's secret reference, and the project's resolved source URL
+func databaseEnv(project *config.Project, worktree *config.Worktree) (string, string, error) {
+	var dbURL string
+	if worktree.DatabaseName != "" {
+		var err error
+		dbURL, err = secrets.ResolveURLPassword(worktree.DatabaseURL, worktree.DatabaseServerRef)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to resolve database URL: %w", err)
+		}
+	}
+	source, err := dbSource(project)
+	if err != nil {
+		return "", "", err
+	}
+	return dbURL, source, nil
+}
+
+// dbSource returns the project's source database URL with any secret reference
+// resolved, or "" if none is configured
+func dbSource(project *config.Project) (string, error) {
+	if project.Database == nil || project.Database.Source == "" {
+		return "", nil
+	}
+	source, err := secrets.Resolve(project.Database.Source)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve database source: %w", err)
+	}
+	return source, nil
 }
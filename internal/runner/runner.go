@@ -44,7 +44,10 @@ func (r *Runner) Run(projectName, worktreeName, scriptName string) error {
 	}
 
 	// Build environment
-	env := BuildEnv(projectName, project, worktreeName, worktree, projectConfig)
+	env, err := BuildEnv(projectName, project, worktreeName, worktree, projectConfig)
+	if err != nil {
+		return err
+	}
 
 	// Execute script
 	return r.executeScript(script, scriptPath, worktree.Path, env)
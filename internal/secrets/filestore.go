@@ -0,0 +1,218 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/hammashamzah/conductor/internal/config"
+)
+
+const (
+	secretsFile = "secrets.enc"
+	keyFile     = "secrets.key"
+	// keyEnvVar overrides the key file with a passphrase
+	keyEnvVar = "CONDUCTOR_SECRETS_KEY"
+)
+
+// FileStore keeps secrets in ~/.conductor/secrets.enc, encrypted with AES-256-GCM.
+// The key is read from CONDUCTOR_SECRETS_KEY (hashed with SHA-256) or, if that
+// is unset, from ~/.conductor/secrets.key, which is generated on first use.
+// Both files are created with mode 0600.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileStore creates a file-backed store in dir
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// Get returns the named secret
+func (f *FileStore) Get(name string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values, err := f.load(false)
+	if err != nil {
+		return "", err
+	}
+	value, ok := values[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Set stores a secret, replacing any previous value
+func (f *FileStore) Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values, err := f.load(true)
+	if err != nil {
+		return err
+	}
+	values[name] = value
+	return f.save(values)
+}
+
+// Delete removes a secret
+func (f *FileStore) Delete(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values, err := f.load(false)
+	if err != nil {
+		return err
+	}
+	if _, ok := values[name]; !ok {
+		return ErrNotFound
+	}
+	delete(values, name)
+	return f.save(values)
+}
+
+// List returns the stored secret names, sorted
+func (f *FileStore) List() ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values, err := f.load(false)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// load decrypts the secrets file. A missing file is an empty store.
+func (f *FileStore) load(createKey bool) (map[string]string, error) {
+	values := make(map[string]string)
+
+	data, err := os.ReadFile(filepath.Join(f.dir, secretsFile))
+	if os.IsNotExist(err) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets: %w", err)
+	}
+
+	gcm, err := f.cipher(createKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("secrets file %s is corrupt", filepath.Join(f.dir, secretsFile))
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secrets (wrong key?): %w", err)
+	}
+
+	if err := json.Unmarshal(plaintext, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets: %w", err)
+	}
+	return values, nil
+}
+
+// save encrypts values with a fresh nonce and writes the secrets file
+func (f *FileStore) save(values map[string]string) error {
+	gcm, err := f.cipher(true)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(f.dir, 0700); err != nil {
+		return err
+	}
+	data := gcm.Seal(nonce, nonce, plaintext, nil)
+	if err := config.WriteFileAtomic(filepath.Join(f.dir, secretsFile), data, 0600); err != nil {
+		return fmt.Errorf("failed to write secrets: %w", err)
+	}
+	return nil
+}
+
+// cipher returns the AES-GCM cipher for the store's key
+func (f *FileStore) cipher(createKey bool) (cipher.AEAD, error) {
+	key, err := f.key(createKey)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// key returns the 32-byte encryption key, generating the key file if allowed
+func (f *FileStore) key(create bool) ([]byte, error) {
+	if passphrase := os.Getenv(keyEnvVar); passphrase != "" {
+		sum := sha256.Sum256([]byte(passphrase))
+		return sum[:], nil
+	}
+
+	path := filepath.Join(f.dir, keyFile)
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("secrets key %s is invalid", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) || !create {
+		return nil, fmt.Errorf("failed to read secrets key: %w", err)
+	}
+
+	key = make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(f.dir, 0700); err != nil {
+		return nil, err
+	}
+	// O_EXCL so two processes generating a key at once can't overwrite each other
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return f.key(false)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets key: %w", err)
+	}
+	_, err = file.Write(key)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write secrets key: %w", err)
+	}
+	return key, nil
+}
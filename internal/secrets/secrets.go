@@ -0,0 +1,180 @@
+// Package secrets resolves secret references stored in conductor.json in place
+// of plaintext credentials, and stores named secrets in a local encrypted file.
+//
+// A config value may be a literal or one of:
+//
+//	secret://<name>  a secret stored with 'conductor secrets set <name>'
+//	env://<VAR>      the value of an environment variable
+//	file://<path>    the contents of a file (trailing newline trimmed)
+//
+// References are resolved when the value is used, never written back.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hammashamzah/conductor/internal/config"
+)
+
+const (
+	secretScheme = "secret://"
+	envScheme    = "env://"
+	fileScheme   = "file://"
+)
+
+// ErrNotFound is returned for a secret name that has not been set
+var ErrNotFound = errors.New("secret not found")
+
+// Backend stores named secrets
+type Backend interface {
+	Get(name string) (string, error)
+	Set(name, value string) error
+	Delete(name string) error
+	// List returns the stored secret names, sorted
+	List() ([]string, error)
+}
+
+// Default returns the backend used for secret:// references
+func Default() (Backend, error) {
+	dir, err := config.ConductorDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewFileStore(dir), nil
+}
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateName checks that name can be used in a secret:// reference
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid secret name '%s' (use letters, digits, '.', '_' and '-')", name)
+	}
+	return nil
+}
+
+// Ref returns the secret:// reference for a stored secret
+func Ref(name string) string {
+	return secretScheme + name
+}
+
+// IsReference reports whether value is a secret reference rather than a literal
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, secretScheme) ||
+		strings.HasPrefix(value, envScheme) ||
+		strings.HasPrefix(value, fileScheme)
+}
+
+// Resolve returns the value a reference points to. Literals are returned unchanged.
+func Resolve(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, secretScheme):
+		name := strings.TrimPrefix(value, secretScheme)
+		backend, err := Default()
+		if err != nil {
+			return "", err
+		}
+		secret, err := backend.Get(name)
+		if errors.Is(err, ErrNotFound) {
+			return "", fmt.Errorf("secret '%s' not found. Set it with 'conductor secrets set %s'", name, name)
+		}
+		return secret, err
+
+	case strings.HasPrefix(value, envScheme):
+		name := strings.TrimPrefix(value, envScheme)
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return secret, nil
+
+	case strings.HasPrefix(value, fileScheme):
+		path := strings.TrimPrefix(value, fileScheme)
+		if rest, ok := strings.CutPrefix(path, "~/"); ok {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			path = filepath.Join(home, rest)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	return value, nil
+}
+
+// StripPassword returns a connection URL without the password in its user
+// info. Values that don't parse as URLs are returned unchanged.
+func StripPassword(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+	if _, ok := u.User.Password(); !ok {
+		return rawURL
+	}
+	u.User = url.User(u.User.Username())
+	return u.String()
+}
+
+// ResolveURLPassword puts back the password StripPassword removed. serverRef
+// is the unresolved server URL dbURL was built from; its password is used
+// when dbURL has none. An empty serverRef returns dbURL unchanged.
+func ResolveURLPassword(dbURL, serverRef string) (string, error) {
+	if serverRef == "" {
+		return dbURL, nil
+	}
+	server, err := Resolve(serverRef)
+	if err != nil {
+		return "", err
+	}
+	serverURL, err := url.Parse(server)
+	if err != nil || serverURL.User == nil {
+		return dbURL, nil
+	}
+	password, ok := serverURL.User.Password()
+	if !ok {
+		return dbURL, nil
+	}
+	u, err := url.Parse(dbURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid database URL: %w", err)
+	}
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			return dbURL, nil
+		}
+	}
+	username := serverURL.User.Username()
+	if u.User != nil && u.User.Username() != "" {
+		username = u.User.Username()
+	}
+	u.User = url.UserPassword(username, password)
+	return u.String(), nil
+}
+
+// ResolveDatabaseConfig returns a copy of cfg with its connection strings resolved
+func ResolveDatabaseConfig(cfg *config.DatabaseConfig) (*config.DatabaseConfig, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	resolved := *cfg
+	for _, field := range []*string{&resolved.Source, &resolved.CloneURL, &resolved.DevURL, &resolved.DevURLExternal} {
+		value, err := Resolve(*field)
+		if err != nil {
+			return nil, err
+		}
+		*field = value
+	}
+	return &resolved, nil
+}
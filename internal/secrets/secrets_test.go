@@ -0,0 +1,130 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+
+	_, err := store.Get("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, store.Set("clickup-token", "pk_123"))
+	require.NoError(t, store.Set("prod-db", "postgresql://u:p@h/db"))
+
+	value, err := store.Get("clickup-token")
+	require.NoError(t, err)
+	assert.Equal(t, "pk_123", value)
+
+	names, err := store.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"clickup-token", "prod-db"}, names)
+
+	// Nothing is stored in plaintext and both files are private
+	data, err := os.ReadFile(filepath.Join(dir, secretsFile))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "pk_123")
+	for _, name := range []string{secretsFile, keyFile} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), name)
+	}
+
+	require.NoError(t, store.Delete("clickup-token"))
+	assert.ErrorIs(t, store.Delete("clickup-token"), ErrNotFound)
+	_, err = store.Get("clickup-token")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestFileStore_WrongKey(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(keyEnvVar, "one")
+	require.NoError(t, NewFileStore(dir).Set("name", "value"))
+
+	t.Setenv(keyEnvVar, "two")
+	_, err := NewFileStore(dir).Get("name")
+	assert.ErrorContains(t, err, "wrong key")
+}
+
+func TestFileStore_InvalidName(t *testing.T) {
+	assert.Error(t, NewFileStore(t.TempDir()).Set("bad name", "x"))
+}
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CONDUCTOR_CONFIG_DIR", dir)
+	require.NoError(t, NewFileStore(dir).Set("token", "s3cret"))
+
+	value, err := Resolve("secret://token")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+
+	_, err = Resolve("secret://nope")
+	assert.ErrorContains(t, err, "conductor secrets set nope")
+
+	t.Setenv("CONDUCTOR_TEST_DB", "postgresql://x")
+	value, err = Resolve("env://CONDUCTOR_TEST_DB")
+	require.NoError(t, err)
+	assert.Equal(t, "postgresql://x", value)
+
+	_, err = Resolve("env://CONDUCTOR_TEST_UNSET_VAR")
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0600))
+	value, err = Resolve("file://" + path)
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", value)
+
+	value, err = Resolve("postgresql://u:p@h/db")
+	require.NoError(t, err)
+	assert.Equal(t, "postgresql://u:p@h/db", value)
+}
+
+func TestResolveDatabaseConfig(t *testing.T) {
+	t.Setenv("CONDUCTOR_TEST_SOURCE", "postgresql://prod")
+	cfg := &config.DatabaseConfig{Source: "env://CONDUCTOR_TEST_SOURCE", DevURL: "postgresql://dev"}
+
+	resolved, err := ResolveDatabaseConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "postgresql://prod", resolved.Source)
+	assert.Equal(t, "postgresql://dev", resolved.DevURL)
+
+	// The original keeps the reference
+	assert.Equal(t, "env://CONDUCTOR_TEST_SOURCE", cfg.Source)
+}
+
+func TestStripPassword(t *testing.T) {
+	assert.Equal(t, "postgresql://u@h:5432/db", StripPassword("postgresql://u:p@h:5432/db"))
+	assert.Equal(t, "postgresql://u@h/db", StripPassword("postgresql://u@h/db"))
+	assert.Equal(t, "postgresql://h/db", StripPassword("postgresql://h/db"))
+}
+
+func TestResolveURLPassword(t *testing.T) {
+	t.Setenv("CONDUCTOR_TEST_DEV", "postgresql://admin:s3cret@h:5432/postgres")
+
+	// No reference: the stored URL is used as is
+	dbURL, err := ResolveURLPassword("postgresql://u:p@h/db", "")
+	require.NoError(t, err)
+	assert.Equal(t, "postgresql://u:p@h/db", dbURL)
+
+	dbURL, err = ResolveURLPassword("postgresql://admin@h:5432/dev_tokyo", "env://CONDUCTOR_TEST_DEV")
+	require.NoError(t, err)
+	assert.Equal(t, "postgresql://admin:s3cret@h:5432/dev_tokyo", dbURL)
+
+	// The server's username is used when the stored URL has none
+	dbURL, err = ResolveURLPassword("postgresql://h:5432/dev_tokyo", "env://CONDUCTOR_TEST_DEV")
+	require.NoError(t, err)
+	assert.Equal(t, "postgresql://admin:s3cret@h:5432/dev_tokyo", dbURL)
+
+	_, err = ResolveURLPassword("postgresql://admin@h/dev_tokyo", "env://CONDUCTOR_TEST_UNSET")
+	assert.Error(t, err)
+}
@@ -108,6 +108,7 @@ func (s *Store) RemoveWorktree(projectName, worktreeName string) error {
 	}
 
 	delete(project.Worktrees, worktreeName)
+	project.PrunePortHistory()
 	s.markDirty()
 	return nil
 }
@@ -174,6 +175,112 @@ func (s *Store) ArchiveWorktree(projectName, worktreeName string) error {
 	return nil
 }
 
+// SetWorktreeSnapshot records the archive snapshot for a worktree
+func (s *Store) SetWorktreeSnapshot(projectName, worktreeName string, snapshot *config.ArchiveSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	project, ok := s.config.Projects[projectName]
+	if !ok {
+		return fmt.Errorf("project '%s' not found", projectName)
+	}
+
+	wt, ok := project.Worktrees[worktreeName]
+	if !ok {
+		return fmt.Errorf("worktree '%s' not found", worktreeName)
+	}
+
+	wt.Snapshot = snapshot
+	s.markDirty()
+	return nil
+}
+
+// AddDatabaseSnapshot records a database snapshot for a worktree, replacing
+// any snapshot with the same name
+func (s *Store) AddDatabaseSnapshot(projectName, worktreeName string, snapshot config.DatabaseSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	project, ok := s.config.Projects[projectName]
+	if !ok {
+		return fmt.Errorf("project '%s' not found", projectName)
+	}
+
+	wt, ok := project.Worktrees[worktreeName]
+	if !ok {
+		return fmt.Errorf("worktree '%s' not found", worktreeName)
+	}
+
+	snapshots := wt.DatabaseSnapshots[:0:0]
+	for _, snap := range wt.DatabaseSnapshots {
+		if snap.Name != snapshot.Name {
+			snapshots = append(snapshots, snap)
+		}
+	}
+	wt.DatabaseSnapshots = append(snapshots, snapshot)
+	s.markDirty()
+	return nil
+}
+
+// RemoveDatabaseSnapshot forgets a worktree's database snapshot
+func (s *Store) RemoveDatabaseSnapshot(projectName, worktreeName, snapshotName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	project, ok := s.config.Projects[projectName]
+	if !ok {
+		return fmt.Errorf("project '%s' not found", projectName)
+	}
+
+	wt, ok := project.Worktrees[worktreeName]
+	if !ok {
+		return fmt.Errorf("worktree '%s' not found", worktreeName)
+	}
+
+	for i, snap := range wt.DatabaseSnapshots {
+		if snap.Name == snapshotName {
+			wt.DatabaseSnapshots = append(wt.DatabaseSnapshots[:i:i], wt.DatabaseSnapshots[i+1:]...)
+			if len(wt.DatabaseSnapshots) == 0 {
+				wt.DatabaseSnapshots = nil
+			}
+			s.markDirty()
+			return nil
+		}
+	}
+	return fmt.Errorf("snapshot '%s' not found", snapshotName)
+}
+
+// RestoreWorktree marks an archived worktree as active again with freshly
+// allocated ports and database, clearing its archive snapshot
+func (s *Store) RestoreWorktree(projectName, worktreeName string, ports []int, dbName, dbURL, dbServerRef string, dbPending bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	project, ok := s.config.Projects[projectName]
+	if !ok {
+		return fmt.Errorf("project '%s' not found", projectName)
+	}
+
+	wt, ok := project.Worktrees[worktreeName]
+	if !ok {
+		return fmt.Errorf("worktree '%s' not found", worktreeName)
+	}
+
+	wt.Archived = false
+	wt.ArchivedAt = time.Time{}
+	wt.ArchiveStatus = config.ArchiveStatusNone
+	wt.SetupStatus = config.SetupStatusCreating
+	wt.Ports = ports
+	wt.DatabaseName = dbName
+	wt.DatabaseURL = dbURL
+	wt.DatabaseServerRef = dbServerRef
+	wt.DatabasePending = dbPending
+	wt.Tunnel = nil
+	wt.Snapshot = nil
+	s.markDirty()
+	return nil
+}
+
 // SetWorktreePorts sets the ports for a worktree
 func (s *Store) SetWorktreePorts(projectName, worktreeName string, ports []int) error {
 	s.mu.Lock()
@@ -267,6 +374,65 @@ func (s *Store) AllocatePorts(projectName, worktreeName string, count int) ([]in
 	return ports, nil
 }
 
+// AllocateWorktreePorts allocates ports for a worktree, reusing the branch's
+// previous block when the project has sticky ports enabled (see
+// config.Config.AllocateWorktreePorts)
+func (s *Store) AllocateWorktreePorts(projectName, worktreeName, branch string, count int) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ports, err := s.config.AllocateWorktreePorts(projectName, worktreeName, branch, count)
+	if err != nil {
+		return nil, err
+	}
+
+	s.markDirty()
+	return ports, nil
+}
+
+// SetProjectPortRange reserves a port sub-range for a project (nil clears it).
+// The range may not overlap another project's reserved range.
+func (s *Store) SetProjectPortRange(projectName string, portRange *config.PortRange) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	project, ok := s.config.Projects[projectName]
+	if !ok {
+		return fmt.Errorf("project '%s' not found", projectName)
+	}
+
+	if portRange != nil {
+		if portRange.Start <= 0 || portRange.End < portRange.Start || portRange.End > 65535 {
+			return fmt.Errorf("invalid port range %d-%d", portRange.Start, portRange.End)
+		}
+		for name, other := range s.config.Projects {
+			if name != projectName && portRange.Overlaps(other.PortRange) {
+				return fmt.Errorf("port range %d-%d overlaps project '%s' (%d-%d)",
+					portRange.Start, portRange.End, name, other.PortRange.Start, other.PortRange.End)
+			}
+		}
+	}
+
+	project.PortRange = portRange
+	s.markDirty()
+	return nil
+}
+
+// SetProjectStickyPorts enables or disables sticky port allocation for a project
+func (s *Store) SetProjectStickyPorts(projectName string, sticky bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	project, ok := s.config.Projects[projectName]
+	if !ok {
+		return fmt.Errorf("project '%s' not found", projectName)
+	}
+
+	project.StickyPorts = sticky
+	s.markDirty()
+	return nil
+}
+
 // FreePorts frees the specified ports
 func (s *Store) FreePorts(ports []int) {
 	s.mu.Lock()
@@ -303,6 +469,23 @@ func (s *Store) RemovePortAllocation(port int) {
 	s.markDirty()
 }
 
+// RepairPorts reconciles port allocations with worktree port lists and the
+// worktrees on disk in a single locked operation (see config.RepairPorts).
+// Nothing is changed if the repair fails.
+func (s *Store) RepairPorts() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changes, err := s.config.RepairPorts()
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) > 0 {
+		s.markDirty()
+	}
+	return changes, nil
+}
+
 // ============================================================================
 // Update Settings Mutations
 // ============================================================================
@@ -335,6 +518,59 @@ func (s *Store) SetUpdateInfo(lastCheck time.Time, version string) {
 	s.markDirty()
 }
 
+// ============================================================================
+// Settings Mutations (conductor config set/unset)
+// ============================================================================
+
+// SetSetting sets a global setting by its dotted path (see config.SetSetting).
+// The change is rejected if it makes the config invalid.
+func (s *Store) SetSetting(path, value string) error {
+	return s.changeSetting(func(cfg *config.Config) error {
+		return config.SetSetting(cfg, path, value)
+	})
+}
+
+// UnsetSetting resets a global setting by its dotted path (see config.UnsetSetting)
+func (s *Store) UnsetSetting(path string) error {
+	return s.changeSetting(func(cfg *config.Config) error {
+		return config.UnsetSetting(cfg, path)
+	})
+}
+
+// changeSetting applies fn and rolls it back if it introduced validation problems
+func (s *Store) changeSetting(fn func(cfg *config.Config) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, err := config.Marshal(s.config)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool)
+	for _, problem := range s.config.Validate() {
+		known[problem] = true
+	}
+
+	restore := func() {
+		if cfg, err := config.Parse(before); err == nil {
+			*s.config = *cfg
+		}
+	}
+	if err := fn(s.config); err != nil {
+		restore()
+		return err
+	}
+	for _, problem := range s.config.Validate() {
+		if !known[problem] {
+			restore()
+			return fmt.Errorf("invalid setting: %s", problem)
+		}
+	}
+
+	s.markDirty()
+	return nil
+}
+
 // ============================================================================
 // Batch Mutations (for complex operations that need atomicity)
 // ============================================================================
@@ -455,6 +691,24 @@ func (s *Store) SetDatabaseConfig(projectName string, dbConfig *config.DatabaseC
 	return nil
 }
 
+// SetDatabaseSyncStatus records the golden copy's sync state for a project
+func (s *Store) SetDatabaseSyncStatus(projectName string, status *config.DatabaseSyncStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	project, ok := s.config.Projects[projectName]
+	if !ok {
+		return fmt.Errorf("project %q not found", projectName)
+	}
+	if project.Database == nil {
+		return fmt.Errorf("database not configured for project %q", projectName)
+	}
+
+	project.Database.SyncStatus = status
+	s.markDirty()
+	return nil
+}
+
 // SetLocalPostgresURL sets the local PostgreSQL URL in defaults
 func (s *Store) SetLocalPostgresURL(url string) error {
 	s.mu.Lock()
@@ -465,6 +719,16 @@ func (s *Store) SetLocalPostgresURL(url string) error {
 	return nil
 }
 
+// SetLocalMySQLURL sets the local MySQL URL in defaults
+func (s *Store) SetLocalMySQLURL(url string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.config.Defaults.LocalMySQLURL = url
+	s.markDirty()
+	return nil
+}
+
 // ClearDatabaseConfig removes database configuration from a project
 func (s *Store) ClearDatabaseConfig(projectName string) error {
 	s.mu.Lock()
@@ -480,8 +744,9 @@ func (s *Store) ClearDatabaseConfig(projectName string) error {
 	return nil
 }
 
-// SetWorktreeDatabase sets the database info for a worktree
-func (s *Store) SetWorktreeDatabase(projectName, worktreeName, dbName, dbURL string) error {
+// SetWorktreeDatabase sets the database info for a worktree. dbServerRef is
+// the secret reference completing a password-less dbURL, or "".
+func (s *Store) SetWorktreeDatabase(projectName, worktreeName, dbName, dbURL, dbServerRef string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -497,6 +762,47 @@ func (s *Store) SetWorktreeDatabase(projectName, worktreeName, dbName, dbURL str
 
 	wt.DatabaseName = dbName
 	wt.DatabaseURL = dbURL
+	wt.DatabaseServerRef = dbServerRef
+	s.markDirty()
+	return nil
+}
+
+// ClearWorktreeDatabasePending records that setup created the worktree's database
+func (s *Store) ClearWorktreeDatabasePending(projectName, worktreeName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	project, ok := s.config.Projects[projectName]
+	if !ok {
+		return fmt.Errorf("project %q not found", projectName)
+	}
+
+	wt, ok := project.Worktrees[worktreeName]
+	if !ok {
+		return fmt.Errorf("worktree %q not found", worktreeName)
+	}
+
+	wt.DatabasePending = false
+	s.markDirty()
+	return nil
+}
+
+// SetWorktreeFiles records the untracked files placed in a worktree from the project root
+func (s *Store) SetWorktreeFiles(projectName, worktreeName string, files []config.WorktreeFile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	project, ok := s.config.Projects[projectName]
+	if !ok {
+		return fmt.Errorf("project %q not found", projectName)
+	}
+
+	wt, ok := project.Worktrees[worktreeName]
+	if !ok {
+		return fmt.Errorf("worktree %q not found", worktreeName)
+	}
+
+	wt.Files = files
 	s.markDirty()
 	return nil
 }
@@ -521,8 +827,8 @@ func (s *Store) SetWorktreeMission(projectName, worktreeName, missionID string)
 	return nil
 }
 
-// SetWorktreeClickUpTask sets the ClickUp task linkage for a worktree
-func (s *Store) SetWorktreeClickUpTask(projectName, worktreeName, taskID, taskURL string) error {
+// SetWorktreeTask links a worktree to the issue-tracker task it was created for
+func (s *Store) SetWorktreeTask(projectName, worktreeName, provider, taskID, taskURL string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -536,8 +842,9 @@ func (s *Store) SetWorktreeClickUpTask(projectName, worktreeName, taskID, taskUR
 		return fmt.Errorf("worktree %q not found", worktreeName)
 	}
 
-	wt.ClickUpTaskID = taskID
-	wt.ClickUpTaskURL = taskURL
+	wt.TaskProvider = provider
+	wt.TaskID = taskID
+	wt.TaskURL = taskURL
 	s.markDirty()
 	return nil
 }
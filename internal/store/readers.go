@@ -1,6 +1,7 @@
 package store
 
 import (
+	"maps"
 	"time"
 
 	"github.com/hammashamzah/conductor/internal/config"
@@ -336,10 +337,21 @@ func (s *Store) copyProject(p *config.Project) *config.Project {
 		GitHubRepo:              p.GitHubRepo,
 		Worktrees:               make(map[string]*config.Worktree, len(p.Worktrees)),
 		Database:                s.copyDatabaseConfig(p.Database),
+		StickyPorts:             p.StickyPorts,
 	}
 	for name, wt := range p.Worktrees {
 		cp.Worktrees[name] = s.copyWorktree(wt)
 	}
+	if p.PortRange != nil {
+		r := *p.PortRange
+		cp.PortRange = &r
+	}
+	if p.PortHistory != nil {
+		cp.PortHistory = make(map[string][]int, len(p.PortHistory))
+		for key, ports := range p.PortHistory {
+			cp.PortHistory[key] = append([]int(nil), ports...)
+		}
+	}
 	return cp
 }
 
@@ -371,6 +383,12 @@ func (s *Store) copyDatabaseConfig(db *config.DatabaseConfig) *config.DatabaseCo
 			cp.FilterTables[k] = v
 		}
 	}
+	if len(db.MaskColumns) > 0 {
+		cp.MaskColumns = make(map[string]string, len(db.MaskColumns))
+		for k, v := range db.MaskColumns {
+			cp.MaskColumns[k] = v
+		}
+	}
 	// Copy nested struct
 	if db.SyncStatus != nil {
 		cp.SyncStatus = &config.DatabaseSyncStatus{
@@ -390,19 +408,22 @@ func (s *Store) copyWorktree(wt *config.Worktree) *config.Worktree {
 		return nil
 	}
 	cp := &config.Worktree{
-		Path:           wt.Path,
-		Branch:         wt.Branch,
-		IsRoot:         wt.IsRoot,
-		CreatedAt:      wt.CreatedAt,
-		Archived:       wt.Archived,
-		ArchivedAt:     wt.ArchivedAt,
-		SetupStatus:    wt.SetupStatus,
-		ArchiveStatus:  wt.ArchiveStatus,
-		Tunnel:         s.copyTunnelState(wt.Tunnel),
-		DatabaseName:   wt.DatabaseName,
-		DatabaseURL:    wt.DatabaseURL,
-		ClickUpTaskID:  wt.ClickUpTaskID,
-		ClickUpTaskURL: wt.ClickUpTaskURL,
+		Path:          wt.Path,
+		Branch:        wt.Branch,
+		IsRoot:        wt.IsRoot,
+		CreatedAt:     wt.CreatedAt,
+		Archived:      wt.Archived,
+		ArchivedAt:    wt.ArchivedAt,
+		SetupStatus:   wt.SetupStatus,
+		ArchiveStatus: wt.ArchiveStatus,
+		Tunnel:        s.copyTunnelState(wt.Tunnel),
+		DatabaseName:  wt.DatabaseName,
+		DatabaseURL:   wt.DatabaseURL,
+
+		DatabaseServerRef: wt.DatabaseServerRef,
+		TaskProvider:      wt.TaskProvider,
+		TaskID:            wt.TaskID,
+		TaskURL:           wt.TaskURL,
 	}
 
 	// Copy ports
@@ -417,6 +438,37 @@ func (s *Store) copyWorktree(wt *config.Worktree) *config.Worktree {
 		copy(cp.PRs, wt.PRs)
 	}
 
+	// Copy archive snapshot
+	if wt.Snapshot != nil {
+		snap := *wt.Snapshot
+		if wt.Snapshot.Ports != nil {
+			snap.Ports = make([]int, len(wt.Snapshot.Ports))
+			copy(snap.Ports, wt.Snapshot.Ports)
+		}
+		if wt.Snapshot.PortLabels != nil {
+			snap.PortLabels = make(map[string]int, len(wt.Snapshot.PortLabels))
+			for k, v := range wt.Snapshot.PortLabels {
+				snap.PortLabels[k] = v
+			}
+		}
+		cp.Snapshot = &snap
+	}
+
+	// Copy database snapshots
+	if wt.DatabaseSnapshots != nil {
+		cp.DatabaseSnapshots = make([]config.DatabaseSnapshot, len(wt.DatabaseSnapshots))
+		copy(cp.DatabaseSnapshots, wt.DatabaseSnapshots)
+	}
+
+	// Copy placed files
+	if wt.Files != nil {
+		cp.Files = make([]config.WorktreeFile, len(wt.Files))
+		copy(cp.Files, wt.Files)
+		for i := range cp.Files {
+			cp.Files[i].Manifest = maps.Clone(cp.Files[i].Manifest)
+		}
+	}
+
 	return cp
 }
 
@@ -468,10 +520,7 @@ func (s *Store) GetConfigSnapshot() *config.Config {
 
 	// Copy tunnel defaults
 	cfg.Defaults.Tunnel = config.TunnelDefaults{
-		Domain:          s.config.Defaults.Tunnel.Domain,
-		CloudflareToken: s.config.Defaults.Tunnel.CloudflareToken,
-		AccountID:       s.config.Defaults.Tunnel.AccountID,
-		ZoneID:          s.config.Defaults.Tunnel.ZoneID,
+		Domain: s.config.Defaults.Tunnel.Domain,
 	}
 
 	return cfg
@@ -484,6 +533,13 @@ func (s *Store) GetAllPortInfo() []config.PortInfo {
 	return s.config.GetAllPortInfo()
 }
 
+// DiagnosePorts reports inconsistencies in port allocations (delegating to config method)
+func (s *Store) DiagnosePorts() []config.PortIssue {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config.DiagnosePorts()
+}
+
 // TotalUsedPorts returns the total number of used ports
 func (s *Store) TotalUsedPorts() int {
 	s.mu.RLock()
@@ -4,7 +4,11 @@
 package store
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,6 +29,13 @@ type Store struct {
 	mu     sync.RWMutex
 	config *config.Config
 
+	// base is the on-disk config this store last read or wrote. When the file
+	// has changed since, saves three-way merge instead of overwriting it.
+	base []byte
+	// generation counts mutations so a save only clears dirty if nothing
+	// changed while it was writing
+	generation uint64
+
 	// Save queue management
 	dirty      bool
 	saveChan   chan struct{}
@@ -32,6 +43,9 @@ type Store struct {
 	closedChan chan struct{}
 	saveErr    *SaveError
 
+	// reallocated lists worktrees a save moved to new ports, until taken
+	reallocated []PortReallocation
+
 	// Configuration
 	debounceTime time.Duration
 	maxRetries   int
@@ -41,6 +55,15 @@ type Store struct {
 	onSaveError func(err error)
 }
 
+// PortReallocation records a worktree that a save moved off ports another
+// conductor process had allocated first
+type PortReallocation struct {
+	Project  string
+	Worktree string
+	OldPorts []int
+	NewPorts []int
+}
+
 // Option is a functional option for configuring the Store
 type Option func(*Store)
 
@@ -87,6 +110,9 @@ func New(cfg *config.Config, opts ...Option) *Store {
 		opt(s)
 	}
 
+	// cfg is normally fresh from disk, so it is the merge base for the first save
+	s.base, _ = config.Marshal(cfg)
+
 	go s.saveWorker()
 	return s
 }
@@ -133,14 +159,11 @@ func (s *Store) performSave() {
 		s.mu.Unlock()
 		return
 	}
-
-	// Make a copy of config for saving (so we can release lock)
-	cfgCopy := s.config
 	s.mu.Unlock()
 
 	var lastErr error
 	for i := 0; i <= s.maxRetries; i++ {
-		if err := config.Save(cfgCopy); err != nil {
+		if err := s.save(); err != nil {
 			lastErr = err
 			time.Sleep(time.Duration(i+1) * 50 * time.Millisecond) // Exponential backoff
 			continue
@@ -148,7 +171,6 @@ func (s *Store) performSave() {
 
 		// Success
 		s.mu.Lock()
-		s.dirty = false
 		s.saveErr = nil
 		s.mu.Unlock()
 
@@ -171,9 +193,228 @@ func (s *Store) performSave() {
 	}
 }
 
+// maxPortReallocations bounds how often one save moves this process's
+// worktrees off ports another process allocated first
+const maxPortReallocations = 3
+
+// errPortConflict aborts a write whose merge would give a port to two worktrees
+var errPortConflict = errors.New("port allocated by another conductor process")
+
+// save writes the in-memory config under the config file lock. If another
+// process changed the file since this store last read or wrote it, the two
+// versions are three-way merged and the merge is loaded back into memory, so
+// neither side's mutations are lost. Where both sides changed the same value
+// ours is kept, except for ports both allocated: those stay with the other
+// process and this process's worktrees are moved to new ports.
+func (s *Store) save() error {
+	for attempt := 0; ; attempt++ {
+		err := s.saveOnce()
+		var conflict *portConflict
+		if !errors.As(err, &conflict) || attempt == maxPortReallocations {
+			return err
+		}
+		if _, err := s.reallocateConflictingPorts(conflict); err != nil {
+			return err
+		}
+	}
+}
+
+// portConflict carries the on-disk config a save found ports allocated in
+type portConflict struct {
+	ports  []int
+	theirs []byte
+}
+
+func (e *portConflict) Error() string {
+	return fmt.Sprintf("%v: %v", errPortConflict, e.ports)
+}
+
+func (e *portConflict) Unwrap() error {
+	return errPortConflict
+}
+
+func (s *Store) saveOnce() error {
+	// Encode under the lock so the snapshot is consistent
+	s.mu.RLock()
+	ours, err := config.Marshal(s.config)
+	base := s.base
+	generation := s.generation
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	written := ours
+	merged := false
+	err = config.UpdateConfigFile(func(current []byte) ([]byte, error) {
+		if current == nil || base == nil || config.SameConfig(current, base) {
+			return ours, nil
+		}
+		result, err := config.MergeConfig(base, current, ours)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.WorktreeConflicts) > 0 {
+			return nil, fmt.Errorf("worktree %s was also created by another conductor process", strings.Join(result.WorktreeConflicts, ", "))
+		}
+		if len(result.PortConflicts) > 0 {
+			return nil, &portConflict{ports: result.PortConflicts, theirs: current}
+		}
+		if len(result.Conflicts) > 0 {
+			log.Printf("store: another conductor process changed %s too; keeping this process's values", strings.Join(result.Conflicts, ", "))
+		}
+		written = result.Data
+		merged = true
+		return written, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.base = written
+	if merged {
+		// Bring the other process's changes into memory, keeping any
+		// mutations made here while the save was in flight
+		next := written
+		var inFlight []int
+		if s.generation != generation {
+			current, err := config.Marshal(s.config)
+			if err != nil {
+				return err
+			}
+			result, err := config.MergeConfig(ours, written, current)
+			if err != nil {
+				return err
+			}
+			next = result.Data
+			inFlight = result.PortConflicts
+		}
+		cfg, err := config.Parse(next)
+		if err != nil {
+			return err
+		}
+		s.config = cfg
+		// Ports allocated here during the save may collide with the other
+		// process's, which are on disk now
+		if len(inFlight) > 0 {
+			onDisk, err := config.Parse(written)
+			if err != nil {
+				return err
+			}
+			if _, err := s.reallocatePortsLocked(onDisk, inFlight); err != nil {
+				return err
+			}
+		}
+	}
+	if s.generation == generation {
+		s.dirty = false
+	}
+	return nil
+}
+
+// reallocateConflictingPorts hands the ports in conflict to the worktrees the
+// other process allocated them to, and gives the worktrees that claimed them
+// here a new block, avoiding everything allocated on disk. Returns the
+// worktrees that were moved; they are also kept for TakePortReallocations.
+func (s *Store) reallocateConflictingPorts(conflict *portConflict) ([]PortReallocation, error) {
+	theirs, err := config.Parse(conflict.theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reallocatePortsLocked(theirs, conflict.ports)
+}
+
+func (s *Store) reallocatePortsLocked(theirs *config.Config, conflicting []int) ([]PortReallocation, error) {
+	type claimer struct{ project, worktree string }
+	var claimers []claimer
+	counts := make(map[claimer]int)
+	for _, port := range conflicting {
+		if alloc := s.config.PortAllocations[strconv.Itoa(port)]; alloc != nil {
+			c := claimer{alloc.Project, alloc.Worktree}
+			if _, ok := counts[c]; !ok {
+				counts[c] = len(s.config.GetWorktreePorts(c.project, c.worktree))
+				claimers = append(claimers, c)
+			}
+		}
+	}
+	for _, port := range conflicting {
+		key := strconv.Itoa(port)
+		if alloc := theirs.PortAllocations[key]; alloc != nil {
+			copied := *alloc
+			s.config.PortAllocations[key] = &copied
+		}
+	}
+	// Keep new blocks clear of every port allocated on disk. These are only
+	// placeholders: the merge decides which of them survive.
+	var reserved []string
+	for key, alloc := range theirs.PortAllocations {
+		if _, ok := s.config.PortAllocations[key]; !ok {
+			copied := *alloc
+			s.config.PortAllocations[key] = &copied
+			reserved = append(reserved, key)
+		}
+	}
+	defer func() {
+		for _, key := range reserved {
+			delete(s.config.PortAllocations, key)
+		}
+	}()
+
+	var moved []PortReallocation
+	for _, c := range claimers {
+		count := counts[c]
+		var wt *config.Worktree
+		if project, ok := s.config.Projects[c.project]; ok {
+			wt = project.Worktrees[c.worktree]
+		}
+		if wt != nil && len(wt.Ports) > count {
+			count = len(wt.Ports)
+		}
+		if count == 0 {
+			continue
+		}
+		move := PortReallocation{Project: c.project, Worktree: c.worktree}
+		if wt != nil {
+			move.OldPorts = append([]int(nil), wt.Ports...)
+		}
+		s.config.FreeWorktreePorts(c.project, c.worktree)
+		ports, err := s.config.AllocatePorts(c.project, c.worktree, count)
+		if err != nil {
+			return moved, fmt.Errorf("failed to reallocate ports for %s/%s: %w", c.project, c.worktree, err)
+		}
+		if wt != nil {
+			wt.Ports = ports
+		}
+		move.NewPorts = ports
+		moved = append(moved, move)
+		s.reallocated = append(s.reallocated, move)
+		log.Printf("store: another conductor process allocated ports %v first; %s/%s moved to ports %v", conflicting, c.project, c.worktree, ports)
+	}
+	s.markDirty()
+	return moved, nil
+}
+
+// TakePortReallocations returns the worktrees that saves moved to new ports
+// since the last call. Their env files and anything else derived from the
+// ports are stale, and the user should be told.
+func (s *Store) TakePortReallocations() []PortReallocation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	moved := s.reallocated
+	s.reallocated = nil
+	return moved
+}
+
 // markDirty marks the store as having unsaved changes and signals the save worker
 func (s *Store) markDirty() {
 	s.dirty = true
+	s.generation++
 	// Non-blocking send to save channel
 	select {
 	case s.saveChan <- struct{}{}:
@@ -208,8 +449,14 @@ func (s *Store) Reload() error {
 		return fmt.Errorf("failed to reload config: %w", err)
 	}
 
+	base, err := config.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	s.config = cfg
+	s.base = base
 	s.dirty = false
 	s.mu.Unlock()
 
@@ -233,11 +480,8 @@ func (s *Store) LastSaveError() *SaveError {
 // ForceSave immediately saves the config, bypassing the debounce queue
 func (s *Store) ForceSave() error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.dirty = true
+	s.mu.Unlock()
 
-	if err := config.Save(s.config); err != nil {
-		return err
-	}
-	s.dirty = false
-	return nil
+	return s.save()
 }
@@ -89,6 +89,27 @@ func TestStore_AddAndGetWorktree(t *testing.T) {
 	assert.Equal(t, "main", got.Branch)
 }
 
+func TestStore_RemoveWorktree_PrunesPortHistory(t *testing.T) {
+	s := newTestStore()
+	defer func() { _, _ = s.Close() }()
+
+	project := config.NewProject("/test/path", 2)
+	project.StickyPorts = true
+	_ = s.AddProject("test", project)
+	_ = s.AddWorktree("test", "tokyo", config.NewWorktree("/test/tokyo", "feature", false, nil))
+	_ = s.AddWorktree("test", "paris", config.NewWorktree("/test/paris", "main", false, nil))
+	_, err := s.AllocateWorktreePorts("test", "tokyo", "feature", 1)
+	require.NoError(t, err)
+	_, err = s.AllocateWorktreePorts("test", "paris", "main", 1)
+	require.NoError(t, err)
+
+	require.NoError(t, s.RemoveWorktree("test", "tokyo"))
+
+	got, _ := s.GetProject("test")
+	assert.NotContains(t, got.PortHistory, "feature")
+	assert.Contains(t, got.PortHistory, "main")
+}
+
 func TestStore_SetWorktreeStatus(t *testing.T) {
 	s := newTestStore()
 	defer func() { _, _ = s.Close() }()
@@ -343,6 +364,23 @@ func TestStore_UpdateSettings(t *testing.T) {
 	assert.True(t, settings.LastCheck.Equal(now))
 }
 
+func TestStore_SetSetting(t *testing.T) {
+	s := newTestStore()
+	defer func() { _, _ = s.Close() }()
+
+	require.NoError(t, s.SetSetting("defaults.multiplexer", "tmux"))
+	assert.Equal(t, "tmux", s.GetDefaults().Multiplexer)
+	assert.True(t, s.HasPendingSaves())
+
+	// A change that makes the config invalid is rolled back
+	err := s.SetSetting("defaults.portRangeEnd", "100")
+	assert.ErrorContains(t, err, "invalid range")
+	assert.Equal(t, 3999, s.GetDefaults().PortRangeEnd)
+
+	require.NoError(t, s.UnsetSetting("defaults.multiplexer"))
+	assert.Empty(t, s.GetDefaults().Multiplexer)
+}
+
 func TestStore_SetWorktreePRs(t *testing.T) {
 	s := newTestStore()
 	defer func() { _, _ = s.Close() }()
@@ -362,3 +400,167 @@ func TestStore_SetWorktreePRs(t *testing.T) {
 	assert.Len(t, got, 2)
 	assert.Equal(t, "First PR", got[0].Title)
 }
+
+func TestStore_SetDatabaseSyncStatus(t *testing.T) {
+	s := newTestStore()
+	defer func() { _, _ = s.Close() }()
+
+	_ = s.AddProject("test", config.NewProject("/test", 1))
+	status := &config.DatabaseSyncStatus{Status: "syncing"}
+	assert.Error(t, s.SetDatabaseSyncStatus("test", status), "project without a database config")
+
+	require.NoError(t, s.SetDatabaseConfig("test", &config.DatabaseConfig{Source: "postgres://prod/db", SyncSchedule: "@daily"}))
+	require.NoError(t, s.SetDatabaseSyncStatus("test", status))
+	assert.True(t, s.HasPendingSaves())
+
+	project, _ := s.GetProject("test")
+	require.NotNil(t, project.Database.SyncStatus)
+	assert.Equal(t, "syncing", project.Database.SyncStatus.Status)
+	assert.Equal(t, "@daily", project.Database.SyncSchedule)
+
+	assert.Error(t, s.SetDatabaseSyncStatus("missing", status))
+}
+
+func TestStore_DatabaseSnapshots(t *testing.T) {
+	s := newTestStore()
+	defer func() { _, _ = s.Close() }()
+
+	_ = s.AddProject("test", config.NewProject("/test/path", 1))
+	_ = s.AddWorktree("test", "tokyo", config.NewWorktree("/test/wt", "main", false, []int{3100}))
+
+	first := config.DatabaseSnapshot{Name: "before", Method: "template", DatabaseName: "snap_a"}
+	require.NoError(t, s.AddDatabaseSnapshot("test", "tokyo", first))
+	require.NoError(t, s.AddDatabaseSnapshot("test", "tokyo", config.DatabaseSnapshot{Name: "after", Method: "pg_dump"}))
+	assert.True(t, s.HasPendingSaves())
+
+	// Re-using a name replaces the old snapshot and moves it to the end
+	require.NoError(t, s.AddDatabaseSnapshot("test", "tokyo", config.DatabaseSnapshot{Name: "before", Method: "template", DatabaseName: "snap_b"}))
+	wt, ok := s.GetWorktree("test", "tokyo")
+	require.True(t, ok)
+	require.Len(t, wt.DatabaseSnapshots, 2)
+	assert.Equal(t, "after", wt.DatabaseSnapshots[0].Name)
+	assert.Equal(t, "snap_b", wt.DatabaseSnapshots[1].DatabaseName)
+
+	// Returned worktrees are copies
+	wt.DatabaseSnapshots[0].Name = "mutated"
+	wt, _ = s.GetWorktree("test", "tokyo")
+	assert.Equal(t, "after", wt.DatabaseSnapshots[0].Name)
+
+	require.NoError(t, s.RemoveDatabaseSnapshot("test", "tokyo", "after"))
+	require.NoError(t, s.RemoveDatabaseSnapshot("test", "tokyo", "before"))
+	wt, _ = s.GetWorktree("test", "tokyo")
+	assert.Nil(t, wt.DatabaseSnapshots)
+
+	assert.Error(t, s.RemoveDatabaseSnapshot("test", "tokyo", "before"))
+	assert.Error(t, s.AddDatabaseSnapshot("test", "missing", first))
+	assert.Error(t, s.AddDatabaseSnapshot("missing", "tokyo", first))
+}
+
+func TestStore_SaveMergesConcurrentWriters(t *testing.T) {
+	t.Setenv("CONDUCTOR_CONFIG_DIR", t.TempDir())
+	require.NoError(t, config.Save(config.NewConfig()))
+
+	// Two processes load the same file
+	a, err := Load(WithDebounceTime(time.Millisecond))
+	require.NoError(t, err)
+	b, err := Load(WithDebounceTime(time.Millisecond))
+	require.NoError(t, err)
+
+	require.NoError(t, a.AddProject("alpha", config.NewProject("/alpha", 1)))
+	require.NoError(t, a.ForceSave())
+
+	// b never saw alpha; its save must not drop it
+	require.NoError(t, b.AddProject("beta", config.NewProject("/beta", 1)))
+	require.NoError(t, b.ForceSave())
+
+	_, err = a.Close()
+	require.NoError(t, err)
+	_, err = b.Close()
+	require.NoError(t, err)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Contains(t, cfg.Projects, "alpha")
+	assert.Contains(t, cfg.Projects, "beta")
+
+	// b's memory picked up alpha during the merge
+	assert.True(t, b.ProjectExists("alpha"))
+}
+
+func TestStore_SaveMovesPortsAllocatedByAnotherProcess(t *testing.T) {
+	t.Setenv("CONDUCTOR_CONFIG_DIR", t.TempDir())
+	cfg := config.NewConfig()
+	cfg.Projects["app"] = config.NewProject("/app", 2)
+	require.NoError(t, config.Save(cfg))
+
+	a, err := Load(WithDebounceTime(time.Millisecond))
+	require.NoError(t, err)
+	b, err := Load(WithDebounceTime(time.Millisecond))
+	require.NoError(t, err)
+
+	// Both processes hand out the same first block
+	portsA, err := a.AllocatePorts("app", "tokyo", 2)
+	require.NoError(t, err)
+	require.NoError(t, a.AddWorktree("app", "tokyo", config.NewWorktree("/wt/tokyo", "tokyo", false, portsA)))
+	require.NoError(t, a.ForceSave())
+
+	portsB, err := b.AllocatePorts("app", "paris", 2)
+	require.NoError(t, err)
+	require.Equal(t, portsA, portsB)
+	require.NoError(t, b.AddWorktree("app", "paris", config.NewWorktree("/wt/paris", "paris", false, portsB)))
+	require.NoError(t, b.ForceSave())
+
+	_, err = a.Close()
+	require.NoError(t, err)
+	_, err = b.Close()
+	require.NoError(t, err)
+
+	onDisk, err := config.Load()
+	require.NoError(t, err)
+	tokyo := onDisk.Projects["app"].Worktrees["tokyo"]
+	paris := onDisk.Projects["app"].Worktrees["paris"]
+	assert.Equal(t, portsA, tokyo.Ports, "the first process keeps its ports")
+	assert.Len(t, paris.Ports, 2)
+	for _, port := range paris.Ports {
+		assert.NotContains(t, tokyo.Ports, port)
+	}
+	assert.ElementsMatch(t, tokyo.Ports, onDisk.GetWorktreePorts("app", "tokyo"))
+	assert.ElementsMatch(t, paris.Ports, onDisk.GetWorktreePorts("app", "paris"))
+
+	// b's memory matches what it wrote
+	wt, ok := b.GetWorktree("app", "paris")
+	require.True(t, ok)
+	assert.Equal(t, paris.Ports, wt.Ports)
+
+	// ...and b reports the move once, so its caller can refresh the worktree
+	assert.Empty(t, a.TakePortReallocations())
+	assert.Equal(t, []PortReallocation{{Project: "app", Worktree: "paris", OldPorts: portsB, NewPorts: paris.Ports}}, b.TakePortReallocations())
+	assert.Empty(t, b.TakePortReallocations())
+}
+
+func TestStore_SaveRefusesWorktreeCreatedByAnotherProcess(t *testing.T) {
+	t.Setenv("CONDUCTOR_CONFIG_DIR", t.TempDir())
+	cfg := config.NewConfig()
+	cfg.Projects["app"] = config.NewProject("/app", 1)
+	require.NoError(t, config.Save(cfg))
+
+	a, err := Load(WithDebounceTime(time.Millisecond))
+	require.NoError(t, err)
+	b, err := Load(WithDebounceTime(time.Millisecond), WithMaxRetries(0))
+	require.NoError(t, err)
+
+	require.NoError(t, a.AddWorktree("app", "tokyo", config.NewWorktree("/wt/tokyo", "main", false, nil)))
+	require.NoError(t, a.ForceSave())
+
+	require.NoError(t, b.AddWorktree("app", "tokyo", config.NewWorktree("/wt/tokyo", "feature", false, nil)))
+	err = b.ForceSave()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "app/tokyo")
+
+	_, _ = a.Close()
+	_, _ = b.Close()
+
+	onDisk, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "main", onDisk.Projects["app"].Worktrees["tokyo"].Branch)
+}
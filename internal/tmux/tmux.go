@@ -198,8 +198,9 @@ func CreateCodingWindow(project, branch, worktreePath string, agent codingagent.
 }
 
 // CreateCodingWindowWithTask creates a new window inside the conductor tmux
-// session with two panes and pre-loads the agent with a task prompt.
-func CreateCodingWindowWithTask(project, branch, worktreePath, taskPrompt string, agent codingagent.Agent) error {
+// session with two panes and pre-loads the agent with a task prompt. It
+// returns the ID of the agent's pane.
+func CreateCodingWindowWithTask(project, branch, worktreePath, taskPrompt string, agent codingagent.Agent) (string, error) {
 	windowName := WindowName(project, branch)
 	windowTarget := fmt.Sprintf("%s:%s", SessionName, windowName)
 
@@ -212,7 +213,7 @@ func CreateCodingWindowWithTask(project, branch, worktreePath, taskPrompt string
 		"bash", "-c", devCmd)
 	devPaneIDBytes, err := cmd.Output()
 	if err != nil {
-		return fmt.Errorf("failed to create tmux window: %w", err)
+		return "", fmt.Errorf("failed to create tmux window: %w", err)
 	}
 	devPaneID := strings.TrimSpace(string(devPaneIDBytes))
 
@@ -222,15 +223,16 @@ func CreateCodingWindowWithTask(project, branch, worktreePath, taskPrompt string
 
 	if agent.UsesContextFile() {
 		if err := codingagent.WriteContextFile(worktreePath, systemPrompt); err != nil {
-			return fmt.Errorf("failed to write agent context file: %w", err)
+			return "", fmt.Errorf("failed to write agent context file: %w", err)
 		}
 	}
 
 	agentArgs := agent.TaskArgs(systemPrompt, taskPrompt)
-	splitArgs := []string{"split-window", "-t", windowTarget, "-hb", "-c", worktreePath}
+	splitArgs := []string{"split-window", "-t", windowTarget, "-hb", "-c", worktreePath, "-P", "-F", "#{pane_id}"}
 	splitArgs = append(splitArgs, agentArgs...)
-	if err := exec.Command("tmux", splitArgs...).Run(); err != nil {
-		return fmt.Errorf("failed to split window: %w", err)
+	agentPaneIDBytes, err := exec.Command("tmux", splitArgs...).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to split window: %w", err)
 	}
 
 	paneLabel := branch + " - " + agent.PaneLabel() + " (agent)"
@@ -240,7 +242,7 @@ func CreateCodingWindowWithTask(project, branch, worktreePath, taskPrompt string
 	_ = exec.Command("tmux", "set-option", "-t", windowTarget, "automatic-rename", "off").Run()
 	_ = exec.Command("tmux", "set-option", "-t", windowTarget, "allow-rename", "off").Run()
 
-	return nil
+	return strings.TrimSpace(string(agentPaneIDBytes)), nil
 }
 
 // WindowExists checks if a worktree window exists in the conductor session.
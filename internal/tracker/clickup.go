@@ -0,0 +1,135 @@
+package tracker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hammashamzah/conductor/internal/clickup"
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/tunnel"
+)
+
+// ClickUp is the ClickUp provider. Events come from the ClickUp webhook when
+// cloudflared is available, with polling as fallback and heartbeat.
+type ClickUp struct {
+	client  *clickup.Client
+	manager *clickup.Manager
+}
+
+// NewClickUp creates a ClickUp provider. The manager records webhook
+// registration in cfg, which the caller saves when the daemon stops.
+func NewClickUp(cfg *config.ClickUpConfig, tunnelMgr *tunnel.Manager) *ClickUp {
+	manager := clickup.NewManager(cfg, tunnelMgr)
+	return &ClickUp{client: manager.Client(), manager: manager}
+}
+
+// Name returns config.TrackerClickUp
+func (c *ClickUp) Name() string {
+	return config.TrackerClickUp
+}
+
+// GetTask fetches a task by ID
+func (c *ClickUp) GetTask(taskID string) (*Task, error) {
+	task, err := c.client.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	return fromClickUpTask(task), nil
+}
+
+// ListTasks returns the tasks of a ClickUp list with the given status
+func (c *ClickUp) ListTasks(listID, status string) ([]Task, error) {
+	tasks, err := c.client.GetFilteredTasks(listID, []string{status})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Task, len(tasks))
+	for i := range tasks {
+		result[i] = *fromClickUpTask(&tasks[i])
+	}
+	return result, nil
+}
+
+// UpdateStatus moves a task to a ClickUp status
+func (c *ClickUp) UpdateStatus(taskID, status string) error {
+	return c.client.UpdateTaskStatus(taskID, status)
+}
+
+// AddComment adds a comment to a task
+func (c *ClickUp) AddComment(taskID, text string) error {
+	return c.client.AddTaskComment(taskID, text)
+}
+
+// Start listens to the lists. Webhook events dispatch on any list's trigger
+// status; polling uses the global one (defaults.clickup.triggerStatus).
+func (c *ClickUp) Start(watches []Watch, handler func(TaskEvent)) error {
+	listIDs := make([]string, len(watches))
+	for i, w := range watches {
+		listIDs[i] = w.ListID
+		c.manager.AddTriggerStatuses(w.TriggerStatus)
+	}
+	c.manager.SetEventHandler(func(event clickup.TaskEvent) {
+		handler(TaskEvent{
+			Provider:  config.TrackerClickUp,
+			TaskID:    event.TaskID,
+			Task:      fromClickUpTask(event.Task),
+			NewStatus: event.NewStatus,
+			OldStatus: event.OldStatus,
+			Timestamp: event.Timestamp,
+		})
+	})
+	return c.manager.Start(listIDs)
+}
+
+// Stop deregisters the webhook and stops polling
+func (c *ClickUp) Stop() error {
+	return c.manager.Stop()
+}
+
+// Mode returns "webhook", "polling", or "inactive"
+func (c *ClickUp) Mode() string {
+	return c.manager.Mode()
+}
+
+// fromClickUpTask converts a ClickUp API task
+func fromClickUpTask(t *clickup.Task) *Task {
+	if t == nil {
+		return nil
+	}
+	task := &Task{
+		Provider:    config.TrackerClickUp,
+		ID:          t.ID,
+		Key:         t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Status:      strings.ToLower(t.Status.Status),
+		URL:         t.URL,
+		ListID:      t.List.ID,
+	}
+	if task.URL == "" {
+		task.URL = fmt.Sprintf("https://app.clickup.com/t/%s", t.ID)
+	}
+	if t.Priority != nil {
+		task.Priority = clickUpPriority(t.Priority.Priority)
+	}
+	for _, dep := range t.Dependencies {
+		task.Dependencies = append(task.Dependencies, dep.DependsOn)
+	}
+	return task
+}
+
+// clickUpPriority converts ClickUp priority ID to human-readable label
+func clickUpPriority(id string) string {
+	switch id {
+	case "1":
+		return "urgent"
+	case "2":
+		return "high"
+	case "3":
+		return "normal"
+	case "4":
+		return "low"
+	default:
+		return ""
+	}
+}
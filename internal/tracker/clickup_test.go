@@ -0,0 +1,30 @@
+package tracker
+
+import (
+	"testing"
+
+	"github.com/hammashamzah/conductor/internal/clickup"
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromClickUpTask(t *testing.T) {
+	task := fromClickUpTask(&clickup.Task{
+		ID:           "86abc",
+		Name:         "Fix login",
+		Status:       clickup.TaskStatus{Status: "In Progress"},
+		List:         clickup.TaskList{ID: "901"},
+		Priority:     &clickup.TaskPriority{Priority: "2"},
+		Dependencies: []clickup.Dependency{{TaskID: "86abc", DependsOn: "86xyz"}},
+	})
+
+	assert.Equal(t, config.TrackerClickUp, task.Provider)
+	assert.Equal(t, "86abc", task.Key)
+	assert.Equal(t, "in progress", task.Status)
+	assert.Equal(t, "901", task.ListID)
+	assert.Equal(t, "https://app.clickup.com/t/86abc", task.URL)
+	assert.Equal(t, "high", task.Priority)
+	assert.Equal(t, []string{"86xyz"}, task.Dependencies)
+
+	assert.Nil(t, fromClickUpTask(nil))
+}
@@ -0,0 +1,344 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hammashamzah/conductor/internal/config"
+)
+
+// githubIssueFields are the fields requested from gh issue list/view
+const githubIssueFields = "number,title,body,url,state,labels"
+
+// githubClosed is the status of a closed issue. Moving a task to it (or to
+// "done") closes the issue; any other status is a label.
+const githubClosed = "closed"
+
+// runGH runs the gh CLI and returns its stdout (replaced in tests)
+var runGH = func(args ...string) ([]byte, error) {
+	cmd := exec.Command("gh", args...)
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return out, err
+}
+
+// ghIssue is the JSON output of gh issue list/view
+type ghIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	URL    string `json:"url"`
+	State  string `json:"state"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// GitHubIssues is the GitHub Issues provider, using the gh CLI. Labels stand
+// in for statuses: a task enters the trigger status when its issue gets the
+// trigger label. GitHub can't deliver webhooks to a laptop without a public
+// endpoint, so events come from polling.
+type GitHubIssues struct {
+	interval time.Duration
+
+	mu sync.Mutex
+	// statusLabels are the labels that stand for a status; moving a task to
+	// a status removes the others
+	statusLabels map[string]bool
+	// labeled is the set of issue IDs carrying each watch's trigger label
+	labeled map[Watch]map[string]bool
+	cancel  context.CancelFunc
+}
+
+// NewGitHubIssues creates a GitHub Issues provider that polls every interval.
+// statusLabels are the labels projects use as statuses (trigger, ready, review).
+func NewGitHubIssues(interval time.Duration, statusLabels ...string) *GitHubIssues {
+	if interval == 0 {
+		interval = 60 * time.Second
+	}
+	g := &GitHubIssues{
+		interval:     interval,
+		statusLabels: make(map[string]bool),
+		labeled:      make(map[Watch]map[string]bool),
+	}
+	for _, label := range statusLabels {
+		g.statusLabels[strings.ToLower(label)] = true
+	}
+	return g
+}
+
+// Name returns config.TrackerGitHub
+func (g *GitHubIssues) Name() string {
+	return config.TrackerGitHub
+}
+
+// GetTask fetches an issue by its "owner/repo#number" ID
+func (g *GitHubIssues) GetTask(taskID string) (*Task, error) {
+	repo, number, err := parseIssueID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := viewIssue(repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return g.fromIssue(repo, issue), nil
+}
+
+// ListTasks returns the repository's open issues with the status label, or
+// its closed issues for "closed"
+func (g *GitHubIssues) ListTasks(repo, status string) ([]Task, error) {
+	args := []string{"issue", "list", "--repo", repo, "--limit", "100", "--json", githubIssueFields}
+	if isClosedStatus(status) {
+		args = append(args, "--state", "closed")
+	} else {
+		args = append(args, "--state", "open", "--label", status)
+	}
+	out, err := runGH(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues in %s: %w", repo, err)
+	}
+	var issues []ghIssue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, fmt.Errorf("failed to parse issues: %w", err)
+	}
+
+	tasks := make([]Task, len(issues))
+	for i, issue := range issues {
+		tasks[i] = *g.fromIssue(repo, issue)
+	}
+	return tasks, nil
+}
+
+// UpdateStatus closes the issue for "closed" or "done"; otherwise it swaps
+// the issue's status labels for the new one, reopening a closed issue
+func (g *GitHubIssues) UpdateStatus(taskID, status string) error {
+	repo, number, err := parseIssueID(taskID)
+	if err != nil {
+		return err
+	}
+	if isClosedStatus(status) {
+		if _, err := runGH("issue", "close", number, "--repo", repo); err != nil {
+			return fmt.Errorf("failed to close issue %s: %w", taskID, err)
+		}
+		return nil
+	}
+
+	issue, err := viewIssue(repo, number)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(issue.State, githubClosed) {
+		if _, err := runGH("issue", "reopen", number, "--repo", repo); err != nil {
+			return fmt.Errorf("failed to reopen issue %s: %w", taskID, err)
+		}
+	}
+
+	label := strings.ToLower(status)
+	args := []string{"issue", "edit", number, "--repo", repo, "--add-label", status}
+	g.mu.Lock()
+	g.statusLabels[label] = true
+	for _, l := range issue.Labels {
+		if name := strings.ToLower(l.Name); name != label && g.statusLabels[name] {
+			args = append(args, "--remove-label", l.Name)
+		}
+	}
+	g.mu.Unlock()
+	if _, err := runGH(args...); err != nil {
+		return fmt.Errorf("failed to label issue %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// AddComment comments on the issue
+func (g *GitHubIssues) AddComment(taskID, text string) error {
+	repo, number, err := parseIssueID(taskID)
+	if err != nil {
+		return err
+	}
+	if _, err := runGH("issue", "comment", number, "--repo", repo, "--body", text); err != nil {
+		return fmt.Errorf("failed to comment on issue %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// Start polls each watched repository for issues that newly carry the
+// trigger label. Issues labeled before Start are not reported.
+func (g *GitHubIssues) Start(watches []Watch, handler func(TaskEvent)) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	g.mu.Lock()
+	g.cancel = cancel
+	for _, w := range watches {
+		g.statusLabels[strings.ToLower(w.TriggerStatus)] = true
+	}
+	g.mu.Unlock()
+
+	// Seed labeled state without emitting events
+	for _, w := range watches {
+		g.poll(w, nil)
+	}
+
+	go func() {
+		ticker := time.NewTicker(g.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, w := range watches {
+					g.poll(w, handler)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops polling
+func (g *GitHubIssues) Stop() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cancel != nil {
+		g.cancel()
+		g.cancel = nil
+	}
+	return nil
+}
+
+// Mode returns "polling" while started, "inactive" otherwise
+func (g *GitHubIssues) Mode() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cancel != nil {
+		return "polling"
+	}
+	return "inactive"
+}
+
+// poll lists the watch's labeled issues and reports those that weren't
+// labeled last time. A nil handler only records the current state.
+func (g *GitHubIssues) poll(w Watch, handler func(TaskEvent)) {
+	tasks, err := g.ListTasks(w.ListID, w.TriggerStatus)
+	if err != nil {
+		log.Printf("github poller error for %s: %v", w.ListID, err)
+		return
+	}
+
+	current := make(map[string]bool, len(tasks))
+	for _, t := range tasks {
+		current[t.ID] = true
+	}
+	g.mu.Lock()
+	previous := g.labeled[w]
+	g.labeled[w] = current
+	g.mu.Unlock()
+
+	if handler == nil {
+		return
+	}
+	for i := range tasks {
+		if previous[tasks[i].ID] {
+			continue
+		}
+		task := tasks[i]
+		handler(TaskEvent{
+			Provider:  config.TrackerGitHub,
+			TaskID:    task.ID,
+			Task:      &task,
+			NewStatus: strings.ToLower(w.TriggerStatus),
+			Timestamp: time.Now(),
+		})
+	}
+}
+
+// fromIssue converts a gh issue. The status is "closed" for closed issues,
+// else the first status label, else "open".
+func (g *GitHubIssues) fromIssue(repo string, issue ghIssue) *Task {
+	task := &Task{
+		Provider:    config.TrackerGitHub,
+		ID:          fmt.Sprintf("%s#%d", repo, issue.Number),
+		Key:         fmt.Sprintf("gh-%d", issue.Number),
+		Name:        issue.Title,
+		Description: issue.Body,
+		Status:      "open",
+		URL:         issue.URL,
+		ListID:      repo,
+	}
+	if task.URL == "" {
+		task.URL = fmt.Sprintf("https://github.com/%s/issues/%d", repo, issue.Number)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, l := range issue.Labels {
+		name := strings.ToLower(l.Name)
+		if task.Priority == "" {
+			task.Priority = labelPriority(name)
+		}
+		if task.Status == "open" && g.statusLabels[name] {
+			task.Status = name
+		}
+	}
+	if strings.EqualFold(issue.State, githubClosed) {
+		task.Status = githubClosed
+	}
+	return task
+}
+
+// viewIssue fetches one issue
+func viewIssue(repo, number string) (ghIssue, error) {
+	var issue ghIssue
+	out, err := runGH("issue", "view", number, "--repo", repo, "--json", githubIssueFields)
+	if err != nil {
+		return issue, fmt.Errorf("failed to get issue %s#%s: %w", repo, number, err)
+	}
+	if err := json.Unmarshal(out, &issue); err != nil {
+		return issue, fmt.Errorf("failed to parse issue %s#%s: %w", repo, number, err)
+	}
+	return issue, nil
+}
+
+// parseIssueID splits "owner/repo#123" into the repository and issue number
+func parseIssueID(taskID string) (repo, number string, err error) {
+	repo, number, ok := strings.Cut(taskID, "#")
+	if !ok || !strings.Contains(repo, "/") {
+		return "", "", fmt.Errorf("invalid GitHub issue ID %q (expected owner/repo#number)", taskID)
+	}
+	if _, err := strconv.Atoi(number); err != nil {
+		return "", "", fmt.Errorf("invalid GitHub issue ID %q (expected owner/repo#number)", taskID)
+	}
+	return repo, number, nil
+}
+
+// isClosedStatus reports whether moving an issue to status closes it
+func isClosedStatus(status string) bool {
+	s := strings.ToLower(status)
+	return s == githubClosed || s == "done"
+}
+
+// labelPriority reads a priority label ("urgent", "priority: high", "P1")
+func labelPriority(label string) string {
+	label = strings.TrimPrefix(label, "priority:")
+	label = strings.TrimPrefix(label, "priority/")
+	switch strings.TrimSpace(label) {
+	case "urgent", "critical", "p0":
+		return "urgent"
+	case "high", "p1":
+		return "high"
+	case "normal", "medium", "p2":
+		return "normal"
+	case "low", "p3":
+		return "low"
+	}
+	return ""
+}
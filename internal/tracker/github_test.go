@@ -0,0 +1,170 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGH stands in for the gh CLI over an in-memory set of issues
+type fakeGH struct {
+	issues map[int]*fakeIssue
+	calls  []string
+}
+
+type fakeIssue struct {
+	title  string
+	closed bool
+	labels []string
+}
+
+func (f *fakeGH) json(number int) map[string]any {
+	issue := f.issues[number]
+	labels := []map[string]string{}
+	for _, l := range issue.labels {
+		labels = append(labels, map[string]string{"name": l})
+	}
+	state := "OPEN"
+	if issue.closed {
+		state = "CLOSED"
+	}
+	return map[string]any{"number": number, "title": issue.title, "state": state, "labels": labels,
+		"url": fmt.Sprintf("https://github.com/acme/web/issues/%d", number)}
+}
+
+func (f *fakeGH) run(args ...string) ([]byte, error) {
+	f.calls = append(f.calls, strings.Join(args, " "))
+	flag := func(name string) []string {
+		var values []string
+		for i := 0; i < len(args)-1; i++ {
+			if args[i] == name {
+				values = append(values, args[i+1])
+			}
+		}
+		return values
+	}
+	number := 0
+	if len(args) > 2 {
+		_, _ = fmt.Sscan(args[2], &number)
+	}
+
+	switch args[0] + " " + args[1] {
+	case "issue list":
+		label := flag("--label")
+		var out []map[string]any
+		for n := range f.issues {
+			issue := f.issues[n]
+			if issue.closed || (len(label) > 0 && !contains(issue.labels, label[0])) {
+				continue
+			}
+			out = append(out, f.json(n))
+		}
+		return json.Marshal(out)
+	case "issue view":
+		return json.Marshal(f.json(number))
+	case "issue edit":
+		issue := f.issues[number]
+		var kept []string
+		for _, l := range issue.labels {
+			if !contains(flag("--remove-label"), l) {
+				kept = append(kept, l)
+			}
+		}
+		issue.labels = append(kept, flag("--add-label")...)
+	case "issue close":
+		f.issues[number].closed = true
+	case "issue reopen":
+		f.issues[number].closed = false
+	}
+	return nil, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func stubGH(t *testing.T, f *fakeGH) {
+	t.Helper()
+	orig := runGH
+	runGH = f.run
+	t.Cleanup(func() { runGH = orig })
+}
+
+func TestGitHubIssues_Tasks(t *testing.T) {
+	gh := &fakeGH{issues: map[int]*fakeIssue{
+		7: {title: "Fix login", labels: []string{"bug", "ready", "priority: high"}},
+		8: {title: "Add export", labels: []string{"conductor"}},
+	}}
+	stubGH(t, gh)
+	p := NewGitHubIssues(0, "conductor", "ready", "in review")
+
+	tasks, err := p.ListTasks("acme/web", "ready")
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	task := tasks[0]
+	assert.Equal(t, config.TrackerGitHub, task.Provider)
+	assert.Equal(t, "acme/web#7", task.ID)
+	assert.Equal(t, "gh-7", task.Key)
+	assert.Equal(t, "acme/web", task.ListID)
+	assert.Equal(t, "ready", task.Status)
+	assert.Equal(t, "high", task.Priority)
+
+	// Moving to a status swaps status labels and keeps the rest
+	require.NoError(t, p.UpdateStatus("acme/web#7", "conductor"))
+	assert.Equal(t, []string{"bug", "priority: high", "conductor"}, gh.issues[7].labels)
+
+	require.NoError(t, p.UpdateStatus("acme/web#7", "done"))
+	assert.True(t, gh.issues[7].closed)
+	got, err := p.GetTask("acme/web#7")
+	require.NoError(t, err)
+	assert.Equal(t, "closed", got.Status)
+
+	// A label status reopens a closed issue
+	require.NoError(t, p.UpdateStatus("acme/web#7", "in review"))
+	assert.False(t, gh.issues[7].closed)
+	assert.Equal(t, []string{"bug", "priority: high", "in review"}, gh.issues[7].labels)
+
+	_, err = p.GetTask("86abc")
+	assert.Error(t, err, "ClickUp IDs are not issue IDs")
+}
+
+func TestGitHubIssues_PollReportsNewlyLabeledIssues(t *testing.T) {
+	gh := &fakeGH{issues: map[int]*fakeIssue{
+		1: {title: "Already labeled", labels: []string{"conductor"}},
+		2: {title: "Not yet", labels: []string{"bug"}},
+	}}
+	stubGH(t, gh)
+	p := NewGitHubIssues(0)
+	w := Watch{ListID: "acme/web", TriggerStatus: "conductor"}
+
+	var events []TaskEvent
+	handler := func(e TaskEvent) { events = append(events, e) }
+
+	p.poll(w, nil) // seed
+	p.poll(w, handler)
+	assert.Empty(t, events, "issues labeled before the daemon started are not reported")
+
+	gh.issues[2].labels = append(gh.issues[2].labels, "conductor")
+	p.poll(w, handler)
+	require.Len(t, events, 1)
+	assert.Equal(t, "acme/web#2", events[0].TaskID)
+	assert.Equal(t, config.TrackerGitHub, events[0].Provider)
+	assert.Equal(t, "Not yet", events[0].Task.Name)
+
+	// Removing and re-adding the label triggers again
+	gh.issues[2].labels = []string{"bug"}
+	p.poll(w, handler)
+	gh.issues[2].labels = []string{"conductor"}
+	p.poll(w, handler)
+	assert.Len(t, events, 2)
+}
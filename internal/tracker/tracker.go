@@ -0,0 +1,68 @@
+// Package tracker is the agent daemon's view of an issue tracker: fetching and
+// listing tasks, moving them between statuses, commenting, and a source of
+// events for tasks entering the trigger status. ClickUp and GitHub Issues are
+// the implementations.
+package tracker
+
+import "time"
+
+// Task is an issue-tracker task
+type Task struct {
+	// Provider is the tracker the task lives in (config.TrackerClickUp, config.TrackerGitHub)
+	Provider string `json:"provider"`
+	// ID identifies the task to its tracker
+	ID string `json:"id"`
+	// Key is a short, branch-safe form of the ID ("86abc", "gh-123")
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Status is the task's current status, lowercased
+	Status string `json:"status,omitempty"`
+	URL    string `json:"url,omitempty"`
+	// ListID is the ClickUp list or GitHub owner/repo the task belongs to
+	ListID string `json:"listId"`
+	// Priority is "urgent", "high", "normal", "low", or ""
+	Priority string `json:"priority,omitempty"`
+	// Dependencies are the IDs of tasks this one waits on
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+// TaskEvent is a task entering a watched list's trigger status
+type TaskEvent struct {
+	Provider  string
+	TaskID    string
+	Task      *Task
+	NewStatus string
+	OldStatus string
+	Timestamp time.Time
+}
+
+// Watch is a list the agent listens to, and the status that triggers it
+type Watch struct {
+	ListID        string
+	TriggerStatus string
+}
+
+// EventSource delivers task status changes, by webhook or by polling
+type EventSource interface {
+	// Start watches the lists and calls handler for each task that enters
+	// its list's trigger status. Events arrive deduplicated, with Task set.
+	Start(watches []Watch, handler func(TaskEvent)) error
+	// Stop stops watching and releases webhooks and tunnels
+	Stop() error
+	// Mode returns "webhook", "polling", or "inactive"
+	Mode() string
+}
+
+// Provider is an issue tracker the agent daemon takes tasks from
+type Provider interface {
+	// Name is the tracker's config name (config.TrackerClickUp, config.TrackerGitHub)
+	Name() string
+	GetTask(taskID string) (*Task, error)
+	// ListTasks returns the tasks of a list that have the given status
+	ListTasks(listID, status string) ([]Task, error)
+	UpdateStatus(taskID, status string) error
+	AddComment(taskID, text string) error
+
+	EventSource
+}
@@ -34,6 +34,10 @@ type KeyMap struct {
 	DatabaseList            key.Binding
 	DatabaseReinstantiate   key.Binding
 	DatabaseMigrationStatus key.Binding
+	DatabaseSnapshot        key.Binding
+	DatabaseRestore         key.Binding
+	DatabaseDiff            key.Binding
+	DatabaseHistory         key.Binding
 	DatabaseLogs            key.Binding
 	ApplyUpdate             key.Binding
 }
@@ -161,6 +165,22 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("B"),
 			key.WithHelp("B", "migration status"),
 		),
+		DatabaseSnapshot: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "snapshot DB"),
+		),
+		DatabaseRestore: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "restore DB snapshot"),
+		),
+		DatabaseDiff: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "schema diff vs golden"),
+		),
+		DatabaseHistory: key.NewBinding(
+			key.WithKeys("h"),
+			key.WithHelp("h", "DB sync history"),
+		),
 		DatabaseLogs: key.NewBinding(
 			key.WithKeys("l"),
 			key.WithHelp("l", "sync logs"),
@@ -184,7 +204,7 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 		{k.Create, k.Archive, k.Delete, k.Retry},
 		{k.Open, k.OpenCursor, k.OpenVSCode, k.OpenTerminal},
 		{k.Filter, k.Refresh, k.Ports, k.MergeReqs, k.AllPRs, k.AutoSetupClaude},
-		{k.Tunnel, k.CopyURL, k.DatabaseList, k.DatabaseReinstantiate, k.DatabaseMigrationStatus},
+		{k.Tunnel, k.CopyURL, k.DatabaseList, k.DatabaseReinstantiate, k.DatabaseMigrationStatus, k.DatabaseSnapshot, k.DatabaseRestore, k.DatabaseDiff, k.DatabaseHistory},
 		{k.Help, k.Quit},
 	}
 }
@@ -224,7 +244,7 @@ func (k KeyMap) KeyGroups() []KeyGroup {
 		},
 		{
 			Name: "Database",
-			Keys: []key.Binding{k.DatabaseList, k.DatabaseReinstantiate, k.DatabaseMigrationStatus, k.DatabaseLogs},
+			Keys: []key.Binding{k.DatabaseList, k.DatabaseReinstantiate, k.DatabaseMigrationStatus, k.DatabaseSnapshot, k.DatabaseRestore, k.DatabaseDiff, k.DatabaseHistory, k.DatabaseLogs},
 		},
 		{
 			Name: "Utility",
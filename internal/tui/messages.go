@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/database"
 	"github.com/hammashamzah/conductor/internal/session"
 	"github.com/hammashamzah/conductor/internal/workspace"
 )
@@ -42,6 +43,14 @@ type WorktreeCreatedMsg struct {
 	Err          error
 }
 
+// WorktreeRestoredMsg indicates an archived worktree was recreated (async)
+type WorktreeRestoredMsg struct {
+	ProjectName  string
+	WorktreeName string
+	Result       *workspace.RestoreResult
+	Err          error
+}
+
 // WorktreeArchivedMsg indicates a worktree was archived
 type WorktreeArchivedMsg struct {
 	ProjectName  string
@@ -267,6 +276,15 @@ const ViewDatabaseLogs View = iota + 601
 // ViewConfirmDbReinstantiate is the confirmation dialog for database reinstantiation
 const ViewConfirmDbReinstantiate View = iota + 602
 
+// ViewConfirmDbRestore is the confirmation dialog for restoring a database snapshot
+const ViewConfirmDbRestore View = iota + 603
+
+// ViewDatabaseDiff is the view for a worktree database's schema diff
+const ViewDatabaseDiff View = iota + 604
+
+// ViewDatabaseHistory is the view for a project's golden copy sync history
+const ViewDatabaseHistory View = iota + 605
+
 // ViewAgentPicker is the modal for choosing which coding agent to open a worktree with
 const ViewAgentPicker View = iota + 700
 
@@ -285,6 +303,41 @@ type DatabaseReinstantiateCompletedMsg struct {
 	Err          error
 }
 
+// DatabaseSnapshotCompletedMsg indicates a worktree database snapshot has been taken
+type DatabaseSnapshotCompletedMsg struct {
+	ProjectName  string
+	WorktreeName string
+	SnapshotName string
+	Method       string
+	Err          error
+}
+
+// DatabaseRestoreCompletedMsg indicates a worktree database was restored from a snapshot
+type DatabaseRestoreCompletedMsg struct {
+	ProjectName  string
+	WorktreeName string
+	DatabaseName string
+	SnapshotName string
+	Err          error
+}
+
+// DatabaseDiffMsg contains the schema diff between a worktree database and the golden copy
+type DatabaseDiffMsg struct {
+	ProjectName  string
+	WorktreeName string
+	Lines        []string // Human-readable report
+	SQLPatch     string   // SQL turning the golden schema into the worktree schema
+	HasChanges   bool
+	Err          error
+}
+
+// DatabaseHistoryMsg contains a project's golden copy sync history, newest first
+type DatabaseHistoryMsg struct {
+	ProjectName string
+	Entries     []database.SyncHistoryEntry
+	Err         error
+}
+
 // DatabaseMigrationStatusMsg contains migration status for a worktree
 type DatabaseMigrationStatusMsg struct {
 	ProjectName       string
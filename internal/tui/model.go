@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"os"
 	"sort"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/database"
 	"github.com/hammashamzah/conductor/internal/mux"
 	"github.com/hammashamzah/conductor/internal/session"
 	"github.com/hammashamzah/conductor/internal/store"
@@ -104,6 +106,10 @@ type Model struct {
 	deleteTarget     string
 	deleteTargetType string // "project" or "worktree"
 
+	// Archive safety: unsaved work found in the worktree being archived
+	archiveRisk    *workspace.ArchiveRisk
+	archiveOptions workspace.ArchiveOptions
+
 	// Status message with history and timeout
 	statusMessage string
 	statusIsError bool
@@ -185,6 +191,26 @@ type Model struct {
 	dbReinstantiateWorktree string // Worktree name for reinstantiate
 	dbReinstantiateDBName   string // Database name for reinstantiate
 
+	// Database schema diff view state
+	dbDiffProject  string   // Project of the diffed worktree
+	dbDiffWorktree string   // Worktree whose database was diffed
+	dbDiffLines    []string // Report lines
+	dbDiffSQL      string   // SQL patch
+	dbDiffShowSQL  bool     // Show the SQL patch instead of the report
+	dbDiffScroll   int      // Scroll offset
+
+	// Database sync history view state
+	dbHistoryProject string                      // Project whose history is shown
+	dbHistory        []database.SyncHistoryEntry // Entries, newest first
+	dbHistoryCursor  int                         // Selected entry
+	dbHistoryOffset  int                         // First visible entry
+
+	// Database snapshot restore confirmation state
+	dbRestoreProject  string                   // Project name for restore
+	dbRestoreWorktree string                   // Worktree name for restore
+	dbRestoreDBName   string                   // Database name for restore
+	dbRestoreSnapshot *config.DatabaseSnapshot // Snapshot to restore
+
 	// Agent picker state
 	agentPickerCursor int    // 0 = Claude Code, 1 = OpenCode, 2 = Codex
 	agentPickerTarget string // worktree name being opened
@@ -199,7 +225,8 @@ type Model struct {
 	configModTime    time.Time // Last known modification time of config file
 	lastConfigReload time.Time // For debouncing rapid reloads
 
-	sessionTracker *session.Tracker // scans multiplexer panes for agents
+	sessionTracker *session.Tracker   // scans multiplexer panes for agents
+	stopScheduler  context.CancelFunc // stops the database sync scheduler
 }
 
 // NewModel creates a new TUI model
@@ -308,6 +335,21 @@ func (m *Model) StopSessionTracker() {
 	}
 }
 
+// StartSyncScheduler runs scheduled golden copy syncs (database.syncSchedule)
+// and database GC sweeps (defaults.databaseGcSchedule) while the TUI is open
+func (m *Model) StartSyncScheduler() {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.stopScheduler = cancel
+	go database.NewScheduler(m.store, nil).Start(ctx)
+}
+
+// StopSyncScheduler stops the sync scheduler, cancelling any running sync
+func (m *Model) StopSyncScheduler() {
+	if m.stopScheduler != nil {
+		m.stopScheduler()
+	}
+}
+
 // SetPendingRestore sets session state to verify on startup (after update restart)
 func (m *Model) SetPendingRestore(state *mux.SessionState) {
 	m.pendingRestore = state
@@ -20,6 +20,7 @@ import (
 	"github.com/hammashamzah/conductor/internal/github"
 	"github.com/hammashamzah/conductor/internal/mux"
 	"github.com/hammashamzah/conductor/internal/opener"
+	"github.com/hammashamzah/conductor/internal/secrets"
 	"github.com/hammashamzah/conductor/internal/tui/ipc"
 	"github.com/hammashamzah/conductor/internal/updater"
 	"github.com/hammashamzah/conductor/internal/workspace"
@@ -58,30 +59,22 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Git worktree created, now run setup
 		m.setStatus("Created "+msg.WorktreeName+", running setup...", false)
 		_ = m.store.SetWorktreeStatus(msg.ProjectName, msg.WorktreeName, config.SetupStatusRunning)
-		projectName := msg.ProjectName
-		worktreeName := msg.WorktreeName
-		return m, func() tea.Msg {
-			done := make(chan SetupCompleteMsg, 1)
-			_ = m.wsManager.RunSetupAsync(projectName, worktreeName, func(success bool, setupErr error) {
-				done <- SetupCompleteMsg{
-					ProjectName:  projectName,
-					WorktreeName: worktreeName,
-					Success:      success,
-					Err:          setupErr,
-				}
-			})
-			select {
-			case msg := <-done:
-				return msg
-			case <-time.After(35 * time.Minute):
-				return SetupCompleteMsg{
-					ProjectName:  projectName,
-					WorktreeName: worktreeName,
-					Success:      false,
-					Err:          fmt.Errorf("setup timed out"),
-				}
-			}
+		return m, m.runSetupCmd(msg.ProjectName, msg.WorktreeName)
+
+	case WorktreeRestoredMsg:
+		m.buildArchivedWorktreesList()
+		m.refreshWorktreeList()
+		if msg.Err != nil {
+			m.setStatus("Failed to restore worktree: "+msg.Err.Error(), true)
+			return m, nil
 		}
+		status := "Restored " + msg.WorktreeName
+		if msg.Result != nil && msg.Result.DatabaseRestored {
+			status += " (database restored)"
+		}
+		m.setStatus(status+", running setup...", false)
+		_ = m.store.SetWorktreeStatus(msg.ProjectName, msg.WorktreeName, config.SetupStatusRunning)
+		return m, m.runSetupCmd(msg.ProjectName, msg.WorktreeName)
 
 	case SetupCompleteMsg:
 		m.refreshWorktreeList()
@@ -264,6 +257,65 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.setStatus("Database reinstantiate failed: "+msg.Err.Error(), true)
 		} else {
 			m.setStatus(fmt.Sprintf("Database %s reinstantiated successfully", msg.DatabaseName), false)
+			return m, m.refreshEnvFileCmd(msg.ProjectName, msg.WorktreeName)
+		}
+		return m, nil
+
+	case DatabaseSnapshotCompletedMsg:
+		if msg.Err != nil {
+			m.setStatus("Database snapshot failed: "+msg.Err.Error(), true)
+		} else {
+			m.setStatus(fmt.Sprintf("Snapshot %s saved for %s (%s) - press u to restore", msg.SnapshotName, msg.WorktreeName, msg.Method), false)
+		}
+		return m, nil
+
+	case DatabaseRestoreCompletedMsg:
+		if msg.Err != nil {
+			m.setStatus("Database restore failed: "+msg.Err.Error(), true)
+		} else {
+			m.setStatus(fmt.Sprintf("Database %s restored from snapshot %s", msg.DatabaseName, msg.SnapshotName), false)
+			return m, m.refreshEnvFileCmd(msg.ProjectName, msg.WorktreeName)
+		}
+		return m, nil
+
+	case DatabaseDiffMsg:
+		if msg.Err != nil {
+			m.setStatus("Schema diff failed: "+msg.Err.Error(), true)
+			return m, nil
+		}
+		if !msg.HasChanges {
+			m.setStatus(fmt.Sprintf("%s: schema matches golden", msg.WorktreeName), false)
+			return m, nil
+		}
+		m.setStatus(fmt.Sprintf("%s: schema differs from golden", msg.WorktreeName), false)
+		m.dbDiffProject = msg.ProjectName
+		m.dbDiffWorktree = msg.WorktreeName
+		m.dbDiffLines = msg.Lines
+		m.dbDiffSQL = msg.SQLPatch
+		m.dbDiffShowSQL = false
+		m.dbDiffScroll = 0
+		if m.currentView == ViewWorktrees {
+			m.prevView = m.currentView
+			m.currentView = ViewDatabaseDiff
+		}
+		return m, nil
+
+	case DatabaseHistoryMsg:
+		if msg.Err != nil {
+			m.setStatus("Failed to load sync history: "+msg.Err.Error(), true)
+			return m, nil
+		}
+		if len(msg.Entries) == 0 {
+			m.setStatus("No syncs recorded for "+msg.ProjectName, false)
+			return m, nil
+		}
+		m.dbHistoryProject = msg.ProjectName
+		m.dbHistory = msg.Entries
+		m.dbHistoryCursor = 0
+		m.dbHistoryOffset = 0
+		if m.currentView == ViewWorktrees {
+			m.prevView = m.currentView
+			m.currentView = ViewDatabaseHistory
 		}
 		return m, nil
 
@@ -432,6 +484,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			})
 			// Refresh to show tunnel status
 			m.refreshWorktreeList()
+			return m, m.refreshEnvFileCmd(msg.ProjectName, msg.WorktreeName)
 		}
 		return m, nil
 
@@ -444,6 +497,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			_ = m.store.ClearTunnelState(msg.ProjectName, msg.WorktreeName)
 			// Refresh to clear tunnel status
 			m.refreshWorktreeList()
+			return m, m.refreshEnvFileCmd(msg.ProjectName, msg.WorktreeName)
 		}
 		return m, nil
 
@@ -513,10 +567,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ConfigWatchTickMsg:
 		// Polling fallback - schedule next check and verify file
-		nextCheck := m.scheduleConfigWatch()
-		return m, tea.Batch(nextCheck, func() tea.Msg {
+		cmds := []tea.Cmd{m.scheduleConfigWatch(), func() tea.Msg {
 			return m.checkConfigFile()
-		})
+		}}
+		// Worktrees a save moved off ports another process took first
+		for _, moved := range m.store.TakePortReallocations() {
+			m.setStatus(fmt.Sprintf("Another conductor process took ports %v first; %s moved to %v",
+				moved.OldPorts, moved.Worktree, moved.NewPorts), false)
+			cmds = append(cmds, m.refreshEnvFileCmd(moved.Project, moved.Worktree))
+		}
+		return m, tea.Batch(cmds...)
 
 	case ConfigFileChangedMsg:
 		// Debounce: skip if we reloaded very recently
@@ -577,6 +637,11 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleConfirmDbReinstantiate(msg)
 	}
 
+	// Handle confirm database snapshot restore
+	if m.currentView == ViewConfirmDbRestore {
+		return m.handleConfirmDbRestore(msg)
+	}
+
 	// Handle help modal
 	if m.currentView == ViewHelp {
 		switch {
@@ -656,6 +721,16 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleDatabaseLogsView(msg)
 	}
 
+	// Handle database schema diff view
+	if m.currentView == ViewDatabaseDiff {
+		return m.handleDatabaseDiffView(msg)
+	}
+
+	// Handle database sync history view
+	if m.currentView == ViewDatabaseHistory {
+		return m.handleDatabaseHistoryView(msg)
+	}
+
 	// Global keys
 	switch {
 	case key.Matches(msg, m.keyMap.Quit):
@@ -858,6 +933,13 @@ func (m *Model) handleWorktreesView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				} else if wt.Archived {
 					m.setStatus("Worktree is already archived (use 'd' to delete)", true)
 				} else {
+					// Check for work that would be lost so the dialog can show it
+					risk, err := m.wsManager.CheckArchiveSafety(m.selectedProject, wtName)
+					if err != nil {
+						risk = nil
+						m.setStatus("Could not check for unsaved work: "+err.Error(), true)
+					}
+					m.archiveRisk = risk
 					m.deleteTarget = wtName
 					m.deleteTargetType = "worktree"
 					m.prevView = ViewWorktrees
@@ -1173,6 +1255,133 @@ func (m *Model) handleWorktreesView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.currentView = ViewConfirmDbReinstantiate
 		return m, nil
 
+	case key.Matches(msg, m.keyMap.DatabaseSnapshot):
+		// Snapshot the selected worktree's local database
+		worktrees := m.worktreeNames
+		if len(worktrees) == 0 || m.cursor >= len(worktrees) {
+			return m, nil
+		}
+
+		worktreeName := worktrees[m.cursor]
+		project := m.config.Projects[m.selectedProject]
+		worktree := project.Worktrees[worktreeName]
+		if !m.canSnapshotDatabase(project, worktree) {
+			return m, nil
+		}
+
+		projectName := m.selectedProject
+		dbName := worktree.DatabaseName
+		snapshotName := "tui-" + time.Now().Format("20060102-150405")
+		defaults := m.store.GetDefaults()
+		m.setStatus("Snapshotting database "+dbName+"...", false)
+
+		return m, func() tea.Msg {
+			localURL, err := secrets.Resolve(defaults.LocalPostgresURL)
+			if err != nil {
+				return DatabaseSnapshotCompletedMsg{ProjectName: projectName, WorktreeName: worktreeName, Err: err}
+			}
+			snap, err := database.CreateSnapshot(context.Background(), localURL, projectName, worktreeName, dbName, snapshotName, nil)
+			if err != nil {
+				return DatabaseSnapshotCompletedMsg{ProjectName: projectName, WorktreeName: worktreeName, Err: err}
+			}
+			if err := m.store.AddDatabaseSnapshot(projectName, worktreeName, *snap); err != nil {
+				_ = database.DeleteSnapshot(localURL, snap)
+				return DatabaseSnapshotCompletedMsg{ProjectName: projectName, WorktreeName: worktreeName, Err: err}
+			}
+			return DatabaseSnapshotCompletedMsg{
+				ProjectName:  projectName,
+				WorktreeName: worktreeName,
+				SnapshotName: snap.Name,
+				Method:       snap.Method,
+			}
+		}
+
+	case key.Matches(msg, m.keyMap.DatabaseRestore):
+		// Show confirmation dialog for restoring the latest snapshot
+		worktrees := m.worktreeNames
+		if len(worktrees) == 0 || m.cursor >= len(worktrees) {
+			return m, nil
+		}
+
+		worktreeName := worktrees[m.cursor]
+		project := m.config.Projects[m.selectedProject]
+		worktree := project.Worktrees[worktreeName]
+		if !m.canSnapshotDatabase(project, worktree) {
+			return m, nil
+		}
+
+		latest := latestDatabaseSnapshot(worktree.DatabaseSnapshots)
+		if latest == nil {
+			m.setStatus("No snapshots for this worktree (press s to take one)", true)
+			return m, nil
+		}
+
+		m.dbRestoreProject = m.selectedProject
+		m.dbRestoreWorktree = worktreeName
+		m.dbRestoreDBName = worktree.DatabaseName
+		m.dbRestoreSnapshot = latest
+		m.prevView = m.currentView
+		m.currentView = ViewConfirmDbRestore
+		return m, nil
+
+	case key.Matches(msg, m.keyMap.DatabaseHistory):
+		// Show the golden copy's sync history for the selected project
+		project := m.config.Projects[m.selectedProject]
+		if project == nil || project.Database == nil {
+			m.setStatus("No database configured for this project", true)
+			return m, nil
+		}
+
+		projectName := m.selectedProject
+		return m, func() tea.Msg {
+			entries, err := database.LoadSyncHistory(projectName, 0)
+			return DatabaseHistoryMsg{ProjectName: projectName, Entries: entries, Err: err}
+		}
+
+	case key.Matches(msg, m.keyMap.DatabaseDiff):
+		// Compare the selected worktree's database schema to the golden copy
+		worktrees := m.worktreeNames
+		if len(worktrees) == 0 || m.cursor >= len(worktrees) {
+			return m, nil
+		}
+
+		worktreeName := worktrees[m.cursor]
+		project := m.config.Projects[m.selectedProject]
+		worktree := project.Worktrees[worktreeName]
+		if !m.canSnapshotDatabase(project, worktree) {
+			return m, nil
+		}
+
+		projectName := m.selectedProject
+		dbName := worktree.DatabaseName
+		defaults := m.store.GetDefaults()
+		m.setStatus("Comparing "+dbName+" to golden...", false)
+
+		return m, func() tea.Msg {
+			localURL, err := secrets.Resolve(defaults.LocalPostgresURL)
+			if err != nil {
+				return DatabaseDiffMsg{ProjectName: projectName, WorktreeName: worktreeName, Err: err}
+			}
+			exists, err := database.GoldenDBExists(localURL, projectName)
+			if err == nil && !exists {
+				err = fmt.Errorf("no golden database for %s, sync first", projectName)
+			}
+			if err != nil {
+				return DatabaseDiffMsg{ProjectName: projectName, WorktreeName: worktreeName, Err: err}
+			}
+			cmp, err := database.CompareDatabaseSchemas(localURL, database.GoldenDBName(projectName), dbName)
+			if err != nil {
+				return DatabaseDiffMsg{ProjectName: projectName, WorktreeName: worktreeName, Err: err}
+			}
+			return DatabaseDiffMsg{
+				ProjectName:  projectName,
+				WorktreeName: worktreeName,
+				Lines:        cmp.Lines(),
+				SQLPatch:     cmp.SQLPatch(),
+				HasChanges:   cmp.HasChanges(),
+			}
+		}
+
 	case key.Matches(msg, m.keyMap.DatabaseMigrationStatus):
 		// Check migration status for selected worktree
 		worktrees := m.worktreeNames
@@ -1189,9 +1398,9 @@ func (m *Model) handleWorktreesView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		defaults := m.store.GetDefaults()
-		if defaults.LocalPostgresURL == "" {
-			m.setStatus("Local PostgreSQL not configured", true)
+		localDBURL := m.store.GetDefaults().LocalDatabaseURL(project.Database)
+		if localDBURL == "" {
+			m.setStatus("Local database server not configured", true)
 			return m, nil
 		}
 
@@ -1201,7 +1410,11 @@ func (m *Model) handleWorktreesView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.setStatus("Checking migration status for "+worktreeName+"...", false)
 
 		return m, func() tea.Msg {
-			state, err := database.GetWorktreeMigrationStatus(defaults.LocalPostgresURL, dbName, worktreePath)
+			localURL, err := secrets.Resolve(localDBURL)
+			if err != nil {
+				return DatabaseMigrationStatusMsg{ProjectName: projectName, WorktreeName: worktreeName, Err: err}
+			}
+			state, err := database.GetWorktreeMigrationStatus(localURL, dbName, worktreePath)
 			if err != nil {
 				return DatabaseMigrationStatusMsg{ProjectName: projectName, WorktreeName: worktreeName, Err: err}
 			}
@@ -1299,13 +1512,32 @@ func (m *Model) handleCreateWorktreeInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) handleConfirmDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.archiveOptions = workspace.ArchiveOptions{Safety: m.config.Defaults.ArchiveSafety}
+	archiveWithRisk := m.deleteTargetType == "worktree" && m.archiveRisk.HasRisk()
+
 	switch msg.String() {
 	case "y", "Y":
 		return m.executeDelete()
+	case "s":
+		if archiveWithRisk {
+			m.archiveOptions.Safety = config.ArchiveSafetyStash
+			return m.executeDelete()
+		}
+	case "p":
+		if archiveWithRisk {
+			m.archiveOptions.Safety = config.ArchiveSafetyPush
+			return m.executeDelete()
+		}
+	case "f":
+		if archiveWithRisk {
+			m.archiveOptions.Force = true
+			return m.executeDelete()
+		}
 	case "n", "N", "esc":
 		m.currentView = m.prevView
 		m.deleteTarget = ""
 		m.deleteTargetType = ""
+		m.archiveRisk = nil
 	}
 	return m, nil
 }
@@ -1353,8 +1585,15 @@ func (m *Model) executeDbReinstantiate() (tea.Model, tea.Cmd) {
 	return m, func() tea.Msg {
 		ctx := context.Background()
 
+		dbConfig, err := secrets.ResolveDatabaseConfig(dbConfig)
+		if err != nil {
+			return DatabaseReinstantiateCompletedMsg{
+				ProjectName: projectName, WorktreeName: worktreeName, Err: err,
+			}
+		}
+
 		// Step 1: Drop existing database via SSH
-		err := database.RemoteDropDatabase(ctx, dbConfig.SSHHost, dbConfig.DevURL, dbName)
+		err = database.RemoteDropDatabase(ctx, dbConfig.SSHHost, dbConfig.DevURL, dbName)
 		if err != nil {
 			return DatabaseReinstantiateCompletedMsg{
 				ProjectName: projectName, WorktreeName: worktreeName, Err: err,
@@ -1379,6 +1618,88 @@ func (m *Model) executeDbReinstantiate() (tea.Model, tea.Cmd) {
 	}
 }
 
+// canSnapshotDatabase reports whether a worktree has a local database that can
+// be snapshotted, setting an error status if not
+func (m *Model) canSnapshotDatabase(project *config.Project, worktree *config.Worktree) bool {
+	if worktree == nil || worktree.DatabaseName == "" {
+		m.setStatus("No database configured for this worktree", true)
+		return false
+	}
+	if project.Database != nil && project.Database.Mode == config.DatabaseModeRemote {
+		m.setStatus("Snapshots are only supported for local databases", true)
+		return false
+	}
+	if project.Database.IsMySQL() {
+		m.setStatus("Snapshots are not supported for MySQL databases yet", true)
+		return false
+	}
+	if m.store.GetDefaults().LocalPostgresURL == "" {
+		m.setStatus("Local PostgreSQL not configured", true)
+		return false
+	}
+	return true
+}
+
+// latestDatabaseSnapshot returns the most recently taken snapshot, or nil
+func latestDatabaseSnapshot(snapshots []config.DatabaseSnapshot) *config.DatabaseSnapshot {
+	var latest *config.DatabaseSnapshot
+	for i := range snapshots {
+		if latest == nil || snapshots[i].CreatedAt.After(latest.CreatedAt) {
+			latest = &snapshots[i]
+		}
+	}
+	return latest
+}
+
+func (m *Model) handleConfirmDbRestore(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		return m.executeDbRestore()
+	case "n", "N", "esc":
+		m.currentView = m.prevView
+		m.dbRestoreProject = ""
+		m.dbRestoreWorktree = ""
+		m.dbRestoreDBName = ""
+		m.dbRestoreSnapshot = nil
+	}
+	return m, nil
+}
+
+func (m *Model) executeDbRestore() (tea.Model, tea.Cmd) {
+	projectName := m.dbRestoreProject
+	worktreeName := m.dbRestoreWorktree
+	dbName := m.dbRestoreDBName
+	snap := m.dbRestoreSnapshot
+
+	// Clear dialog state
+	m.dbRestoreProject = ""
+	m.dbRestoreWorktree = ""
+	m.dbRestoreDBName = ""
+	m.dbRestoreSnapshot = nil
+	m.currentView = m.prevView
+
+	if snap == nil {
+		return m, nil
+	}
+
+	defaults := m.store.GetDefaults()
+	m.setStatus(fmt.Sprintf("Restoring database %s from snapshot %s...", dbName, snap.Name), false)
+
+	return m, func() tea.Msg {
+		localURL, err := secrets.Resolve(defaults.LocalPostgresURL)
+		if err == nil {
+			err = database.RestoreSnapshot(context.Background(), localURL, dbName, snap, nil)
+		}
+		return DatabaseRestoreCompletedMsg{
+			ProjectName:  projectName,
+			WorktreeName: worktreeName,
+			DatabaseName: dbName,
+			SnapshotName: snap.Name,
+			Err:          err,
+		}
+	}
+}
+
 func (m *Model) createWorktree() (tea.Model, tea.Cmd) {
 	branch := m.createInput.Value()
 
@@ -1448,12 +1769,14 @@ func (m *Model) executeDelete() (tea.Model, tea.Cmd) {
 		m.currentView = ViewWorktrees
 		m.deleteTarget = ""
 		m.deleteTargetType = ""
+		m.archiveRisk = nil
 		m.setStatus("Archiving "+wtName+"...", false)
 
+		opts := m.archiveOptions
 		return m, func() tea.Msg {
-			err := m.wsManager.ArchiveWorktree(projectName, wtName)
+			err := m.wsManager.ArchiveWorktreeWithOptions(projectName, wtName, opts)
 			if err != nil {
-				return WorktreeArchivedMsg{Err: err}
+				return WorktreeArchivedMsg{ProjectName: projectName, WorktreeName: wtName, Err: err}
 			}
 
 			return WorktreeArchivedMsg{
@@ -2188,6 +2511,30 @@ func (m *Model) handleArchivedListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.currentView = ViewLogs
 		}
 
+	case msg.String() == "R":
+		// Restore selected archived worktree
+		if m.archivedListMode == 0 && m.archivedListCursor < len(m.archivedWorktrees) {
+			wt := m.archivedWorktrees[m.archivedListCursor]
+			projectName := m.selectedProject
+			worktreeName := wt.Name
+			m.setStatus("Restoring worktree: "+worktreeName+"...", false)
+			return m, func() tea.Msg {
+				done := make(chan WorktreeRestoredMsg, 1)
+				err := m.wsManager.RestoreWorktreeAsync(projectName, worktreeName, func(result *workspace.RestoreResult, restoreErr error) {
+					done <- WorktreeRestoredMsg{
+						ProjectName:  projectName,
+						WorktreeName: worktreeName,
+						Result:       result,
+						Err:          restoreErr,
+					}
+				})
+				if err != nil {
+					return WorktreeRestoredMsg{ProjectName: projectName, WorktreeName: worktreeName, Err: err}
+				}
+				return <-done
+			}
+		}
+
 	case key.Matches(msg, m.keyMap.Delete):
 		// Delete selected item
 		if m.archivedListMode == 0 {
@@ -2247,6 +2594,40 @@ func (m *Model) handleArchivedListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// runSetupCmd runs a worktree's setup script in the background and reports a SetupCompleteMsg
+func (m *Model) runSetupCmd(projectName, worktreeName string) tea.Cmd {
+	return func() tea.Msg {
+		done := make(chan SetupCompleteMsg, 1)
+		_ = m.wsManager.RunSetupAsync(projectName, worktreeName, func(success bool, setupErr error) {
+			done <- SetupCompleteMsg{
+				ProjectName:  projectName,
+				WorktreeName: worktreeName,
+				Success:      success,
+				Err:          setupErr,
+			}
+		})
+		select {
+		case msg := <-done:
+			return msg
+		case <-time.After(35 * time.Minute):
+			return SetupCompleteMsg{
+				ProjectName:  projectName,
+				WorktreeName: worktreeName,
+				Success:      false,
+				Err:          fmt.Errorf("setup timed out"),
+			}
+		}
+	}
+}
+
+// refreshEnvFileCmd regenerates a worktree's env file in the background
+func (m *Model) refreshEnvFileCmd(projectName, worktreeName string) tea.Cmd {
+	return func() tea.Msg {
+		_, _ = workspace.RefreshEnvFile(m.store, projectName, worktreeName)
+		return nil
+	}
+}
+
 // getArchivedListItemCount returns the number of items in the current archived list mode
 func (m *Model) getArchivedListItemCount() int {
 	if m.archivedListMode == 0 {
@@ -2411,6 +2792,86 @@ func (m *Model) handleDatabaseLogsView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// dbDiffContent returns the lines shown in the schema diff view
+func (m *Model) dbDiffContent() []string {
+	if m.dbDiffShowSQL {
+		return strings.Split(strings.TrimRight(m.dbDiffSQL, "\n"), "\n")
+	}
+	return m.dbDiffLines
+}
+
+func (m *Model) handleDatabaseDiffView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	maxScroll := len(m.dbDiffContent()) - m.tableHeight()
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+
+	switch {
+	case key.Matches(msg, m.keyMap.Back):
+		m.currentView = m.prevView
+		return m, nil
+
+	case key.Matches(msg, m.keyMap.Up):
+		if m.dbDiffScroll > 0 {
+			m.dbDiffScroll--
+		}
+
+	case key.Matches(msg, m.keyMap.Down):
+		if m.dbDiffScroll < maxScroll {
+			m.dbDiffScroll++
+		}
+
+	case msg.String() == "g":
+		m.dbDiffScroll = 0
+
+	case msg.String() == "G":
+		m.dbDiffScroll = maxScroll
+
+	case msg.String() == "s":
+		// Toggle between the summary and the SQL patch
+		m.dbDiffShowSQL = !m.dbDiffShowSQL
+		m.dbDiffScroll = 0
+
+	case msg.String() == "y":
+		return m, m.copyToClipboard(m.dbDiffSQL)
+	}
+
+	return m, nil
+}
+
+func (m *Model) handleDatabaseHistoryView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keyMap.Back):
+		m.currentView = m.prevView
+		return m, nil
+
+	case key.Matches(msg, m.keyMap.Up):
+		if m.dbHistoryCursor > 0 {
+			m.dbHistoryCursor--
+		}
+
+	case key.Matches(msg, m.keyMap.Down):
+		if m.dbHistoryCursor < len(m.dbHistory)-1 {
+			m.dbHistoryCursor++
+		}
+
+	case msg.String() == "g":
+		m.dbHistoryCursor = 0
+
+	case msg.String() == "G":
+		m.dbHistoryCursor = len(m.dbHistory) - 1
+	}
+
+	// Keep the cursor inside the list pane
+	listHeight := m.dbHistoryListHeight()
+	if m.dbHistoryCursor < m.dbHistoryOffset {
+		m.dbHistoryOffset = m.dbHistoryCursor
+	} else if m.dbHistoryCursor >= m.dbHistoryOffset+listHeight {
+		m.dbHistoryOffset = m.dbHistoryCursor - listHeight + 1
+	}
+	return m, nil
+}
+
 // ensureDatabaseCursorVisible adjusts offset to keep cursor visible
 func (m *Model) ensureDatabaseCursorVisible() {
 	tableHeight := m.tableHeight()
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/database"
 	"github.com/hammashamzah/conductor/internal/tui/styles"
 )
 
@@ -53,8 +55,8 @@ func (m *Model) renderMainView() string {
 		} else {
 			sections = append(sections, m.renderProjectsTable())
 		}
-	case ViewConfirmDbReinstantiate:
-		// Render worktrees table as background (reinit is always from worktrees view)
+	case ViewConfirmDbReinstantiate, ViewConfirmDbRestore:
+		// Render worktrees table as background (reinit and restore are always from worktrees view)
 		sections = append(sections, m.renderWorktreesTable())
 	case ViewHelp:
 		// Render previous view as background
@@ -82,6 +84,10 @@ func (m *Model) renderMainView() string {
 		sections = append(sections, m.renderDatabasesTable())
 	case ViewDatabaseLogs:
 		sections = append(sections, m.renderDatabaseLogsView())
+	case ViewDatabaseDiff:
+		sections = append(sections, m.renderDatabaseDiffView())
+	case ViewDatabaseHistory:
+		sections = append(sections, m.renderDatabaseHistoryView())
 	}
 
 	// Status bar (with separator above)
@@ -103,6 +109,8 @@ func (m *Model) renderMainView() string {
 		return m.overlayModal(baseView, m.renderConfirmDeleteModal())
 	case ViewConfirmDbReinstantiate:
 		return m.overlayModal(baseView, m.renderConfirmDbReinstantiateModal())
+	case ViewConfirmDbRestore:
+		return m.overlayModal(baseView, m.renderConfirmDbRestoreModal())
 	case ViewHelp:
 		return m.overlayModal(baseView, m.renderHelpModal())
 	case ViewQuit:
@@ -192,6 +200,9 @@ func (m *Model) renderTitleBar() string {
 	case ViewConfirmDbReinstantiate:
 		title = "CONFIRM REINIT"
 		count = 0
+	case ViewConfirmDbRestore:
+		title = "CONFIRM RESTORE"
+		count = 0
 	case ViewHelp:
 		title = "HELP"
 		count = 0
@@ -222,6 +233,12 @@ func (m *Model) renderTitleBar() string {
 	case ViewDatabases:
 		title = "DATABASES"
 		count = len(m.databaseProjects)
+	case ViewDatabaseDiff:
+		title = "SCHEMA DIFF: " + m.dbDiffWorktree
+		count = 0
+	case ViewDatabaseHistory:
+		title = "SYNC HISTORY: " + m.dbHistoryProject
+		count = len(m.dbHistory)
 	}
 
 	// Build title: ─────── TITLE(count) ───────
@@ -576,13 +593,17 @@ func (m *Model) getContextKeys() []CommandKey {
 		return []CommandKey{{"S", "sync"}, {"F", "force sync"}, {"l", "logs"}, {"1", "projects"}, {"p", "ports"}, {"?", "help"}, {"esc", "back"}}
 	case ViewDatabaseLogs:
 		return []CommandKey{{"j/k", "scroll"}, {"a", "auto-scroll"}, {"g/G", "top/bottom"}, {"esc", "back"}}
+	case ViewDatabaseDiff:
+		return []CommandKey{{"j/k", "scroll"}, {"s", "summary/SQL"}, {"y", "copy SQL"}, {"g/G", "top/bottom"}, {"esc", "back"}}
+	case ViewDatabaseHistory:
+		return []CommandKey{{"j/k", "select"}, {"g/G", "top/bottom"}, {"esc", "back"}}
 	case ViewPRs:
 		return []CommandKey{{"o", "open"}, {"w", "worktree"}, {"r", "refresh"}, {"?", "help"}, {"esc", "back"}}
 	case ViewAllPRs:
 		return []CommandKey{{"enter", "worktree"}, {"o", "open"}, {"r", "refresh"}, {"?", "help"}, {"esc", "back"}}
 	case ViewArchivedList:
 		if m.archivedListMode == 0 {
-			return []CommandKey{{"tab", "orphaned"}, {"l", "logs"}, {"d", "delete"}, {"r", "refresh"}, {"esc", "back"}}
+			return []CommandKey{{"tab", "orphaned"}, {"R", "restore"}, {"l", "logs"}, {"d", "delete"}, {"r", "refresh"}, {"esc", "back"}}
 		}
 		return []CommandKey{{"tab", "archived"}, {"d", "delete"}, {"r", "refresh"}, {"esc", "back"}}
 	case ViewStatusHistory:
@@ -591,7 +612,7 @@ func (m *Model) getContextKeys() []CommandKey {
 		return []CommandKey{{"enter", "create"}, {"tab", "next"}, {"esc", "cancel"}}
 	case ViewConfirmDelete:
 		return []CommandKey{{"enter", "confirm"}, {"esc", "cancel"}}
-	case ViewConfirmDbReinstantiate:
+	case ViewConfirmDbReinstantiate, ViewConfirmDbRestore:
 		return []CommandKey{{"y", "yes"}, {"n", "no"}, {"esc", "cancel"}}
 	case ViewTunnelModal:
 		return []CommandKey{{"enter", "start"}, {"tab", "switch"}, {"esc", "cancel"}}
@@ -642,6 +663,10 @@ func (m *Model) renderFooter() string {
 		breadcrumbs = append(breadcrumbs, "projects")
 		breadcrumbs = append(breadcrumbs, m.dbReinstantiateProject)
 		breadcrumbs = append(breadcrumbs, "reinit-db")
+	case ViewConfirmDbRestore:
+		breadcrumbs = append(breadcrumbs, "projects")
+		breadcrumbs = append(breadcrumbs, m.dbRestoreProject)
+		breadcrumbs = append(breadcrumbs, "restore-db")
 	case ViewPRs:
 		breadcrumbs = append(breadcrumbs, "projects")
 		breadcrumbs = append(breadcrumbs, m.selectedProject)
@@ -667,6 +692,15 @@ func (m *Model) renderFooter() string {
 		breadcrumbs = append(breadcrumbs, "databases")
 		breadcrumbs = append(breadcrumbs, m.databaseLogsProject)
 		breadcrumbs = append(breadcrumbs, "logs")
+	case ViewDatabaseDiff:
+		breadcrumbs = append(breadcrumbs, "projects")
+		breadcrumbs = append(breadcrumbs, m.dbDiffProject)
+		breadcrumbs = append(breadcrumbs, m.dbDiffWorktree)
+		breadcrumbs = append(breadcrumbs, "schema-diff")
+	case ViewDatabaseHistory:
+		breadcrumbs = append(breadcrumbs, "projects")
+		breadcrumbs = append(breadcrumbs, m.dbHistoryProject)
+		breadcrumbs = append(breadcrumbs, "sync-history")
 	}
 
 	for i, bc := range breadcrumbs {
@@ -775,6 +809,15 @@ func (m *Model) renderConfirmDeleteModal() string {
 		content.WriteString(fmt.Sprintf("  Archive worktree '%s'?\n\n", m.deleteTarget))
 		content.WriteString(m.styles.Muted.Render("  This will remove the git worktree and free its ports.\n"))
 		content.WriteString(m.styles.Muted.Render("  The entry will remain for viewing logs."))
+		if m.archiveRisk != nil && len(m.archiveRisk.ModifiedFiles) > 0 {
+			content.WriteString("\n\n")
+			content.WriteString(m.styles.StatusPending.Render("  Changed files from project root (discarded):"))
+			content.WriteString("\n")
+			content.WriteString(m.styles.Muted.Render("    " + truncate(strings.Join(m.archiveRisk.ModifiedFiles, ", "), 60)))
+		}
+		if m.archiveRisk.HasRisk() {
+			return m.renderArchiveRiskModal(content.String())
+		}
 	case "worktree-delete":
 		content.WriteString(m.styles.ModalTitle.Render("Confirm Delete"))
 		content.WriteString("\n\n")
@@ -797,6 +840,49 @@ func (m *Model) renderConfirmDeleteModal() string {
 	return modal
 }
 
+// renderArchiveRiskModal renders the archive confirmation when the worktree
+// still holds uncommitted or unpushed work, listing what would be lost
+func (m *Model) renderArchiveRiskModal(header string) string {
+	width := 70
+	if width > m.width-4 {
+		width = m.width - 4
+	}
+	const maxLines = 5
+
+	var content strings.Builder
+	content.WriteString(header)
+	content.WriteString("\n\n")
+	content.WriteString(m.styles.StatusError.Render("  ⚠ Unsaved work: " + m.archiveRisk.Summary()))
+	content.WriteString("\n")
+
+	writeList := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		content.WriteString("\n  " + title + "\n")
+		for i, line := range lines {
+			if i == maxLines {
+				content.WriteString(m.styles.Muted.Render(fmt.Sprintf("    … and %d more\n", len(lines)-maxLines)))
+				break
+			}
+			content.WriteString(m.styles.Muted.Render("    " + truncate(line, width-8) + "\n"))
+		}
+	}
+	writeList("Uncommitted files:", m.archiveRisk.Uncommitted)
+	writeList("Unpushed commits:", m.archiveRisk.Unpushed)
+
+	content.WriteString("\n  ")
+	content.WriteString(m.styles.RenderKeyHelp("s", "stash to conductor/archive/"+m.deleteTarget))
+	content.WriteString("\n  ")
+	content.WriteString(m.styles.RenderKeyHelp("p", "stash + push backup"))
+	content.WriteString("  ")
+	content.WriteString(m.styles.RenderKeyHelp("f", "discard"))
+	content.WriteString("  ")
+	content.WriteString(m.styles.RenderKeyHelp("n", "cancel"))
+
+	return m.styles.Modal.Width(width).Render(content.String())
+}
+
 func (m *Model) renderConfirmDbReinstantiateModal() string {
 	width := 60
 	if width > m.width-4 {
@@ -823,6 +909,31 @@ func (m *Model) renderConfirmDbReinstantiateModal() string {
 	return modal
 }
 
+func (m *Model) renderConfirmDbRestoreModal() string {
+	width := 60
+	if width > m.width-4 {
+		width = m.width - 4
+	}
+
+	var content strings.Builder
+
+	content.WriteString(m.styles.ModalTitle.Render("Confirm Database Restore"))
+	content.WriteString("\n\n")
+	content.WriteString(fmt.Sprintf("  Restore database '%s'\n", m.dbRestoreDBName))
+	if snap := m.dbRestoreSnapshot; snap != nil {
+		content.WriteString(fmt.Sprintf("  from snapshot '%s' (%s)?\n\n", snap.Name, snap.CreatedAt.Format("2006-01-02 15:04")))
+	}
+	content.WriteString(m.styles.Muted.Render("  Open connections to the database are closed.\n\n"))
+	content.WriteString(m.styles.StatusError.Render("  ⚠ CHANGES SINCE THE SNAPSHOT WILL BE LOST!"))
+
+	content.WriteString("\n\n  ")
+	content.WriteString(m.styles.RenderKeyHelp("y", "yes"))
+	content.WriteString("  ")
+	content.WriteString(m.styles.RenderKeyHelp("n", "no"))
+
+	return m.styles.Modal.Width(width).Render(content.String())
+}
+
 func (m *Model) renderHelpModal() string {
 	width := 70
 	if width > m.width-4 {
@@ -1822,6 +1933,111 @@ func parseDBSource(source string) (*dbSourceInfo, error) {
 }
 
 // renderDatabaseLogsView renders the database sync logs view
+func (m *Model) renderDatabaseDiffView() string {
+	lines := m.dbDiffContent()
+	viewHeight := m.tableHeight() - 2 // room for the footer line
+
+	start := m.dbDiffScroll
+	if start > len(lines) {
+		start = len(lines)
+	}
+	end := start + viewHeight
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	var formatted []string
+	for _, line := range lines[start:end] {
+		if maxWidth := m.width - 2; maxWidth > 3 && len(line) > maxWidth {
+			line = line[:maxWidth-3] + "..."
+		}
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case m.dbDiffShowSQL:
+		case strings.HasPrefix(trimmed, "+ "):
+			line = m.styles.StatusRunning.Render(line)
+		case strings.HasPrefix(trimmed, "- "):
+			line = m.styles.StatusError.Render(line)
+		case strings.HasPrefix(trimmed, "~ "), strings.HasPrefix(trimmed, "→ "):
+			line = m.styles.StatusPending.Render(line)
+		}
+		formatted = append(formatted, line)
+	}
+	for len(formatted) < viewHeight {
+		formatted = append(formatted, "")
+	}
+
+	mode := "summary"
+	if m.dbDiffShowSQL {
+		mode = "SQL patch"
+	}
+	info := fmt.Sprintf("Golden → %s, %s, lines %d-%d of %d (s: summary/SQL, y: copy SQL, esc: close)", m.dbDiffWorktree, mode, start+1, end, len(lines))
+	formatted = append(formatted, "", m.styles.Muted.Render(info))
+
+	return m.padContent(strings.Join(formatted, "\n"))
+}
+
+// dbHistoryListHeight is how many sync history entries fit above the details pane
+func (m *Model) dbHistoryListHeight() int {
+	h := m.tableHeight() / 3
+	if h < 3 {
+		h = 3
+	}
+	return h
+}
+
+// renderDatabaseHistoryView renders a project's golden copy sync history: the
+// entries on top and the selected entry's details below
+func (m *Model) renderDatabaseHistoryView() string {
+	listHeight := m.dbHistoryListHeight()
+	header := fmt.Sprintf("  %-19s  %-10s  %-8s  %9s  %12s  %-7s", "STARTED", "TRIGGER", "OUTCOME", "DURATION", "ROWS", "SCHEMA")
+	rows := []string{m.styles.Muted.Render(header)}
+
+	end := m.dbHistoryOffset + listHeight
+	if end > len(m.dbHistory) {
+		end = len(m.dbHistory)
+	}
+	for i := m.dbHistoryOffset; i < end; i++ {
+		e := &m.dbHistory[i]
+		row := fmt.Sprintf("%-19s  %-10s  %-8s  %9s  %12s  %-7s",
+			e.StartedAt.Local().Format("2006-01-02 15:04:05"),
+			e.Trigger,
+			e.Outcome,
+			e.Duration().Round(time.Second).String(),
+			fmt.Sprintf("%+d", e.NetRowDelta()),
+			e.SchemaSummary())
+		row = padRight(row, m.width-2)
+		if i == m.dbHistoryCursor {
+			rows = append(rows, m.styles.TableRowSelected.Width(m.width).Render("> "+row))
+		} else if e.Outcome == database.SyncOutcomeFailed {
+			rows = append(rows, "  "+m.styles.StatusError.Render(row))
+		} else {
+			rows = append(rows, "  "+row)
+		}
+	}
+	for len(rows) < listHeight+1 {
+		rows = append(rows, "")
+	}
+	rows = append(rows, "")
+
+	// Details of the selected entry fill the rest of the view
+	detailsHeight := m.tableHeight() - len(rows)
+	if m.dbHistoryCursor < len(m.dbHistory) {
+		details := m.dbHistory[m.dbHistoryCursor].Details()
+		if detailsHeight > 0 && len(details) > detailsHeight {
+			details = append(details[:detailsHeight-1], m.styles.Muted.Render(fmt.Sprintf("... %d more (conductor database history %d)", len(details)-detailsHeight+1, m.dbHistoryCursor+1)))
+		}
+		for _, line := range details {
+			if maxWidth := m.width - 2; maxWidth > 3 && len(line) > maxWidth {
+				line = line[:maxWidth-3] + "..."
+			}
+			rows = append(rows, "  "+line)
+		}
+	}
+
+	return m.padContent(strings.Join(rows, "\n"))
+}
+
 func (m *Model) renderDatabaseLogsView() string {
 	logs := m.databaseLogs[m.databaseLogsProject]
 
@@ -0,0 +1,181 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hammashamzah/conductor/internal/config"
+)
+
+// archiveBranchPrefix is the namespace for branches holding work preserved at archive time
+const archiveBranchPrefix = "conductor/archive/"
+
+// ArchiveBackupBranch returns the branch name used to preserve a worktree's work on archive
+func ArchiveBackupBranch(worktreeName string) string {
+	return archiveBranchPrefix + worktreeName
+}
+
+// IsArchiveBackupBranch reports whether a branch was created by archive to preserve work
+func IsArchiveBackupBranch(branch string) bool {
+	return strings.HasPrefix(branch, archiveBranchPrefix)
+}
+
+// ArchiveRisk describes the work that would be lost if a worktree were archived
+type ArchiveRisk struct {
+	// Uncommitted holds porcelain status lines for modified and untracked files
+	Uncommitted []string
+	// Unpushed holds one-line summaries of commits not on any remote or other branch
+	Unpushed []string
+	// ModifiedFiles are files placed from the project root (see
+	// ProjectConfig.Files) that the worktree changed. They are usually
+	// git-ignored, so archive can't preserve them; they are reported but
+	// don't block archiving.
+	ModifiedFiles []string
+}
+
+// HasRisk returns true if archiving would lose any work
+func (r *ArchiveRisk) HasRisk() bool {
+	return r != nil && (len(r.Uncommitted) > 0 || len(r.Unpushed) > 0)
+}
+
+// Summary returns a short human-readable description of the risk
+func (r *ArchiveRisk) Summary() string {
+	if !r.HasRisk() {
+		return "no unsaved work"
+	}
+	var parts []string
+	if n := len(r.Uncommitted); n > 0 {
+		parts = append(parts, pluralize(n, "uncommitted file", "uncommitted files"))
+	}
+	if n := len(r.Unpushed); n > 0 {
+		parts = append(parts, pluralize(n, "unpushed commit", "unpushed commits"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// ArchiveBlockedError is returned when archive refuses to discard unsaved work
+type ArchiveBlockedError struct {
+	Worktree string
+	Risk     *ArchiveRisk
+}
+
+func (e *ArchiveBlockedError) Error() string {
+	return fmt.Sprintf("worktree '%s' has %s; commit and push it, or archive with stash/push/force", e.Worktree, e.Risk.Summary())
+}
+
+// ArchiveOptions controls how ArchiveWorktreeWithOptions treats unsaved work
+type ArchiveOptions struct {
+	// Safety selects block/stash/push behaviour (empty = block)
+	Safety config.ArchiveSafetyMode
+	// Force archives without checking, discarding any unsaved work
+	Force bool
+}
+
+// CheckArchiveSafety inspects a worktree for uncommitted changes and for
+// commits that exist only on its branch
+func CheckArchiveSafety(worktreePath, branch string) (*ArchiveRisk, error) {
+	risk := &ArchiveRisk{}
+
+	dirty, err := GitHasUncommittedChanges(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		if risk.Uncommitted, err = GitUncommittedFiles(worktreePath); err != nil {
+			return nil, err
+		}
+	}
+
+	if risk.Unpushed, err = GitUnpushedCommits(worktreePath, branch); err != nil {
+		return nil, err
+	}
+
+	return risk, nil
+}
+
+// PreserveWorktreeWork snapshots everything in the worktree (HEAD plus any
+// uncommitted and untracked files) into the conductor/archive/<name> branch
+// without touching the worktree's own branch or index. Worktree names are
+// reused, so if an earlier archive already holds that branch the snapshot's
+// short SHA is appended rather than overwriting it.
+// If push is true, the backup branch is also pushed to origin.
+// Returns the backup branch name.
+func PreserveWorktreeWork(worktreePath, worktreeName string, push bool) (string, error) {
+	head, err := gitOutput(worktreePath, nil, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	snapshot := head
+
+	dirty, err := GitHasUncommittedChanges(worktreePath)
+	if err != nil {
+		return "", err
+	}
+	if dirty {
+		// Build the snapshot tree in a throwaway index so the user's staging area is untouched
+		tmpDir, err := os.MkdirTemp("", "conductor-archive-")
+		if err != nil {
+			return "", fmt.Errorf("failed to create temp dir: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(tmpDir) }()
+		env := []string{"GIT_INDEX_FILE=" + filepath.Join(tmpDir, "index")}
+
+		if _, err := gitOutput(worktreePath, env, "read-tree", "HEAD"); err != nil {
+			return "", fmt.Errorf("failed to read HEAD tree: %w", err)
+		}
+		if _, err := gitOutput(worktreePath, env, "add", "-A"); err != nil {
+			return "", fmt.Errorf("failed to stage worktree files: %w", err)
+		}
+		tree, err := gitOutput(worktreePath, env, "write-tree")
+		if err != nil {
+			return "", fmt.Errorf("failed to write snapshot tree: %w", err)
+		}
+		msg := fmt.Sprintf("conductor: uncommitted work from '%s' at archive", worktreeName)
+		snapshot, err = gitOutput(worktreePath, nil, "commit-tree", tree, "-p", head, "-m", msg)
+		if err != nil {
+			return "", fmt.Errorf("failed to create snapshot commit: %w", err)
+		}
+	}
+
+	branch := ArchiveBackupBranch(worktreeName)
+	if GitBranchExists(worktreePath, branch) {
+		branch += "-" + snapshot[:12]
+	}
+	// The empty old value makes update-ref refuse to replace an existing branch
+	if _, err := gitOutput(worktreePath, nil, "update-ref", "refs/heads/"+branch, snapshot, ""); err != nil {
+		return "", fmt.Errorf("failed to create backup branch: %w", err)
+	}
+
+	if push {
+		// Not forced: a branch of the same name on origin is never overwritten
+		if _, err := gitOutput(worktreePath, nil, "push", "origin", snapshot+":refs/heads/"+branch); err != nil {
+			return "", fmt.Errorf("failed to push backup branch: %w", err)
+		}
+	}
+
+	return branch, nil
+}
+
+// gitOutput runs a git command with optional extra environment and returns trimmed stdout
+func gitOutput(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,133 @@
+package workspace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupArchiveRepo creates a bare origin, a clone of it with one pushed commit,
+// and a worktree on branch "feature". Returns (repoPath, worktreePath).
+func setupArchiveRepo(t *testing.T) (string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin.git")
+	repo := filepath.Join(root, "repo")
+	wt := filepath.Join(root, "wt")
+
+	mustGit(t, root, "init", "--bare", "-b", "main", origin)
+	mustGit(t, root, "clone", origin, repo)
+	mustGit(t, repo, "checkout", "-b", "main")
+	mustGit(t, repo, "config", "user.name", "test")
+	mustGit(t, repo, "config", "user.email", "test@example.com")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "README.md"), []byte("hello\n"), 0644))
+	mustGit(t, repo, "add", ".")
+	mustGit(t, repo, "commit", "-m", "initial")
+	mustGit(t, repo, "push", "origin", "main")
+	mustGit(t, repo, "worktree", "add", "-b", "feature", wt, "main")
+
+	return repo, wt
+}
+
+func mustGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %v: %s", args, out)
+	return string(out)
+}
+
+func TestCheckArchiveSafety_CleanWorktree(t *testing.T) {
+	_, wt := setupArchiveRepo(t)
+
+	risk, err := CheckArchiveSafety(wt, "feature")
+	require.NoError(t, err)
+	assert.False(t, risk.HasRisk())
+}
+
+func TestCheckArchiveSafety_DetectsUncommittedAndUnpushed(t *testing.T) {
+	_, wt := setupArchiveRepo(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(wt, "feature.go"), []byte("package x\n"), 0644))
+	mustGit(t, wt, "add", ".")
+	mustGit(t, wt, "commit", "-m", "local only")
+	require.NoError(t, os.WriteFile(filepath.Join(wt, "scratch.txt"), []byte("wip\n"), 0644))
+
+	risk, err := CheckArchiveSafety(wt, "feature")
+	require.NoError(t, err)
+	require.True(t, risk.HasRisk())
+	assert.Len(t, risk.Uncommitted, 1)
+	assert.Contains(t, risk.Uncommitted[0], "scratch.txt")
+	require.Len(t, risk.Unpushed, 1)
+	assert.Contains(t, risk.Unpushed[0], "local only")
+	assert.Equal(t, "1 uncommitted file, 1 unpushed commit", risk.Summary())
+}
+
+func TestPreserveWorktreeWork_SnapshotsUncommittedFiles(t *testing.T) {
+	repo, wt := setupArchiveRepo(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(wt, "scratch.txt"), []byte("wip\n"), 0644))
+
+	branch, err := PreserveWorktreeWork(wt, "tokyo", false)
+	require.NoError(t, err)
+	assert.Equal(t, "conductor/archive/tokyo", branch)
+
+	// The backup branch contains the untracked file
+	out := mustGit(t, repo, "show", branch+":scratch.txt")
+	assert.Equal(t, "wip\n", out)
+
+	// The worktree itself is untouched
+	files, err := GitUncommittedFiles(wt)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestArchiveWorktree_BlocksUnsavedWork(t *testing.T) {
+	repo, wt := setupArchiveRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(wt, "scratch.txt"), []byte("wip\n"), 0644))
+
+	cfg := config.NewConfig()
+	cfg.Projects["app"] = &config.Project{
+		Path: repo,
+		Worktrees: map[string]*config.Worktree{
+			"tokyo": {Path: wt, Branch: "feature"},
+		},
+	}
+
+	err := NewManager(cfg).ArchiveWorktree("app", "tokyo")
+	var blocked *ArchiveBlockedError
+	require.ErrorAs(t, err, &blocked)
+	assert.False(t, cfg.Projects["app"].Worktrees["tokyo"].Archived)
+	assert.True(t, WorktreeExists(wt))
+}
+
+func TestPreserveWorktreeWork_KeepsEarlierArchiveBranch(t *testing.T) {
+	repo, wt := setupArchiveRepo(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(wt, "scratch.txt"), []byte("first\n"), 0644))
+	first, err := PreserveWorktreeWork(wt, "tokyo", true)
+	require.NoError(t, err)
+	firstSHA := mustGit(t, repo, "rev-parse", first)
+
+	// A later worktree that got the same name is archived too
+	require.NoError(t, os.WriteFile(filepath.Join(wt, "scratch.txt"), []byte("second\n"), 0644))
+	second, err := PreserveWorktreeWork(wt, "tokyo", true)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.True(t, strings.HasPrefix(second, first+"-"))
+
+	assert.Equal(t, firstSHA, mustGit(t, repo, "rev-parse", first), "the first archive's branch is kept")
+	assert.Equal(t, firstSHA, mustGit(t, repo, "rev-parse", "origin/"+first), "and so is its pushed copy")
+	assert.Equal(t, "second\n", mustGit(t, repo, "show", second+":scratch.txt"))
+}
@@ -0,0 +1,371 @@
+package workspace
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/detect"
+)
+
+// dependencyLayout is where a package manager keeps a worktree's dependencies
+// and the lockfiles that pin them
+type dependencyLayout struct {
+	dirs      []string
+	lockfiles []string
+}
+
+// dependencyLayouts by detect.ProjectInfo.PackageManager. Package managers with
+// a global cache (go, gradle, spm) gain nothing from seeding and are left out.
+var dependencyLayouts = map[string]dependencyLayout{
+	"bun":   {dirs: []string{"**/node_modules"}, lockfiles: []string{"bun.lock", "bun.lockb"}},
+	"pnpm":  {dirs: []string{"**/node_modules"}, lockfiles: []string{"pnpm-lock.yaml"}},
+	"yarn":  {dirs: []string{"**/node_modules"}, lockfiles: []string{"yarn.lock"}},
+	"npm":   {dirs: []string{"**/node_modules"}, lockfiles: []string{"package-lock.json"}},
+	"pip":   {dirs: []string{".venv", "venv"}, lockfiles: []string{"uv.lock", "poetry.lock", "Pipfile.lock", "requirements.txt", "requirements-dev.txt", "pyproject.toml"}},
+	"cargo": {dirs: []string{"target"}, lockfiles: []string{"Cargo.lock"}},
+}
+
+// SeedResult describes how a worktree's dependency directories were seeded
+type SeedResult struct {
+	// Donor is the worktree the directories were copied from
+	Donor string
+	// Dirs are the seeded directories, relative to the worktree
+	Dirs []string
+	// Method is how files were copied: "reflink", "hardlink" or "copy"
+	Method   string
+	Files    int
+	Duration time.Duration
+}
+
+// String summarizes the result for setup logs
+func (r *SeedResult) String() string {
+	return fmt.Sprintf("Seeded %s from %s (%s, %s, %s)", strings.Join(r.Dirs, ", "), r.Donor,
+		r.Method, pluralize(r.Files, "file", "files"), r.Duration.Round(time.Millisecond))
+}
+
+// SeedDependencies fills a new worktree's dependency directories (node_modules,
+// .venv, target) from the most recently created worktree, or else the root
+// checkout, whose lockfiles have the same content, so the setup script's
+// install only has to verify them. Returns nil if there was nothing to seed
+// or no worktree had matching lockfiles.
+func SeedDependencies(project *config.Project, worktreeName string, worktree *config.Worktree) (*SeedResult, error) {
+	projectConfig, _ := config.LoadProjectConfig(project.Path)
+	var cacheConfig *config.DependencyCacheConfig
+	if projectConfig != nil {
+		cacheConfig = projectConfig.DependencyCache
+	}
+	if cacheConfig != nil && cacheConfig.Disabled {
+		return nil, nil
+	}
+	if !WorktreeExists(worktree.Path) {
+		return nil, nil
+	}
+
+	layout := detectDependencyLayout(worktree.Path, cacheConfig)
+	if len(layout.dirs) == 0 {
+		return nil, nil
+	}
+	key := lockfileHash(worktree.Path, layout.lockfiles)
+	if key == "" {
+		return nil, nil // without a lockfile, a cache can't be matched to the dependencies
+	}
+
+	for _, donor := range dependencyDonors(project, worktreeName) {
+		if lockfileHash(donor.path, layout.lockfiles) != key {
+			continue
+		}
+		var dirs []string
+		for _, pattern := range layout.dirs {
+			matches, err := matchProjectFiles(donor.path, pattern)
+			if err != nil {
+				return nil, err
+			}
+			dirs = append(dirs, matches...)
+		}
+		if len(dirs) == 0 {
+			continue
+		}
+		return seedDirs(donor, worktree.Path, dirs, cacheConfig.GetMethod())
+	}
+	return nil, nil
+}
+
+// detectDependencyLayout returns the dependency directories and lockfiles of
+// the worktree's package manager, with the project's overrides applied
+func detectDependencyLayout(worktreePath string, cacheConfig *config.DependencyCacheConfig) dependencyLayout {
+	var layout dependencyLayout
+	if info, err := detect.DetectProject(worktreePath); err == nil {
+		layout = dependencyLayouts[info.PackageManager]
+	}
+	if cacheConfig != nil && len(cacheConfig.Dirs) > 0 {
+		layout.dirs = cacheConfig.Dirs
+	}
+	if cacheConfig != nil && len(cacheConfig.Lockfiles) > 0 {
+		layout.lockfiles = cacheConfig.Lockfiles
+	}
+	return layout
+}
+
+// lockfileHash hashes the names and contents of the lockfiles present in dir,
+// or returns "" if there are none
+func lockfileHash(dir string, lockfiles []string) string {
+	h := sha256.New()
+	found := false
+	for _, name := range lockfiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		found = true
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00", name, len(data))
+		h.Write(data)
+	}
+	if !found {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// dependencyDonor is a worktree dependency directories can be copied from
+type dependencyDonor struct {
+	name string
+	path string
+}
+
+// dependencyDonors lists the worktrees to seed from, most recently created
+// first and the root checkout last. Worktrees still being set up are skipped:
+// their install may be half done.
+func dependencyDonors(project *config.Project, exclude string) []dependencyDonor {
+	type candidate struct {
+		dependencyDonor
+		createdAt time.Time
+	}
+	var siblings []candidate
+	var root *dependencyDonor
+	for name, wt := range project.Worktrees {
+		if name == exclude || wt == nil || wt.Archived || !WorktreeExists(wt.Path) {
+			continue
+		}
+		if wt.IsRoot {
+			root = &dependencyDonor{name: name, path: wt.Path}
+			continue
+		}
+		if wt.SetupStatus != config.SetupStatusDone {
+			continue
+		}
+		siblings = append(siblings, candidate{dependencyDonor{name: name, path: wt.Path}, wt.CreatedAt})
+	}
+	sort.Slice(siblings, func(i, j int) bool { return siblings[i].createdAt.After(siblings[j].createdAt) })
+
+	donors := make([]dependencyDonor, 0, len(siblings)+1)
+	for _, c := range siblings {
+		donors = append(donors, c.dependencyDonor)
+	}
+	if root == nil && project.Path != "" && WorktreeExists(project.Path) {
+		root = &dependencyDonor{name: "root", path: project.Path}
+	}
+	if root != nil {
+		donors = append(donors, *root)
+	}
+	return donors
+}
+
+// seedDirs copies each directory into the worktree. A directory is copied
+// into a scratch path first and renamed into place, so an interrupted seed
+// never leaves a partial node_modules for the install to trip over.
+func seedDirs(donor dependencyDonor, worktreePath string, dirs []string, method string) (*SeedResult, error) {
+	start := time.Now()
+	copier := &treeSeeder{oldRoot: donor.path, newRoot: worktreePath}
+	switch method {
+	case config.DependencyCacheCopy:
+		copier.method = seedCopy
+	case config.DependencyCacheHardlink:
+		copier.method = seedReflink
+		copier.hardlinks = true
+	default:
+		// Hardlinks share inodes with the donor, so a tool that writes
+		// installed files in place would change the donor too
+		copier.method = seedReflink
+	}
+
+	result := &SeedResult{Donor: donor.name}
+	for _, rel := range dirs {
+		dst := filepath.Join(worktreePath, filepath.FromSlash(rel))
+		if _, err := os.Lstat(dst); err == nil {
+			continue // the checkout already has it
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return nil, err
+		}
+
+		scratch := dst + ".conductor-seed"
+		_ = os.RemoveAll(scratch) // leftover from an interrupted seed
+		src := filepath.Join(donor.path, filepath.FromSlash(rel))
+		if err := copier.tree(src, scratch); err != nil {
+			_ = os.RemoveAll(scratch)
+			return nil, fmt.Errorf("failed to seed %s: %w", rel, err)
+		}
+		if isVirtualenv(scratch) {
+			if err := relocateVirtualenv(scratch, donor.path, worktreePath); err != nil {
+				_ = os.RemoveAll(scratch)
+				return nil, fmt.Errorf("failed to relocate %s: %w", rel, err)
+			}
+		}
+		if err := os.Rename(scratch, dst); err != nil {
+			_ = os.RemoveAll(scratch)
+			return nil, err
+		}
+		result.Dirs = append(result.Dirs, rel)
+	}
+	if len(result.Dirs) == 0 {
+		return nil, nil
+	}
+
+	result.Method = copier.method.String()
+	result.Files = copier.files
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// seedMethod is how treeSeeder copies files, from fastest to slowest
+type seedMethod int
+
+const (
+	seedReflink seedMethod = iota
+	seedHardlink
+	seedCopy
+)
+
+func (m seedMethod) String() string {
+	switch m {
+	case seedReflink:
+		return "reflink"
+	case seedHardlink:
+		return "hardlink"
+	default:
+		return "copy"
+	}
+}
+
+// treeSeeder copies directory trees with the fastest method the filesystem
+// supports, stepping down to the next method the first time one fails.
+// Hardlinks are only tried when allowed.
+type treeSeeder struct {
+	method    seedMethod
+	hardlinks bool
+	files     int
+
+	// Absolute symlinks into oldRoot are pointed at newRoot instead
+	oldRoot, newRoot string
+}
+
+func (s *treeSeeder) tree(src, dst string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case d.IsDir():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			if filepath.IsAbs(link) {
+				if r, err := filepath.Rel(s.oldRoot, link); err == nil && filepath.IsLocal(r) {
+					link = filepath.Join(s.newRoot, r)
+				}
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			s.files++
+			return s.file(p, target)
+		}
+		return nil // sockets, devices and pipes are skipped
+	})
+}
+
+func (s *treeSeeder) file(src, dst string) error {
+	if s.method == seedReflink {
+		if err := reflinkFile(src, dst); err == nil {
+			return nil
+		}
+		_ = os.Remove(dst)
+		s.method = seedCopy
+		if s.hardlinks {
+			s.method = seedHardlink
+		}
+	}
+	if s.method == seedHardlink {
+		if err := os.Link(src, dst); err == nil {
+			return nil
+		}
+		s.method = seedCopy
+	}
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	return cloneOrCopyFile(src, dst, info.Mode().Perm(), false)
+}
+
+// isVirtualenv reports whether dir is a Python virtualenv
+func isVirtualenv(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "pyvenv.cfg"))
+	return err == nil
+}
+
+// relocateVirtualenv rewrites the absolute paths a virtualenv keeps to the
+// checkout it was created in: script shebangs, activate scripts and editable
+// install hooks. Files are replaced rather than edited in place so hardlinked
+// copies don't change the donor's virtualenv.
+func relocateVirtualenv(venv, oldRoot, newRoot string) error {
+	old, repl := []byte(oldRoot), []byte(newRoot)
+	var candidates []string
+	for _, pattern := range []string{"bin/*", "Scripts/*", "lib/*/site-packages/*.pth", "lib/*/site-packages/__editable__*", "Lib/site-packages/*.pth", "Lib/site-packages/__editable__*"} {
+		matches, _ := filepath.Glob(filepath.Join(venv, filepath.FromSlash(pattern)))
+		candidates = append(candidates, matches...)
+	}
+
+	const maxScriptSize = 1 << 20
+	for _, path := range candidates {
+		info, err := os.Lstat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxScriptSize {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(data, 0) != -1 || !bytes.Contains(data, old) {
+			continue // binaries and files without the old path
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, bytes.ReplaceAll(data, old, repl), info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
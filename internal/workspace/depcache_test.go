@@ -0,0 +1,212 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0755))
+	}
+}
+
+func TestLockfileHash(t *testing.T) {
+	a, b := t.TempDir(), t.TempDir()
+	lockfiles := []string{"bun.lock", "bun.lockb"}
+
+	assert.Empty(t, lockfileHash(a, lockfiles), "no lockfile, no key")
+
+	writeTree(t, a, map[string]string{"bun.lock": "deps v1"})
+	writeTree(t, b, map[string]string{"bun.lock": "deps v1"})
+	assert.NotEmpty(t, lockfileHash(a, lockfiles))
+	assert.Equal(t, lockfileHash(a, lockfiles), lockfileHash(b, lockfiles))
+
+	writeTree(t, b, map[string]string{"bun.lock": "deps v2"})
+	assert.NotEqual(t, lockfileHash(a, lockfiles), lockfileHash(b, lockfiles))
+
+	// The same content under another lockfile name is a different key
+	writeTree(t, b, map[string]string{"bun.lock": "", "bun.lockb": "deps v1"})
+	require.NoError(t, os.Remove(filepath.Join(b, "bun.lock")))
+	assert.NotEqual(t, lockfileHash(a, lockfiles), lockfileHash(b, lockfiles))
+}
+
+func TestDependencyDonors(t *testing.T) {
+	root := t.TempDir()
+	dir := func(name string) string {
+		p := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(p, 0755))
+		return p
+	}
+	now := time.Now()
+	project := &config.Project{
+		Path: dir("repo"),
+		Worktrees: map[string]*config.Worktree{
+			"root":   {Path: filepath.Join(root, "repo"), IsRoot: true},
+			"old":    {Path: dir("old"), CreatedAt: now.Add(-2 * time.Hour), SetupStatus: config.SetupStatusDone},
+			"recent": {Path: dir("recent"), CreatedAt: now.Add(-time.Hour), SetupStatus: config.SetupStatusDone},
+			"busy":   {Path: dir("busy"), CreatedAt: now, SetupStatus: config.SetupStatusRunning},
+			"gone":   {Path: filepath.Join(root, "gone"), CreatedAt: now, SetupStatus: config.SetupStatusDone},
+			"shelf":  {Path: dir("shelf"), CreatedAt: now, SetupStatus: config.SetupStatusDone, Archived: true},
+			"new":    {Path: dir("new"), CreatedAt: now, SetupStatus: config.SetupStatusRunning},
+		},
+	}
+
+	var names []string
+	for _, d := range dependencyDonors(project, "new") {
+		names = append(names, d.name)
+	}
+	assert.Equal(t, []string{"recent", "old", "root"}, names)
+}
+
+func TestSeedDependencies(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	donor := filepath.Join(root, "donor")
+	wt := filepath.Join(root, "wt")
+
+	manifest := map[string]string{"package.json": `{"dependencies":{"left-pad":"1.0.0"}}`, "bun.lock": "lock v1"}
+	writeTree(t, repo, manifest)
+	writeTree(t, donor, manifest)
+	writeTree(t, wt, manifest)
+	writeTree(t, donor, map[string]string{
+		"node_modules/left-pad/index.js":     "module.exports = pad",
+		"apps/web/node_modules/x/index.js":   "x",
+		"node_modules/left-pad/package.json": "{}",
+	})
+	require.NoError(t, os.Symlink("../left-pad/index.js", filepath.Join(donor, "node_modules", "pad")))
+
+	project := &config.Project{
+		Path: repo,
+		Worktrees: map[string]*config.Worktree{
+			"root":  {Path: repo, IsRoot: true},
+			"donor": {Path: donor, CreatedAt: time.Now().Add(-time.Hour), SetupStatus: config.SetupStatusDone},
+			"wt":    {Path: wt, CreatedAt: time.Now(), SetupStatus: config.SetupStatusRunning},
+		},
+	}
+
+	result, err := SeedDependencies(project, "wt", project.Worktrees["wt"])
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "donor", result.Donor, "root has no node_modules, the sibling does")
+	assert.Equal(t, []string{"apps/web/node_modules", "node_modules"}, result.Dirs)
+	assert.Equal(t, 3, result.Files)
+
+	data, err := os.ReadFile(filepath.Join(wt, "node_modules/left-pad/index.js"))
+	require.NoError(t, err)
+	assert.Equal(t, "module.exports = pad", string(data))
+	link, err := os.Readlink(filepath.Join(wt, "node_modules", "pad"))
+	require.NoError(t, err)
+	assert.Equal(t, "../left-pad/index.js", link)
+	_, err = os.Stat(filepath.Join(wt, "node_modules.conductor-seed"))
+	assert.True(t, os.IsNotExist(err), "scratch directory is renamed into place")
+
+	// A lockfile change means the cache is stale
+	other := filepath.Join(root, "other")
+	writeTree(t, other, map[string]string{"package.json": manifest["package.json"], "bun.lock": "lock v2"})
+	project.Worktrees["other"] = &config.Worktree{Path: other, CreatedAt: time.Now()}
+	result, err = SeedDependencies(project, "other", project.Worktrees["other"])
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	// Seeding can be turned off
+	require.NoError(t, os.RemoveAll(filepath.Join(wt, "node_modules")))
+	require.NoError(t, config.SaveProjectConfig(repo, &config.ProjectConfig{DependencyCache: &config.DependencyCacheConfig{Disabled: true}}))
+	result, err = SeedDependencies(project, "wt", project.Worktrees["wt"])
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestSeedDependencies_CopyMethodDoesNotShareFiles(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	wt := filepath.Join(root, "wt")
+	writeTree(t, repo, map[string]string{"Cargo.toml": "[package]", "Cargo.lock": "v1", "target/debug/app": "bin"})
+	writeTree(t, wt, map[string]string{"Cargo.toml": "[package]", "Cargo.lock": "v1"})
+	require.NoError(t, config.SaveProjectConfig(repo, &config.ProjectConfig{DependencyCache: &config.DependencyCacheConfig{Method: config.DependencyCacheCopy}}))
+
+	project := &config.Project{Path: repo, Worktrees: map[string]*config.Worktree{"root": {Path: repo, IsRoot: true}}}
+	result, err := SeedDependencies(project, "wt", &config.Worktree{Path: wt})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "copy", result.Method)
+
+	require.NoError(t, os.WriteFile(filepath.Join(wt, "target/debug/app"), []byte("rebuilt"), 0755))
+	data, err := os.ReadFile(filepath.Join(repo, "target/debug/app"))
+	require.NoError(t, err)
+	assert.Equal(t, "bin", string(data))
+}
+
+func TestRelocateVirtualenv(t *testing.T) {
+	root := t.TempDir()
+	oldRoot := filepath.Join(root, "repo")
+	newRoot := filepath.Join(root, "wt")
+	venv := filepath.Join(newRoot, ".venv")
+	writeTree(t, venv, map[string]string{
+		"pyvenv.cfg":                           "home = /usr/bin\n",
+		"bin/activate":                         "VIRTUAL_ENV=\"" + oldRoot + "/.venv\"\n",
+		"bin/pytest":                           "#!" + oldRoot + "/.venv/bin/python\nimport pytest\n",
+		"bin/compiled":                         "\x00" + oldRoot,
+		"lib/python3.12/site-packages/app.pth": oldRoot + "/src\n",
+	})
+	// A hardlinked copy must not change the donor's file
+	donorFile := filepath.Join(root, "donor-activate")
+	require.NoError(t, os.WriteFile(donorFile, []byte("VIRTUAL_ENV=\""+oldRoot+"/.venv\"\n"), 0644))
+	require.NoError(t, os.Remove(filepath.Join(venv, "bin/activate")))
+	require.NoError(t, os.Link(donorFile, filepath.Join(venv, "bin/activate")))
+
+	require.True(t, isVirtualenv(venv))
+	require.NoError(t, relocateVirtualenv(venv, oldRoot, newRoot))
+
+	read := func(rel string) string {
+		data, err := os.ReadFile(filepath.Join(venv, rel))
+		require.NoError(t, err)
+		return string(data)
+	}
+	assert.Equal(t, "VIRTUAL_ENV=\""+newRoot+"/.venv\"\n", read("bin/activate"))
+	assert.Equal(t, "#!"+newRoot+"/.venv/bin/python\nimport pytest\n", read("bin/pytest"))
+	assert.Equal(t, "\x00"+oldRoot, read("bin/compiled"), "binaries are left alone")
+	assert.Equal(t, newRoot+"/src\n", read("lib/python3.12/site-packages/app.pth"))
+
+	data, err := os.ReadFile(donorFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), oldRoot+"/.venv")
+
+	info, err := os.Stat(filepath.Join(venv, "bin/pytest"))
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode().Perm()&0100, "scripts stay executable")
+}
+
+func TestSeedDependencies_AutoMethodDoesNotShareFiles(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	wt := filepath.Join(root, "wt")
+	writeTree(t, repo, map[string]string{"Cargo.toml": "[package]", "Cargo.lock": "v1", "target/debug/app": "bin"})
+	writeTree(t, wt, map[string]string{"Cargo.toml": "[package]", "Cargo.lock": "v1"})
+
+	project := &config.Project{Path: repo, Worktrees: map[string]*config.Worktree{"root": {Path: repo, IsRoot: true}}}
+	result, err := SeedDependencies(project, "wt", &config.Worktree{Path: wt})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.NotEqual(t, "hardlink", result.Method)
+
+	// Write the seeded file in place, the way a tool patching it would
+	f, err := os.OpenFile(filepath.Join(wt, "target/debug/app"), os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteAt([]byte("BIN"), 0)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	data, err := os.ReadFile(filepath.Join(repo, "target/debug/app"))
+	require.NoError(t, err)
+	assert.Equal(t, "bin", string(data))
+}
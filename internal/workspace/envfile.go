@@ -0,0 +1,305 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/runner"
+	"github.com/hammashamzah/conductor/internal/store"
+)
+
+const (
+	envBlockStart = "# >>> conductor managed block (regenerated automatically, do not edit) >>>"
+	envBlockEnd   = "# <<< conductor managed block <<<"
+)
+
+// envFileSkipped are variables left out of the env file unless a template asks
+// for them: the source database URL may carry production credentials
+var envFileSkipped = map[string]bool{
+	"CONDUCTOR_DB_SOURCE": true,
+}
+
+// WriteEnvFile regenerates the managed block of the worktree's env file from
+// the project's envFile config. Lines outside the block are left untouched.
+// Returns the file path, or "" if the project has no envFile configured.
+func WriteEnvFile(projectName string, project *config.Project, worktreeName string, worktree *config.Worktree) (string, error) {
+	projectConfig, _ := config.LoadProjectConfig(project.Path)
+	if projectConfig == nil || projectConfig.EnvFile == nil || !WorktreeExists(worktree.Path) {
+		return "", nil
+	}
+
+	rel := projectConfig.EnvFile.GetPath()
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("envFile.path '%s' must be a relative path inside the worktree", rel)
+	}
+	path := filepath.Join(worktree.Path, rel)
+
+	envMap, err := runner.GetEnvMap(projectName, project, worktreeName, worktree, projectConfig)
+	if err != nil {
+		return "", err
+	}
+	block := renderEnvBlock(envFileVars(envMap, projectConfig.EnvFile.Template))
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read env file: %w", err)
+	}
+	updated := replaceEnvBlock(string(existing), block)
+	if updated == string(existing) {
+		return path, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create env file directory: %w", err)
+	}
+	if err := config.WriteFileAtomic(path, []byte(updated), 0600); err != nil {
+		return "", fmt.Errorf("failed to write env file: %w", err)
+	}
+
+	// Keep the generated file out of git status so it never blocks archiving
+	excludeFromGit(worktree.Path, rel, "conductor env file")
+
+	return path, nil
+}
+
+// RefreshEnvFile regenerates a worktree's env file from the store's current
+// state; call it after ports, the tunnel or the database URL change
+func RefreshEnvFile(s *store.Store, projectName, worktreeName string) (string, error) {
+	project, ok := s.GetProject(projectName)
+	if !ok {
+		return "", fmt.Errorf("project '%s' not found", projectName)
+	}
+	worktree, ok := s.GetWorktree(projectName, worktreeName)
+	if !ok {
+		return "", fmt.Errorf("worktree '%s' not found", worktreeName)
+	}
+	if worktree.Archived {
+		return "", nil
+	}
+	return WriteEnvFile(projectName, project, worktreeName, worktree)
+}
+
+// RemoveEnvFile strips conductor's block from the worktree's env file, and
+// deletes the file if nothing else is left in it
+func RemoveEnvFile(project *config.Project, worktree *config.Worktree) error {
+	projectConfig, _ := config.LoadProjectConfig(project.Path)
+	if projectConfig == nil || projectConfig.EnvFile == nil {
+		return nil
+	}
+	rel := projectConfig.EnvFile.GetPath()
+	if !filepath.IsLocal(rel) {
+		return nil
+	}
+	path := filepath.Join(worktree.Path, rel)
+
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	remaining := replaceEnvBlock(string(existing), "")
+	if strings.TrimSpace(remaining) == "" {
+		return os.Remove(path)
+	}
+	if remaining == string(existing) {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return config.WriteFileAtomic(path, []byte(remaining), info.Mode().Perm())
+}
+
+// envFileVars returns the sorted KEY=value lines for the env file. Without a
+// template every conductor variable is written; with one, only the template's
+// keys, with ${VAR} references expanded from conductor's variables.
+func envFileVars(envMap map[string]string, template map[string]string) []string {
+	values := make(map[string]string)
+	if len(template) == 0 {
+		for k, v := range envMap {
+			if !envFileSkipped[k] {
+				values[k] = v
+			}
+		}
+	} else {
+		for k, v := range template {
+			values[k] = os.Expand(v, func(name string) string { return envMap[name] })
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, len(keys))
+	for i, k := range keys {
+		lines[i] = k + "=" + quoteEnvValue(values[k])
+	}
+	return lines
+}
+
+// quoteEnvValue double-quotes values that dotenv parsers would otherwise split or truncate
+func quoteEnvValue(v string) string {
+	if !strings.ContainsAny(v, " \t\n\"'#$\\`") {
+		return v
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "$", `\$`, "`", "\\`")
+	return `"` + r.Replace(v) + `"`
+}
+
+// renderEnvBlock wraps lines in conductor's block markers
+func renderEnvBlock(lines []string) string {
+	var b strings.Builder
+	b.WriteString(envBlockStart + "\n")
+	for _, line := range lines {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString(envBlockEnd + "\n")
+	return b.String()
+}
+
+// replaceEnvBlock swaps conductor's block in content for block (which may be
+// empty to remove it). Without an existing block, block is appended.
+func replaceEnvBlock(content, block string) string {
+	start := strings.Index(content, envBlockStart)
+	if start == -1 {
+		if block == "" {
+			return content
+		}
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		if content != "" {
+			content += "\n"
+		}
+		return content + block
+	}
+
+	end := len(content)
+	if i := strings.Index(content[start:], envBlockEnd); i != -1 {
+		end = start + i + len(envBlockEnd)
+		if end < len(content) && content[end] == '\n' {
+			end++
+		}
+	}
+
+	before, after := content[:start], content[end:]
+	if block == "" {
+		// Drop the blank line that separated the block from the user's lines
+		before = strings.TrimRight(before, "\n")
+		if before != "" {
+			before += "\n"
+		}
+	}
+	return before + block + after
+}
+
+// excludeFromGit hides rel from git status in this worktree only, under a
+// comment saying why, unless git already ignores it. The repository's
+// info/exclude is shared by all worktrees and the main checkout, so the
+// pattern goes into the worktree's own exclude file instead (see
+// worktreeExcludeFile). Best-effort: errors leave the file visible in git status.
+func excludeFromGit(worktreePath, rel, reason string) {
+	if _, err := runGit(worktreePath, "check-ignore", "-q", rel); err == nil {
+		return
+	}
+	excludePath, err := worktreeExcludeFile(worktreePath)
+	if err != nil {
+		return
+	}
+	f, err := os.OpenFile(excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+	_, _ = fmt.Fprintf(f, "\n# %s\n/%s\n", reason, escapeGitignore(filepath.ToSlash(rel)))
+}
+
+// worktreeExcludeFile returns info/exclude in a linked worktree's own git dir,
+// which git doesn't read by itself: the first call makes it the worktree's
+// core.excludesFile (via extensions.worktreeConfig). That replaces the user's
+// global excludes file for the worktree, so its patterns are copied in.
+func worktreeExcludeFile(worktreePath string) (string, error) {
+	gitDir, err := gitOutput(worktreePath, nil, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", err
+	}
+	commonDir, err := gitOutput(worktreePath, nil, "rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(worktreePath, commonDir)
+	}
+	if filepath.Clean(gitDir) == filepath.Clean(commonDir) {
+		return "", fmt.Errorf("%s is not a linked worktree", worktreePath)
+	}
+
+	excludePath := filepath.Join(gitDir, "info", "exclude")
+	if current, err := gitOutput(worktreePath, nil, "config", "--worktree", "--get", "core.excludesFile"); err == nil && current == excludePath {
+		return excludePath, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(excludePath); os.IsNotExist(err) {
+		var seed string
+		if global := globalExcludesFile(worktreePath); global != "" {
+			if data, err := os.ReadFile(global); err == nil && len(data) > 0 {
+				seed = fmt.Sprintf("# copied from %s, which this file replaces in this worktree\n%s", global, data)
+			}
+		}
+		if err := os.WriteFile(excludePath, []byte(seed), 0644); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := gitOutput(worktreePath, nil, "config", "extensions.worktreeConfig", "true"); err != nil {
+		return "", err
+	}
+	if _, err := gitOutput(worktreePath, nil, "config", "--worktree", "core.excludesFile", excludePath); err != nil {
+		return "", err
+	}
+	return excludePath, nil
+}
+
+// globalExcludesFile returns the excludes file git uses when core.excludesFile
+// isn't set per worktree, or "" if there is none
+func globalExcludesFile(worktreePath string) string {
+	if p, err := gitOutput(worktreePath, nil, "config", "--path", "--get", "core.excludesFile"); err == nil && p != "" {
+		return p
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "git", "ignore")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".config", "git", "ignore")
+	}
+	return ""
+}
+
+// escapeGitignore escapes the characters gitignore would otherwise read as
+// wildcards, negation, a comment or trailing whitespace
+func escapeGitignore(rel string) string {
+	var b strings.Builder
+	for i, r := range rel {
+		if strings.ContainsRune(`*?[\`, r) ||
+			i == 0 && (r == '!' || r == '#') ||
+			r == ' ' && strings.TrimRight(rel[i:], " ") == "" {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteEnvFile_MergesIntoTargetAndRegenerates(t *testing.T) {
+	repo, wt := setupArchiveRepo(t)
+	require.NoError(t, config.SaveProjectConfig(repo, &config.ProjectConfig{
+		Ports: config.PortConfig{Default: 2, Labels: []string{"web", "api"}},
+		EnvFile: &config.EnvFileConfig{
+			Path: ".env.local",
+			Template: map[string]string{
+				"API_URL":  "http://localhost:${CONDUCTOR_PORT_API}",
+				"APP_NAME": "my app",
+			},
+		},
+	}))
+	envPath := filepath.Join(wt, ".env.local")
+	require.NoError(t, os.WriteFile(envPath, []byte("SECRET=mine\n"), 0600))
+
+	project := &config.Project{Path: repo}
+	worktree := &config.Worktree{Path: wt, Branch: "feature", Ports: []int{3100, 3101}}
+
+	path, err := WriteEnvFile("app", project, "tokyo", worktree)
+	require.NoError(t, err)
+	assert.Equal(t, envPath, path)
+	data, err := os.ReadFile(envPath)
+	require.NoError(t, err)
+	assert.Equal(t, "SECRET=mine\n\n"+envBlockStart+"\nAPI_URL=http://localhost:3101\nAPP_NAME=\"my app\"\n"+envBlockEnd+"\n", string(data))
+
+	// Only the managed block changes when ports move
+	worktree.Ports = []int{3200, 3201}
+	_, err = WriteEnvFile("app", project, "tokyo", worktree)
+	require.NoError(t, err)
+	data, err = os.ReadFile(envPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "SECRET=mine\n")
+	assert.Contains(t, string(data), "API_URL=http://localhost:3201\n")
+	assert.NotContains(t, string(data), "3101")
+
+	// The generated file never counts as unsaved work
+	risk, err := CheckArchiveSafety(wt, "feature")
+	require.NoError(t, err)
+	assert.False(t, risk.HasRisk())
+
+	require.NoError(t, RemoveEnvFile(project, worktree))
+	data, err = os.ReadFile(envPath)
+	require.NoError(t, err)
+	assert.Equal(t, "SECRET=mine\n", string(data))
+}
+
+func TestWriteEnvFile_DefaultFileWithAllVariables(t *testing.T) {
+	repo, wt := setupArchiveRepo(t)
+	require.NoError(t, config.SaveProjectConfig(repo, &config.ProjectConfig{EnvFile: &config.EnvFileConfig{}}))
+
+	project := &config.Project{Path: repo, Database: &config.DatabaseConfig{Source: "postgres://u:p@prod/db"}}
+	worktree := &config.Worktree{Path: wt, Branch: "feature", Ports: []int{3100}, DatabaseName: "app-3100", DatabaseURL: "postgres://localhost/app-3100"}
+
+	path, err := WriteEnvFile("app", project, "tokyo", worktree)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(wt, config.DefaultEnvFilePath), path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "PORT=3100\n")
+	assert.Contains(t, string(data), "DATABASE_URL=postgres://localhost/app-3100\n")
+	assert.NotContains(t, string(data), "CONDUCTOR_DB_SOURCE", "source credentials are only written when templated")
+
+	require.NoError(t, RemoveEnvFile(project, worktree))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestWriteEnvFile_NotConfigured(t *testing.T) {
+	repo, wt := setupArchiveRepo(t)
+
+	path, err := WriteEnvFile("app", &config.Project{Path: repo}, "tokyo", &config.Worktree{Path: wt})
+	require.NoError(t, err)
+	assert.Empty(t, path)
+}
@@ -0,0 +1,386 @@
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/runner"
+)
+
+// templateVarPattern matches the ${VAR} references expanded in templated files.
+// Bare $VAR is left alone so shell snippets and dotenv interpolation survive.
+var templateVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// PlaceWorktreeFiles brings the untracked files matched by the project's
+// files rules from the project root into a worktree. Paths that already
+// exist in the worktree (tracked files, or a previous run) are left alone.
+// A failing file doesn't stop the others; their errors are joined.
+func PlaceWorktreeFiles(projectName string, project *config.Project, worktreeName string, worktree *config.Worktree) ([]config.WorktreeFile, error) {
+	projectConfig, _ := config.LoadProjectConfig(project.Path)
+	if projectConfig == nil || len(projectConfig.Files) == 0 || !WorktreeExists(worktree.Path) {
+		return nil, nil
+	}
+
+	var envMap map[string]string
+	var placed []config.WorktreeFile
+	var errs []error
+	seen := make(map[string]bool)
+
+	for _, rule := range projectConfig.Files {
+		mode := rule.GetMode()
+		if mode != config.FileModeCopy && mode != config.FileModeSymlink && mode != config.FileModeReflink {
+			errs = append(errs, fmt.Errorf("files: pattern '%s' has unknown mode '%s'", rule.Pattern, rule.Mode))
+			continue
+		}
+		matches, err := matchProjectFiles(project.Path, rule.Pattern)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, rel := range matches {
+			// First rule to match a path wins; skip anything inside a placed directory
+			if seen[rel] || insidePlaced(seen, rel) {
+				continue
+			}
+			seen[rel] = true
+
+			if rule.Template && envMap == nil {
+				envMap, err = runner.GetEnvMap(projectName, project, worktreeName, worktree, projectConfig)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("files: %s: %w", rel, err))
+					continue
+				}
+			}
+			file, err := placeFile(project.Path, worktree.Path, rel, mode, rule.Template, envMap)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("files: %s: %w", rel, err))
+				continue
+			}
+			if file == nil {
+				continue
+			}
+			placed = append(placed, *file)
+
+			// Keep placed files out of git status so they never block archiving
+			excludeFromGit(worktree.Path, rel, "conductor placed file")
+		}
+	}
+	return placed, errors.Join(errs...)
+}
+
+// ModifiedWorktreeFiles returns the files placed from the project root that
+// have since been changed in the worktree, including files changed or added
+// inside a copied directory. Archive discards them.
+func ModifiedWorktreeFiles(worktree *config.Worktree) []string {
+	var modified []string
+	for _, f := range worktree.Files {
+		if f.Manifest != nil {
+			modified = append(modified, modifiedInTree(filepath.Join(worktree.Path, f.Path), f.Path, f.Manifest)...)
+			continue
+		}
+		if f.Hash == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(worktree.Path, f.Path))
+		if err != nil {
+			continue // deleted files lose nothing
+		}
+		if hashPlacedFile(data) != f.Hash {
+			modified = append(modified, f.Path)
+		}
+	}
+	return modified
+}
+
+// modifiedInTree returns the files under dir, as rel/<path>, whose content
+// differs from manifest or that manifest doesn't list
+func modifiedInTree(dir, rel string, manifest map[string]string) []string {
+	current, err := hashPlacedTree(dir)
+	if err != nil {
+		return nil // a removed directory loses nothing
+	}
+	var modified []string
+	for p, hash := range current {
+		if manifest[p] != hash {
+			modified = append(modified, path.Join(rel, p))
+		}
+	}
+	sort.Strings(modified)
+	return modified
+}
+
+// matchProjectFiles expands a files pattern to paths relative to the project root
+func matchProjectFiles(root, pattern string) ([]string, error) {
+	pattern = filepath.ToSlash(pattern)
+	if !filepath.IsLocal(filepath.FromSlash(pattern)) {
+		return nil, fmt.Errorf("files: pattern '%s' must be relative to the project root", pattern)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("files: invalid pattern '%s': %w", pattern, err)
+	}
+
+	if !strings.Contains(pattern, "**") {
+		paths, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, err
+		}
+		var matches []string
+		for _, p := range paths {
+			rel, err := filepath.Rel(root, p)
+			if err != nil || isGitPath(filepath.ToSlash(rel)) {
+				continue
+			}
+			matches = append(matches, filepath.ToSlash(rel))
+		}
+		return matches, nil
+	}
+
+	// Walk from the part of the pattern without wildcards
+	segments := strings.Split(pattern, "/")
+	base := 0
+	for base < len(segments)-1 && !strings.ContainsAny(segments[base], "*?[") {
+		base++
+	}
+	start := filepath.Join(root, filepath.FromSlash(strings.Join(segments[:base], "/")))
+
+	var matches []string
+	err := filepath.WalkDir(start, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == start && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipAll
+			}
+			return nil // unreadable directories are skipped
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if matchSegments(segments, strings.Split(rel, "/")) {
+			matches = append(matches, rel)
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	sort.Strings(matches)
+	return matches, err
+}
+
+// matchSegments matches a path against a pattern segment by segment, with
+// "**" standing for zero or more directories
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// isGitPath reports whether a relative path is git's own metadata
+func isGitPath(rel string) bool {
+	return rel == ".git" || strings.HasPrefix(rel, ".git/")
+}
+
+// insidePlaced reports whether rel is inside a directory already placed
+func insidePlaced(placed map[string]bool, rel string) bool {
+	for dir := path.Dir(rel); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if placed[dir] {
+			return true
+		}
+	}
+	return false
+}
+
+// placeFile copies, links or clones one match into the worktree. Returns nil
+// if the worktree already has something at that path.
+func placeFile(root, worktreePath, rel, mode string, template bool, envMap map[string]string) (*config.WorktreeFile, error) {
+	src := filepath.Join(root, filepath.FromSlash(rel))
+	dst := filepath.Join(worktreePath, filepath.FromSlash(rel))
+
+	if _, err := os.Lstat(dst); err == nil {
+		return nil, nil
+	}
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return nil, err
+	}
+
+	file := &config.WorktreeFile{Path: rel, Mode: mode}
+	if mode == config.FileModeSymlink {
+		return file, os.Symlink(src, dst)
+	}
+
+	if info.IsDir() {
+		if err := copyTree(src, dst, mode == config.FileModeReflink); err != nil {
+			return nil, err
+		}
+		manifest, err := hashPlacedTree(dst)
+		if err != nil {
+			return nil, err
+		}
+		file.Manifest = manifest
+		return file, nil
+	}
+
+	if template {
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return nil, err
+		}
+		data = expandTemplateVars(data, envMap)
+		if err := os.WriteFile(dst, data, info.Mode().Perm()); err != nil {
+			return nil, err
+		}
+		file.Hash = hashPlacedFile(data)
+		return file, nil
+	}
+
+	if err := cloneOrCopyFile(src, dst, info.Mode().Perm(), mode == config.FileModeReflink); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(dst)
+	if err != nil {
+		return nil, err
+	}
+	file.Hash = hashPlacedFile(data)
+	return file, nil
+}
+
+// copyTree copies a directory, cloning files when reflink is set. Symlinks
+// inside it are recreated as they are.
+func copyTree(src, dst string, reflink bool) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			return cloneOrCopyFile(p, target, info.Mode().Perm(), reflink)
+		}
+		return nil // sockets, devices and pipes are skipped
+	})
+}
+
+// cloneOrCopyFile clones src to dst copy-on-write when reflink is set and the
+// filesystem supports it, and copies it otherwise
+func cloneOrCopyFile(src, dst string, perm fs.FileMode, reflink bool) error {
+	if reflink {
+		if err := reflinkFile(src, dst); err == nil {
+			return nil
+		}
+		_ = os.Remove(dst) // a failed clone may leave an empty file behind
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// expandTemplateVars replaces ${VAR} references to conductor's variables
+func expandTemplateVars(data []byte, envMap map[string]string) []byte {
+	return templateVarPattern.ReplaceAllFunc(data, func(ref []byte) []byte {
+		if v, ok := envMap[string(ref[2:len(ref)-1])]; ok {
+			return []byte(v)
+		}
+		return ref
+	})
+}
+
+// hashPlacedFile hashes a placed file's content. Conductor's env file block is
+// left out, so a placed file that is also the env file doesn't count as changed.
+func hashPlacedFile(data []byte) string {
+	content := strings.TrimRight(replaceEnvBlock(string(data), ""), "\n")
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashPlacedTree hashes every regular file under dir, keyed by slash-separated
+// path relative to dir. Symlinks point elsewhere and are left out.
+func hashPlacedTree(dir string) (map[string]string, error) {
+	hashes := make(map[string]string)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		hashes[filepath.ToSlash(rel)] = hashPlacedFile(data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
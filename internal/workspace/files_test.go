@@ -0,0 +1,194 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchSegments(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{".env*", ".env", true},
+		{".env*", ".env.local", true},
+		{".env*", "config/.env", false},
+		{"config/*.key", "config/master.key", true},
+		{"**/.env", ".env", true},
+		{"**/.env", "apps/web/.env", true},
+		{"apps/**/.env", "apps/.env", true},
+		{"apps/**/.env", "apps/web/.env", true},
+		{"apps/**/.env", "libs/web/.env", false},
+		{"**/node_modules", "apps/web/node_modules", true},
+		{"**/*.pem", "certs/dev/server.pem", true},
+		{"**/*.pem", "certs/dev/server.pem.bak", false},
+	}
+	for _, tt := range tests {
+		got := matchSegments(strings.Split(tt.pattern, "/"), strings.Split(tt.name, "/"))
+		assert.Equal(t, tt.want, got, "%s ~ %s", tt.pattern, tt.name)
+	}
+}
+
+func TestPlaceWorktreeFiles(t *testing.T) {
+	repo, wt := setupArchiveRepo(t)
+	writeFile := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(repo, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0600))
+	}
+	writeFile(".env", "SECRET=abc\n")
+	writeFile(".env.local", "PORT=${PORT}\nDATABASE_URL=${DATABASE_URL}\nHOME_DIR=$HOME\nOTHER=${NOT_A_CONDUCTOR_VAR}\n")
+	writeFile("apps/web/.env", "WEB=1\n")
+	writeFile(".cache/build/out.bin", "cached")
+	writeFile("README.md", "root copy, not placed\n") // tracked, already in the worktree
+
+	require.NoError(t, config.SaveProjectConfig(repo, &config.ProjectConfig{
+		Files: []config.FileRule{
+			{Pattern: ".env.local", Template: true},
+			{Pattern: ".env*"},
+			{Pattern: "apps/**/.env", Mode: config.FileModeReflink},
+			{Pattern: ".cache", Mode: config.FileModeSymlink},
+			{Pattern: "README.md"},
+		},
+	}))
+
+	project := &config.Project{Path: repo}
+	worktree := &config.Worktree{Path: wt, Branch: "feature", Ports: []int{3100}, DatabaseName: "app_3100", DatabaseURL: "postgres://localhost/app_3100"}
+
+	files, err := PlaceWorktreeFiles("app", project, "tokyo", worktree)
+	require.NoError(t, err)
+
+	byPath := make(map[string]config.WorktreeFile)
+	for _, f := range files {
+		byPath[f.Path] = f
+	}
+	assert.Len(t, byPath, 4)
+	assert.Equal(t, config.FileModeCopy, byPath[".env"].Mode)
+	assert.Equal(t, config.FileModeCopy, byPath[".env.local"].Mode, "first matching rule wins")
+	assert.Equal(t, config.FileModeReflink, byPath["apps/web/.env"].Mode)
+	assert.Equal(t, config.FileModeSymlink, byPath[".cache"].Mode)
+	assert.Empty(t, byPath[".cache"].Hash)
+	assert.NotContains(t, byPath, "README.md", "files already in the worktree are left alone")
+
+	data, err := os.ReadFile(filepath.Join(wt, ".env.local"))
+	require.NoError(t, err)
+	assert.Equal(t, "PORT=3100\nDATABASE_URL=postgres://localhost/app_3100\nHOME_DIR=$HOME\nOTHER=${NOT_A_CONDUCTOR_VAR}\n", string(data))
+
+	data, err = os.ReadFile(filepath.Join(wt, "apps/web/.env"))
+	require.NoError(t, err)
+	assert.Equal(t, "WEB=1\n", string(data))
+
+	link, err := os.Readlink(filepath.Join(wt, ".cache"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, ".cache"), link)
+
+	data, err = os.ReadFile(filepath.Join(wt, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", string(data))
+
+	// Placed files never count as unsaved work
+	risk, err := CheckArchiveSafety(wt, "feature")
+	require.NoError(t, err)
+	assert.False(t, risk.HasRisk(), "uncommitted: %v", risk.Uncommitted)
+
+	// ...but only in this worktree: the project root still shows its own
+	// untracked files
+	status := mustGit(t, repo, "status", "--porcelain", "--untracked-files=all")
+	assert.Contains(t, status, "?? .env\n")
+	assert.Contains(t, status, "?? apps/web/.env")
+
+	// Running again leaves existing files alone
+	again, err := PlaceWorktreeFiles("app", project, "tokyo", worktree)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+}
+
+func TestModifiedWorktreeFiles(t *testing.T) {
+	repo, wt := setupArchiveRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".env"), []byte("A=1\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".env.local"), []byte("B=2"), 0600))
+	require.NoError(t, config.SaveProjectConfig(repo, &config.ProjectConfig{
+		Files:   []config.FileRule{{Pattern: ".env*"}},
+		EnvFile: &config.EnvFileConfig{Path: ".env.local"},
+	}))
+
+	project := &config.Project{Path: repo}
+	worktree := &config.Worktree{Path: wt, Branch: "feature", Ports: []int{3100}}
+
+	files, err := PlaceWorktreeFiles("app", project, "tokyo", worktree)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	worktree.Files = files
+
+	// Conductor's own env block doesn't count as a change
+	_, err = WriteEnvFile("app", project, "tokyo", worktree)
+	require.NoError(t, err)
+	assert.Empty(t, ModifiedWorktreeFiles(worktree))
+
+	require.NoError(t, os.WriteFile(filepath.Join(wt, ".env"), []byte("A=changed\n"), 0600))
+	assert.Equal(t, []string{".env"}, ModifiedWorktreeFiles(worktree))
+
+	// Deleting a placed file loses nothing
+	require.NoError(t, os.Remove(filepath.Join(wt, ".env")))
+	assert.Empty(t, ModifiedWorktreeFiles(worktree))
+}
+
+func TestModifiedWorktreeFiles_InsideCopiedDirectory(t *testing.T) {
+	repo, wt := setupArchiveRepo(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "certs", "dev"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "certs", "dev", "server.pem"), []byte("cert"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "certs", "ca.pem"), []byte("ca"), 0600))
+	require.NoError(t, config.SaveProjectConfig(repo, &config.ProjectConfig{
+		Files: []config.FileRule{{Pattern: "certs"}},
+	}))
+
+	project := &config.Project{Path: repo}
+	worktree := &config.Worktree{Path: wt, Branch: "feature", Ports: []int{3100}}
+
+	files, err := PlaceWorktreeFiles("app", project, "tokyo", worktree)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Len(t, files[0].Manifest, 2)
+	worktree.Files = files
+	assert.Empty(t, ModifiedWorktreeFiles(worktree))
+
+	require.NoError(t, os.WriteFile(filepath.Join(wt, "certs", "dev", "server.pem"), []byte("changed"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(wt, "certs", "new.pem"), []byte("new"), 0600))
+	assert.Equal(t, []string{"certs/dev/server.pem", "certs/new.pem"}, ModifiedWorktreeFiles(worktree))
+}
+
+func TestEscapeGitignore(t *testing.T) {
+	assert.Equal(t, ".env", escapeGitignore(".env"))
+	assert.Equal(t, `config/\*.key`, escapeGitignore("config/*.key"))
+	assert.Equal(t, `a\?b/\[x]`, escapeGitignore("a?b/[x]"))
+	assert.Equal(t, `\!important`, escapeGitignore("!important"))
+	assert.Equal(t, `\#notes`, escapeGitignore("#notes"))
+	assert.Equal(t, `back\\slash`, escapeGitignore(`back\slash`))
+	assert.Equal(t, `my file\ \ `, escapeGitignore("my file  "))
+}
+
+func TestExcludeFromGit_OnlyHidesInThatWorktree(t *testing.T) {
+	repo, wt := setupArchiveRepo(t)
+	other := filepath.Join(filepath.Dir(wt), "other")
+	mustGit(t, repo, "worktree", "add", "-b", "other", other, "main")
+	for _, dir := range []string{repo, wt, other} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "[draft].env"), []byte("x"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "d.env"), []byte("x"), 0600))
+	}
+
+	excludeFromGit(wt, "[draft].env", "test")
+
+	status := mustGit(t, wt, "status", "--porcelain")
+	assert.NotContains(t, status, "[draft].env")
+	assert.Contains(t, status, "d.env", "the pattern matches its path literally")
+	assert.Contains(t, mustGit(t, other, "status", "--porcelain"), "[draft].env")
+	assert.Contains(t, mustGit(t, repo, "status", "--porcelain"), "[draft].env")
+}
@@ -378,6 +378,11 @@ func GitListOrphanedBranches(repoPath string, knownBranches map[string]bool) ([]
 			continue
 		}
 
+		// Skip backup branches holding work preserved at archive time
+		if IsArchiveBackupBranch(branch) {
+			continue
+		}
+
 		info := OrphanedBranchInfo{
 			Branch:     branch,
 			LastCommit: commit,
@@ -410,3 +415,49 @@ func GitDeleteBranchForce(repoPath, branch string) error {
 	}
 	return nil
 }
+
+// GitUncommittedFiles returns the porcelain status lines for modified, staged
+// and untracked files in a worktree (empty if the working directory is clean)
+func GitUncommittedFiles(worktreePath string) ([]string, error) {
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = worktreePath
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %w", err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.TrimSpace(line) != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// GitUnpushedCommits returns commits on HEAD that are not contained in any
+// remote-tracking branch or any other local branch - i.e. the commits that
+// would be lost if the worktree's branch were deleted.
+// Each entry is a one-line summary ("abc1234 commit subject").
+func GitUnpushedCommits(worktreePath, branch string) ([]string, error) {
+	args := []string{"log", "--oneline", "--max-count=100", "HEAD", "--not", "--remotes"}
+	if branch != "" {
+		args = append(args, "--exclude="+branch)
+	}
+	args = append(args, "--branches")
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = worktreePath
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	var commits []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			commits = append(commits, line)
+		}
+	}
+	return commits, nil
+}
@@ -2,7 +2,6 @@ package workspace
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,9 +12,9 @@ import (
 	"github.com/hammashamzah/conductor/internal/database"
 	"github.com/hammashamzah/conductor/internal/github"
 	"github.com/hammashamzah/conductor/internal/mux"
+	"github.com/hammashamzah/conductor/internal/secrets"
 	"github.com/hammashamzah/conductor/internal/store"
 	"github.com/hammashamzah/conductor/internal/tunnel"
-	_ "github.com/lib/pq"
 )
 
 // Manager handles worktree operations
@@ -139,13 +138,18 @@ func (m *Manager) PrepareWorktree(projectName, branch string, portCount int) (st
 		portCount = project.DefaultPortsPerWorktree
 	}
 
+	// Determine branch name - use worktree name if not specified
+	if branch == "" {
+		branch = name
+	}
+
 	// Allocate ports (use store if available for persistence)
 	var ports []int
 	var err error
 	if m.store != nil {
-		ports, err = m.store.AllocatePorts(projectName, name, portCount)
+		ports, err = m.store.AllocateWorktreePorts(projectName, name, branch, portCount)
 	} else {
-		ports, err = m.config.AllocatePorts(projectName, name, portCount)
+		ports, err = m.config.AllocateWorktreePorts(projectName, name, branch, portCount)
 	}
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to allocate ports: %w", err)
@@ -162,24 +166,22 @@ func (m *Manager) PrepareWorktree(projectName, branch string, portCount int) (st
 		return "", nil, fmt.Errorf("failed to get worktree path: %w", err)
 	}
 
-	// Determine branch name - use worktree name if not specified
-	if branch == "" {
-		branch = name
-	}
-
 	// Create worktree entry with "creating" status
 	worktree := config.NewWorktree(worktreePath, branch, false, ports)
 	worktree.SetupStatus = config.SetupStatusCreating
 
 	// Generate database name if database is configured for project
-	if project.Database != nil && m.config.Defaults.LocalPostgresURL != "" && len(ports) > 0 {
-		pattern := project.Database.DBNamePattern
-		if pattern == "" {
-			pattern = "{project}-{port}"
+	db, err := m.worktreeDatabase(projectName, project, ports)
+	if err != nil {
+		if m.store != nil {
+			m.store.FreePorts(ports)
+		} else {
+			m.config.FreePorts(ports)
 		}
-		worktree.DatabaseName = generateDBName(projectName, ports[0], pattern)
-		worktree.DatabaseURL = buildWorktreeDBURL(m.config.Defaults.LocalPostgresURL, worktree.DatabaseName)
+		return "", nil, err
 	}
+	worktree.DatabaseName, worktree.DatabaseURL, worktree.DatabaseServerRef = db.name, db.url, db.serverRef
+	worktree.DatabasePending = db.name != ""
 
 	// Add worktree (use store if available for persistence)
 	if m.store != nil {
@@ -288,9 +290,19 @@ func (m *Manager) RunSetupAsync(projectName, worktreeName string, onComplete fun
 }
 
 // ArchiveWorktree marks a worktree as archived, removes git worktree and frees ports
+// Unsaved work is handled according to the configured Defaults.ArchiveSafety mode
 // Runs archive script first (if exists), then removes worktree regardless of script result
 // The worktree entry remains in config so logs can still be viewed
 func (m *Manager) ArchiveWorktree(projectName, worktreeName string) error {
+	return m.ArchiveWorktreeWithOptions(projectName, worktreeName, ArchiveOptions{
+		Safety: m.config.Defaults.ArchiveSafety,
+	})
+}
+
+// ArchiveWorktreeWithOptions archives a worktree after checking it for uncommitted
+// or unpushed work. Depending on opts the work blocks the archive, is preserved
+// in a conductor/archive/<name> branch (optionally pushed), or is discarded (Force).
+func (m *Manager) ArchiveWorktreeWithOptions(projectName, worktreeName string, opts ArchiveOptions) error {
 	project, ok := m.config.GetProject(projectName)
 	if !ok {
 		return fmt.Errorf("project '%s' not found", projectName)
@@ -309,21 +321,51 @@ func (m *Manager) ArchiveWorktree(projectName, worktreeName string) error {
 		return fmt.Errorf("worktree '%s' is already archived", worktreeName)
 	}
 
+	// Make sure no work is lost before anything destructive happens
+	backupBranch, err := m.protectUnsavedWork(worktreeName, worktree, opts)
+	if err != nil {
+		return err
+	}
+
+	// Keep enough state around to restore the worktree later
+	snapshot, err := m.captureArchiveSnapshot(project, projectName, worktreeName, worktree, backupBranch, opts.Force)
+	if err != nil {
+		return err
+	}
+	if m.store != nil {
+		_ = m.store.SetWorktreeSnapshot(projectName, worktreeName, snapshot)
+	} else {
+		worktree.Snapshot = snapshot
+	}
+
 	// Run archive script first (logs are saved to file for debugging)
 	// We ignore the error - archiving proceeds regardless
 	_ = GetSetupManager().RunArchiveScript(project, projectName, worktreeName, worktree)
 
-	// Drop database if one was created for this worktree
+	// Drop database if one was created for this worktree. Failures are left
+	// for `conductor database gc` to collect.
 	if worktree.DatabaseName != "" {
-		// Check if this is a remote database (dev_* prefix indicates remote mode)
-		if strings.HasPrefix(worktree.DatabaseName, "dev_") && project.Database != nil && project.Database.SSHHost != "" {
+		if isRemoteWorktreeDB(project, worktree) {
 			// Drop remote database via SSH
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			_ = database.RemoteDropDatabase(ctx, project.Database.SSHHost, project.Database.DevURL, worktree.DatabaseName)
-			cancel()
-		} else if m.config.Defaults.LocalPostgresURL != "" {
-			// Drop local database
-			_ = dropWorktreeDB(m.config.Defaults.LocalPostgresURL, worktree.DatabaseName)
+			if devURL, err := secrets.Resolve(project.Database.DevURL); err == nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				_ = database.RemoteDropDatabase(ctx, project.Database.SSHHost, devURL, worktree.DatabaseName)
+				cancel()
+			}
+		} else if localURL, _ := m.localDatabaseURL(project); localURL != "" {
+			// Drop local database and its snapshots
+			_ = database.DropDatabase(localURL, worktree.DatabaseName)
+			var kept []config.DatabaseSnapshot
+			for _, snap := range worktree.DatabaseSnapshots {
+				if err := database.DeleteSnapshot(localURL, &snap); err != nil {
+					kept = append(kept, snap)
+				} else if m.store != nil {
+					_ = m.store.RemoveDatabaseSnapshot(projectName, worktreeName, snap.Name)
+				}
+			}
+			if m.store == nil {
+				worktree.DatabaseSnapshots = kept
+			}
 		}
 	}
 
@@ -341,6 +383,9 @@ func (m *Manager) ArchiveWorktree(projectName, worktreeName string) error {
 	// Kill tmux window if it exists
 	_ = mux.Current().KillWindow(projectName, worktree.Branch)
 
+	// Remove conductor's env file block (user lines in a shared file are kept)
+	_ = RemoveEnvFile(project, worktree)
+
 	// Remove git worktree
 	if err := GitWorktreeRemove(project.Path, worktree.Path); err != nil {
 		// Try to remove directory manually
@@ -364,6 +409,54 @@ func (m *Manager) ArchiveWorktree(projectName, worktreeName string) error {
 	return nil
 }
 
+// CheckArchiveSafety reports what would be lost by archiving a worktree
+// Returns an empty risk if the worktree directory no longer exists
+func (m *Manager) CheckArchiveSafety(projectName, worktreeName string) (*ArchiveRisk, error) {
+	worktree, err := m.GetWorktree(projectName, worktreeName)
+	if err != nil {
+		return nil, err
+	}
+	if !WorktreeExists(worktree.Path) {
+		return &ArchiveRisk{}, nil
+	}
+	risk, err := CheckArchiveSafety(worktree.Path, worktree.Branch)
+	if err != nil {
+		return nil, err
+	}
+	risk.ModifiedFiles = ModifiedWorktreeFiles(worktree)
+	return risk, nil
+}
+
+// protectUnsavedWork applies the archive safety mode to a worktree about to be archived
+// Returns the backup branch if unsaved work was preserved
+func (m *Manager) protectUnsavedWork(worktreeName string, worktree *config.Worktree, opts ArchiveOptions) (string, error) {
+	if opts.Force || !WorktreeExists(worktree.Path) {
+		return "", nil
+	}
+
+	risk, err := CheckArchiveSafety(worktree.Path, worktree.Branch)
+	if err != nil {
+		return "", fmt.Errorf("failed to check worktree for unsaved work (use force to archive anyway): %w", err)
+	}
+	if !risk.HasRisk() {
+		return "", nil
+	}
+
+	var backupBranch string
+	switch opts.Safety {
+	case config.ArchiveSafetyStash:
+		backupBranch, err = PreserveWorktreeWork(worktree.Path, worktreeName, false)
+	case config.ArchiveSafetyPush:
+		backupBranch, err = PreserveWorktreeWork(worktree.Path, worktreeName, true)
+	default:
+		return "", &ArchiveBlockedError{Worktree: worktreeName, Risk: risk}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to preserve unsaved work: %w", err)
+	}
+	return backupBranch, nil
+}
+
 // DeleteWorktree permanently removes a worktree from config
 // Should only be called on archived worktrees
 func (m *Manager) DeleteWorktree(projectName, worktreeName string) error {
@@ -385,6 +478,34 @@ func (m *Manager) DeleteWorktree(projectName, worktreeName string) error {
 		return fmt.Errorf("worktree '%s' must be archived before deletion", worktreeName)
 	}
 
+	// Snapshots archive failed to delete would otherwise leak for good
+	if len(worktree.DatabaseSnapshots) > 0 {
+		localURL, err := m.localDatabaseURL(project)
+		if err != nil {
+			return err
+		}
+		for len(worktree.DatabaseSnapshots) > 0 {
+			snap := worktree.DatabaseSnapshots[0]
+			if err := database.DeleteSnapshot(localURL, &snap); err != nil {
+				return fmt.Errorf("failed to delete snapshot '%s': %w", snap.Name, err)
+			}
+			if m.store != nil {
+				_ = m.store.RemoveDatabaseSnapshot(projectName, worktreeName, snap.Name)
+			}
+			worktree.DatabaseSnapshots = worktree.DatabaseSnapshots[1:]
+		}
+	}
+
+	// Nothing can be restored any more, so drop what archive kept for it
+	if snap := worktree.Snapshot; snap != nil {
+		if snap.Ref != "" {
+			_, _ = gitOutput(project.Path, nil, "update-ref", "-d", snap.Ref)
+		}
+		if snap.DatabaseDump != "" {
+			_ = os.Remove(snap.DatabaseDump)
+		}
+	}
+
 	// Remove from config using store (persists to disk)
 	if m.store != nil {
 		return m.store.RemoveWorktree(projectName, worktreeName)
@@ -871,6 +992,54 @@ func (m *Manager) FetchGitStatusForProject(projectName string) (map[string]*GitS
 	return result, nil
 }
 
+// worktreeDB is a worktree's database as recorded in the config
+type worktreeDB struct {
+	name string
+	// url is stored without its password when serverRef is set
+	url       string
+	serverRef string
+}
+
+// worktreeDatabase returns the local database for a worktree with the given
+// ports, or a zero worktreeDB if the project has no local database configured
+func (m *Manager) worktreeDatabase(projectName string, project *config.Project, ports []int) (worktreeDB, error) {
+	if project.Database == nil || len(ports) == 0 {
+		return worktreeDB{}, nil
+	}
+	localURL, err := m.localDatabaseURL(project)
+	if err != nil || localURL == "" {
+		return worktreeDB{}, err
+	}
+	pattern := project.Database.DBNamePattern
+	if pattern == "" {
+		pattern = "{project}-{port}"
+	}
+	name := generateDBName(projectName, ports[0], pattern)
+	dbURL, serverRef := storedDatabaseURL(buildWorktreeDBURL(localURL, name), m.config.Defaults.LocalDatabaseURL(project.Database))
+	return worktreeDB{name: name, url: dbURL, serverRef: serverRef}, nil
+}
+
+// localDatabaseURL returns the configured local server URL for the project's
+// database engine with any secret reference resolved, or "" if it is unset
+func (m *Manager) localDatabaseURL(project *config.Project) (string, error) {
+	url, err := secrets.Resolve(m.config.Defaults.LocalDatabaseURL(project.Database))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve local database URL: %w", err)
+	}
+	return url, nil
+}
+
+// storedDatabaseURL returns the form of a worktree database URL kept in
+// conductor.json. When the server URL it was built from is a secret
+// reference, the password is left out and the reference returned, so the
+// resolved secret is never written back.
+func storedDatabaseURL(dbURL, serverURL string) (string, string) {
+	if !secrets.IsReference(serverURL) {
+		return dbURL, ""
+	}
+	return secrets.StripPassword(dbURL), serverURL
+}
+
 // generateDBName generates a database name from project and port using a pattern
 func generateDBName(projectName string, port int, pattern string) string {
 	name := pattern
@@ -927,33 +1096,3 @@ func buildWorktreeDBURL(localURL, dbName string) string {
 
 	return baseURL + dbName
 }
-
-// dropWorktreeDB drops a worktree database
-func dropWorktreeDB(localURL, dbName string) error {
-	// Connect to postgres database to drop
-	adminURL := buildWorktreeDBURL(localURL, "postgres")
-
-	db, err := sql.Open("postgres", adminURL)
-	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
-	}
-	defer func() { _ = db.Close() }()
-
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	// Terminate existing connections
-	_, _ = db.ExecContext(ctx, `
-		SELECT pg_terminate_backend(pid)
-		FROM pg_stat_activity
-		WHERE datname = $1 AND pid <> pg_backend_pid()
-	`, dbName)
-
-	// Drop database
-	_, err = db.ExecContext(ctx, fmt.Sprintf("DROP DATABASE IF EXISTS %q", dbName))
-	if err != nil {
-		return fmt.Errorf("failed to drop database: %w", err)
-	}
-
-	return nil
-}
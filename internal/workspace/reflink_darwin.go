@@ -0,0 +1,10 @@
+//go:build darwin
+
+package workspace
+
+import "golang.org/x/sys/unix"
+
+// reflinkFile clones src to dst with clonefile(2) (APFS)
+func reflinkFile(src, dst string) error {
+	return unix.Clonefile(src, dst, 0)
+}
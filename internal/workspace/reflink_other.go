@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package workspace
+
+import "errors"
+
+// reflinkFile is not supported here; callers fall back to a copy
+func reflinkFile(src, dst string) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,326 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hammashamzah/conductor/internal/config"
+	"github.com/hammashamzah/conductor/internal/database"
+)
+
+// archivedRefPrefix namespaces hidden refs that keep archived branch tips reachable
+const archivedRefPrefix = "refs/conductor/archived/"
+
+// archiveDumpTimeout bounds how long archive waits for the database dump
+const archiveDumpTimeout = 10 * time.Minute
+
+// ArchiveDumpPath returns where archive stores the database dump of a worktree
+func ArchiveDumpPath(projectName, worktreeName string) (string, error) {
+	dir, err := config.ConductorDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "archives", projectName, worktreeName+".sql"), nil
+}
+
+// isRemoteWorktreeDB returns true if the worktree database lives on the remote server
+// (dev_* prefix indicates remote mode)
+func isRemoteWorktreeDB(project *config.Project, worktree *config.Worktree) bool {
+	return strings.HasPrefix(worktree.DatabaseName, "dev_") && project.Database != nil && project.Database.SSHHost != ""
+}
+
+// captureArchiveSnapshot records the branch tip, a database dump and the port
+// labels of a worktree that is about to be archived. Archive drops the
+// database afterwards, so a failed dump is an error unless force is set;
+// anything else that fails is simply left out of the snapshot.
+func (m *Manager) captureArchiveSnapshot(project *config.Project, projectName, worktreeName string, worktree *config.Worktree, backupBranch string, force bool) (*config.ArchiveSnapshot, error) {
+	snap := &config.ArchiveSnapshot{
+		BackupBranch: backupBranch,
+		CreatedAt:    time.Now(),
+	}
+	if len(worktree.Ports) > 0 {
+		snap.Ports = append([]int(nil), worktree.Ports...)
+	}
+
+	// Pin the branch tip with a hidden ref so it survives branch deletion and gc
+	if sha, err := gitOutput(project.Path, nil, "rev-parse", "--verify", "refs/heads/"+worktree.Branch+"^{commit}"); err == nil {
+		snap.HeadSHA = sha
+		ref := archivedRefPrefix + worktreeName
+		if _, err := gitOutput(project.Path, nil, "update-ref", ref, sha); err == nil {
+			snap.Ref = ref
+		}
+	}
+
+	// Remember which label each port had
+	if projCfg, _ := config.LoadProjectConfig(project.Path); projCfg != nil && len(projCfg.Ports.Labels) > 0 {
+		snap.PortLabels = make(map[string]int)
+		for i, label := range projCfg.Ports.Labels {
+			if i < len(worktree.Ports) {
+				snap.PortLabels[label] = worktree.Ports[i]
+			}
+		}
+	}
+
+	// Dump the local database (remote databases are re-cloned from source on restore)
+	localURL, _ := m.localDatabaseURL(project)
+	if worktree.DatabaseName != "" && localURL != "" && !isRemoteWorktreeDB(project, worktree) {
+		if err := m.dumpArchiveDatabase(projectName, worktreeName, buildWorktreeDBURL(localURL, worktree.DatabaseName), snap); err != nil && !force {
+			if snap.Ref != "" {
+				_, _ = gitOutput(project.Path, nil, "update-ref", "-d", snap.Ref)
+			}
+			return nil, fmt.Errorf("failed to save database %s, archive would lose it (use force to archive anyway): %w", worktree.DatabaseName, err)
+		}
+	}
+
+	return snap, nil
+}
+
+// dumpArchiveDatabase dumps a worktree database for its archive snapshot
+func (m *Manager) dumpArchiveDatabase(projectName, worktreeName, dbURL string, snap *config.ArchiveSnapshot) error {
+	path, err := ArchiveDumpPath(projectName, worktreeName)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), archiveDumpTimeout)
+	defer cancel()
+	if err := database.DumpDatabase(ctx, dbURL, path); err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+	snap.DatabaseDump = path
+	return nil
+}
+
+// RestoreResult describes what RestoreWorktree did
+type RestoreResult struct {
+	Worktree *config.Worktree
+	// PreviousPorts are the ports the worktree had before it was archived
+	PreviousPorts []int
+	// PortLabels maps port labels to the ports they had before archiving
+	PortLabels map[string]int
+	// ReappliedWork is true if uncommitted work from the backup branch was put back
+	ReappliedWork bool
+	// DatabaseRestored is true if the database was loaded from the archive dump
+	DatabaseRestored bool
+	// DatabaseError is set if the dump could not be restored (setup clones from golden instead)
+	DatabaseError error
+}
+
+// RestoreWorktree recreates an archived worktree from its archive snapshot and
+// runs the setup script synchronously (for CLI use)
+func (m *Manager) RestoreWorktree(projectName, worktreeName string) (*RestoreResult, error) {
+	result, err := m.restoreFromSnapshot(projectName, worktreeName)
+	if err != nil {
+		return nil, err
+	}
+
+	project, _, err := m.lookupWorktree(projectName, worktreeName)
+	if err != nil {
+		return nil, err
+	}
+	worktree := result.Worktree
+
+	m.setSetupStatus(projectName, worktreeName, worktree, config.SetupStatusRunning)
+	err = runSetupSync(project, projectName, worktreeName, worktree)
+	if m.store != nil && !worktree.DatabasePending {
+		// worktree is the store's copy; record the database setup created
+		_ = m.store.ClearWorktreeDatabasePending(projectName, worktreeName)
+	}
+	if err != nil {
+		m.setSetupStatus(projectName, worktreeName, worktree, config.SetupStatusFailed)
+		return result, fmt.Errorf("setup script failed: %w", err)
+	}
+	m.setSetupStatus(projectName, worktreeName, worktree, config.SetupStatusDone)
+
+	return result, nil
+}
+
+// RestoreWorktreeAsync recreates an archived worktree in the background (for TUI use).
+// The setup script is not run; callers follow up with RunSetupAsync.
+func (m *Manager) RestoreWorktreeAsync(projectName, worktreeName string, onComplete func(result *RestoreResult, err error)) error {
+	_, worktree, err := m.lookupWorktree(projectName, worktreeName)
+	if err != nil {
+		return err
+	}
+	if !worktree.Archived {
+		return fmt.Errorf("worktree '%s' is not archived", worktreeName)
+	}
+
+	go func() {
+		result, err := m.restoreFromSnapshot(projectName, worktreeName)
+		if onComplete != nil {
+			onComplete(result, err)
+		}
+	}()
+
+	return nil
+}
+
+// restoreFromSnapshot recreates the branch, git worktree, ports and database of an
+// archived worktree and marks it active again. Setup is left to the caller.
+func (m *Manager) restoreFromSnapshot(projectName, worktreeName string) (*RestoreResult, error) {
+	project, worktree, err := m.lookupWorktree(projectName, worktreeName)
+	if err != nil {
+		return nil, err
+	}
+	if !worktree.Archived {
+		return nil, fmt.Errorf("worktree '%s' is not archived", worktreeName)
+	}
+
+	snap := worktree.Snapshot
+	if snap == nil {
+		snap = &config.ArchiveSnapshot{}
+	}
+	result := &RestoreResult{PreviousPorts: snap.Ports, PortLabels: snap.PortLabels}
+
+	// Recreate the branch at the archived tip, refusing to clobber a branch that moved on
+	if !GitBranchExists(project.Path, worktree.Branch) {
+		if snap.HeadSHA == "" {
+			return nil, fmt.Errorf("worktree '%s' has no archive snapshot and branch '%s' no longer exists", worktreeName, worktree.Branch)
+		}
+		if _, err := gitOutput(project.Path, nil, "branch", worktree.Branch, snap.HeadSHA); err != nil {
+			return nil, fmt.Errorf("failed to recreate branch '%s': %w", worktree.Branch, err)
+		}
+	} else if snap.HeadSHA != "" {
+		tip, err := gitOutput(project.Path, nil, "rev-parse", "refs/heads/"+worktree.Branch)
+		if err == nil && tip != snap.HeadSHA {
+			return nil, fmt.Errorf("branch '%s' already exists at a different commit than when archived; rename or delete it first", worktree.Branch)
+		}
+	}
+	if path := GitBranchCheckedOutAt(project.Path, worktree.Branch); path != "" {
+		return nil, fmt.Errorf("branch '%s' is already checked out at %s", worktree.Branch, path)
+	}
+	if WorktreeExists(worktree.Path) {
+		return nil, fmt.Errorf("worktree path %s already exists", worktree.Path)
+	}
+
+	// Allocate ports (the old ones were freed on archive; sticky projects get them back if free)
+	portCount := len(snap.Ports)
+	if portCount == 0 {
+		portCount = project.DefaultPortsPerWorktree
+	}
+	var ports []int
+	if m.store != nil {
+		ports, err = m.store.AllocateWorktreePorts(projectName, worktreeName, worktree.Branch, portCount)
+	} else {
+		ports, err = m.config.AllocateWorktreePorts(projectName, worktreeName, worktree.Branch, portCount)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate ports: %w", err)
+	}
+	freePorts := func() {
+		if m.store != nil {
+			m.store.FreePorts(ports)
+		} else {
+			m.config.FreePorts(ports)
+		}
+	}
+
+	// Recreate the git worktree
+	if err := os.MkdirAll(filepath.Dir(worktree.Path), 0755); err != nil {
+		freePorts()
+		return nil, fmt.Errorf("failed to create worktrees directory: %w", err)
+	}
+	if err := GitWorktreeAddExisting(project.Path, worktree.Path, worktree.Branch); err != nil {
+		freePorts()
+		return nil, fmt.Errorf("failed to create git worktree: %w", err)
+	}
+
+	// Put back uncommitted work preserved at archive time
+	if snap.BackupBranch != "" && GitBranchExists(project.Path, snap.BackupBranch) {
+		backupSHA, err := gitOutput(project.Path, nil, "rev-parse", "refs/heads/"+snap.BackupBranch)
+		if err == nil && backupSHA != snap.HeadSHA {
+			if _, err := gitOutput(worktree.Path, nil, "checkout", snap.BackupBranch, "--", "."); err == nil {
+				_, _ = gitOutput(worktree.Path, nil, "reset", "-q")
+				result.ReappliedWork = true
+			}
+		}
+	}
+
+	// Restore the database from the archive dump; setup clones from golden otherwise
+	db, err := m.worktreeDatabase(projectName, project, ports)
+	if err != nil {
+		result.DatabaseError = err
+	}
+	if db.name != "" && snap.DatabaseDump != "" {
+		localURL, _ := m.localDatabaseURL(project)
+		// The name comes from the newly allocated ports, so anything already
+		// there is a leftover from another worktree
+		if exists, err := database.DatabaseExists(localURL, db.name); err == nil && exists {
+			_ = database.DropDatabase(localURL, db.name)
+		}
+		if err := database.RestoreDatabaseFromDump(localURL, db.name, snap.DatabaseDump); err != nil {
+			result.DatabaseError = err
+		} else {
+			result.DatabaseRestored = true
+		}
+	}
+
+	// Mark the worktree active again
+	if m.store != nil {
+		if err := m.store.RestoreWorktree(projectName, worktreeName, ports, db.name, db.url, db.serverRef, db.name != "" && !result.DatabaseRestored); err != nil {
+			return nil, err
+		}
+		worktree, _ = m.store.GetWorktree(projectName, worktreeName)
+	} else {
+		worktree.Archived = false
+		worktree.ArchivedAt = time.Time{}
+		worktree.ArchiveStatus = config.ArchiveStatusNone
+		worktree.SetupStatus = config.SetupStatusCreating
+		worktree.Ports = ports
+		worktree.DatabaseName = db.name
+		worktree.DatabaseURL = db.url
+		worktree.DatabaseServerRef = db.serverRef
+		worktree.DatabasePending = db.name != "" && !result.DatabaseRestored
+		worktree.Tunnel = nil
+		worktree.Snapshot = nil
+	}
+	result.Worktree = worktree
+
+	// Point the env file at the new ports and database straight away; setup
+	// rewrites it (and reports failures) when it runs
+	_, _ = WriteEnvFile(projectName, project, worktreeName, worktree)
+
+	// The branch is live again, so the snapshot artifacts are no longer needed
+	if snap.Ref != "" {
+		_, _ = gitOutput(project.Path, nil, "update-ref", "-d", snap.Ref)
+	}
+	if result.DatabaseRestored {
+		_ = os.Remove(snap.DatabaseDump)
+	}
+
+	return result, nil
+}
+
+// lookupWorktree returns a project and worktree, preferring the store's latest state
+func (m *Manager) lookupWorktree(projectName, worktreeName string) (*config.Project, *config.Worktree, error) {
+	var project *config.Project
+	var ok bool
+	if m.store != nil {
+		project, ok = m.store.GetProject(projectName)
+	} else {
+		project, ok = m.config.GetProject(projectName)
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("project '%s' not found", projectName)
+	}
+
+	worktree, ok := project.Worktrees[worktreeName]
+	if !ok {
+		return nil, nil, fmt.Errorf("worktree '%s' not found", worktreeName)
+	}
+	return project, worktree, nil
+}
+
+// setSetupStatus updates a worktree's setup status through the store when available
+func (m *Manager) setSetupStatus(projectName, worktreeName string, worktree *config.Worktree, status config.SetupStatus) {
+	if m.store != nil {
+		_ = m.store.SetWorktreeStatus(projectName, worktreeName, status)
+	} else {
+		worktree.SetupStatus = status
+	}
+}
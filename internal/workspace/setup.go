@@ -14,6 +14,7 @@ import (
 	"github.com/hammashamzah/conductor/internal/config"
 	"github.com/hammashamzah/conductor/internal/database"
 	"github.com/hammashamzah/conductor/internal/runner"
+	"github.com/hammashamzah/conductor/internal/secrets"
 	"github.com/hammashamzah/conductor/internal/store"
 )
 
@@ -187,7 +188,7 @@ func (sm *SetupManager) RunSetupAsync(
 					}
 				}
 
-				dbName, dbURL, err := cloneWorktreeDBRemote(context.Background(), project.Database, worktreeName, progress)
+				db, err := cloneWorktreeDBRemote(context.Background(), project.Database, worktreeName, progress)
 				if err != nil {
 					errMsg := fmt.Sprintf("Warning: remote database clone failed: %v\n", err)
 					sm.mu.Lock()
@@ -201,13 +202,14 @@ func (sm *SetupManager) RunSetupAsync(
 					// Continue anyway - setup script may handle missing DB
 				} else {
 					// Update worktree with database info via store
-					_ = sm.store.SetWorktreeDatabase(projectName, worktreeName, dbName, dbURL)
+					_ = sm.store.SetWorktreeDatabase(projectName, worktreeName, db.name, db.url, db.serverRef)
 
 					// Also update the local worktree variable so env vars are correct
-					worktree.DatabaseName = dbName
-					worktree.DatabaseURL = dbURL
+					worktree.DatabaseName = db.name
+					worktree.DatabaseURL = db.url
+					worktree.DatabaseServerRef = db.serverRef
 
-					successMsg := fmt.Sprintf("Remote database %s created successfully\n", dbName)
+					successMsg := fmt.Sprintf("Remote database %s created successfully\n", db.name)
 					sm.mu.Lock()
 					if buf, ok := sm.logs[key]; ok {
 						buf.WriteString(successMsg)
@@ -217,7 +219,7 @@ func (sm *SetupManager) RunSetupAsync(
 						logFile.WriteString(successMsg)
 					}
 				}
-			} else if defaults.LocalPostgresURL != "" && worktree.DatabaseName != "" {
+			} else if localURL := defaults.LocalDatabaseURL(project.Database); localURL != "" && worktree.DatabaseName != "" {
 				// Local mode: clone from golden database
 				dbMsg := fmt.Sprintf("Cloning database to %s...\n", worktree.DatabaseName)
 				sm.mu.Lock()
@@ -233,7 +235,7 @@ func (sm *SetupManager) RunSetupAsync(
 				conductorDir, _ := config.ConductorDir()
 
 				// Clone the database
-				if err := cloneWorktreeDB(defaults.LocalPostgresURL, worktree.DatabaseName, projectName, conductorDir); err != nil {
+				if method, err := cloneWorktreeDB(localURL, worktree.DatabaseName, projectName, conductorDir, worktree.DatabasePending); err != nil {
 					errMsg := fmt.Sprintf("Warning: database clone failed: %v\n", err)
 					sm.mu.Lock()
 					if buf, ok := sm.logs[key]; ok {
@@ -245,7 +247,10 @@ func (sm *SetupManager) RunSetupAsync(
 					}
 					// Continue anyway - setup script may handle missing DB
 				} else {
-					successMsg := fmt.Sprintf("Database %s created successfully\n", worktree.DatabaseName)
+					_ = sm.store.ClearWorktreeDatabasePending(projectName, worktreeName)
+					worktree.DatabasePending = false
+
+					successMsg := fmt.Sprintf("Database %s created successfully%s\n", worktree.DatabaseName, cloneMethodSuffix(method))
 					sm.mu.Lock()
 					if buf, ok := sm.logs[key]; ok {
 						buf.WriteString(successMsg)
@@ -258,6 +263,48 @@ func (sm *SetupManager) RunSetupAsync(
 			}
 		}
 
+		// Bring untracked files (.env, secrets, caches) over from the project root
+		files, filesErr := PlaceWorktreeFiles(projectName, project, worktreeName, worktree)
+		var filesMsg string
+		if len(files) > 0 {
+			_ = sm.store.SetWorktreeFiles(projectName, worktreeName, files)
+			worktree.Files = files
+			filesMsg = fmt.Sprintf("Placed %s from the project root\n", pluralize(len(files), "file", "files"))
+		}
+		if filesErr != nil {
+			filesMsg += fmt.Sprintf("Warning: failed to place files: %v\n", filesErr)
+		}
+
+		// Seed node_modules/.venv/target from a worktree with the same lockfiles
+		if seeded, err := SeedDependencies(project, worktreeName, worktree); err != nil {
+			filesMsg += fmt.Sprintf("Warning: failed to seed dependencies: %v\n", err)
+		} else if seeded != nil {
+			filesMsg += seeded.String() + "\n"
+		}
+		if filesMsg != "" {
+			sm.mu.Lock()
+			if buf, ok := sm.logs[key]; ok {
+				buf.WriteString(filesMsg)
+			}
+			sm.mu.Unlock()
+			if logFile != nil {
+				logFile.WriteString(filesMsg)
+			}
+		}
+
+		// Render the env file now that ports and the database URL are final
+		if _, err := WriteEnvFile(projectName, project, worktreeName, worktree); err != nil {
+			errMsg := fmt.Sprintf("Warning: failed to write env file: %v\n", err)
+			sm.mu.Lock()
+			if buf, ok := sm.logs[key]; ok {
+				buf.WriteString(errMsg)
+			}
+			sm.mu.Unlock()
+			if logFile != nil {
+				logFile.WriteString(errMsg)
+			}
+		}
+
 		// Check for setup script in .conductor-scripts/setup.sh
 		scriptPath := filepath.Join(project.Path, ".conductor-scripts", "setup.sh")
 		var cmd *exec.Cmd
@@ -287,14 +334,24 @@ func (sm *SetupManager) RunSetupAsync(
 		cmdWithTimeout := exec.CommandContext(setupCtx, cmd.Args[0], cmd.Args[1:]...)
 		cmdWithTimeout.Dir = worktree.Path
 
-		// Build environment variables using the same function as CLI runner
-		cmdWithTimeout.Env = runner.BuildEnv(projectName, project, worktreeName, worktree, projectConfig)
-
 		// Capture output to both memory buffer and file
 		sm.mu.RLock()
 		logBuf := sm.logs[key]
 		sm.mu.RUnlock()
 
+		// Build environment variables using the same function as CLI runner
+		env, err := runner.BuildEnv(projectName, project, worktreeName, worktree, projectConfig)
+		if err != nil {
+			setupErr = err
+			footer := fmt.Sprintf("=== Setup FAILED at %s: %v ===\n", time.Now().Format(time.RFC3339), setupErr)
+			_, _ = logBuf.WriteString(footer)
+			if logFile != nil {
+				_, _ = logFile.WriteString(footer)
+			}
+			return
+		}
+		cmdWithTimeout.Env = env
+
 		// Write header to log
 		header := fmt.Sprintf("=== Setup started at %s ===\n", time.Now().Format(time.RFC3339))
 		_, _ = logBuf.WriteString(header)
@@ -403,7 +460,11 @@ func (sm *SetupManager) RunArchiveScript(
 	cmdWithTimeout.Dir = worktree.Path
 
 	// Build environment variables using the same function as CLI runner
-	cmdWithTimeout.Env = runner.BuildEnv(projectName, project, worktreeName, worktree, projectConfig)
+	env, err := runner.BuildEnv(projectName, project, worktreeName, worktree, projectConfig)
+	if err != nil {
+		return err
+	}
+	cmdWithTimeout.Env = env
 
 	// Write header to log
 	header := fmt.Sprintf("=== Archive started at %s ===\n", time.Now().Format(time.RFC3339))
@@ -488,7 +549,7 @@ func runSetupSync(project *config.Project, projectName, worktreeName string, wor
 				}
 			}
 
-			dbName, dbURL, err := cloneWorktreeDBRemote(context.Background(), project.Database, worktreeName, progress)
+			db, err := cloneWorktreeDBRemote(context.Background(), project.Database, worktreeName, progress)
 			if err != nil {
 				warnMsg := fmt.Sprintf("Warning: remote database clone failed: %v\n", err)
 				fmt.Print(warnMsg)
@@ -498,23 +559,24 @@ func runSetupSync(project *config.Project, projectName, worktreeName string, wor
 				// Continue anyway - setup script may handle missing DB
 			} else {
 				// Update the local worktree variable so env vars are correct
-				worktree.DatabaseName = dbName
-				worktree.DatabaseURL = dbURL
+				worktree.DatabaseName = db.name
+				worktree.DatabaseURL = db.url
+				worktree.DatabaseServerRef = db.serverRef
 
-				successMsg := fmt.Sprintf("Remote database %s cloned successfully\n", dbName)
+				successMsg := fmt.Sprintf("Remote database %s cloned successfully\n", db.name)
 				fmt.Print(successMsg)
 				if logFile != nil {
 					logFile.WriteString(successMsg)
 				}
 			}
-		} else if cfg != nil && cfg.Defaults.LocalPostgresURL != "" && worktree.DatabaseName != "" {
+		} else if cfg != nil && cfg.Defaults.LocalDatabaseURL(project.Database) != "" && worktree.DatabaseName != "" {
 			// Local mode: clone from golden database
 			fmt.Printf("Cloning database to %s...\n", worktree.DatabaseName)
 			if logFile != nil {
 				fmt.Fprintf(logFile, "Cloning database to %s...\n", worktree.DatabaseName)
 			}
 
-			if err := cloneWorktreeDB(cfg.Defaults.LocalPostgresURL, worktree.DatabaseName, projectName, conductorDir); err != nil {
+			if method, err := cloneWorktreeDB(cfg.Defaults.LocalDatabaseURL(project.Database), worktree.DatabaseName, projectName, conductorDir, worktree.DatabasePending); err != nil {
 				warnMsg := fmt.Sprintf("Warning: database clone failed: %v\n", err)
 				fmt.Print(warnMsg)
 				if logFile != nil {
@@ -522,7 +584,8 @@ func runSetupSync(project *config.Project, projectName, worktreeName string, wor
 				}
 				// Continue anyway - setup script may handle missing DB
 			} else {
-				successMsg := fmt.Sprintf("Database %s cloned successfully\n", worktree.DatabaseName)
+				worktree.DatabasePending = false
+				successMsg := fmt.Sprintf("Database %s cloned successfully%s\n", worktree.DatabaseName, cloneMethodSuffix(method))
 				fmt.Print(successMsg)
 				if logFile != nil {
 					logFile.WriteString(successMsg)
@@ -531,6 +594,48 @@ func runSetupSync(project *config.Project, projectName, worktreeName string, wor
 		}
 	}
 
+	// Bring untracked files (.env, secrets, caches) over from the project root
+	files, filesErr := PlaceWorktreeFiles(projectName, project, worktreeName, worktree)
+	if len(files) > 0 {
+		worktree.Files = files
+		msg := fmt.Sprintf("Placed %s from the project root\n", pluralize(len(files), "file", "files"))
+		fmt.Print(msg)
+		if logFile != nil {
+			logFile.WriteString(msg)
+		}
+	}
+	if filesErr != nil {
+		warnMsg := fmt.Sprintf("Warning: failed to place files: %v\n", filesErr)
+		fmt.Print(warnMsg)
+		if logFile != nil {
+			logFile.WriteString(warnMsg)
+		}
+	}
+
+	// Seed node_modules/.venv/target from a worktree with the same lockfiles
+	if seeded, err := SeedDependencies(project, worktreeName, worktree); err != nil {
+		warnMsg := fmt.Sprintf("Warning: failed to seed dependencies: %v\n", err)
+		fmt.Print(warnMsg)
+		if logFile != nil {
+			logFile.WriteString(warnMsg)
+		}
+	} else if seeded != nil {
+		msg := seeded.String() + "\n"
+		fmt.Print(msg)
+		if logFile != nil {
+			logFile.WriteString(msg)
+		}
+	}
+
+	// Render the env file now that ports and the database URL are final
+	if _, err := WriteEnvFile(projectName, project, worktreeName, worktree); err != nil {
+		warnMsg := fmt.Sprintf("Warning: failed to write env file: %v\n", err)
+		fmt.Print(warnMsg)
+		if logFile != nil {
+			logFile.WriteString(warnMsg)
+		}
+	}
+
 	// Load project config for environment variables
 	projectConfig, _ := config.LoadProjectConfig(project.Path)
 
@@ -557,7 +662,11 @@ func runSetupSync(project *config.Project, projectName, worktreeName string, wor
 	cmd.Dir = worktree.Path
 
 	// Build environment variables using the same function as CLI runner
-	cmd.Env = runner.BuildEnv(projectName, project, worktreeName, worktree, projectConfig)
+	env, err := runner.BuildEnv(projectName, project, worktreeName, worktree, projectConfig)
+	if err != nil {
+		return err
+	}
+	cmd.Env = env
 
 	// Write header to log and stdout
 	header := fmt.Sprintf("=== Setup started at %s ===\n", time.Now().Format(time.RFC3339))
@@ -593,28 +702,71 @@ func runSetupSync(project *config.Project, projectName, worktreeName string, wor
 }
 
 // cloneWorktreeDB clones the golden database to a worktree database (V3 only)
-func cloneWorktreeDB(localURL, dbName, projectName, conductorDir string) error {
+// and reports how it was copied ("" if the database already existed).
+// pending is the worktree's DatabasePending: while it is set, an existing
+// database under dbName is a stale leftover and is dropped and re-cloned.
+func cloneWorktreeDB(localURL, dbName, projectName, conductorDir string, pending bool) (database.CloneMethod, error) {
+	localURL, err := secrets.Resolve(localURL)
+	if err != nil {
+		return "", err
+	}
+
+	// Otherwise the database is the worktree's own: loaded from the archive
+	// dump on restore, or cloned by an earlier run of setup
+	exists, err := database.DatabaseExists(localURL, dbName)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		if !pending {
+			return "", nil
+		}
+		if err := database.DropDatabase(localURL, dbName); err != nil {
+			return "", fmt.Errorf("failed to drop stale database %s: %w", dbName, err)
+		}
+	}
+
 	// Use V3 golden database clone (no file-based fallback)
 	return database.CloneFromGoldenDB(context.Background(), localURL, projectName, dbName, nil)
 }
 
+// cloneMethodSuffix describes the clone path for log messages
+func cloneMethodSuffix(method database.CloneMethod) string {
+	if method == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (via %s)", method)
+}
+
 // cloneWorktreeDBRemote clones the source database to a worktree database on the remote server via SSH
-func cloneWorktreeDBRemote(ctx context.Context, cfg *config.DatabaseConfig, worktreeName string, progress func(string)) (string, string, error) {
+func cloneWorktreeDBRemote(ctx context.Context, cfg *config.DatabaseConfig, worktreeName string, progress func(string)) (worktreeDB, error) {
 	if cfg.SSHHost == "" {
-		return "", "", fmt.Errorf("remote mode requires sshHost to be configured")
+		return worktreeDB{}, fmt.Errorf("remote mode requires sshHost to be configured")
+	}
+	// The URL the worktree connects through, before secrets are resolved
+	serverURL := cfg.DevURLExternal
+	if serverURL == "" {
+		serverURL = cfg.DevURL
+	}
+	cfg, err := secrets.ResolveDatabaseConfig(cfg)
+	if err != nil {
+		return worktreeDB{}, err
 	}
 	if cfg.CloneURL == "" || cfg.DevURL == "" {
-		return "", "", fmt.Errorf("remote mode requires cloneUrl and devUrl to be configured")
+		return worktreeDB{}, fmt.Errorf("remote mode requires cloneUrl and devUrl to be configured")
+	}
+	if cfg.IsMySQL() || database.DriverName(cfg.DevURL) != config.DatabaseDriverPostgres {
+		return worktreeDB{}, fmt.Errorf("remote mode only supports PostgreSQL")
 	}
 
 	dbName := database.GenerateRemoteDBName(worktreeName)
 
-	err := database.RemoteCloneForWorktree(ctx, cfg.SSHHost, cfg.CloneURL, cfg.DevURL, dbName, cfg.ExcludeTables, progress)
+	err = database.RemoteCloneForWorktree(ctx, cfg.SSHHost, cfg.CloneURL, cfg.DevURL, dbName, cfg.ExcludeTables, progress)
 	if err != nil {
-		return "", "", err
+		return worktreeDB{}, err
 	}
 
 	// Use external URL for worktree .env (what the app connects to)
-	dbURL := database.BuildRemoteWorktreeURL(cfg.DevURLExternal, cfg.DevURL, dbName)
-	return dbName, dbURL, nil
+	dbURL, serverRef := storedDatabaseURL(database.BuildRemoteWorktreeURL(cfg.DevURLExternal, cfg.DevURL, dbName), serverURL)
+	return worktreeDB{name: dbName, url: dbURL, serverRef: serverRef}, nil
 }